@@ -53,6 +53,20 @@ func (s *Server) NodeTagList(rw http.ResponseWriter, req *http.Request) error {
 	return nil
 }
 
+// PodVolumeMappingList rebuilds the pod->PVC->PV->Longhorn volume mappings from the API server for troubleshooting,
+// bypassing the informer caches of the controllers.
+func (s *Server) PodVolumeMappingList(rw http.ResponseWriter, req *http.Request) error {
+	apiContext := api.GetApiContext(req)
+
+	mappings, err := s.m.ListPodVolumeMappings()
+	if err != nil {
+		return errors.Wrap(err, "failed to list pod volume mappings")
+	}
+
+	apiContext.Write(toPodVolumeMappingCollection(mappings))
+	return nil
+}
+
 func (s *Server) InstanceManagerGet(rw http.ResponseWriter, req *http.Request) error {
 	id := mux.Vars(req)["name"]
 	apiContext := api.GetApiContext(req)
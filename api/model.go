@@ -29,61 +29,76 @@ type Empty struct {
 type Volume struct {
 	client.Resource
 
-	Name                            string                                 `json:"name"`
-	Size                            string                                 `json:"size"`
-	Frontend                        longhorn.VolumeFrontend                `json:"frontend"`
-	DisableFrontend                 bool                                   `json:"disableFrontend"`
-	FromBackup                      string                                 `json:"fromBackup"`
-	RestoreVolumeRecurringJob       longhorn.RestoreVolumeRecurringJobType `json:"restoreVolumeRecurringJob"`
-	DataSource                      longhorn.VolumeDataSource              `json:"dataSource"`
-	CloneMode                       longhorn.CloneMode                     `json:"cloneMode"`
-	DataLocality                    longhorn.DataLocality                  `json:"dataLocality"`
-	StaleReplicaTimeout             int                                    `json:"staleReplicaTimeout"`
-	State                           longhorn.VolumeState                   `json:"state"`
-	Robustness                      longhorn.VolumeRobustness              `json:"robustness"`
-	Image                           string                                 `json:"image"`
-	CurrentImage                    string                                 `json:"currentImage"`
-	BackingImage                    string                                 `json:"backingImage"`
-	Created                         string                                 `json:"created"`
-	LastBackup                      string                                 `json:"lastBackup"`
-	LastBackupAt                    string                                 `json:"lastBackupAt"`
-	LastAttachedBy                  string                                 `json:"lastAttachedBy"`
-	Standby                         bool                                   `json:"standby"`
-	RestoreRequired                 bool                                   `json:"restoreRequired"`
-	RestoreInitiated                bool                                   `json:"restoreInitiated"`
-	RevisionCounterDisabled         bool                                   `json:"revisionCounterDisabled"`
-	SnapshotDataIntegrity           longhorn.SnapshotDataIntegrity         `json:"snapshotDataIntegrity"`
-	UnmapMarkSnapChainRemoved       longhorn.UnmapMarkSnapChainRemoved     `json:"unmapMarkSnapChainRemoved"`
-	BackupCompressionMethod         longhorn.BackupCompressionMethod       `json:"backupCompressionMethod"`
-	BackupBlockSize                 string                                 `json:"backupBlockSize"`
-	ReplicaSoftAntiAffinity         longhorn.ReplicaSoftAntiAffinity       `json:"replicaSoftAntiAffinity"`
-	ReplicaZoneSoftAntiAffinity     longhorn.ReplicaZoneSoftAntiAffinity   `json:"replicaZoneSoftAntiAffinity"`
-	ReplicaDiskSoftAntiAffinity     longhorn.ReplicaDiskSoftAntiAffinity   `json:"replicaDiskSoftAntiAffinity"`
-	DataEngine                      longhorn.DataEngineType                `json:"dataEngine"`
-	SnapshotMaxCount                int                                    `json:"snapshotMaxCount"`
-	SnapshotMaxSize                 string                                 `json:"snapshotMaxSize"`
-	ReplicaRebuildingBandwidthLimit int64                                  `json:"replicaRebuildingBandwidthLimit"`
-	UblkQueueDepth                  int                                    `json:"ublkQueueDepth"`
-	UblkNumberOfQueue               int                                    `json:"ublkNumberOfQueue"`
-	FreezeFilesystemForSnapshot     longhorn.FreezeFilesystemForSnapshot   `json:"freezeFilesystemForSnapshot"`
-	BackupTargetName                string                                 `json:"backupTargetName"`
+	Name                            string                                   `json:"name"`
+	Size                            string                                   `json:"size"`
+	MaxSize                         string                                   `json:"maxSize"`
+	Frontend                        longhorn.VolumeFrontend                  `json:"frontend"`
+	DisableFrontend                 bool                                     `json:"disableFrontend"`
+	FromBackup                      string                                   `json:"fromBackup"`
+	RestoreVolumeRecurringJob       longhorn.RestoreVolumeRecurringJobType   `json:"restoreVolumeRecurringJob"`
+	DataSource                      longhorn.VolumeDataSource                `json:"dataSource"`
+	CloneMode                       longhorn.CloneMode                       `json:"cloneMode"`
+	DataLocality                    longhorn.DataLocality                    `json:"dataLocality"`
+	NodeID                          string                                   `json:"nodeID"`
+	MigrationTargetNodeID           string                                   `json:"migrationTargetNodeID"`
+	StaleReplicaTimeout             int                                      `json:"staleReplicaTimeout"`
+	State                           longhorn.VolumeState                     `json:"state"`
+	Robustness                      longhorn.VolumeRobustness                `json:"robustness"`
+	Image                           string                                   `json:"image"`
+	CurrentImage                    string                                   `json:"currentImage"`
+	BackingImage                    string                                   `json:"backingImage"`
+	BackingImageCleanupPolicy       longhorn.BackingImageCleanupPolicy       `json:"backingImageCleanupPolicy"`
+	Created                         string                                   `json:"created"`
+	LastBackup                      string                                   `json:"lastBackup"`
+	LastBackupAt                    string                                   `json:"lastBackupAt"`
+	LastAttachedBy                  string                                   `json:"lastAttachedBy"`
+	Standby                         bool                                     `json:"standby"`
+	StandbyRestoreInterval          int                                      `json:"standbyRestoreInterval"`
+	RestoreRequired                 bool                                     `json:"restoreRequired"`
+	RestoreInitiated                bool                                     `json:"restoreInitiated"`
+	RevisionCounterDisabled         bool                                     `json:"revisionCounterDisabled"`
+	SnapshotDataIntegrity           longhorn.SnapshotDataIntegrity           `json:"snapshotDataIntegrity"`
+	SnapshotDataIntegrityCronJob    string                                   `json:"snapshotDataIntegrityCronJob"`
+	UnmapMarkSnapChainRemoved       longhorn.UnmapMarkSnapChainRemoved       `json:"unmapMarkSnapChainRemoved"`
+	BackupCompressionMethod         longhorn.BackupCompressionMethod         `json:"backupCompressionMethod"`
+	BackupBlockSize                 string                                   `json:"backupBlockSize"`
+	ReplicaSoftAntiAffinity         longhorn.ReplicaSoftAntiAffinity         `json:"replicaSoftAntiAffinity"`
+	ReplicaZoneSoftAntiAffinity     longhorn.ReplicaZoneSoftAntiAffinity     `json:"replicaZoneSoftAntiAffinity"`
+	ReplicaDiskSoftAntiAffinity     longhorn.ReplicaDiskSoftAntiAffinity     `json:"replicaDiskSoftAntiAffinity"`
+	DataEngine                      longhorn.DataEngineType                  `json:"dataEngine"`
+	DataEngineLogLevel              string                                   `json:"dataEngineLogLevel"`
+	InstanceManagerImage            string                                   `json:"instanceManagerImage"`
+	EngineImagePullSecret           string                                   `json:"engineImagePullSecret"`
+	SnapshotMaxCount                int                                      `json:"snapshotMaxCount"`
+	SnapshotMaxSize                 string                                   `json:"snapshotMaxSize"`
+	SnapshotMaxSizeAction           longhorn.SnapshotMaxSizeAction           `json:"snapshotMaxSizeAction"`
+	RecurringSnapshotOverflowAction longhorn.RecurringSnapshotOverflowAction `json:"recurringSnapshotOverflowAction"`
+	SnapshotReclaimThreshold        string                                   `json:"snapshotReclaimThreshold"`
+	ReplicaRebuildingBandwidthLimit int64                                    `json:"replicaRebuildingBandwidthLimit"`
+	UblkQueueDepth                  int                                      `json:"ublkQueueDepth"`
+	UblkNumberOfQueue               int                                      `json:"ublkNumberOfQueue"`
+	FreezeFilesystemForSnapshot     longhorn.FreezeFilesystemForSnapshot     `json:"freezeFilesystemForSnapshot"`
+	BackupTargetName                string                                   `json:"backupTargetName"`
 
 	DiskSelector         []string                      `json:"diskSelector"`
 	NodeSelector         []string                      `json:"nodeSelector"`
 	RecurringJobSelector []longhorn.VolumeRecurringJob `json:"recurringJobSelector"`
 
-	NumberOfReplicas   int                         `json:"numberOfReplicas"`
-	ReplicaAutoBalance longhorn.ReplicaAutoBalance `json:"replicaAutoBalance"`
+	NumberOfReplicas                         int                         `json:"numberOfReplicas"`
+	ReplicaAutoBalance                       longhorn.ReplicaAutoBalance `json:"replicaAutoBalance"`
+	ReplicaAutoBalanceDiskPressurePercentage int64                       `json:"replicaAutoBalanceDiskPressurePercentage"`
 
 	Conditions       map[string]longhorn.Condition `json:"conditions"`
 	KubernetesStatus longhorn.KubernetesStatus     `json:"kubernetesStatus"`
 	CloneStatus      longhorn.VolumeCloneStatus    `json:"cloneStatus"`
 	Ready            bool                          `json:"ready"`
 
-	AccessMode        longhorn.AccessMode              `json:"accessMode"`
-	ShareEndpoint     string                           `json:"shareEndpoint"`
-	ShareState        longhorn.ShareManagerState       `json:"shareState"`
-	OfflineRebuilding longhorn.VolumeOfflineRebuilding `json:"offlineRebuilding"`
+	AccessMode           longhorn.AccessMode              `json:"accessMode"`
+	AutoDowngradeFromRWX bool                             `json:"autoDowngradeFromRWX"`
+	ShareEndpoint        string                           `json:"shareEndpoint"`
+	ShareState           longhorn.ShareManagerState       `json:"shareState"`
+	OfflineRebuilding    longhorn.VolumeOfflineRebuilding `json:"offlineRebuilding"`
+	ReplicaRebuilding    longhorn.VolumeReplicaRebuilding `json:"replicaRebuilding"`
 
 	Migratable bool `json:"migratable"`
 
@@ -556,6 +571,17 @@ type SystemRestoreInput struct {
 	SystemBackup string `json:"systemBackup"`
 }
 
+type PodVolumeMapping struct {
+	client.Resource
+	Namespace   string `json:"namespace"`
+	Pod         string `json:"pod"`
+	NodeID      string `json:"nodeID"`
+	PVC         string `json:"pvc"`
+	PV          string `json:"pv"`
+	Volume      string `json:"volume"`
+	VolumeFound bool   `json:"volumeFound"`
+}
+
 type Tag struct {
 	client.Resource
 	Name    string `json:"name"`
@@ -733,6 +759,8 @@ func NewSchema() *client.Schemas {
 
 	schemas.AddType("tag", Tag{})
 
+	schemas.AddType("podVolumeMapping", PodVolumeMapping{})
+
 	schemas.AddType("instanceManager", InstanceManager{})
 	schemas.AddType("instanceProcess", longhorn.InstanceProcess{})
 
@@ -1237,11 +1265,28 @@ func volumeSchema(volume *client.Schema) {
 	volumeDataLocality.Default = longhorn.DataLocalityDisabled
 	volume.ResourceFields["dataLocality"] = volumeDataLocality
 
+	volumeNodeID := volume.ResourceFields["nodeID"]
+	volumeNodeID.Create = true
+	volume.ResourceFields["nodeID"] = volumeNodeID
+
+	volumeReplicaAutoBalanceDiskPressurePercentage := volume.ResourceFields["replicaAutoBalanceDiskPressurePercentage"]
+	volumeReplicaAutoBalanceDiskPressurePercentage.Create = true
+	volumeReplicaAutoBalanceDiskPressurePercentage.Default = 0
+	volume.ResourceFields["replicaAutoBalanceDiskPressurePercentage"] = volumeReplicaAutoBalanceDiskPressurePercentage
+
+	volumeMigrationTargetNodeID := volume.ResourceFields["migrationTargetNodeID"]
+	volumeMigrationTargetNodeID.Create = true
+	volume.ResourceFields["migrationTargetNodeID"] = volumeMigrationTargetNodeID
+
 	volumeSnapshotDataIntegrity := volume.ResourceFields["snapshotDataIntegrity"]
 	volumeSnapshotDataIntegrity.Create = true
 	volumeSnapshotDataIntegrity.Default = longhorn.SnapshotDataIntegrityIgnored
 	volume.ResourceFields["snapshotDataIntegrity"] = volumeSnapshotDataIntegrity
 
+	volumeSnapshotDataIntegrityCronJob := volume.ResourceFields["snapshotDataIntegrityCronJob"]
+	volumeSnapshotDataIntegrityCronJob.Create = true
+	volume.ResourceFields["snapshotDataIntegrityCronJob"] = volumeSnapshotDataIntegrityCronJob
+
 	volumeBackupCompressionMethod := volume.ResourceFields["backupCompressionMethod"]
 	volumeBackupCompressionMethod.Create = true
 	volumeBackupCompressionMethod.Default = longhorn.BackupCompressionMethodLz4
@@ -1261,6 +1306,53 @@ func volumeSchema(volume *client.Schema) {
 	volumeBackingImage.Create = true
 	volume.ResourceFields["backingImage"] = volumeBackingImage
 
+	volumeBackingImageCleanupPolicy := volume.ResourceFields["backingImageCleanupPolicy"]
+	volumeBackingImageCleanupPolicy.Create = true
+	volumeBackingImageCleanupPolicy.Default = longhorn.BackingImageCleanupPolicyRetain
+	volume.ResourceFields["backingImageCleanupPolicy"] = volumeBackingImageCleanupPolicy
+
+	volumeSnapshotReclaimThreshold := volume.ResourceFields["snapshotReclaimThreshold"]
+	volumeSnapshotReclaimThreshold.Create = true
+	volume.ResourceFields["snapshotReclaimThreshold"] = volumeSnapshotReclaimThreshold
+
+	volumeInstanceManagerImage := volume.ResourceFields["instanceManagerImage"]
+	volumeInstanceManagerImage.Create = true
+	volume.ResourceFields["instanceManagerImage"] = volumeInstanceManagerImage
+
+	volumeAutoDowngradeFromRWX := volume.ResourceFields["autoDowngradeFromRWX"]
+	volumeAutoDowngradeFromRWX.Create = true
+	volume.ResourceFields["autoDowngradeFromRWX"] = volumeAutoDowngradeFromRWX
+
+	volumeStandbyRestoreInterval := volume.ResourceFields["standbyRestoreInterval"]
+	volumeStandbyRestoreInterval.Create = true
+	volume.ResourceFields["standbyRestoreInterval"] = volumeStandbyRestoreInterval
+
+	volumeMaxSize := volume.ResourceFields["maxSize"]
+	volumeMaxSize.Create = true
+	volume.ResourceFields["maxSize"] = volumeMaxSize
+
+	volumeReplicaRebuilding := volume.ResourceFields["replicaRebuilding"]
+	volumeReplicaRebuilding.Create = true
+	volumeReplicaRebuilding.Default = longhorn.VolumeReplicaRebuildingIgnored
+	volume.ResourceFields["replicaRebuilding"] = volumeReplicaRebuilding
+
+	volumeSnapshotMaxSizeAction := volume.ResourceFields["snapshotMaxSizeAction"]
+	volumeSnapshotMaxSizeAction.Create = true
+	volumeSnapshotMaxSizeAction.Default = longhorn.SnapshotMaxSizeActionBlock
+	volume.ResourceFields["snapshotMaxSizeAction"] = volumeSnapshotMaxSizeAction
+
+	volumeRecurringSnapshotOverflowAction := volume.ResourceFields["recurringSnapshotOverflowAction"]
+	volumeRecurringSnapshotOverflowAction.Create = true
+	volume.ResourceFields["recurringSnapshotOverflowAction"] = volumeRecurringSnapshotOverflowAction
+
+	volumeDataEngineLogLevel := volume.ResourceFields["dataEngineLogLevel"]
+	volumeDataEngineLogLevel.Create = true
+	volume.ResourceFields["dataEngineLogLevel"] = volumeDataEngineLogLevel
+
+	volumeEngineImagePullSecret := volume.ResourceFields["engineImagePullSecret"]
+	volumeEngineImagePullSecret.Create = true
+	volume.ResourceFields["engineImagePullSecret"] = volumeEngineImagePullSecret
+
 	replicas := volume.ResourceFields["replicas"]
 	replicas.Type = "array[replica]"
 	volume.ResourceFields["replicas"] = replicas
@@ -1641,35 +1733,45 @@ func toVolumeResource(v *longhorn.Volume, ves []*longhorn.Engine, vrs []*longhor
 			Actions: map[string]string{},
 			Links:   map[string]string{},
 		},
-		Name:                            v.Name,
-		Size:                            strconv.FormatInt(v.Spec.Size, 10),
-		Frontend:                        v.Spec.Frontend,
-		DisableFrontend:                 v.Spec.DisableFrontend,
-		LastAttachedBy:                  v.Spec.LastAttachedBy,
-		FromBackup:                      v.Spec.FromBackup,
-		DataSource:                      v.Spec.DataSource,
-		CloneMode:                       v.Spec.CloneMode,
-		NumberOfReplicas:                v.Spec.NumberOfReplicas,
-		ReplicaAutoBalance:              v.Spec.ReplicaAutoBalance,
-		DataLocality:                    v.Spec.DataLocality,
-		SnapshotDataIntegrity:           v.Spec.SnapshotDataIntegrity,
-		SnapshotMaxCount:                v.Spec.SnapshotMaxCount,
-		SnapshotMaxSize:                 strconv.FormatInt(v.Spec.SnapshotMaxSize, 10),
-		ReplicaRebuildingBandwidthLimit: v.Spec.ReplicaRebuildingBandwidthLimit,
-		UblkQueueDepth:                  v.Spec.UblkQueueDepth,
-		UblkNumberOfQueue:               v.Spec.UblkNumberOfQueue,
-		BackupCompressionMethod:         v.Spec.BackupCompressionMethod,
-		BackupBlockSize:                 strconv.FormatInt(v.Spec.BackupBlockSize, 10),
-		StaleReplicaTimeout:             v.Spec.StaleReplicaTimeout,
-		Created:                         v.CreationTimestamp.String(),
-		Image:                           v.Spec.Image,
-		BackingImage:                    v.Spec.BackingImage,
-		Standby:                         v.Spec.Standby,
-		DiskSelector:                    v.Spec.DiskSelector,
-		NodeSelector:                    v.Spec.NodeSelector,
-		RestoreVolumeRecurringJob:       v.Spec.RestoreVolumeRecurringJob,
-		FreezeFilesystemForSnapshot:     v.Spec.FreezeFilesystemForSnapshot,
-		BackupTargetName:                v.Spec.BackupTargetName,
+		Name:                                     v.Name,
+		Size:                                     strconv.FormatInt(v.Spec.Size, 10),
+		MaxSize:                                  strconv.FormatInt(v.Spec.MaxSize, 10),
+		Frontend:                                 v.Spec.Frontend,
+		DisableFrontend:                          v.Spec.DisableFrontend,
+		LastAttachedBy:                           v.Spec.LastAttachedBy,
+		FromBackup:                               v.Spec.FromBackup,
+		DataSource:                               v.Spec.DataSource,
+		CloneMode:                                v.Spec.CloneMode,
+		NumberOfReplicas:                         v.Spec.NumberOfReplicas,
+		ReplicaAutoBalance:                       v.Spec.ReplicaAutoBalance,
+		ReplicaAutoBalanceDiskPressurePercentage: v.Spec.ReplicaAutoBalanceDiskPressurePercentage,
+		DataLocality:                             v.Spec.DataLocality,
+		NodeID:                                   v.Spec.NodeID,
+		MigrationTargetNodeID:                    v.Spec.MigrationTargetNodeID,
+		SnapshotDataIntegrity:                    v.Spec.SnapshotDataIntegrity,
+		SnapshotDataIntegrityCronJob:             v.Spec.SnapshotDataIntegrityCronJob,
+		SnapshotMaxCount:                         v.Spec.SnapshotMaxCount,
+		SnapshotMaxSize:                          strconv.FormatInt(v.Spec.SnapshotMaxSize, 10),
+		SnapshotMaxSizeAction:                    v.Spec.SnapshotMaxSizeAction,
+		RecurringSnapshotOverflowAction:          v.Spec.RecurringSnapshotOverflowAction,
+		SnapshotReclaimThreshold:                 v.Spec.SnapshotReclaimThreshold,
+		ReplicaRebuildingBandwidthLimit:          v.Spec.ReplicaRebuildingBandwidthLimit,
+		UblkQueueDepth:                           v.Spec.UblkQueueDepth,
+		UblkNumberOfQueue:                        v.Spec.UblkNumberOfQueue,
+		BackupCompressionMethod:                  v.Spec.BackupCompressionMethod,
+		BackupBlockSize:                          strconv.FormatInt(v.Spec.BackupBlockSize, 10),
+		StaleReplicaTimeout:                      v.Spec.StaleReplicaTimeout,
+		Created:                                  v.CreationTimestamp.String(),
+		Image:                                    v.Spec.Image,
+		BackingImage:                             v.Spec.BackingImage,
+		BackingImageCleanupPolicy:                v.Spec.BackingImageCleanupPolicy,
+		Standby:                                  v.Spec.Standby,
+		StandbyRestoreInterval:                   v.Spec.StandbyRestoreInterval,
+		DiskSelector:                             v.Spec.DiskSelector,
+		NodeSelector:                             v.Spec.NodeSelector,
+		RestoreVolumeRecurringJob:                v.Spec.RestoreVolumeRecurringJob,
+		FreezeFilesystemForSnapshot:              v.Spec.FreezeFilesystemForSnapshot,
+		BackupTargetName:                         v.Spec.BackupTargetName,
 
 		State:                       v.Status.State,
 		Robustness:                  v.Status.Robustness,
@@ -1684,12 +1786,17 @@ func toVolumeResource(v *longhorn.Volume, ves []*longhorn.Engine, vrs []*longhor
 		ReplicaZoneSoftAntiAffinity: v.Spec.ReplicaZoneSoftAntiAffinity,
 		ReplicaDiskSoftAntiAffinity: v.Spec.ReplicaDiskSoftAntiAffinity,
 		DataEngine:                  v.Spec.DataEngine,
+		DataEngineLogLevel:          v.Spec.DataEngineLogLevel,
+		InstanceManagerImage:        v.Spec.InstanceManagerImage,
+		EngineImagePullSecret:       v.Spec.EngineImagePullSecret,
 		Ready:                       ready,
 
-		AccessMode:        v.Spec.AccessMode,
-		ShareEndpoint:     v.Status.ShareEndpoint,
-		ShareState:        v.Status.ShareState,
-		OfflineRebuilding: v.Spec.OfflineRebuilding,
+		AccessMode:           v.Spec.AccessMode,
+		AutoDowngradeFromRWX: v.Spec.AutoDowngradeFromRWX,
+		ShareEndpoint:        v.Status.ShareEndpoint,
+		ShareState:           v.Status.ShareState,
+		OfflineRebuilding:    v.Spec.OfflineRebuilding,
+		ReplicaRebuilding:    v.Spec.ReplicaRebuilding,
 
 		Migratable: v.Spec.Migratable,
 
@@ -2386,6 +2493,27 @@ func toTagCollection(tags []string, tagType string, apiContext *api.ApiContext)
 	return &client.GenericCollection{Data: data, Collection: client.Collection{ResourceType: "tag"}}
 }
 
+func toPodVolumeMappingCollection(mappings []datastore.PodVolumeMapping) *client.GenericCollection {
+	data := []interface{}{}
+	for _, mapping := range mappings {
+		data = append(data, &PodVolumeMapping{
+			Resource: client.Resource{
+				Id:    mapping.Namespace + "/" + mapping.Pod + "/" + mapping.PVC,
+				Type:  "podVolumeMapping",
+				Links: map[string]string{},
+			},
+			Namespace:   mapping.Namespace,
+			Pod:         mapping.Pod,
+			NodeID:      mapping.NodeID,
+			PVC:         mapping.PVC,
+			PV:          mapping.PV,
+			Volume:      mapping.Volume,
+			VolumeFound: mapping.VolumeFound,
+		})
+	}
+	return &client.GenericCollection{Data: data, Collection: client.Collection{ResourceType: "podVolumeMapping"}}
+}
+
 func toInstanceManagerResource(im *longhorn.InstanceManager) *InstanceManager {
 	return &InstanceManager{
 		Resource: client.Resource{
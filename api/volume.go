@@ -177,41 +177,61 @@ func (s *Server) VolumeCreate(rw http.ResponseWriter, req *http.Request) error {
 		return errors.Wrapf(err, "failed to parse backup block size %v", volume.BackupBlockSize)
 	}
 
+	maxSize, err := util.ConvertSize(volume.MaxSize)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse max size %v", volume.MaxSize)
+	}
+
 	v, err := s.m.Create(volume.Name, &longhorn.VolumeSpec{
-		Size:                            size,
-		AccessMode:                      volume.AccessMode,
-		Migratable:                      volume.Migratable,
-		Encrypted:                       volume.Encrypted,
-		Frontend:                        volume.Frontend,
-		FromBackup:                      volume.FromBackup,
-		RestoreVolumeRecurringJob:       volume.RestoreVolumeRecurringJob,
-		DataSource:                      volume.DataSource,
-		CloneMode:                       volume.CloneMode,
-		NumberOfReplicas:                volume.NumberOfReplicas,
-		ReplicaAutoBalance:              volume.ReplicaAutoBalance,
-		DataLocality:                    volume.DataLocality,
-		StaleReplicaTimeout:             volume.StaleReplicaTimeout,
-		BackingImage:                    volume.BackingImage,
-		Standby:                         volume.Standby,
-		RevisionCounterDisabled:         volume.RevisionCounterDisabled,
-		DiskSelector:                    volume.DiskSelector,
-		NodeSelector:                    volume.NodeSelector,
-		SnapshotDataIntegrity:           volume.SnapshotDataIntegrity,
-		SnapshotMaxCount:                volume.SnapshotMaxCount,
-		SnapshotMaxSize:                 snapshotMaxSize,
-		ReplicaRebuildingBandwidthLimit: volume.ReplicaRebuildingBandwidthLimit,
-		UblkQueueDepth:                  volume.UblkQueueDepth,
-		UblkNumberOfQueue:               volume.UblkNumberOfQueue,
-		BackupCompressionMethod:         volume.BackupCompressionMethod,
-		BackupBlockSize:                 backupBlockSize,
-		UnmapMarkSnapChainRemoved:       volume.UnmapMarkSnapChainRemoved,
-		ReplicaSoftAntiAffinity:         volume.ReplicaSoftAntiAffinity,
-		ReplicaZoneSoftAntiAffinity:     volume.ReplicaZoneSoftAntiAffinity,
-		ReplicaDiskSoftAntiAffinity:     volume.ReplicaDiskSoftAntiAffinity,
-		DataEngine:                      volume.DataEngine,
-		FreezeFilesystemForSnapshot:     volume.FreezeFilesystemForSnapshot,
-		BackupTargetName:                volume.BackupTargetName,
-		OfflineRebuilding:               volume.OfflineRebuilding,
+		Size:                                     size,
+		MaxSize:                                  maxSize,
+		AccessMode:                               volume.AccessMode,
+		AutoDowngradeFromRWX:                     volume.AutoDowngradeFromRWX,
+		Migratable:                               volume.Migratable,
+		Encrypted:                                volume.Encrypted,
+		Frontend:                                 volume.Frontend,
+		FromBackup:                               volume.FromBackup,
+		RestoreVolumeRecurringJob:                volume.RestoreVolumeRecurringJob,
+		DataSource:                               volume.DataSource,
+		CloneMode:                                volume.CloneMode,
+		NumberOfReplicas:                         volume.NumberOfReplicas,
+		ReplicaAutoBalance:                       volume.ReplicaAutoBalance,
+		ReplicaAutoBalanceDiskPressurePercentage: volume.ReplicaAutoBalanceDiskPressurePercentage,
+		DataLocality:                             volume.DataLocality,
+		NodeID:                                   volume.NodeID,
+		MigrationTargetNodeID:                    volume.MigrationTargetNodeID,
+		StaleReplicaTimeout:                      volume.StaleReplicaTimeout,
+		BackingImage:                             volume.BackingImage,
+		BackingImageCleanupPolicy:                volume.BackingImageCleanupPolicy,
+		Standby:                                  volume.Standby,
+		StandbyRestoreInterval:                   volume.StandbyRestoreInterval,
+		RevisionCounterDisabled:                  volume.RevisionCounterDisabled,
+		DiskSelector:                             volume.DiskSelector,
+		NodeSelector:                             volume.NodeSelector,
+		SnapshotDataIntegrity:                    volume.SnapshotDataIntegrity,
+		SnapshotDataIntegrityCronJob:             volume.SnapshotDataIntegrityCronJob,
+		SnapshotMaxCount:                         volume.SnapshotMaxCount,
+		SnapshotMaxSize:                          snapshotMaxSize,
+		SnapshotMaxSizeAction:                    volume.SnapshotMaxSizeAction,
+		RecurringSnapshotOverflowAction:          volume.RecurringSnapshotOverflowAction,
+		SnapshotReclaimThreshold:                 volume.SnapshotReclaimThreshold,
+		ReplicaRebuildingBandwidthLimit:          volume.ReplicaRebuildingBandwidthLimit,
+		UblkQueueDepth:                           volume.UblkQueueDepth,
+		UblkNumberOfQueue:                        volume.UblkNumberOfQueue,
+		BackupCompressionMethod:                  volume.BackupCompressionMethod,
+		BackupBlockSize:                          backupBlockSize,
+		UnmapMarkSnapChainRemoved:                volume.UnmapMarkSnapChainRemoved,
+		ReplicaSoftAntiAffinity:                  volume.ReplicaSoftAntiAffinity,
+		ReplicaZoneSoftAntiAffinity:              volume.ReplicaZoneSoftAntiAffinity,
+		ReplicaDiskSoftAntiAffinity:              volume.ReplicaDiskSoftAntiAffinity,
+		DataEngine:                               volume.DataEngine,
+		DataEngineLogLevel:                       volume.DataEngineLogLevel,
+		InstanceManagerImage:                     volume.InstanceManagerImage,
+		EngineImagePullSecret:                    volume.EngineImagePullSecret,
+		FreezeFilesystemForSnapshot:              volume.FreezeFilesystemForSnapshot,
+		BackupTargetName:                         volume.BackupTargetName,
+		OfflineRebuilding:                        volume.OfflineRebuilding,
+		ReplicaRebuilding:                        volume.ReplicaRebuilding,
 	}, volume.RecurringJobSelector)
 	if err != nil {
 		return errors.Wrap(err, "failed to create volume")
@@ -188,7 +188,7 @@ func migratePVAndPVCForPre070Volume(kubeClient *kubeclientset.Clientset, lhClien
 		return fmt.Errorf("failed to wait for the old PV deletion complete")
 	}
 
-	newPV := datastore.NewPVManifestForVolume(v, oldPV.Name, staticStorageClass.Value, oldPV.Spec.CSI.FSType)
+	newPV := datastore.NewPVManifestForVolume(v, oldPV.Name, staticStorageClass.Value, oldPV.Spec.CSI.FSType, oldPV.Spec.MountOptions, "", nil, oldPV.Spec.CSI.NodeStageSecretRef)
 	if _, err = kubeClient.CoreV1().PersistentVolumes().Create(context.TODO(), newPV, metav1.CreateOptions{}); err != nil {
 		return err
 	}
@@ -80,6 +80,32 @@ func snapshotCRsToNameWithTimestamps(snapshotCRs []longhornclient.SnapshotCR) []
 	return result
 }
 
+// filterSnapshotCRsToPruneForMaxCount returns the oldest snapshots of the recurring job to prune, so that the new snapshot
+// fits in snapshotMaxCount, and whether the new snapshot should be skipped instead. The snapshots being removed are not
+// counted, since the snapshot controller purges them right away.
+func filterSnapshotCRsToPruneForMaxCount(snapshotCRs []longhornclient.SnapshotCR, jobLabel string, snapshotMaxCount int, action longhorn.RecurringSnapshotOverflowAction) ([]string, bool) {
+	if snapshotMaxCount <= 0 || action == "" {
+		return nil, false
+	}
+
+	snapshotCRs = filterSnapshotCRs(snapshotCRs, func(snapshotCR longhornclient.SnapshotCR) bool {
+		return snapshotCR.CreationTime != "" && !snapshotCR.MarkRemoved
+	})
+	overflow := len(snapshotCRs) - snapshotMaxCount + 1
+	if overflow <= 0 {
+		return nil, false
+	}
+	if action != longhorn.RecurringSnapshotOverflowActionPrune {
+		return nil, true
+	}
+
+	candidates := snapshotCRsToNameWithTimestamps(filterSnapshotCRsWithLabel(snapshotCRs, types.RecurringJobLabel, jobLabel))
+	if len(candidates) < overflow {
+		return nil, true
+	}
+	return filterExpiredItems(candidates, len(candidates)-overflow), false
+}
+
 func snapshotCRsToNames(snapshotCRs []longhornclient.SnapshotCR) []string {
 	result := []string{}
 	for _, snapshotCR := range snapshotCRs {
@@ -0,0 +1,109 @@
+package recurringjob
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhornclient "github.com/longhorn/longhorn-manager/client"
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+func TestFilterSnapshotCRsToPruneForMaxCount(t *testing.T) {
+	newSnapshotCR := func(name, jobLabel, creationTime string) longhornclient.SnapshotCR {
+		snapshotCR := longhornclient.SnapshotCR{
+			Name:           name,
+			CrCreationTime: creationTime,
+			CreationTime:   creationTime,
+		}
+		if jobLabel != "" {
+			snapshotCR.Labels = map[string]string{types.RecurringJobLabel: jobLabel}
+		}
+		return snapshotCR
+	}
+
+	snapshotCRs := []longhornclient.SnapshotCR{
+		newSnapshotCR("user-1", "", "2024-01-01T00:00:00Z"),
+		newSnapshotCR("job-2", "job", "2024-01-03T00:00:00Z"),
+		newSnapshotCR("job-1", "job", "2024-01-02T00:00:00Z"),
+		newSnapshotCR("other-1", "other", "2024-01-01T12:00:00Z"),
+	}
+
+	pendingSnapshotCR := newSnapshotCR("job-pending", "job", "")
+	pendingSnapshotCR.CrCreationTime = "2024-01-04T00:00:00Z"
+	removedSnapshotCR := newSnapshotCR("job-removed", "job", "2024-01-01T06:00:00Z")
+	removedSnapshotCR.MarkRemoved = true
+
+	tests := map[string]struct {
+		snapshotCRs      []longhornclient.SnapshotCR
+		snapshotMaxCount int
+		action           longhorn.RecurringSnapshotOverflowAction
+
+		expectPruned  []string
+		expectSkipped bool
+	}{
+		"no action": {
+			snapshotCRs:      snapshotCRs,
+			snapshotMaxCount: 4,
+		},
+		"no snapshotMaxCount": {
+			snapshotCRs: snapshotCRs,
+			action:      longhorn.RecurringSnapshotOverflowActionSkip,
+		},
+		"skip below snapshotMaxCount": {
+			snapshotCRs:      snapshotCRs,
+			snapshotMaxCount: 5,
+			action:           longhorn.RecurringSnapshotOverflowActionSkip,
+		},
+		"skip at snapshotMaxCount": {
+			snapshotCRs:      snapshotCRs,
+			snapshotMaxCount: 4,
+			action:           longhorn.RecurringSnapshotOverflowActionSkip,
+			expectSkipped:    true,
+		},
+		"skip ignores pending and removed snapshots": {
+			snapshotCRs:      append([]longhornclient.SnapshotCR{pendingSnapshotCR, removedSnapshotCR}, snapshotCRs...),
+			snapshotMaxCount: 5,
+			action:           longhorn.RecurringSnapshotOverflowActionSkip,
+		},
+		"prune below snapshotMaxCount": {
+			snapshotCRs:      snapshotCRs,
+			snapshotMaxCount: 5,
+			action:           longhorn.RecurringSnapshotOverflowActionPrune,
+		},
+		"prune the oldest snapshot of the job": {
+			snapshotCRs:      snapshotCRs,
+			snapshotMaxCount: 4,
+			action:           longhorn.RecurringSnapshotOverflowActionPrune,
+			expectPruned:     []string{"job-1"},
+		},
+		"prune the oldest snapshots of the job": {
+			snapshotCRs:      snapshotCRs,
+			snapshotMaxCount: 3,
+			action:           longhorn.RecurringSnapshotOverflowActionPrune,
+			expectPruned:     []string{"job-1", "job-2"},
+		},
+		"prune skips without enough snapshots of the job": {
+			snapshotCRs:      snapshotCRs,
+			snapshotMaxCount: 2,
+			action:           longhorn.RecurringSnapshotOverflowActionPrune,
+			expectSkipped:    true,
+		},
+		"prune ignores removed snapshots": {
+			snapshotCRs:      append([]longhornclient.SnapshotCR{removedSnapshotCR}, snapshotCRs...),
+			snapshotMaxCount: 4,
+			action:           longhorn.RecurringSnapshotOverflowActionPrune,
+			expectPruned:     []string{"job-1"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pruned, skipped := filterSnapshotCRsToPruneForMaxCount(tc.snapshotCRs, "job", tc.snapshotMaxCount, tc.action)
+			assert.Equal(t, tc.expectPruned, pruned)
+			assert.Equal(t, tc.expectSkipped, skipped)
+		})
+	}
+}
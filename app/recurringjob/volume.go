@@ -250,6 +250,14 @@ func (job *VolumeJob) doSnapshot() (err error) {
 	if latestSnapshotCR.Name != "" && !alreadyCreatedBefore {
 		job.snapshotName = latestSnapshotCR.Name
 	} else {
+		skipped, err := job.handleSnapshotOverflow(volume, snapshotCRList.Data)
+		if err != nil {
+			return err
+		}
+		if skipped {
+			return nil
+		}
+
 		_, err = job.api.Volume.ActionSnapshotCRCreate(volume, &longhornclient.SnapshotCRInput{
 			Labels: job.specLabels,
 			Name:   job.snapshotName,
@@ -268,6 +276,27 @@ func (job *VolumeJob) doSnapshot() (err error) {
 	return nil
 }
 
+// handleSnapshotOverflow applies the RecurringSnapshotOverflowAction of the volume once its snapshot count reaches
+// SnapshotMaxCount, by pruning the oldest snapshots of the recurring job or skipping the new snapshot.
+func (job *VolumeJob) handleSnapshotOverflow(volume *longhornclient.Volume, snapshotCRs []longhornclient.SnapshotCR) (bool, error) {
+	action := longhorn.RecurringSnapshotOverflowAction(volume.RecurringSnapshotOverflowAction)
+	prunedSnapshots, skipped := filterSnapshotCRsToPruneForMaxCount(snapshotCRs, job.specLabels[types.RecurringJobLabel], int(volume.SnapshotMaxCount), action)
+	if skipped {
+		job.logger.Warnf("Skipped creating the snapshot %v since volume %v reached SnapshotMaxCount %v", job.snapshotName, volume.Name, volume.SnapshotMaxCount)
+		return true, nil
+	}
+
+	for _, snapshotName := range prunedSnapshots {
+		if _, err := job.api.Volume.ActionSnapshotCRDelete(volume, &longhornclient.SnapshotCRInput{
+			Name: snapshotName,
+		}); err != nil {
+			return false, errors.Wrapf(err, "failed to prune snapshot %v", snapshotName)
+		}
+		job.logger.Infof("Pruned snapshot CR %v to make room for snapshot %v since volume %v reached SnapshotMaxCount %v", snapshotName, job.snapshotName, volume.Name, volume.SnapshotMaxCount)
+	}
+	return false, nil
+}
+
 func (job *VolumeJob) waitForSnaphotReady(volume *longhornclient.Volume, timeout int) error {
 	for i := 0; i < timeout; i++ {
 		existSnapshotCR, err := job.api.Volume.ActionSnapshotCRGet(volume, &longhornclient.SnapshotCRInput{
@@ -9,8 +9,12 @@ type Volume struct {
 
 	AccessMode string `json:"accessMode,omitempty" yaml:"access_mode,omitempty"`
 
+	AutoDowngradeFromRWX bool `json:"autoDowngradeFromRWX,omitempty" yaml:"auto_downgrade_from_r_w_x,omitempty"`
+
 	BackingImage string `json:"backingImage,omitempty" yaml:"backing_image,omitempty"`
 
+	BackingImageCleanupPolicy string `json:"backingImageCleanupPolicy,omitempty" yaml:"backing_image_cleanup_policy,omitempty"`
+
 	BackupBlockSize string `json:"backupBlockSize,omitempty" yaml:"backup_block_size,omitempty"`
 
 	BackupCompressionMethod string `json:"backupCompressionMethod,omitempty" yaml:"backup_compression_method,omitempty"`
@@ -33,6 +37,8 @@ type Volume struct {
 
 	DataEngine string `json:"dataEngine,omitempty" yaml:"data_engine,omitempty"`
 
+	DataEngineLogLevel string `json:"dataEngineLogLevel,omitempty" yaml:"data_engine_log_level,omitempty"`
+
 	DataLocality string `json:"dataLocality,omitempty" yaml:"data_locality,omitempty"`
 
 	DataSource string `json:"dataSource,omitempty" yaml:"data_source,omitempty"`
@@ -43,6 +49,8 @@ type Volume struct {
 
 	Encrypted bool `json:"encrypted,omitempty" yaml:"encrypted,omitempty"`
 
+	EngineImagePullSecret string `json:"engineImagePullSecret,omitempty" yaml:"engine_image_pull_secret,omitempty"`
+
 	FreezeFilesystemForSnapshot string `json:"freezeFSForSnapshot,omitempty" yaml:"freeze_fsfor_snapshot,omitempty"`
 
 	FromBackup string `json:"fromBackup,omitempty" yaml:"from_backup,omitempty"`
@@ -51,6 +59,8 @@ type Volume struct {
 
 	Image string `json:"image,omitempty" yaml:"image,omitempty"`
 
+	InstanceManagerImage string `json:"instanceManagerImage,omitempty" yaml:"instance_manager_image,omitempty"`
+
 	KubernetesStatus KubernetesStatus `json:"kubernetesStatus,omitempty" yaml:"kubernetes_status,omitempty"`
 
 	LastAttachedBy string `json:"lastAttachedBy,omitempty" yaml:"last_attached_by,omitempty"`
@@ -59,10 +69,16 @@ type Volume struct {
 
 	LastBackupAt string `json:"lastBackupAt,omitempty" yaml:"last_backup_at,omitempty"`
 
+	MaxSize string `json:"maxSize,omitempty" yaml:"max_size,omitempty"`
+
 	Migratable bool `json:"migratable,omitempty" yaml:"migratable,omitempty"`
 
+	MigrationTargetNodeID string `json:"migrationTargetNodeID,omitempty" yaml:"migration_target_node_id,omitempty"`
+
 	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 
+	NodeID string `json:"nodeID,omitempty" yaml:"node_id,omitempty"`
+
 	NodeSelector []string `json:"nodeSelector,omitempty" yaml:"node_selector,omitempty"`
 
 	NumberOfReplicas int64 `json:"numberOfReplicas,omitempty" yaml:"number_of_replicas,omitempty"`
@@ -77,10 +93,16 @@ type Volume struct {
 
 	RecurringJobSelector []VolumeRecurringJob `json:"recurringJobSelector,omitempty" yaml:"recurring_job_selector,omitempty"`
 
+	RecurringSnapshotOverflowAction string `json:"recurringSnapshotOverflowAction,omitempty" yaml:"recurring_snapshot_overflow_action,omitempty"`
+
 	ReplicaAutoBalance string `json:"replicaAutoBalance,omitempty" yaml:"replica_auto_balance,omitempty"`
 
+	ReplicaAutoBalanceDiskPressurePercentage int64 `json:"replicaAutoBalanceDiskPressurePercentage,omitempty" yaml:"replica_auto_balance_disk_pressure_percentage,omitempty"`
+
 	ReplicaDiskSoftAntiAffinity string `json:"replicaDiskSoftAntiAffinity,omitempty" yaml:"replica_disk_soft_anti_affinity,omitempty"`
 
+	ReplicaRebuilding string `json:"replicaRebuilding,omitempty" yaml:"replica_rebuilding,omitempty"`
+
 	ReplicaSoftAntiAffinity string `json:"replicaSoftAntiAffinity,omitempty" yaml:"replica_soft_anti_affinity,omitempty"`
 
 	ReplicaZoneSoftAntiAffinity string `json:"replicaZoneSoftAntiAffinity,omitempty" yaml:"replica_zone_soft_anti_affinity,omitempty"`
@@ -107,14 +129,22 @@ type Volume struct {
 
 	SnapshotDataIntegrity string `json:"snapshotDataIntegrity,omitempty" yaml:"snapshot_data_integrity,omitempty"`
 
+	SnapshotDataIntegrityCronJob string `json:"snapshotDataIntegrityCronJob,omitempty" yaml:"snapshot_data_integrity_cron_job,omitempty"`
+
 	SnapshotMaxCount int64 `json:"snapshotMaxCount,omitempty" yaml:"snapshot_max_count,omitempty"`
 
 	SnapshotMaxSize string `json:"snapshotMaxSize,omitempty" yaml:"snapshot_max_size,omitempty"`
 
+	SnapshotMaxSizeAction string `json:"snapshotMaxSizeAction,omitempty" yaml:"snapshot_max_size_action,omitempty"`
+
+	SnapshotReclaimThreshold string `json:"snapshotReclaimThreshold,omitempty" yaml:"snapshot_reclaim_threshold,omitempty"`
+
 	StaleReplicaTimeout int64 `json:"staleReplicaTimeout,omitempty" yaml:"stale_replica_timeout,omitempty"`
 
 	Standby bool `json:"standby,omitempty" yaml:"standby,omitempty"`
 
+	StandbyRestoreInterval int64 `json:"standbyRestoreInterval,omitempty" yaml:"standby_restore_interval,omitempty"`
+
 	State string `json:"state,omitempty" yaml:"state,omitempty"`
 
 	UblkNumberOfQueue int64 `json:"ublkNumberOfQueue,omitempty" yaml:"ublk_number_of_queue,omitempty"`
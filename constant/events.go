@@ -72,5 +72,19 @@ const (
 
 	EventReasonMigrationFailed = "MigrationFailed"
 
+	EventReasonDataEngineFallback = "DataEngineFallback"
+
 	EventReasonOrphanCleanupCompleted = "OrphanCleanupCompleted"
+
+	EventReasonQuarantined         = "Quarantined"
+	EventReasonForceDeleted        = "ForceDeleted"
+	EventReasonForceDeletionDryRun = "ForceDeletionDryRun"
+	EventReasonPodDeletionSkipped  = "PodDeletionSkipped"
+
+	EventReasonPodDisruptionBudgetExhausted = "PodDisruptionBudgetExhausted"
+
+	EventReasonForceDeletionUnverified = "ForceDeletionUnverified"
+	EventReasonReplacementPodPending   = "ReplacementPodPending"
+
+	EventReasonAttachmentConflict = "AttachmentConflict"
 )
@@ -27,6 +27,14 @@ func newBaseController(name string, logger logrus.FieldLogger) *baseController {
 		workqueue.NewTypedRateLimitingQueueWithConfig[any](EnhancedDefaultControllerRateLimiter(), nameConfig))
 }
 
+// newBaseControllerWithMetrics returns a base controller whose queue reports its metrics to the given provider,
+// instead of the global one that is only set once the metrics collector is loaded.
+func newBaseControllerWithMetrics(name string, logger logrus.FieldLogger, metricsProvider workqueue.MetricsProvider) *baseController {
+	config := workqueue.TypedRateLimitingQueueConfig[any]{Name: name, MetricsProvider: metricsProvider}
+	return newBaseControllerWithQueue(name, logger,
+		workqueue.NewTypedRateLimitingQueueWithConfig[any](EnhancedDefaultControllerRateLimiter(), config))
+}
+
 func newBaseControllerWithQueue(name string, logger logrus.FieldLogger,
 	queue workqueue.TypedRateLimitingInterface[any]) *baseController {
 	c := &baseController{
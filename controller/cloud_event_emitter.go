@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+
+	cloudeventmetrics "github.com/longhorn/longhorn-manager/metrics_collector/cloudevent"
+)
+
+const (
+	cloudEventSpecVersion = "1.0"
+
+	cloudEventTypePodForceDeleted         = "io.longhorn.pod.force-deleted"
+	cloudEventTypePodForceDeletionFailed  = "io.longhorn.pod.force-deletion-failed"
+	cloudEventTypePodForceDeletionSkipped = "io.longhorn.pod.force-deletion-skipped"
+
+	cloudEventContentTypeStructured = "application/cloudevents+json"
+	cloudEventContentTypeJSON       = "application/json"
+
+	cloudEventQueueSize     = 100
+	cloudEventMaxRetries    = 3
+	cloudEventRetryInterval = 2 * time.Second
+	cloudEventSendTimeout   = 10 * time.Second
+	// cloudEventDeliveryTimeout bounds the total time spent on delivering an event, retries included.
+	cloudEventDeliveryTimeout = 20 * time.Second
+)
+
+// cloudEvent is an event following the CloudEvents specification v1.0.
+type cloudEvent struct {
+	SpecVersion     string `json:"specversion"`
+	ID              string `json:"id"`
+	Source          string `json:"source"`
+	Type            string `json:"type"`
+	Subject         string `json:"subject,omitempty"`
+	Time            string `json:"time,omitempty"`
+	DataContentType string `json:"datacontenttype,omitempty"`
+	Data            any    `json:"data,omitempty"`
+}
+
+// podForceDeletionEventData is the data of the CloudEvents about the force deletion of a pod on a down node.
+type podForceDeletionEventData struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	Node      string `json:"node"`
+	Policy    string `json:"policy"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+func newCloudEvent(source, eventType, subject string, data any) *cloudEvent {
+	return &cloudEvent{
+		SpecVersion:     cloudEventSpecVersion,
+		ID:              util.UUID(),
+		Source:          source,
+		Type:            eventType,
+		Subject:         subject,
+		Time:            time.Now().UTC().Format(time.RFC3339Nano),
+		DataContentType: cloudEventContentTypeJSON,
+		Data:            data,
+	}
+}
+
+type cloudEventDelivery struct {
+	sinkURL string
+	format  types.CloudEventFormat
+	event   *cloudEvent
+}
+
+// cloudEventEmitter publishes CloudEvents to HTTP sinks in the background.
+// Events are delivered one at a time by a single goroutine, so a slow sink delays the following events.
+// To bound this delay, the delivery of an event including its retries is given up after deliveryTimeout.
+// Events are dropped when the queue is full or when they cannot be delivered in time,
+// and the dropped events are counted in the longhorn_cloudevent_dropped_total metric.
+type cloudEventEmitter struct {
+	logger logrus.FieldLogger
+	name   string
+
+	client          *http.Client
+	queue           chan *cloudEventDelivery
+	maxRetries      int
+	retryInterval   time.Duration
+	deliveryTimeout time.Duration
+
+	dropped atomic.Uint64
+}
+
+func newCloudEventEmitter(logger logrus.FieldLogger, name string, queueSize, maxRetries int, retryInterval time.Duration) *cloudEventEmitter {
+	return &cloudEventEmitter{
+		logger:          logger,
+		name:            name,
+		client:          &http.Client{Timeout: cloudEventSendTimeout},
+		queue:           make(chan *cloudEventDelivery, queueSize),
+		maxRetries:      maxRetries,
+		retryInterval:   retryInterval,
+		deliveryTimeout: cloudEventDeliveryTimeout,
+	}
+}
+
+// Emit queues the event for the delivery to the sink without blocking.
+func (e *cloudEventEmitter) Emit(sinkURL string, format types.CloudEventFormat, event *cloudEvent) {
+	select {
+	case e.queue <- &cloudEventDelivery{sinkURL: sinkURL, format: format, event: event}:
+	default:
+		e.drop(cloudeventmetrics.DroppedReasonQueueFull)
+		e.logger.Warnf("Dropped CloudEvent %v of type %v since the queue is full", event.ID, event.Type)
+	}
+}
+
+// Dropped returns the number of events that have been dropped.
+func (e *cloudEventEmitter) Dropped() uint64 {
+	return e.dropped.Load()
+}
+
+func (e *cloudEventEmitter) drop(reason string) {
+	e.dropped.Add(1)
+	cloudeventmetrics.IncDropped(e.name, reason)
+}
+
+func (e *cloudEventEmitter) Run(stopCh <-chan struct{}) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case delivery := <-e.queue:
+			if err := e.deliver(delivery, stopCh); err != nil {
+				e.logger.WithError(err).Warnf("Dropped CloudEvent %v of type %v", delivery.event.ID, delivery.event.Type)
+			}
+		}
+	}
+}
+
+func (e *cloudEventEmitter) deliver(delivery *cloudEventDelivery, stopCh <-chan struct{}) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.deliveryTimeout)
+	defer cancel()
+
+	for i := 0; i <= e.maxRetries; i++ {
+		if i > 0 {
+			select {
+			case <-stopCh:
+				e.drop(cloudeventmetrics.DroppedReasonDeliveryFailed)
+				return errors.Wrap(err, "stopped before the delivery succeeded")
+			case <-ctx.Done():
+				e.drop(cloudeventmetrics.DroppedReasonDeliveryFailed)
+				return errors.Wrapf(err, "failed to deliver the event within %v", e.deliveryTimeout)
+			case <-time.After(e.retryInterval):
+			}
+		}
+		if err = e.send(ctx, delivery); err == nil {
+			return nil
+		}
+	}
+	e.drop(cloudeventmetrics.DroppedReasonDeliveryFailed)
+	return errors.Wrapf(err, "failed to deliver the event after %v retries", e.maxRetries)
+}
+
+func (e *cloudEventEmitter) send(ctx context.Context, delivery *cloudEventDelivery) error {
+	req, err := newCloudEventRequest(ctx, delivery)
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			e.logger.WithError(errClose).Warn("Failed to close the response body of the CloudEvent sink")
+		}
+	}()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// newCloudEventRequest builds the HTTP request of the event in the structured or binary content mode.
+func newCloudEventRequest(ctx context.Context, delivery *cloudEventDelivery) (*http.Request, error) {
+	event := delivery.event
+
+	var body []byte
+	var err error
+	if delivery.format == types.CloudEventFormatBinary {
+		body, err = json.Marshal(event.Data)
+	} else {
+		body, err = json.Marshal(event)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal CloudEvent %v", event.ID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.sinkURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	if delivery.format != types.CloudEventFormatBinary {
+		req.Header.Set("Content-Type", cloudEventContentTypeStructured)
+		return req, nil
+	}
+
+	req.Header.Set("Content-Type", event.DataContentType)
+	req.Header.Set("ce-specversion", event.SpecVersion)
+	req.Header.Set("ce-id", event.ID)
+	req.Header.Set("ce-source", event.Source)
+	req.Header.Set("ce-type", event.Type)
+	if event.Subject != "" {
+		req.Header.Set("ce-subject", event.Subject)
+	}
+	if event.Time != "" {
+		req.Header.Set("ce-time", event.Time)
+	}
+	return req, nil
+}
+
+// emitPodForceDeletionCloudEvent publishes the force deletion decision of the pod to the CloudEvent sink, if configured.
+func (kc *KubernetesPodController) emitPodForceDeletionCloudEvent(pod *corev1.Pod, nodeID string, deletionPolicy types.NodeDownPodDeletionPolicy, eventType, reason string) {
+	sinkURL, err := kc.ds.GetSettingValueExisted(types.SettingNameNodeDownPodDeletionCloudEventSinkURL)
+	if err != nil || sinkURL == "" {
+		return
+	}
+	format, err := kc.ds.GetSettingValueExisted(types.SettingNameNodeDownPodDeletionCloudEventFormat)
+	if err != nil {
+		kc.logger.WithError(err).Warnf("%v: failed to get the CloudEvent format, use %v", controllerAgentName, types.CloudEventFormatStructured)
+		format = string(types.CloudEventFormatStructured)
+	}
+
+	data := &podForceDeletionEventData{
+		Pod:       pod.Name,
+		Namespace: pod.Namespace,
+		Node:      nodeID,
+		Policy:    string(deletionPolicy),
+		Reason:    reason,
+	}
+	source := fmt.Sprintf("/%v/%v", controllerAgentName, kc.controllerID)
+	subject := pod.Namespace + "/" + pod.Name
+	kc.cloudEventEmitter.Emit(sinkURL, types.CloudEventFormat(format), newCloudEvent(source, eventType, subject, data))
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+func newTestPodForceDeletionCloudEvent() *cloudEvent {
+	return newCloudEvent("/"+controllerAgentName+"/"+TestNode1, cloudEventTypePodForceDeleted, TestNamespace+"/test-pod", &podForceDeletionEventData{
+		Pod:       "test-pod",
+		Namespace: TestNamespace,
+		Node:      TestNode2,
+		Policy:    string(types.NodeDownPodDeletionPolicyDeleteBothStatefulsetAndDeploymentPod),
+	})
+}
+
+func TestCloudEventEmitterStructured(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- body
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	emitter := newCloudEventEmitter(logrus.StandardLogger(), controllerAgentName, 1, 0, 0)
+	event := newTestPodForceDeletionCloudEvent()
+	require.NoError(t, emitter.deliver(&cloudEventDelivery{sinkURL: server.URL, format: types.CloudEventFormatStructured, event: event}, nil))
+
+	r := <-requests
+	assert.Equal(t, http.MethodPost, r.Method)
+	assert.Equal(t, cloudEventContentTypeStructured, r.Header.Get("Content-Type"))
+	assert.Empty(t, r.Header.Get("ce-id"))
+
+	received := map[string]any{}
+	require.NoError(t, json.Unmarshal(<-bodies, &received))
+	assert.Equal(t, "1.0", received["specversion"])
+	assert.Equal(t, event.ID, received["id"])
+	assert.Equal(t, "/"+controllerAgentName+"/"+TestNode1, received["source"])
+	assert.Equal(t, cloudEventTypePodForceDeleted, received["type"])
+	assert.Equal(t, TestNamespace+"/test-pod", received["subject"])
+	assert.Equal(t, "application/json", received["datacontenttype"])
+	_, err := time.Parse(time.RFC3339Nano, received["time"].(string))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"pod":       "test-pod",
+		"namespace": TestNamespace,
+		"node":      TestNode2,
+		"policy":    string(types.NodeDownPodDeletionPolicyDeleteBothStatefulsetAndDeploymentPod),
+	}, received["data"])
+	assert.Zero(t, emitter.Dropped())
+}
+
+func TestCloudEventEmitterBinary(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- body
+	}))
+	defer server.Close()
+
+	emitter := newCloudEventEmitter(logrus.StandardLogger(), controllerAgentName, 1, 0, 0)
+	event := newTestPodForceDeletionCloudEvent()
+	require.NoError(t, emitter.deliver(&cloudEventDelivery{sinkURL: server.URL, format: types.CloudEventFormatBinary, event: event}, nil))
+
+	r := <-requests
+	assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+	assert.Equal(t, "1.0", r.Header.Get("ce-specversion"))
+	assert.Equal(t, event.ID, r.Header.Get("ce-id"))
+	assert.Equal(t, event.Source, r.Header.Get("ce-source"))
+	assert.Equal(t, cloudEventTypePodForceDeleted, r.Header.Get("ce-type"))
+	assert.Equal(t, TestNamespace+"/test-pod", r.Header.Get("ce-subject"))
+	assert.Equal(t, event.Time, r.Header.Get("ce-time"))
+
+	data := &podForceDeletionEventData{}
+	require.NoError(t, json.Unmarshal(<-bodies, data))
+	assert.Equal(t, event.Data, data)
+}
+
+func TestCloudEventEmitterRetryAndDrop(t *testing.T) {
+	tests := map[string]struct {
+		failures int32
+
+		expectAttempts int32
+		expectDropped  uint64
+	}{
+		"succeed at first attempt": {
+			expectAttempts: 1,
+		},
+		"succeed after retries": {
+			failures:       2,
+			expectAttempts: 3,
+		},
+		"drop after retries": {
+			failures:       10,
+			expectAttempts: 4,
+			expectDropped:  1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) <= tc.failures {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			emitter := newCloudEventEmitter(logrus.StandardLogger(), controllerAgentName, 1, 3, time.Millisecond)
+			err := emitter.deliver(&cloudEventDelivery{sinkURL: server.URL, format: types.CloudEventFormatStructured, event: newTestPodForceDeletionCloudEvent()}, nil)
+			assert.Equal(t, tc.expectDropped > 0, err != nil)
+			assert.Equal(t, tc.expectAttempts, attempts.Load())
+			assert.Equal(t, tc.expectDropped, emitter.Dropped())
+		})
+	}
+}
+
+func TestCloudEventEmitterDropWhenQueueFull(t *testing.T) {
+	emitter := newCloudEventEmitter(logrus.StandardLogger(), controllerAgentName, 1, 0, 0)
+
+	emitter.Emit("http://sink.invalid", types.CloudEventFormatStructured, newTestPodForceDeletionCloudEvent())
+	emitter.Emit("http://sink.invalid", types.CloudEventFormatStructured, newTestPodForceDeletionCloudEvent())
+
+	assert.Len(t, emitter.queue, 1)
+	assert.Equal(t, uint64(1), emitter.Dropped())
+}
+
+func TestCloudEventEmitterDeliveryTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	emitter := newCloudEventEmitter(logrus.StandardLogger(), controllerAgentName, 1, 100, time.Millisecond)
+	emitter.deliveryTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	err := emitter.deliver(&cloudEventDelivery{sinkURL: server.URL, format: types.CloudEventFormatStructured, event: newTestPodForceDeletionCloudEvent()}, nil)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Less(t, attempts.Load(), int32(100))
+	assert.Equal(t, uint64(1), emitter.Dropped())
+}
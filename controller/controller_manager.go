@@ -144,6 +144,10 @@ func StartControllers(logger logrus.FieldLogger, clients *client.Clients,
 	if err != nil {
 		return nil, err
 	}
+	volumeIdleController, err := NewVolumeIdleController(logger, ds, scheme, kubeClient, controllerID, namespace)
+	if err != nil {
+		return nil, err
+	}
 	volumeCloneController, err := NewVolumeCloneController(logger, ds, scheme, kubeClient, controllerID, namespace)
 	if err != nil {
 		return nil, err
@@ -158,6 +162,14 @@ func StartControllers(logger logrus.FieldLogger, clients *client.Clients,
 	if err != nil {
 		return nil, err
 	}
+	kubernetesPVCController, err := NewKubernetesPVCController(logger, ds, scheme, kubeClient, controllerID)
+	if err != nil {
+		return nil, err
+	}
+	kubernetesOrphanedVolumeController, err := NewKubernetesOrphanedVolumeController(logger, ds, scheme, kubeClient, controllerID, namespace)
+	if err != nil {
+		return nil, err
+	}
 	kubernetesNodeController, err := NewKubernetesNodeController(logger, ds, scheme, kubeClient, controllerID)
 	if err != nil {
 		return nil, err
@@ -210,11 +222,14 @@ func StartControllers(logger logrus.FieldLogger, clients *client.Clients,
 	go volumeRestoreController.Run(Workers, stopCh)
 	go volumeRebuildingController.Run(Workers, stopCh)
 	go volumeEvictionController.Run(Workers, stopCh)
+	go volumeIdleController.Run(Workers, stopCh)
 	go volumeCloneController.Run(Workers, stopCh)
 	go volumeExpansionController.Run(Workers, stopCh)
 
 	// Start goroutines for Kubernetes controllers
 	go kubernetesPVController.Run(Workers, stopCh)
+	go kubernetesPVCController.Run(Workers, stopCh)
+	go kubernetesOrphanedVolumeController.Run(Workers, stopCh)
 	go kubernetesNodeController.Run(Workers, stopCh)
 	go kubernetesPodController.Run(Workers, stopCh)
 	go kubernetesConfigMapController.Run(Workers, stopCh)
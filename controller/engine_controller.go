@@ -1017,6 +1017,8 @@ func (m *EngineMonitor) refresh(engine *longhorn.Engine) error {
 		engine.Status.PurgeStatus = purgeStatus
 	}
 
+	m.checkAndReclaimSnapshotSpace(engine, engineClientProxy)
+
 	removeInvalidEngineOpStatus(engine)
 
 	// Make sure the engine object is updated before engineapi calls.
@@ -1165,6 +1167,76 @@ func (m *EngineMonitor) refresh(engine *longhorn.Engine) error {
 	return nil
 }
 
+// checkAndReclaimSnapshotSpace starts a snapshot purge once the snapshot space usage of the volume reaches
+// the volume SnapshotReclaimThreshold, so that the space of the removed snapshots is reclaimed.
+func (m *EngineMonitor) checkAndReclaimSnapshotSpace(engine *longhorn.Engine, engineClientProxy engineapi.EngineClientProxy) {
+	v, err := m.ds.GetVolumeRO(engine.Spec.VolumeName)
+	if err != nil {
+		m.logger.WithError(err).Warn("Failed to get volume for snapshot space reclaim")
+		return
+	}
+	if v.Spec.SnapshotReclaimThreshold == "" || engine.Status.SnapshotsError != "" {
+		return
+	}
+
+	threshold, err := types.GetSnapshotReclaimThresholdSize(v.Spec.SnapshotReclaimThreshold, v.Spec.Size)
+	if err != nil {
+		m.logger.WithError(err).Warnf("Invalid snapshot reclaim threshold %v", v.Spec.SnapshotReclaimThreshold)
+		return
+	}
+
+	if !shouldReclaimSnapshotSpace(engine.Status.Snapshots, engine.Status.PurgeStatus, threshold) {
+		return
+	}
+
+	allowSnapshotPurge, err := m.snapshotConcurrentLimiter.CanStartSnapshotPurge(engineClientProxy, engine, m.ds)
+	if err != nil {
+		m.logger.WithError(err).Warn("Failed to check whether can start snapshot purge for snapshot space reclaim")
+		return
+	}
+	if !allowSnapshotPurge {
+		m.logger.Debug("Cannot start snapshot purge for snapshot space reclaim because the concurrent limit is reached")
+		return
+	}
+
+	m.logger.Infof("Starting snapshot purge since the snapshot space usage reached the reclaim threshold %v", v.Spec.SnapshotReclaimThreshold)
+	if err := engineClientProxy.SnapshotPurge(engine); err != nil {
+		m.logger.WithError(err).Warn("Failed to start snapshot purge for snapshot space reclaim")
+		m.eventRecorder.Eventf(engine, corev1.EventTypeWarning, constant.EventReasonFailedStartingSnapshotPurge,
+			"Failed to start snapshot purge for engine %v and volume %v to reclaim snapshot space: %v", engine.Name, engine.Spec.VolumeName, err)
+	}
+}
+
+// shouldReclaimSnapshotSpace returns true if the total size of the snapshots reaches the threshold,
+// there are removed snapshots whose space can be reclaimed, and no purge is in progress.
+func shouldReclaimSnapshotSpace(snapshots map[string]*longhorn.SnapshotInfo, purgeStatus map[string]*longhorn.PurgeStatus, threshold int64) bool {
+	if threshold <= 0 {
+		return false
+	}
+	for _, status := range purgeStatus {
+		if status.IsPurging {
+			return false
+		}
+	}
+
+	hasRemovedSnapshot := false
+	usage := int64(0)
+	for name, snapshot := range snapshots {
+		if name == etypes.VolumeHeadName || snapshot == nil {
+			continue
+		}
+		if snapshot.Removed {
+			hasRemovedSnapshot = true
+		}
+		size, err := strconv.ParseInt(snapshot.Size, 10, 64)
+		if err != nil {
+			continue
+		}
+		usage += size
+	}
+	return hasRemovedSnapshot && usage >= threshold
+}
+
 func (m *EngineMonitor) checkAndApplyRebuildQoS(engine *longhorn.Engine, engineClientProxy engineapi.EngineClientProxy, rebuildStatus map[string]*longhorn.RebuildStatus) error {
 	if !types.IsDataEngineV2(engine.Spec.DataEngine) {
 		return nil
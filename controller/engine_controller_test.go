@@ -132,3 +132,52 @@ func TestNeedStatusUpdate(t *testing.T) {
 		assert.Equal(tc.expectRateLimited, rateLimited, "rateLimited")
 	}
 }
+
+func TestShouldReclaimSnapshotSpace(t *testing.T) {
+	assert := require.New(t)
+
+	snapshots := map[string]*longhorn.SnapshotInfo{
+		"snap-1":              {Size: "600", Removed: true},
+		"snap-2":              {Size: "400"},
+		etypes.VolumeHeadName: {Size: "5000"},
+	}
+
+	tests := map[string]struct {
+		snapshots   map[string]*longhorn.SnapshotInfo
+		purgeStatus map[string]*longhorn.PurgeStatus
+		threshold   int64
+
+		expectReclaim bool
+	}{
+		"no threshold": {
+			snapshots: snapshots,
+		},
+		"usage below threshold": {
+			snapshots: snapshots,
+			threshold: 1001,
+		},
+		"usage reaches threshold": {
+			snapshots:     snapshots,
+			threshold:     1000,
+			expectReclaim: true,
+		},
+		"no removed snapshot": {
+			snapshots: map[string]*longhorn.SnapshotInfo{
+				"snap-1": {Size: "600"},
+				"snap-2": {Size: "400"},
+			},
+			threshold: 500,
+		},
+		"purge in progress": {
+			snapshots: snapshots,
+			purgeStatus: map[string]*longhorn.PurgeStatus{
+				"tcp://10.0.0.1:10000": {IsPurging: true},
+			},
+			threshold: 500,
+		},
+	}
+
+	for name, tc := range tests {
+		assert.Equal(tc.expectReclaim, shouldReclaimSnapshotSpace(tc.snapshots, tc.purgeStatus, tc.threshold), name)
+	}
+}
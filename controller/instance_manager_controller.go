@@ -7,6 +7,7 @@ import (
 	"io"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -201,6 +202,13 @@ func NewInstanceManagerController(
 	}
 	imc.cacheSyncs = append(imc.cacheSyncs, ds.SettingInformer.HasSynced)
 
+	if _, err = ds.VolumeInformer.AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: imc.enqueueVolumeDataEngineLogLevelChange,
+	}, 0); err != nil {
+		return nil, err
+	}
+	imc.cacheSyncs = append(imc.cacheSyncs, ds.VolumeInformer.HasSynced)
+
 	return imc, nil
 }
 
@@ -602,6 +610,11 @@ func (imc *InstanceManagerController) syncLogSettingsToInstanceManagerPod(im *lo
 				if err != nil {
 					return err
 				}
+				volumeLogLevels, err := imc.getVolumeDataEngineLogLevels(im)
+				if err != nil {
+					return err
+				}
+				value = getMostVerboseDataEngineLogLevel(value, volumeLogLevels)
 				if err := client.LogSetLevel(longhorn.DataEngineTypeV2, "", value); err != nil {
 					return errors.Wrapf(err, "failed to set data engine log level to setting %v value: %v", settingName, value)
 				}
@@ -622,6 +635,44 @@ func (imc *InstanceManagerController) syncLogSettingsToInstanceManagerPod(im *lo
 	return nil
 }
 
+// getVolumeDataEngineLogLevels returns the data engine log levels requested by the volumes whose engine runs on the instance manager.
+func (imc *InstanceManagerController) getVolumeDataEngineLogLevels(im *longhorn.InstanceManager) ([]string, error) {
+	engines, err := imc.ds.ListEnginesByNodeRO(im.Spec.NodeID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list engines on node %v", im.Spec.NodeID)
+	}
+
+	levels := []string{}
+	for _, e := range engines {
+		if e.Status.InstanceManagerName != im.Name {
+			continue
+		}
+		v, err := imc.ds.GetVolumeRO(e.Spec.VolumeName)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get volume %v", e.Spec.VolumeName)
+		}
+		if v.Spec.DataEngineLogLevel != "" {
+			levels = append(levels, v.Spec.DataEngineLogLevel)
+		}
+	}
+	return levels, nil
+}
+
+// getMostVerboseDataEngineLogLevel returns the most verbose of the given levels. The data engine log level
+// applies to the whole SPDK target daemon, so a volume can only raise it for the engines it shares the daemon with.
+func getMostVerboseDataEngineLogLevel(settingLevel string, volumeLevels []string) string {
+	level := settingLevel
+	for _, volumeLevel := range volumeLevels {
+		if slices.Index(types.DataEngineLogLevels, volumeLevel) > slices.Index(types.DataEngineLogLevels, level) {
+			level = volumeLevel
+		}
+	}
+	return level
+}
+
 func (imc *InstanceManagerController) handlePod(im *longhorn.InstanceManager) error {
 	log := getLoggerForInstanceManager(imc.logger, im)
 
@@ -1405,6 +1456,28 @@ func (imc *InstanceManagerController) enqueueSettingChange(obj interface{}) {
 	imc.enqueueInstanceManagersForNode(imc.controllerID)
 }
 
+// enqueueVolumeDataEngineLogLevelChange resyncs the log level of the instance managers on this node
+// when a volume requesting its own data engine log level changes its level or the node it is attached to.
+func (imc *InstanceManagerController) enqueueVolumeDataEngineLogLevelChange(old, cur interface{}) {
+	oldVolume, ok := old.(*longhorn.Volume)
+	if !ok {
+		return
+	}
+	curVolume, ok := cur.(*longhorn.Volume)
+	if !ok {
+		return
+	}
+
+	if oldVolume.Spec.DataEngineLogLevel == curVolume.Spec.DataEngineLogLevel &&
+		(curVolume.Spec.DataEngineLogLevel == "" || oldVolume.Status.CurrentNodeID == curVolume.Status.CurrentNodeID) {
+		return
+	}
+
+	if oldVolume.Status.CurrentNodeID == imc.controllerID || curVolume.Status.CurrentNodeID == imc.controllerID {
+		imc.enqueueInstanceManagersForNode(imc.controllerID)
+	}
+}
+
 func (imc *InstanceManagerController) cleanupInstanceManagerPod(imName string) error {
 	imc.stopMonitoring(imName)
 	imc.stopBackingImageMonitoring(imName)
@@ -1449,6 +1522,12 @@ func (imc *InstanceManagerController) createInstanceManagerPod(im *longhorn.Inst
 		return err
 	}
 
+	volumePullSecrets, err := imc.getVolumeImagePullSecrets(im)
+	if err != nil {
+		return errors.Wrap(err, "failed to get image pull secrets of volumes before creating instance manager pod")
+	}
+	podSpec.Spec.ImagePullSecrets = appendImagePullSecrets(podSpec.Spec.ImagePullSecrets, volumePullSecrets)
+
 	log.Info("Creating instance manager pod")
 	if _, err := imc.ds.CreatePod(podSpec); err != nil {
 		if apierrors.IsAlreadyExists(err) {
@@ -1460,6 +1539,60 @@ func (imc *InstanceManagerController) createInstanceManagerPod(im *longhorn.Inst
 	return nil
 }
 
+// getVolumeImagePullSecrets returns the image pull secrets of the volumes with an engine or a replica
+// of the data engine of the instance manager on its node.
+func (imc *InstanceManagerController) getVolumeImagePullSecrets(im *longhorn.InstanceManager) ([]string, error) {
+	volumeNames := map[string]struct{}{}
+
+	replicas, err := imc.ds.ListReplicasByNodeRO(im.Spec.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range replicas {
+		if r.Spec.DataEngine == im.Spec.DataEngine {
+			volumeNames[r.Spec.VolumeName] = struct{}{}
+		}
+	}
+
+	engines, err := imc.ds.ListEnginesByNodeRO(im.Spec.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range engines {
+		if e.Spec.DataEngine == im.Spec.DataEngine {
+			volumeNames[e.Spec.VolumeName] = struct{}{}
+		}
+	}
+
+	secrets := []string{}
+	for volumeName := range volumeNames {
+		v, err := imc.ds.GetVolumeRO(volumeName)
+		if err != nil {
+			if datastore.ErrorIsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if v.Spec.EngineImagePullSecret != "" {
+			secrets = append(secrets, v.Spec.EngineImagePullSecret)
+		}
+	}
+	return secrets, nil
+}
+
+// appendImagePullSecrets appends the secrets that are not referenced yet, in a stable order.
+func appendImagePullSecrets(references []corev1.LocalObjectReference, secrets []string) []corev1.LocalObjectReference {
+	secrets = slices.Clone(secrets)
+	slices.Sort(secrets)
+	for _, secret := range secrets {
+		if slices.ContainsFunc(references, func(reference corev1.LocalObjectReference) bool { return reference.Name == secret }) {
+			continue
+		}
+		references = append(references, corev1.LocalObjectReference{Name: secret})
+	}
+	return references
+}
+
 func (imc *InstanceManagerController) createGenericManagerPodSpec(im *longhorn.InstanceManager, tolerations []corev1.Toleration, registrySecret string, nodeSelector map[string]string, dataEngine longhorn.DataEngineType) (*corev1.Pod, error) {
 	tolerationsByte, err := json.Marshal(tolerations)
 	if err != nil {
@@ -311,3 +311,21 @@ func (s *TestSuite) TestSyncInstanceManager(c *C) {
 		c.Assert(updatedIM.Status, DeepEquals, tc.expectedStatus)
 	}
 }
+
+func (s *TestSuite) TestGetMostVerboseDataEngineLogLevel(c *C) {
+	c.Assert(getMostVerboseDataEngineLogLevel("Notice", nil), Equals, "Notice")
+	c.Assert(getMostVerboseDataEngineLogLevel("Notice", []string{"Error", "Warning"}), Equals, "Notice")
+	c.Assert(getMostVerboseDataEngineLogLevel("Notice", []string{"Info", "Debug", "Error"}), Equals, "Debug")
+	c.Assert(getMostVerboseDataEngineLogLevel("Debug", []string{"Info"}), Equals, "Debug")
+}
+
+func (s *TestSuite) TestAppendImagePullSecrets(c *C) {
+	c.Assert(appendImagePullSecrets(nil, nil), HasLen, 0)
+
+	registrySecret := []corev1.LocalObjectReference{{Name: "registry-secret"}}
+	c.Assert(appendImagePullSecrets(registrySecret, []string{"volume-secret-b", "registry-secret", "volume-secret-a", "volume-secret-b"}), DeepEquals, []corev1.LocalObjectReference{
+		{Name: "registry-secret"},
+		{Name: "volume-secret-a"},
+		{Name: "volume-secret-b"},
+	})
+}
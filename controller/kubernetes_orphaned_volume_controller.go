@@ -0,0 +1,360 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+// KubernetesOrphanedVolumeController recreates the missing PV of the Longhorn volumes still used by a PVC, and the
+// PVC as well if it is missing too, when the recreate missing PV for orphaned volume setting is enabled.
+type KubernetesOrphanedVolumeController struct {
+	*baseController
+
+	// which namespace controller is running with
+	namespace string
+	// use as the OwnerID of the controller
+	controllerID string
+
+	kubeClient    clientset.Interface
+	eventRecorder record.EventRecorder
+
+	ds *datastore.DataStore
+
+	cacheSyncs []cache.InformerSynced
+}
+
+func NewKubernetesOrphanedVolumeController(
+	logger logrus.FieldLogger,
+	ds *datastore.DataStore,
+	scheme *runtime.Scheme,
+	kubeClient clientset.Interface,
+	controllerID string,
+	namespace string,
+) (*KubernetesOrphanedVolumeController, error) {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logrus.Infof)
+	// TODO: remove the wrapper when every clients have moved to use the clientset.
+	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: v1core.New(kubeClient.CoreV1().RESTClient()).Events("")})
+
+	kc := &KubernetesOrphanedVolumeController{
+		baseController: newBaseController("longhorn-kubernetes-orphaned-volume", logger),
+
+		namespace:    namespace,
+		controllerID: controllerID,
+
+		ds: ds,
+
+		kubeClient:    kubeClient,
+		eventRecorder: eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: "longhorn-kubernetes-orphaned-volume-controller"}),
+	}
+
+	var err error
+	if _, err = ds.VolumeInformer.AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
+		AddFunc:    kc.enqueueVolume,
+		UpdateFunc: func(old, cur interface{}) { kc.enqueueVolume(cur) },
+	}, 0); err != nil {
+		return nil, err
+	}
+	kc.cacheSyncs = append(kc.cacheSyncs, ds.VolumeInformer.HasSynced)
+
+	if _, err = ds.PersistentVolumeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: kc.enqueuePersistentVolumeDeletion,
+	}); err != nil {
+		return nil, err
+	}
+	kc.cacheSyncs = append(kc.cacheSyncs, ds.PersistentVolumeInformer.HasSynced, ds.PersistentVolumeClaimInformer.HasSynced)
+
+	if _, err = ds.SettingInformer.AddEventHandlerWithResyncPeriod(cache.FilteringResourceEventHandler{
+		FilterFunc: isSettingRecreateMissingPVForOrphanedVolume,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    kc.enqueueSetting,
+			UpdateFunc: func(old, cur interface{}) { kc.enqueueSetting(cur) },
+		},
+	}, 0); err != nil {
+		return nil, err
+	}
+	kc.cacheSyncs = append(kc.cacheSyncs, ds.SettingInformer.HasSynced)
+
+	return kc, nil
+}
+
+func (kc *KubernetesOrphanedVolumeController) enqueueVolume(obj interface{}) {
+	key, err := controller.KeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to get key for object %#v: %v", obj, err))
+		return
+	}
+
+	kc.queue.Add(key)
+}
+
+// enqueuePersistentVolumeDeletion enqueues the volume of a deleted Longhorn PV, so that the PV is recreated if the
+// volume is still used by a PVC.
+func (kc *KubernetesOrphanedVolumeController) enqueuePersistentVolumeDeletion(obj interface{}) {
+	pv, ok := obj.(*corev1.PersistentVolume)
+	if !ok {
+		deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("received unexpected obj: %#v", obj))
+			return
+		}
+
+		// use the last known state, to enqueue, dependent objects
+		pv, ok = deletedState.Obj.(*corev1.PersistentVolume)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("DeletedFinalStateUnknown contained invalid object: %#v", deletedState.Obj))
+			return
+		}
+	}
+
+	if !datastore.IsLonghornPersistentVolume(pv) || pv.Spec.CSI.VolumeHandle == "" {
+		return
+	}
+
+	kc.queue.Add(kc.namespace + "/" + pv.Spec.CSI.VolumeHandle)
+}
+
+func isSettingRecreateMissingPVForOrphanedVolume(obj interface{}) bool {
+	setting, ok := obj.(*longhorn.Setting)
+	if !ok {
+		return false
+	}
+	return setting.Name == string(types.SettingNameRecreateMissingPVForOrphanedVolume)
+}
+
+func (kc *KubernetesOrphanedVolumeController) enqueueSetting(obj interface{}) {
+	_, ok := obj.(*longhorn.Setting)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("received unexpected obj: %#v", obj))
+		return
+	}
+
+	vs, err := kc.ds.ListVolumesRO()
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list volumes: %v", err))
+		return
+	}
+
+	for _, v := range vs {
+		kc.enqueueVolume(v)
+	}
+}
+
+func (kc *KubernetesOrphanedVolumeController) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer kc.queue.ShutDown()
+
+	kc.logger.Info("Starting Kubernetes orphaned volume controller")
+	defer kc.logger.Info("Shut down Kubernetes orphaned volume controller")
+
+	if !cache.WaitForNamedCacheSync(kc.name, stopCh, kc.cacheSyncs...) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(kc.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (kc *KubernetesOrphanedVolumeController) worker() {
+	for kc.processNextWorkItem() {
+	}
+}
+
+func (kc *KubernetesOrphanedVolumeController) processNextWorkItem() bool {
+	key, quit := kc.queue.Get()
+	if quit {
+		return false
+	}
+	defer kc.queue.Done(key)
+	err := kc.syncHandler(key.(string))
+	kc.handleErr(err, key)
+	return true
+}
+
+func (kc *KubernetesOrphanedVolumeController) handleErr(err error, key interface{}) {
+	if err == nil {
+		kc.queue.Forget(key)
+		return
+	}
+
+	log := kc.logger.WithField("Volume", key)
+	if kc.queue.NumRequeues(key) < maxRetries {
+		handleReconcileErrorLogging(log, err, "Failed to sync Longhorn volume")
+		kc.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	handleReconcileErrorLogging(log, err, "Dropping Longhorn volume out of the queue")
+	kc.queue.Forget(key)
+}
+
+func (kc *KubernetesOrphanedVolumeController) syncHandler(key string) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "%v: failed to sync volume %v", kc.name, key)
+	}()
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	if namespace != kc.namespace {
+		return nil
+	}
+	return kc.reconcile(name)
+}
+
+func (kc *KubernetesOrphanedVolumeController) reconcile(volName string) error {
+	volume, err := kc.ds.GetVolumeRO(volName)
+	if err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// Only the owner of the volume recreates the PV to avoid racing with the other managers.
+	if volume.Status.OwnerID != kc.controllerID || !volume.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	enabled, err := kc.ds.GetSettingAsBool(types.SettingNameRecreateMissingPVForOrphanedVolume)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+
+	pvName, pvc, err := kc.getMissingPersistentVolume(volume)
+	if err != nil {
+		return err
+	}
+	if pvName == "" {
+		return nil
+	}
+
+	ks := volume.Status.KubernetesStatus
+	log := getLoggerForVolume(kc.logger, volume).WithFields(logrus.Fields{
+		"namespace": ks.Namespace,
+		"pvc":       ks.PVCName,
+		"pv":        pvName,
+	})
+
+	storageClassName := ""
+	if pvc != nil {
+		if pvc.Spec.StorageClassName != nil {
+			storageClassName = *pvc.Spec.StorageClassName
+		}
+	} else {
+		storageClassName, err = kc.ds.GetSettingValueExisted(types.SettingNameDefaultLonghornStaticStorageClass)
+		if err != nil {
+			return errors.Wrapf(err, "failed to get the static storage class to recreate PV %v", pvName)
+		}
+	}
+
+	// The secret of the original PV of an encrypted volume is unknown, so it is looked up in the storage class.
+	encryptionSecretRef, err := kc.ds.GetEncryptionSecretRefForStorageClass(storageClassName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the encryption secret of storage class %v to recreate PV %v", storageClassName, pvName)
+	}
+	if volume.Spec.Encrypted && encryptionSecretRef == nil {
+		log.Warnf("Skipping PV recreation for the encrypted volume since storage class %v does not set the secret of the encryption key", storageClassName)
+		return nil
+	}
+
+	pv := datastore.NewPVManifestForVolume(volume, pvName, storageClassName, getFSTypeForStorageClass(kc.ds, storageClassName), nil, "", nil, encryptionSecretRef)
+	if pvc != nil {
+		pv.Spec.ClaimRef = &corev1.ObjectReference{
+			Kind:       types.KubernetesKindPersistentVolumeClaim,
+			APIVersion: "v1",
+			Namespace:  pvc.Namespace,
+			Name:       pvc.Name,
+			UID:        pvc.UID,
+		}
+	}
+	if _, err := kc.ds.CreatePersistentVolume(pv); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to recreate PV %v", pvName)
+	}
+
+	if pvc != nil {
+		log.Info("Recreated missing PV for the volume used by the PVC")
+		kc.eventRecorder.Eventf(volume, corev1.EventTypeNormal, constant.EventReasonCreated,
+			"Recreated missing PersistentVolume %v for PersistentVolumeClaim %v/%v", pvName, ks.Namespace, ks.PVCName)
+		return nil
+	}
+
+	pvc = datastore.NewPVCManifestForVolume(volume, pvName, ks.Namespace, ks.PVCName, storageClassName)
+	if _, err := kc.ds.CreatePersistentVolumeClaim(ks.Namespace, pvc); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "failed to recreate PVC %v/%v", ks.Namespace, ks.PVCName)
+	}
+
+	log.Info("Recreated missing PV and PVC for the volume")
+	kc.eventRecorder.Eventf(volume, corev1.EventTypeNormal, constant.EventReasonCreated,
+		"Recreated missing PersistentVolume %v and PersistentVolumeClaim %v/%v", pvName, ks.Namespace, ks.PVCName)
+	return nil
+}
+
+// getMissingPersistentVolume returns the name of the missing PV of the volume still used by a PVC by its Kubernetes
+// status, with the PVC if it exists, or an empty name if the volume is not missing its PV. The PV keeps the name
+// recorded by the volume or claimed by the PVC, or the name of the volume if neither is known.
+func (kc *KubernetesOrphanedVolumeController) getMissingPersistentVolume(volume *longhorn.Volume) (string, *corev1.PersistentVolumeClaim, error) {
+	ks := volume.Status.KubernetesStatus
+	if ks.PVCName == "" || ks.Namespace == "" {
+		return "", nil, nil
+	}
+	pvName := ks.PVName
+
+	pvc, err := kc.ds.GetPersistentVolumeClaimRO(ks.Namespace, ks.PVCName)
+	if err != nil {
+		if !datastore.ErrorIsNotFound(err) {
+			return "", nil, err
+		}
+		pvc = nil
+	}
+	if pvc != nil {
+		// A PVC being deleted or waiting for a PV is not missing one, and a PVC claiming another PV is not the one
+		// the volume was used by.
+		if !pvc.DeletionTimestamp.IsZero() || pvc.Spec.VolumeName == "" {
+			return "", nil, nil
+		}
+		if pvName != "" && pvc.Spec.VolumeName != pvName {
+			return "", nil, nil
+		}
+		pvName = pvc.Spec.VolumeName
+	}
+	if pvName == "" {
+		pvName = volume.Name
+	}
+
+	if _, err := kc.ds.GetPersistentVolumeRO(pvName); err == nil {
+		return "", nil, nil
+	} else if !datastore.ErrorIsNotFound(err) {
+		return "", nil, err
+	}
+	return pvName, pvc, nil
+}
@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	lhfake "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned/fake"
+)
+
+type orphanedVolumeControllerFixture struct {
+	kc         *KubernetesOrphanedVolumeController
+	kubeClient *fake.Clientset
+}
+
+func newTestKubernetesOrphanedVolumeController(t *testing.T, settingValue string, volume *longhorn.Volume, pv *corev1.PersistentVolume, pvc *corev1.PersistentVolumeClaim) *orphanedVolumeControllerFixture {
+	kubeClient := fake.NewSimpleClientset() // nolint: staticcheck
+	lhClient := lhfake.NewSimpleClientset() // nolint: staticcheck
+	extensionsClient := apiextensionsfake.NewSimpleClientset()
+	informerFactories := util.NewInformerFactories(TestNamespace, kubeClient, lhClient, controller.NoResyncPeriodFunc())
+
+	ds := datastore.NewDataStore(TestNamespace, lhClient, kubeClient, extensionsClient, informerFactories)
+	kc, err := NewKubernetesOrphanedVolumeController(logrus.StandardLogger(), ds, scheme.Scheme, kubeClient, TestNode1, TestNamespace)
+	require.NoError(t, err)
+	kc.eventRecorder = record.NewFakeRecorder(100)
+
+	lhInformers := informerFactories.LhInformerFactory.Longhorn().V1beta2()
+	kubeInformers := informerFactories.KubeInformerFactory.Core().V1()
+	for name, value := range map[types.SettingName]string{
+		types.SettingNameRecreateMissingPVForOrphanedVolume: settingValue,
+		types.SettingNameDefaultLonghornStaticStorageClass:  TestStorageClassName,
+	} {
+		setting, err := lhClient.LonghornV1beta2().Settings(TestNamespace).Create(context.TODO(), newSetting(string(name), value), metav1.CreateOptions{})
+		require.NoError(t, err)
+		require.NoError(t, lhInformers.Settings().Informer().GetIndexer().Add(setting))
+	}
+
+	v, err := lhClient.LonghornV1beta2().Volumes(TestNamespace).Create(context.TODO(), volume, metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, lhInformers.Volumes().Informer().GetIndexer().Add(v))
+
+	if pv != nil {
+		pv, err = kubeClient.CoreV1().PersistentVolumes().Create(context.TODO(), pv, metav1.CreateOptions{})
+		require.NoError(t, err)
+		require.NoError(t, kubeInformers.PersistentVolumes().Informer().GetIndexer().Add(pv))
+	}
+	if pvc != nil {
+		pvc, err = kubeClient.CoreV1().PersistentVolumeClaims(TestNamespace).Create(context.TODO(), pvc, metav1.CreateOptions{})
+		require.NoError(t, err)
+		require.NoError(t, kubeInformers.PersistentVolumeClaims().Informer().GetIndexer().Add(pvc))
+	}
+
+	return &orphanedVolumeControllerFixture{kc: kc, kubeClient: kubeClient}
+}
+
+func TestGetMissingPersistentVolume(t *testing.T) {
+	newVolumeUsedBy := func(pvName, pvcName string) *longhorn.Volume {
+		v := newVolume(TestVolumeName, 2)
+		v.Namespace = TestNamespace
+		v.Status.KubernetesStatus = longhorn.KubernetesStatus{
+			PVName:    pvName,
+			Namespace: TestNamespace,
+			PVCName:   pvcName,
+		}
+		return v
+	}
+	newBoundPVC := func(pvName string) *corev1.PersistentVolumeClaim {
+		pvc := newPVC()
+		pvc.Namespace = TestNamespace
+		pvc.Spec.VolumeName = pvName
+		return pvc
+	}
+
+	tests := map[string]struct {
+		volume *longhorn.Volume
+		pv     *corev1.PersistentVolume
+		pvc    *corev1.PersistentVolumeClaim
+
+		expectedPVName string
+		expectPVC      bool
+	}{
+		"pv present": {
+			volume: newVolumeUsedBy(TestPVName, TestPVCName),
+			pv:     newPV(),
+			pvc:    newBoundPVC(TestPVName),
+		},
+		"volume not used by a pvc": {
+			volume: newVolumeUsedBy("", ""),
+		},
+		"pv missing for the pvc": {
+			volume:         newVolumeUsedBy(TestPVName, TestPVCName),
+			pvc:            newBoundPVC(TestPVName),
+			expectedPVName: TestPVName,
+			expectPVC:      true,
+		},
+		"pv missing and cleared from the volume status": {
+			volume:         newVolumeUsedBy("", TestPVCName),
+			pvc:            newBoundPVC(TestPVName),
+			expectedPVName: TestPVName,
+			expectPVC:      true,
+		},
+		"pv and pvc missing": {
+			volume:         newVolumeUsedBy(TestPVName, TestPVCName),
+			expectedPVName: TestPVName,
+		},
+		"pv and pvc missing and cleared from the volume status": {
+			volume:         newVolumeUsedBy("", TestPVCName),
+			expectedPVName: TestVolumeName,
+		},
+		"pvc claiming another pv": {
+			volume: newVolumeUsedBy(TestPVName, TestPVCName),
+			pvc:    newBoundPVC("another-pv"),
+		},
+		"pvc waiting for a pv": {
+			volume: newVolumeUsedBy("", TestPVCName),
+			pvc:    newBoundPVC(""),
+		},
+		"pvc being deleted": {
+			volume: newVolumeUsedBy(TestPVName, TestPVCName),
+			pvc: func() *corev1.PersistentVolumeClaim {
+				pvc := newBoundPVC(TestPVName)
+				now := metav1.Now()
+				pvc.DeletionTimestamp = &now
+				pvc.Finalizers = []string{"kubernetes.io/pvc-protection"}
+				return pvc
+			}(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newTestKubernetesOrphanedVolumeController(t, "true", tc.volume, tc.pv, tc.pvc)
+
+			pvName, pvc, err := f.kc.getMissingPersistentVolume(tc.volume)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPVName, pvName)
+			assert.Equal(t, tc.expectPVC, pvc != nil)
+		})
+	}
+}
+
+func TestKubernetesOrphanedVolumeControllerReconcile(t *testing.T) {
+	newVolumeUsedBy := func(ownerID string) *longhorn.Volume {
+		v := newVolume(TestVolumeName, 2)
+		v.Namespace = TestNamespace
+		v.Status.OwnerID = ownerID
+		v.Status.KubernetesStatus = longhorn.KubernetesStatus{
+			PVName:    TestPVName,
+			Namespace: TestNamespace,
+			PVCName:   TestPVCName,
+		}
+		return v
+	}
+	newBoundPVC := func() *corev1.PersistentVolumeClaim {
+		pvc := newPVC()
+		pvc.Namespace = TestNamespace
+		pvc.UID = "test-pvc-uid"
+		return pvc
+	}
+
+	tests := map[string]struct {
+		settingValue string
+		volume       *longhorn.Volume
+		pvc          *corev1.PersistentVolumeClaim
+		// storageClassParameters are the parameters of the static storage class
+		storageClassParameters map[string]string
+
+		expectPVCreated           bool
+		expectPVCCreated          bool
+		expectEncryptionSecretRef *corev1.SecretReference
+	}{
+		"recreate pv for the pvc": {
+			settingValue:    "true",
+			volume:          newVolumeUsedBy(TestNode1),
+			pvc:             newBoundPVC(),
+			expectPVCreated: true,
+		},
+		"recreate pv and pvc": {
+			settingValue:     "true",
+			volume:           newVolumeUsedBy(TestNode1),
+			expectPVCreated:  true,
+			expectPVCCreated: true,
+		},
+		"setting disabled": {
+			settingValue: "false",
+			volume:       newVolumeUsedBy(TestNode1),
+		},
+		"volume owned by another node": {
+			settingValue: "true",
+			volume:       newVolumeUsedBy(TestNode2),
+		},
+		"encrypted volume": {
+			settingValue: "true",
+			volume: func() *longhorn.Volume {
+				v := newVolumeUsedBy(TestNode1)
+				v.Spec.Encrypted = true
+				return v
+			}(),
+		},
+		"encrypted volume with the secret of the storage class": {
+			settingValue: "true",
+			volume: func() *longhorn.Volume {
+				v := newVolumeUsedBy(TestNode1)
+				v.Spec.Encrypted = true
+				return v
+			}(),
+			storageClassParameters: map[string]string{
+				"csi.storage.k8s.io/node-stage-secret-name":      "test-crypto",
+				"csi.storage.k8s.io/node-stage-secret-namespace": TestNamespace,
+			},
+			expectPVCreated:           true,
+			expectPVCCreated:          true,
+			expectEncryptionSecretRef: &corev1.SecretReference{Name: "test-crypto", Namespace: TestNamespace},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newTestKubernetesOrphanedVolumeController(t, tc.settingValue, tc.volume, nil, tc.pvc)
+			require.NoError(t, f.kc.ds.StorageClassInformer.GetStore().Add(&storagev1.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: TestStorageClassName},
+				Provisioner: types.LonghornDriverName,
+				Parameters:  tc.storageClassParameters,
+			}))
+
+			require.NoError(t, f.kc.reconcile(TestVolumeName))
+
+			pv, err := f.kubeClient.CoreV1().PersistentVolumes().Get(context.TODO(), TestPVName, metav1.GetOptions{})
+			if !tc.expectPVCreated {
+				assert.True(t, datastore.ErrorIsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, types.LonghornDriverName, pv.Spec.CSI.Driver)
+			assert.Equal(t, TestVolumeName, pv.Spec.CSI.VolumeHandle)
+			assert.Equal(t, TestStorageClassName, pv.Spec.StorageClassName)
+			assert.Equal(t, tc.expectEncryptionSecretRef, pv.Spec.CSI.NodeStageSecretRef)
+			assert.Equal(t, tc.expectEncryptionSecretRef, pv.Spec.CSI.NodePublishSecretRef)
+
+			pvc, err := f.kubeClient.CoreV1().PersistentVolumeClaims(TestNamespace).Get(context.TODO(), TestPVCName, metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, TestPVName, pvc.Spec.VolumeName)
+			if tc.expectPVCCreated {
+				assert.Nil(t, pv.Spec.ClaimRef)
+				return
+			}
+			require.NotNil(t, pv.Spec.ClaimRef)
+			assert.Equal(t, TestPVCName, pv.Spec.ClaimRef.Name)
+			assert.Equal(t, tc.pvc.UID, pv.Spec.ClaimRef.UID)
+		})
+	}
+}
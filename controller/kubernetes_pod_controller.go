@@ -3,17 +3,21 @@ package controller
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/sirupsen/logrus"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/kubernetes/pkg/controller"
+	"k8s.io/utils/ptr"
 
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -29,12 +33,81 @@ import (
 	"github.com/longhorn/longhorn-manager/util"
 
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	poddeletionmetrics "github.com/longhorn/longhorn-manager/metrics_collector/poddeletion"
+	workqueuemetrics "github.com/longhorn/longhorn-manager/metrics_collector/workqueue"
 )
 
 const (
 	controllerAgentName = "longhorn-kubernetes-pod-controller"
 
 	remountRequestDelayDuration = 5 * time.Second
+
+	// forceDeletionQuarantineFailureThreshold is the number of consecutive force deletion failures
+	// on a node before the node is quarantined from force deletion.
+	forceDeletionQuarantineFailureThreshold = 5
+	// forceDeletionQuarantineCooldown is how long a node stays quarantined before force deletion is retried.
+	forceDeletionQuarantineCooldown = 10 * time.Minute
+
+	// podDeletionApprovalRetryInterval is how long to wait before asking the approval webhook again
+	// after the force deletion of a pod is denied.
+	podDeletionApprovalRetryInterval = time.Minute
+
+	// podDeletionFencingRetryInterval is how long to wait before asking the fencing endpoint again
+	// after it fails to fence a down node.
+	podDeletionFencingRetryInterval = time.Minute
+
+	// podDeletionRebuildRetryInterval is how often the rebuild of the volumes of a pod on a down node is checked
+	// while its force deletion waits for the rebuild to start.
+	podDeletionRebuildRetryInterval = 30 * time.Second
+	// podDeletionRebuildWaitTimeout bounds the wait for the rebuild since the pod deletion, because a volume whose
+	// engine was on the down node cannot rebuild until the pod is deleted and the volume is attached again.
+	podDeletionRebuildWaitTimeout = 10 * time.Minute
+
+	// podDeletionHealthyReplicaRetryInterval is how often the replicas of the volumes of a pod on a down node are checked
+	// again while its force deletion waits for a healthy replica on another node.
+	podDeletionHealthyReplicaRetryInterval = 30 * time.Second
+
+	// podDeletionPDBRetryInterval is how often the PodDisruptionBudgets of a pod on a down node are checked again
+	// while its force deletion is skipped for a PodDisruptionBudget allowing no more disruption.
+	podDeletionPDBRetryInterval = 30 * time.Second
+
+	// podDeletionOwnerRetryInterval is how often a manager not owning the force deletion of a pod on a down node
+	// checks it again, so that it takes over once the ownership of the volumes of the pod moves to it.
+	podDeletionOwnerRetryInterval = 30 * time.Second
+
+	// forceDeletionBatchRetryInterval is how long a pod waits for a slot while the batch of its node has all of its
+	// force deletions in flight.
+	forceDeletionBatchRetryInterval = time.Second
+
+	// podForceDeletionVerifyInterval is how often a force deleted pod is checked again while it is kept by finalizers.
+	podForceDeletionVerifyInterval = 10 * time.Second
+
+	// podDeletionPausedRetryInterval is how often a terminating pod is checked again while the force deletion is paused,
+	// so that the force deletion resumes without waiting for a change of the pod.
+	podDeletionPausedRetryInterval = 30 * time.Second
+
+	// forceDeletionSummaryWindow is how long the force deletions on a node are counted after the first one
+	// before a single summary event is recorded for them, when the events are summarized.
+	forceDeletionSummaryWindow = 30 * time.Second
+	// forceDeletionSummaryFlushInterval is how often the summaries of the expired windows are recorded.
+	forceDeletionSummaryFlushInterval = 5 * time.Second
+
+	// podDeletionSkippedEventInterval is the minimum interval between the events recorded on a terminating pod
+	// for the same reason of skipping its force deletion.
+	podDeletionSkippedEventInterval = 10 * time.Minute
+
+	// podEnqueueBurst and podEnqueueRate pace the pods enqueued at once, for example by a full informer relist,
+	// so the workers and the API calls they make are not flooded. Beyond the burst, the pods are spread at the rate per second.
+	podEnqueueBurst = 100
+	podEnqueueRate  = 200
+
+	// nodeDownCacheTTL is how long the evaluation of whether a node is down is reused for the pods on the node,
+	// so a burst of pod events during a node outage does not evaluate the same node over and over.
+	nodeDownCacheTTL = 10 * time.Second
+
+	// kubeNodeShutdownMessage is the message of the not ready condition the kubelet reports during the graceful
+	// shutdown of its node.
+	kubeNodeShutdownMessage = "node is shutting down"
 )
 
 type KubernetesPodController struct {
@@ -48,6 +121,39 @@ type KubernetesPodController struct {
 
 	ds *datastore.DataStore
 
+	// forceDeletionLimiter spreads force deletions of pods on down nodes fairly across namespaces
+	forceDeletionLimiter *namespaceRateLimiter
+	// forceDeletionQuota bounds the force deletions per namespace within the quota window
+	forceDeletionQuota *namespaceQuota
+	// forceDeletionQuarantine stops retrying force deletions on a node where they keep failing
+	forceDeletionQuarantine *nodeQuarantine
+	// approvalHTTPClient sends the requests to the pod deletion approval webhook
+	approvalHTTPClient *http.Client
+	// nodeFencer power fences the down nodes through the fencing endpoint before their pods are force deleted
+	nodeFencer *nodeFencer
+	// cloudEventEmitter publishes the force deletion decisions to the CloudEvent sink
+	cloudEventEmitter *cloudEventEmitter
+	// enqueuePacer spreads the pods enqueued by the informer events over time
+	enqueuePacer *enqueuePacer
+	// podDeletionSkippedEventThrottle limits the events of the skipped force deletions per pod and reason
+	podDeletionSkippedEventThrottle *eventThrottle
+	// forceDeletionBatcher paces the force deletions of the pods of each node in batches
+	forceDeletionBatcher *nodeDeletionBatcher
+	// forceDeletionSummary counts the force deletions per node for the summary events
+	forceDeletionSummary *forceDeletionSummary
+	// nodeDownCache reuses the evaluations of whether the nodes are down for a short while
+	nodeDownCache *nodeDownCache
+	// podForceDeletionVerifier keeps the force deleted pods until the verification of the outcome
+	podForceDeletionVerifier *podForceDeletionVerifier
+	// terminatingPodsOnDownNodes tracks the terminating pods on down nodes for the metrics
+	terminatingPodsOnDownNodes *downNodePodTracker
+	// eligibilityEvaluators decide whether the pods on down nodes can be force deleted, the built-in one first
+	eligibilityEvaluators []ForceDeleteEligibilityEvaluator
+	// startTime is when the controller started handling pods, from which the startup observe period is counted
+	startTime time.Time
+	// maxRetries is how many times a pod key failing to sync is requeued before it is dropped out of the queue
+	maxRetries int
+
 	cacheSyncs []cache.InformerSynced
 }
 
@@ -66,26 +172,69 @@ func NewKubernetesPodController(
 	})
 
 	kc := &KubernetesPodController{
-		baseController: newBaseController("longhorn-kubernetes-pod", logger),
+		baseController: newBaseControllerWithMetrics("longhorn-kubernetes-pod", logger, workqueuemetrics.MetricsProvider()),
 
 		controllerID: controllerID,
 
 		ds: ds,
 
+		forceDeletionLimiter:    newNamespaceRateLimiter(),
+		forceDeletionQuota:      newNamespaceQuota(poddeletionmetrics.SetQuotaUsed),
+		forceDeletionQuarantine: newNodeQuarantine(forceDeletionQuarantineFailureThreshold, forceDeletionQuarantineCooldown),
+		approvalHTTPClient:      &http.Client{},
+		nodeFencer:              newNodeFencer(&http.Client{}),
+		cloudEventEmitter:       newCloudEventEmitter(logger, controllerAgentName, cloudEventQueueSize, cloudEventMaxRetries, cloudEventRetryInterval),
+		enqueuePacer:            newEnqueuePacer(podEnqueueRate, podEnqueueBurst),
+		forceDeletionSummary:    newForceDeletionSummary(forceDeletionSummaryWindow),
+		forceDeletionBatcher:    newNodeDeletionBatcher(),
+		nodeDownCache:           newNodeDownCache(nodeDownCacheTTL, newNodeDownEvaluator(ds, kubeClient).IsNodeDownOrDeleted),
+
+		podForceDeletionVerifier: newPodForceDeletionVerifier(),
+
+		terminatingPodsOnDownNodes: newDownNodePodTracker(poddeletionmetrics.SetTerminating),
+
+		podDeletionSkippedEventThrottle: newEventThrottle(podDeletionSkippedEventInterval),
+
+		startTime:  time.Now(),
+		maxRetries: maxRetries,
+
 		kubeClient:    kubeClient,
 		eventRecorder: eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: "longhorn-kubernetes-pod-controller"}),
 	}
 
+	kc.eligibilityEvaluators = append([]ForceDeleteEligibilityEvaluator{&ownerKindEligibilityEvaluator{kc: kc}},
+		getRegisteredForceDeleteEligibilityEvaluators()...)
+
 	var err error
 	if _, err = ds.PodInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    kc.enqueuePodChange,
-		UpdateFunc: func(old, cur interface{}) { kc.enqueuePodChange(cur) },
+		AddFunc:    kc.enqueuePodChangeIfSyncNeeded,
+		UpdateFunc: func(old, cur interface{}) { kc.enqueuePodChangeIfSyncNeeded(cur) },
+		// The deleted pods are always synced, to clean up after the force deleted ones.
 		DeleteFunc: kc.enqueuePodChange,
 	}); err != nil {
 		return nil, err
 	}
 	kc.cacheSyncs = append(kc.cacheSyncs, ds.PodInformer.HasSynced)
 
+	if _, err = ds.NodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, cur interface{}) { kc.handleLonghornNodeChange(cur) },
+		DeleteFunc: kc.handleLonghornNodeChange,
+	}); err != nil {
+		return nil, err
+	}
+	kc.cacheSyncs = append(kc.cacheSyncs, ds.NodeInformer.HasSynced)
+
+	// The annotated pods are not updated by a setting change, so they are enqueued to resync their config annotation.
+	if _, err = ds.SettingInformer.AddEventHandlerWithResyncPeriod(cache.FilteringResourceEventHandler{
+		FilterFunc: isPodForceDeletionConfigSetting,
+		Handler: cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(old, cur interface{}) { kc.enqueuePodsForSettingChange(cur) },
+		},
+	}, 0); err != nil {
+		return nil, err
+	}
+	kc.cacheSyncs = append(kc.cacheSyncs, ds.SettingInformer.HasSynced)
+
 	return kc, nil
 }
 
@@ -99,9 +248,20 @@ func (kc *KubernetesPodController) Run(workers int, stopCh <-chan struct{}) {
 	if !cache.WaitForNamedCacheSync(controllerAgentName, stopCh, kc.cacheSyncs...) {
 		return
 	}
+	kc.startTime = time.Now()
+	if namespaces, err := kc.getNodeDownPodDeletionNamespaces(); err != nil {
+		kc.logger.WithError(err).Warnf("Invalid setting %v", types.SettingNameNodeDownPodDeletionNamespaces)
+	} else if len(namespaces) == 0 {
+		kc.logger.Info("Pod deletion when node is down applies to all namespaces")
+	} else {
+		kc.logger.Infof("Pod deletion when node is down applies to namespaces %v", strings.Join(namespaces, ","))
+	}
 	for i := 0; i < workers; i++ {
 		go wait.Until(kc.worker, time.Second, stopCh)
 	}
+	go kc.cloudEventEmitter.Run(stopCh)
+	go wait.Until(kc.recordForceDeletionSummaries, forceDeletionSummaryFlushInterval, stopCh)
+	go wait.Until(func() { kc.podDeletionSkippedEventThrottle.Prune(time.Now()) }, podDeletionSkippedEventInterval, stopCh)
 	<-stopCh
 }
 
@@ -128,7 +288,7 @@ func (kc *KubernetesPodController) handleErr(err error, key interface{}) {
 	}
 
 	log := kc.logger.WithField("Pod", key)
-	if kc.queue.NumRequeues(key) < maxRetries {
+	if kc.queue.NumRequeues(key) < kc.maxRetries {
 		handleReconcileErrorLogging(log, err, "Failed to sync Longhorn kubernetes pod")
 		kc.queue.AddRateLimited(key)
 		return
@@ -146,6 +306,18 @@ func getLoggerForPod(logger logrus.FieldLogger, pod *corev1.Pod) *logrus.Entry {
 	})
 }
 
+// getLoggerForPodDeletion returns a logger carrying the fields of the force deletion of the pod on the downed node,
+// so that the decisions on a pod can be filtered out of the logs of all the pods.
+func (kc *KubernetesPodController) getLoggerForPodDeletion(pod *corev1.Pod, nodeID string, deletionPolicy types.NodeDownPodDeletionPolicy) *logrus.Entry {
+	return kc.logger.WithFields(logrus.Fields{
+		"controllerID": kc.controllerID,
+		"namespace":    pod.Namespace,
+		"pod":          pod.Name,
+		"node":         nodeID,
+		"policy":       deletionPolicy,
+	})
+}
+
 func (kc *KubernetesPodController) syncHandler(key string) (err error) {
 	defer func() {
 		err = errors.Wrapf(err, "failed to sync pod %v", key)
@@ -155,16 +327,25 @@ func (kc *KubernetesPodController) syncHandler(key string) (err error) {
 		return err
 	}
 
+	if err := kc.verifyPodForceDeletion(key, namespace, name); err != nil {
+		return err
+	}
+
 	pod, err := kc.ds.GetPodRO(namespace, name)
 	if err != nil {
 		return errors.Wrapf(err, "Error getting Pod: %s", name)
 	}
 	if pod == nil {
+		kc.terminatingPodsOnDownNodes.Forget(key)
 		return nil
 	}
 	nodeID := pod.Spec.NodeName
 	if nodeID == "" {
-		kc.logger.WithField("pod", pod.Name).Trace("skipping pod check since pod is not scheduled yet")
+		kc.logger.WithFields(logrus.Fields{
+			"controllerID": kc.controllerID,
+			"namespace":    namespace,
+			"pod":          pod.Name,
+		}).Trace("skipping pod check since pod is not scheduled yet")
 		return nil
 	}
 
@@ -172,6 +353,12 @@ func (kc *KubernetesPodController) syncHandler(key string) (err error) {
 		return kc.handleWorkloadPodDeletionIfCSIPluginPodIsDown(pod)
 	}
 
+	kc.trackTerminatingPodOnDownNode(key, pod, nodeID)
+
+	// The annotation is informational, so failing to sync it does not hold back the deletion of the pod. The error is
+	// returned once the pod is handled, to retry the sync.
+	annotationErr := kc.syncPodForceDeletionConfigAnnotation(pod)
+
 	if err := kc.cleanupForceDeletedPodResources(pod); err != nil {
 		return err
 	}
@@ -184,7 +371,7 @@ func (kc *KubernetesPodController) syncHandler(key string) (err error) {
 		return err
 	}
 
-	return nil
+	return annotationErr
 }
 
 // handleWorkloadPodDeletionIfCSIPluginPodIsDown deletes workload pods of RWX volumes
@@ -243,7 +430,7 @@ func (kc *KubernetesPodController) handleWorkloadPodDeletionIfCSIPluginPodIsDown
 		return err
 	}
 	for _, pv := range persistentVolume {
-		if pv.Spec.CSI != nil && pv.Spec.CSI.Driver == types.LonghornDriverName {
+		if datastore.IsLonghornPersistentVolume(pv) {
 			persistentVolumes = append(persistentVolumes, pv)
 		}
 	}
@@ -500,44 +687,367 @@ func (kc *KubernetesPodController) getPodWithConflictedAttachment(pods []*corev1
 // and spin up replacement pods on a new node.
 //
 // Force delete a pod when all of the below conditions are meet:
-// 1. NodeDownPodDeletionPolicy is different than DoNothing
+// 1. NodeDownPodDeletionPolicy, or its override by the PVC annotation of the pod, is different than DoNothing
 // 2. pod belongs to a StatefulSet/Deployment depend on NodeDownPodDeletionPolicy
 // 3. node containing the pod is down
 // 4. the pod is terminating and the DeletionTimestamp has passed.
 // 5. pod has a PV with provisioner driver.longhorn.io
+// 6. this manager owns the Longhorn volumes of the pod, if they have an owner
+//
+// The pod is deleted with GracePeriodSeconds 0 rather than the node down pod deletion grace period setting, since the
+// kubelet of a down node cannot complete a graceful deletion and the pod would stay terminating. The grace period is
+// instead waited out from the DeletionTimestamp of the pod, before its volume attachments and the pod are deleted.
 func (kc *KubernetesPodController) handlePodDeletionIfNodeDown(pod *corev1.Pod, nodeID string, namespace string) error {
+	if pod.DeletionTimestamp == nil {
+		return nil
+	}
+
+	// Pause before any check of the pod, so the paused handling neither reports the pod nor acts on it.
+	// The terminating pods are requeued to resume the handling.
+	paused, err := kc.ds.GetSettingAsBool(types.SettingNameNodeDownPodDeletionPaused)
+	if err != nil {
+		return err
+	}
+	if paused {
+		getLoggerForPod(kc.logger, pod).Infof("%v: node down pod deletion handling is paused, skipped pod %v on node %v, requeue after %v", controllerAgentName, pod.Name, nodeID, podDeletionPausedRetryInterval)
+		kc.enqueuePodAfter(pod, podDeletionPausedRetryInterval)
+		return nil
+	}
+
 	deletionPolicy := types.NodeDownPodDeletionPolicyDoNothing
 	if deletionSetting, err := kc.ds.GetSettingValueExisted(types.SettingNameNodeDownPodDeletionPolicy); err == nil {
 		deletionPolicy = types.NodeDownPodDeletionPolicy(deletionSetting)
 	}
+	deletionPolicy, err = kc.getNodeDownPodDeletionPolicyOfPod(pod, deletionPolicy)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the node down pod deletion policy of pod %v in handlePodDeletionIfNodeDown", pod.Name)
+	}
+
+	if deletionPolicy == types.NodeDownPodDeletionPolicyDoNothing {
+		return nil
+	}
+	log := kc.getLoggerForPodDeletion(pod, nodeID, deletionPolicy)
 
-	shouldDelete := (deletionPolicy == types.NodeDownPodDeletionPolicyDeleteStatefulSetPod && isOwnedByStatefulSet(pod)) ||
-		(deletionPolicy == types.NodeDownPodDeletionPolicyDeleteDeploymentPod && isOwnedByDeployment(pod)) ||
-		(deletionPolicy == types.NodeDownPodDeletionPolicyDeleteBothStatefulsetAndDeploymentPod && (isOwnedByStatefulSet(pod) || isOwnedByDeployment(pod)))
+	// gates are the outcomes of the checks passed by the pod, for the dry run to report why the pod qualified.
+	gates := []string{fmt.Sprintf("deletion policy is %v", deletionPolicy)}
 
-	if !shouldDelete {
+	// Every manager watches the pods, so only the owner of the volumes of the pod acts on it. Otherwise the managers
+	// would all issue the deletion and report the same decisions, for example during an ownership handoff.
+	owner, err := kc.getPodDeletionOwner(pod)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the owner of the force deletion of pod %v in handlePodDeletionIfNodeDown", pod.Name)
+	}
+	if owner != "" && owner != kc.controllerID {
+		log.Debugf("%v: skipped pod %v on node %v since its force deletion is owned by %v, requeue after %v", controllerAgentName, pod.Name, nodeID, owner, podDeletionOwnerRetryInterval)
+		kc.enqueuePodAfter(pod, podDeletionOwnerRetryInterval)
 		return nil
 	}
+	if owner == "" {
+		gates = append(gates, "volumes have no owner")
+	} else {
+		gates = append(gates, fmt.Sprintf("volumes are owned by %v", owner))
+	}
 
-	isNodeDown, err := kc.ds.IsNodeDownOrDeleted(nodeID)
+	isNodeDown, err := kc.nodeDownCache.IsNodeDownOrDeleted(nodeID, time.Now())
 	if err != nil {
 		return errors.Wrapf(err, "failed to evaluate Node %v for pod %v in handlePodDeletionIfNodeDown", nodeID, pod.Name)
 	}
 	if !isNodeDown {
+		// Only the pods stuck terminating past their deletion time are worth an event, not every pod terminating normally.
+		if time.Now().After(pod.DeletionTimestamp.Time) {
+			kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("node %v is not down", nodeID))
+		}
 		return nil
 	}
+	gates = append(gates, fmt.Sprintf("node %v is down", nodeID))
 
-	if pod.DeletionTimestamp == nil {
+	// The kubelet of a node shutting down gracefully terminates the pods itself, so leave them to it unless the
+	// shutdown takes longer than the timeout.
+	shutdownRemaining, err := kc.getGracefulNodeShutdownRemaining(pod, nodeID, time.Now())
+	if err != nil {
+		return errors.Wrapf(err, "failed to evaluate the graceful shutdown of Node %v for pod %v in handlePodDeletionIfNodeDown", nodeID, pod.Name)
+	}
+	if shutdownRemaining > 0 {
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("node %v is shutting down gracefully and its kubelet terminates the pod", nodeID))
+		kc.enqueuePodAfter(pod, shutdownRemaining)
+		return nil
+	}
+	gates = append(gates, fmt.Sprintf("node %v is not shutting down gracefully", nodeID))
+
+	namespaceSelected, err := kc.isPodNamespaceSelectedForDeletion(pod)
+	if err != nil {
+		log.WithError(err).Warnf("%v: invalid setting %v, skipped force deletion of pod %v", controllerAgentName, types.SettingNameNodeDownPodDeletionNamespaces, pod.Name)
+		return nil
+	}
+	if !namespaceSelected {
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("namespace %v is not in the deletion namespaces", pod.Namespace))
+		return nil
+	}
+	gates = append(gates, fmt.Sprintf("namespace %v is in the deletion namespaces", pod.Namespace))
+
+	// Evaluate the eligibility after the cheap checks since the ownership may query the owners from the API server.
+	eligible, reason, err := kc.isPodEligibleForForceDeletion(pod, nodeID, deletionPolicy)
+	if err != nil {
+		return errors.Wrapf(err, "failed to evaluate pod %v in handlePodDeletionIfNodeDown", pod.Name)
+	}
+	if !eligible {
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, reason)
 		return nil
 	}
+	gates = append(gates, "pod is eligible by the policy")
 
-	// make sure the volumeattachments of the pods are gone first
+	if !kc.isPodSelectedForDeletion(pod) {
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, "pod does not match the deletion selector")
+		return nil
+	}
+	gates = append(gates, "pod matches the deletion selector")
+
+	optOutPV, err := kc.getNodeDownPodDeletionDisabledPersistentVolume(pod)
+	if err != nil {
+		return errors.Wrapf(err, "failed to evaluate the volumes of pod %v in handlePodDeletionIfNodeDown", pod.Name)
+	}
+	if optOutPV != "" {
+		log.Debugf("%v: skipped force deletion of pod %v on downed node %v since its persistent volume %v opts out of it", controllerAgentName, pod.Name, nodeID, optOutPV)
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("persistent volume %v disables the force deletion by volume attribute %v", optOutPV, types.PVVolumeAttributeDisableNodeDownPodDeletion))
+		return nil
+	}
+	gates = append(gates, "no persistent volume opts out")
+
+	requireAllLonghornVolumes, err := kc.ds.GetSettingAsBool(types.SettingNameNodeDownPodDeletionRequireAllLonghornVolumes)
+	if err != nil {
+		return err
+	}
+	if requireAllLonghornVolumes {
+		nonLonghornClaim, err := kc.getNonLonghornPersistentVolumeClaimOfPod(pod)
+		if err != nil {
+			return errors.Wrapf(err, "failed to evaluate the volumes of pod %v in handlePodDeletionIfNodeDown", pod.Name)
+		}
+		if nonLonghornClaim != "" {
+			log.Debugf("%v: skipped force deletion of pod %v on downed node %v since its persistent volume claim %v is not bound to a Longhorn volume", controllerAgentName, pod.Name, nodeID, nonLonghornClaim)
+			kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("persistent volume claim %v is not bound to a Longhorn volume", nonLonghornClaim))
+			return nil
+		}
+		gates = append(gates, "all persistent volume claims are bound to Longhorn volumes")
+	}
+
+	// A volume that has never been healthy, like a new volume still initializing, has nothing worth failing over.
+	neverHealthyVolume, err := kc.getNeverHealthyVolume(pod)
+	if err != nil {
+		return errors.Wrapf(err, "failed to evaluate the volumes of pod %v in handlePodDeletionIfNodeDown", pod.Name)
+	}
+	if neverHealthyVolume != "" {
+		log.Debugf("%v: skipped force deletion of pod %v on downed node %v since its volume %v has never been healthy", controllerAgentName, pod.Name, nodeID, neverHealthyVolume)
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("volume %v has never been healthy", neverHealthyVolume))
+		return nil
+	}
+	gates = append(gates, "all volumes have been healthy")
+
+	requireHealthyReplica, err := kc.ds.GetSettingAsBool(types.SettingNameNodeDownPodDeletionRequireHealthyReplica)
+	if err != nil {
+		return err
+	}
+	if requireHealthyReplica {
+		volumeName, err := kc.getVolumeWithoutHealthyReplica(pod, nodeID)
+		if err != nil {
+			return errors.Wrapf(err, "failed to evaluate the replicas of pod %v in handlePodDeletionIfNodeDown", pod.Name)
+		}
+		if volumeName != "" {
+			log.Warnf("%v: skipped force deletion of pod %v on downed node %v since its volume %v has no healthy replica on another node, requeue after %v", controllerAgentName, pod.Name, nodeID, volumeName, podDeletionHealthyReplicaRetryInterval)
+			kc.reportPodDeletionSkippedWithType(pod, nodeID, deletionPolicy, corev1.EventTypeWarning, fmt.Sprintf("volume %v has no healthy replica off downed node %v", volumeName, nodeID))
+			kc.enqueuePodAfter(pod, podDeletionHealthyReplicaRetryInterval)
+			return nil
+		}
+		gates = append(gates, fmt.Sprintf("all volumes have a healthy replica off node %v", nodeID))
+	}
+
+	// The node states may be stale right after the startup, so only observe the force deletions for a while
+	// rather than deleting the pods of all the nodes that look down at once.
+	observeRemaining, err := kc.getStartupObservePeriodRemaining(time.Now())
+	if err != nil {
+		return err
+	}
+	if observeRemaining > 0 {
+		if err := kc.handlePodDeletionDryRun(pod, nodeID, namespace, deletionPolicy, poddeletionmetrics.ObservedModeStartup, gates); err != nil {
+			return err
+		}
+		kc.enqueuePodAfter(pod, observeRemaining)
+		return nil
+	}
+
+	// The dry run goes through all the gates, and only skips the deletions and the fencing of the node.
+	dryRun, err := kc.ds.GetSettingAsBool(types.SettingNameNodeDownPodDeletionDryRun)
+	if err != nil {
+		return err
+	}
+
+	forceDeletionTime, err := kc.getPodForceDeletionTimeBySetting(pod)
+	if err != nil {
+		return err
+	}
+	if remaining := time.Until(forceDeletionTime); remaining > 0 {
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("force deletion time %v is not reached", forceDeletionTime.UTC().Format(time.RFC3339)))
+		kc.enqueuePodAfter(pod, remaining)
+		return nil
+	}
+
+	waitForRebuild, err := kc.ds.GetSettingAsBool(types.SettingNameNodeDownPodDeletionWaitForRebuild)
+	if err != nil {
+		return err
+	}
+	if waitForRebuild && time.Since(pod.DeletionTimestamp.Time) < podDeletionRebuildWaitTimeout {
+		volumeName, err := kc.getVolumeWaitingForRebuild(pod, nodeID)
+		if err != nil {
+			return err
+		}
+		if volumeName != "" {
+			log.Infof("%v: force deletion of pod %v on downed node %v waits for volume %v to start rebuilding on a surviving node, requeue after %v", controllerAgentName, pod.Name, nodeID, volumeName, podDeletionRebuildRetryInterval)
+			kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("waiting for volume %v to start rebuilding", volumeName))
+			kc.enqueuePodAfter(pod, podDeletionRebuildRetryInterval)
+			return nil
+		}
+		gates = append(gates, "no volume waits for rebuilding")
+	}
+
+	remaining, lifted := kc.forceDeletionQuarantine.Remaining(nodeID, time.Now())
+	if remaining > 0 {
+		log.Debugf("%v: skipped force deletion of pod %v since downed node %v is quarantined, requeue after %v", controllerAgentName, pod.Name, nodeID, remaining)
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("node %v is quarantined from force deletion", nodeID))
+		kc.enqueuePodAfter(pod, remaining)
+		return nil
+	}
+	if lifted {
+		log.Infof("%v: downed node %v left the force deletion quarantine, retrying force deletion of pod %v", controllerAgentName, nodeID, pod.Name)
+	}
+	gates = append(gates, fmt.Sprintf("node %v is not quarantined", nodeID))
+
+	pdbMode := types.NodeDownPodDeletionPDBModeWarn
+	if pdbModeSetting, err := kc.ds.GetSettingValueExisted(types.SettingNameNodeDownPodDeletionPDBMode); err == nil {
+		pdbMode = types.NodeDownPodDeletionPDBMode(pdbModeSetting)
+	}
+	if pdbMode != types.NodeDownPodDeletionPDBModeIgnore {
+		pdbName, err := kc.getExhaustedPodDisruptionBudget(pod)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check the PodDisruptionBudgets of pod %v in handlePodDeletionIfNodeDown", pod.Name)
+		}
+		if pdbName != "" && pdbMode == types.NodeDownPodDeletionPDBModeSkip {
+			log.Infof("%v: force deletion of pod %v on downed node %v is skipped since PodDisruptionBudget %v allows no more disruption, requeue after %v", controllerAgentName, pod.Name, nodeID, pdbName, podDeletionPDBRetryInterval)
+			kc.reportPodDeletionSkippedWithType(pod, nodeID, deletionPolicy, corev1.EventTypeWarning, fmt.Sprintf("PodDisruptionBudget %v allows no more disruption", pdbName))
+			kc.enqueuePodAfter(pod, podDeletionPDBRetryInterval)
+			return nil
+		}
+		switch {
+		case pdbName == "":
+			gates = append(gates, "no PodDisruptionBudget is exhausted")
+		case dryRun:
+			gates = append(gates, fmt.Sprintf("PodDisruptionBudget %v allows no more disruption, which PDB mode %v only warns of", pdbName, pdbMode))
+		default:
+			log.Warnf("%v: force deleting pod %v on downed node %v while PodDisruptionBudget %v allows no more disruption", controllerAgentName, pod.Name, nodeID, pdbName)
+			kc.eventRecorder.Eventf(pod, corev1.EventTypeWarning, constant.EventReasonPodDisruptionBudgetExhausted,
+				"Force deleting pod %v on downed node %v while PodDisruptionBudget %v allows no more disruption", pod.Name, nodeID, pdbName)
+		}
+	}
+
+	// Consume the rate limit token before asking the approval webhook, so that the webhook is asked
+	// only when the deletion can proceed rather than on every rate limited requeue.
+	namespaceRateLimit, err := kc.ds.GetSettingAsInt(types.SettingNameNodeDownPodDeletionNamespaceRateLimit)
+	if err != nil {
+		return err
+	}
+	if delay := kc.forceDeletionLimiter.Delay(namespace, int(namespaceRateLimit), time.Now()); delay > 0 {
+		log.Infof("%v: force deletion of pod %v on downed node %v is rate limited in namespace %v, requeue after %v", controllerAgentName, pod.Name, nodeID, namespace, delay)
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("rate limited in namespace %v", namespace))
+		kc.enqueuePodAfter(pod, delay)
+		return nil
+	}
+	gates = append(gates, fmt.Sprintf("namespace %v is not rate limited", namespace))
+
+	// Count the deletion against the quota of the namespace, and give it back if the pod is not force deleted after all.
+	quotaReservedAt := time.Now()
+	quotaReserved, delay, err := kc.reserveNamespaceQuota(namespace, quotaReservedAt)
+	if err != nil {
+		return err
+	}
+	if delay > 0 {
+		poddeletionmetrics.IncQuotaExceeded(namespace)
+		log.Infof("%v: force deletion of pod %v on downed node %v exceeds the quota of namespace %v, requeue after %v", controllerAgentName, pod.Name, nodeID, namespace, delay)
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("force deletion quota of namespace %v is exceeded", namespace))
+		kc.enqueuePodAfter(pod, delay)
+		return nil
+	}
+	gates = append(gates, fmt.Sprintf("quota of namespace %v is not exceeded", namespace))
+	forceDeleted := false
+	defer func() {
+		if quotaReserved && !forceDeleted {
+			kc.forceDeletionQuota.Release(namespace, quotaReservedAt)
+		}
+	}()
+
+	approved, reason, err := kc.isPodDeletionApproved(pod, nodeID, deletionPolicy, dryRun)
+	if err != nil {
+		return err
+	}
+	if !approved {
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, reason)
+		if kc.quarantineNodeOnFailure(pod, nodeID, reason) {
+			return nil
+		}
+		log.Infof("%v: force deletion of pod %v on downed node %v is not approved: %v, requeue after %v", controllerAgentName, pod.Name, nodeID, reason, podDeletionApprovalRetryInterval)
+		kc.enqueuePodAfter(pod, podDeletionApprovalRetryInterval)
+		return nil
+	}
+	gates = append(gates, "deletion is approved")
+
+	// Fence the node last, right before the deletion, so that a node is not powered off for a deletion denied anyway.
+	// The dry run never powers off a node.
+	fenced := true
+	if dryRun {
+		gates = append(gates, kc.describeNodeFencing(nodeID))
+	} else {
+		fenced, reason, err = kc.fenceNode(nodeID)
+		if err != nil {
+			return err
+		}
+	}
+	if !fenced {
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, reason)
+		if kc.quarantineNodeOnFailure(pod, nodeID, reason) {
+			return nil
+		}
+		log.Infof("%v: force deletion of pod %v on downed node %v waits for the node fencing: %v, requeue after %v", controllerAgentName, pod.Name, nodeID, reason, podDeletionFencingRetryInterval)
+		kc.enqueuePodAfter(pod, podDeletionFencingRetryInterval)
+		return nil
+	}
+
+	// Delete the pods of a node in batches, so that a large node failure does not flood the API server with deletions.
+	batchSize, err := kc.ds.GetSettingAsInt(types.SettingNameNodeDownPodDeletionBatchSize)
+	if err != nil {
+		return err
+	}
+	batchInterval, err := kc.ds.GetSettingAsInt(types.SettingNameNodeDownPodDeletionBatchInterval)
+	if err != nil {
+		return err
+	}
+	releaseBatchSlot, delay := kc.forceDeletionBatcher.Acquire(nodeID, int(batchSize), time.Duration(batchInterval)*time.Millisecond, time.Now())
+	if delay > 0 {
+		log.Debugf("%v: force deletion batch of downed node %v is full, requeue pod %v after %v", controllerAgentName, nodeID, pod.Name, delay)
+		kc.reportPodDeletionSkipped(pod, nodeID, deletionPolicy, fmt.Sprintf("force deletion batch of node %v is full", nodeID))
+		kc.enqueuePodAfter(pod, delay)
+		return nil
+	}
+	defer releaseBatchSlot()
+
+	// make sure the volumeattachments of the pods are gone first. They are deleted only once every gate has passed
+	// and the node is fenced, since the volumes may still be written by the pods of a node that is not actually down.
 	// ref: https://github.com/longhorn/longhorn/issues/2947
 	volumeAttachments, err := kc.getVolumeAttachmentsOfPod(pod)
 	if err != nil {
 		return err
 	}
 	for _, va := range volumeAttachments {
+		if dryRun {
+			gates = append(gates, fmt.Sprintf("volume attachment %v would be deleted", va.Name))
+			continue
+		}
 		if va.DeletionTimestamp == nil {
 			err := kc.kubeClient.StorageV1().VolumeAttachments().Delete(context.TODO(), va.Name, metav1.DeleteOptions{})
 			if err != nil {
@@ -546,29 +1056,197 @@ func (kc *KubernetesPodController) handlePodDeletionIfNodeDown(pod *corev1.Pod,
 				}
 				return err
 			}
-			kc.logger.Infof("%v: deleted volume attachment %v for pod %v on downed node %v", controllerAgentName, va.Name, pod.Name, nodeID)
+			log.Infof("%v: deleted volume attachment %v for pod %v on downed node %v", controllerAgentName, va.Name, pod.Name, nodeID)
 		}
 		// wait the volumeattachment object to be deleted
-		kc.logger.Infof("%v: wait for volume attachment %v for pod %v on downed node %v to be deleted", controllerAgentName, va.Name, pod.Name, nodeID)
+		log.Infof("%v: wait for volume attachment %v for pod %v on downed node %v to be deleted", controllerAgentName, va.Name, pod.Name, nodeID)
 		return nil
 	}
 
-	if pod.DeletionTimestamp.After(time.Now()) {
-		return nil
+	if dryRun {
+		return kc.handlePodDeletionDryRun(pod, nodeID, namespace, deletionPolicy, poddeletionmetrics.ObservedModeDryRun, gates)
 	}
 
-	gracePeriod := int64(0)
+	// Describe the node at decision time, for the post-incident analysis of the force deletion
+	nodeCondition := kc.getNodeConditionSnapshot(nodeID)
+
 	err = kc.kubeClient.CoreV1().Pods(namespace).Delete(context.TODO(), pod.Name, metav1.DeleteOptions{
-		GracePeriodSeconds: &gracePeriod,
+		GracePeriodSeconds: ptr.To(int64(0)),
 	})
-	if err != nil {
+	if err != nil && !datastore.ErrorIsNotFound(err) {
+		kc.emitPodForceDeletionCloudEvent(pod, nodeID, deletionPolicy, cloudEventTypePodForceDeletionFailed, err.Error())
+		if kc.quarantineNodeOnFailure(pod, nodeID, err.Error()) {
+			return nil
+		}
 		return errors.Wrapf(err, "failed to forcefully delete Pod %v on the downed Node %v in handlePodDeletionIfNodeDown", pod.Name, nodeID)
 	}
-	kc.logger.Infof("%v: Forcefully deleted pod %v on downed node %v", controllerAgentName, pod.Name, nodeID)
+	forceDeleted = true
+
+	// A pod kept by finalizers is not gone yet, so its deletion is not reported until then.
+	existing, err := kc.kubeClient.CoreV1().Pods(namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	if err != nil && !datastore.ErrorIsNotFound(err) {
+		return errors.Wrapf(err, "failed to verify the force deletion of Pod %v on the downed Node %v in handlePodDeletionIfNodeDown", pod.Name, nodeID)
+	}
+	if err == nil && existing.UID == pod.UID {
+		log.Infof("%v: pod %v on downed node %v is still present after the force deletion, requeue after %v", controllerAgentName, pod.Name, nodeID, podForceDeletionVerifyInterval)
+		kc.enqueuePodAfter(pod, podForceDeletionVerifyInterval)
+		return nil
+	}
+
+	kc.forceDeletionQuarantine.RecordSuccess(nodeID)
+	poddeletionmetrics.IncForceDeletions(nodeID, namespace)
+	log.Infof("%v: Forcefully deleted pod %v on downed node %v", controllerAgentName, pod.Name, nodeID)
+	kc.recordForceDeletionEvent(pod, nodeID, nodeCondition)
+	kc.emitPodForceDeletionCloudEvent(pod, nodeID, deletionPolicy, cloudEventTypePodForceDeleted, "")
+
+	return kc.schedulePodForceDeletionVerification(pod, nodeID)
+}
+
+// getPodDeletionOwner returns the owner of the first Longhorn volume of the pod by name, which is the only manager to
+// act on the force deletion of the pod. The ownership of a volume is moved off a down node by the volume controller
+// to a single manager. An empty string is returned if no Longhorn volume of the pod has an owner, leaving the pod to
+// every manager.
+func (kc *KubernetesPodController) getPodDeletionOwner(pod *corev1.Pod) (string, error) {
+	pvs, err := kc.getLonghornPersistentVolumesOfPod(pod)
+	if err != nil {
+		return "", err
+	}
+	volumeNames := []string{}
+	for _, pv := range pvs {
+		volumeNames = append(volumeNames, pv.Spec.CSI.VolumeHandle)
+	}
+	slices.Sort(volumeNames)
+
+	for _, volumeName := range volumeNames {
+		volume, err := kc.ds.GetVolumeRO(volumeName)
+		if err != nil {
+			if datastore.ErrorIsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		if volume.Status.OwnerID != "" {
+			return volume.Status.OwnerID, nil
+		}
+	}
+	return "", nil
+}
+
+// getExhaustedPodDisruptionBudget returns the name of a PodDisruptionBudget selecting the pod that allows no more
+// disruption, or an empty string if there is none. The budgets are listed from the API server, since the informer
+// only watches the Longhorn namespace.
+func (kc *KubernetesPodController) getExhaustedPodDisruptionBudget(pod *corev1.Pod) (string, error) {
+	pdbs, err := kc.kubeClient.PolicyV1().PodDisruptionBudgets(pod.Namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, pdb := range pdbs.Items {
+		// A PodDisruptionBudget without a selector selects no pod.
+		if pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			kc.logger.WithError(err).Warnf("Ignoring PodDisruptionBudget %v/%v with an invalid selector", pdb.Namespace, pdb.Name)
+			continue
+		}
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if pdb.Status.DisruptionsAllowed < 1 {
+			return pdb.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// isPodSelectedForDeletion checks the pod labels against the deletion selector setting. An empty selector selects
+// all pods, while a malformed one selects none so that a typo cannot widen the force deletion.
+func (kc *KubernetesPodController) isPodSelectedForDeletion(pod *corev1.Pod) bool {
+	selectorSetting, err := kc.ds.GetSettingWithAutoFillingRO(types.SettingNameNodeDownPodDeletionSelector)
+	if err != nil {
+		kc.logger.WithError(err).Warnf("%v: failed to get setting %v, skipped force deletion of pod %v", controllerAgentName, types.SettingNameNodeDownPodDeletionSelector, pod.Name)
+		return false
+	}
+	selector, err := labels.Parse(selectorSetting.Value)
+	if err != nil {
+		kc.logger.WithError(err).Warnf("%v: invalid setting %v value %v, skipped force deletion of pod %v", controllerAgentName, types.SettingNameNodeDownPodDeletionSelector, selectorSetting.Value, pod.Name)
+		return false
+	}
+	return selector.Matches(labels.Set(pod.Labels))
+}
+
+// isPodNamespaceSelectedForDeletion returns whether the namespace of the pod is in the deletion namespaces setting,
+// which selects all namespaces if empty.
+func (kc *KubernetesPodController) isPodNamespaceSelectedForDeletion(pod *corev1.Pod) (bool, error) {
+	namespaces, err := kc.getNodeDownPodDeletionNamespaces()
+	if err != nil {
+		return false, err
+	}
+	return len(namespaces) == 0 || slices.Contains(namespaces, pod.Namespace), nil
+}
+
+func (kc *KubernetesPodController) getNodeDownPodDeletionNamespaces() ([]string, error) {
+	namespacesSetting, err := kc.ds.GetSettingWithAutoFillingRO(types.SettingNameNodeDownPodDeletionNamespaces)
+	if err != nil {
+		return nil, err
+	}
+	return types.UnmarshalNodeDownPodDeletionNamespaces(namespacesSetting.Value)
+}
+
+// reportPodDeletionSkipped records an event on the terminating pod with the unmet condition for its force deletion,
+// and publishes it to the CloudEvent sink, at most once per interval for the same condition. Pods that are not
+// terminating are not considered for the force deletion, so nothing is reported for them.
+func (kc *KubernetesPodController) reportPodDeletionSkipped(pod *corev1.Pod, nodeID string, deletionPolicy types.NodeDownPodDeletionPolicy, reason string) {
+	kc.reportPodDeletionSkippedWithType(pod, nodeID, deletionPolicy, corev1.EventTypeNormal, reason)
+}
+
+// reportPodDeletionSkippedWithType is reportPodDeletionSkipped with an event of the given type, for example a warning
+// when the skipped force deletion needs the attention of the operators.
+func (kc *KubernetesPodController) reportPodDeletionSkippedWithType(pod *corev1.Pod, nodeID string, deletionPolicy types.NodeDownPodDeletionPolicy, eventType, reason string) {
+	if pod.DeletionTimestamp == nil {
+		return
+	}
+	if !kc.podDeletionSkippedEventThrottle.Allow(pod.Namespace+"/"+pod.Name+"/"+reason, time.Now()) {
+		return
+	}
+	kc.eventRecorder.Eventf(pod, eventType, constant.EventReasonPodDeletionSkipped, "Skipped force deletion of pod %v: %v", pod.Name, reason)
+	kc.emitPodForceDeletionCloudEvent(pod, nodeID, deletionPolicy, cloudEventTypePodForceDeletionSkipped, reason)
+}
+
+// handlePodDeletionDryRun logs and records an event for the pod on a down node that would be force deleted,
+// without deleting the pod or its volume attachments. The mode tells whether it is the dry run or the startup observe period,
+// and the gates are the outcomes of the checks passed by the pod.
+func (kc *KubernetesPodController) handlePodDeletionDryRun(pod *corev1.Pod, nodeID, namespace string, deletionPolicy types.NodeDownPodDeletionPolicy, mode string, gates []string) error {
+	forceDeletionTime, err := kc.getPodForceDeletionTimeBySetting(pod)
+	if err != nil {
+		return err
+	}
+	if remaining := time.Until(forceDeletionTime); remaining > 0 {
+		kc.enqueuePodAfter(pod, remaining)
+		return nil
+	}
 
+	poddeletionmetrics.IncObserved(mode)
+	kc.getLoggerForPodDeletion(pod, nodeID, deletionPolicy).Infof("%v: %v, would have forcefully deleted pod %v in namespace %v on downed node %v: "+
+		"pod deletion is requested at %v, grace period is %vs, force deletion time %v is over, %v",
+		controllerAgentName, mode, pod.Name, namespace, nodeID,
+		pod.DeletionTimestamp.UTC().Format(time.RFC3339), kc.getPodForceDeletionGracePeriod(), forceDeletionTime.UTC().Format(time.RFC3339),
+		strings.Join(gates, ", "))
+	kc.eventRecorder.Eventf(pod, corev1.EventTypeNormal, constant.EventReasonForceDeletionDryRun,
+		"Would have forcefully deleted pod %v on downed node %v by deletion policy %v (%v)", pod.Name, nodeID, deletionPolicy, mode)
 	return nil
 }
 
+// getStartupObservePeriodRemaining returns how long the force deletions are still only observed after the startup.
+func (kc *KubernetesPodController) getStartupObservePeriodRemaining(now time.Time) (time.Duration, error) {
+	observePeriod, err := kc.ds.GetSettingAsInt(types.SettingNameNodeDownPodDeletionStartupObservePeriod)
+	if err != nil {
+		return 0, err
+	}
+	return kc.startTime.Add(time.Duration(observePeriod) * time.Second).Sub(now), nil
+}
+
 func (kc *KubernetesPodController) getVolumeAttachmentsOfPod(pod *corev1.Pod) ([]*storagev1.VolumeAttachment, error) {
 	var res []*storagev1.VolumeAttachment
 	volumeAttachments, err := kc.ds.ListVolumeAttachmentsRO()
@@ -698,18 +1376,96 @@ func (kc *KubernetesPodController) handlePodDeletionIfVolumeRequestRemount(pod *
 	return nil
 }
 
-func isOwnedByStatefulSet(pod *corev1.Pod) bool {
-	if ownerRef := metav1.GetControllerOf(pod); ownerRef != nil {
-		return ownerRef.Kind == types.KubernetesKindStatefulSet
+// isPodOwnedByDeletionPolicy returns true if the pod belongs to the kind of workload covered by the NodeDownPodDeletionPolicy.
+func isPodOwnedByDeletionPolicy(ds *datastore.DataStore, pod *corev1.Pod, deletionPolicy types.NodeDownPodDeletionPolicy) (bool, error) {
+	var ownerKinds []string
+	switch deletionPolicy {
+	case types.NodeDownPodDeletionPolicyDeleteStatefulSetPod:
+		ownerKinds = []string{types.KubernetesKindStatefulSet}
+	case types.NodeDownPodDeletionPolicyDeleteDeploymentPod:
+		ownerKinds = []string{types.KubernetesKindReplicaSet}
+	case types.NodeDownPodDeletionPolicyDeleteBothStatefulsetAndDeploymentPod:
+		ownerKinds = []string{types.KubernetesKindStatefulSet, types.KubernetesKindReplicaSet}
+	case types.NodeDownPodDeletionPolicyDeleteJobPod:
+		// The pods of a CronJob are controlled by the Jobs the CronJob creates.
+		ownerKinds = []string{types.KubernetesKindJob}
+	default:
+		return false, nil
+	}
+
+	depth, err := ds.GetSettingAsInt(types.SettingNameNodeDownPodDeletionOwnerReferenceDepth)
+	if err != nil {
+		return false, err
 	}
-	return false
+
+	return isOwnedByKinds(ds, pod, int(depth), ownerKinds...)
 }
 
-func isOwnedByDeployment(pod *corev1.Pod) bool {
-	if ownerRef := metav1.GetControllerOf(pod); ownerRef != nil {
-		return ownerRef.Kind == types.KubernetesKindReplicaSet
+// isOwnedByKinds follows the controller owner references of the pod up to depth levels,
+// and returns true if any of the controllers is one of the given kinds.
+func isOwnedByKinds(ds *datastore.DataStore, pod *corev1.Pod, depth int, kinds ...string) (bool, error) {
+	ownerRef := metav1.GetControllerOf(pod)
+	for level := 1; ownerRef != nil && level <= depth; level++ {
+		if slices.Contains(kinds, ownerRef.Kind) {
+			return true, nil
+		}
+		if level == depth {
+			break
+		}
+
+		ownerRefs, err := ds.GetOwnerReferencesOfOwner(pod.Namespace, *ownerRef)
+		if err != nil {
+			if datastore.ErrorIsNotFound(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		ownerRef = getControllerOfOwnerReferences(ownerRefs)
 	}
-	return false
+	return false, nil
+}
+
+func getControllerOfOwnerReferences(ownerRefs []metav1.OwnerReference) *metav1.OwnerReference {
+	for i := range ownerRefs {
+		if ownerRefs[i].Controller != nil && *ownerRefs[i].Controller {
+			return &ownerRefs[i]
+		}
+	}
+	return nil
+}
+
+// enqueuePodChangeIfSyncNeeded enqueues the added or updated pod like enqueuePodChange, unless the sync of the pod
+// has nothing to do for it. Most of the pods are running on the nodes of other managers and would only flood the queue.
+func (kc *KubernetesPodController) enqueuePodChangeIfSyncNeeded(obj interface{}) {
+	if pod, ok := obj.(*corev1.Pod); ok && !kc.isPodSyncNeeded(pod) {
+		return
+	}
+	kc.enqueuePodChange(obj)
+}
+
+// isPodSyncNeeded returns true if the sync of the pod may act on it:
+//   - the terminating pods are force deleted if their node is down. A pod keeps being updated while it is terminating,
+//     for example when its node goes down and its conditions are updated, so the node going down after the deletion
+//     of the pod is still seen.
+//   - the pods on the node of this manager are deleted when their volumes request a remount.
+//   - the pods are annotated with their force deletion config if enabled, or have the annotation removed otherwise.
+//     A pod already annotated with its config by the current settings is not synced again.
+func (kc *KubernetesPodController) isPodSyncNeeded(pod *corev1.Pod) bool {
+	if pod.DeletionTimestamp != nil || isCSIPluginPod(pod) {
+		return true
+	}
+	// The sync skips the pods not scheduled yet.
+	if pod.Spec.NodeName == "" {
+		return false
+	}
+	if pod.Spec.NodeName == kc.controllerID {
+		return true
+	}
+	value, err := kc.getPodForceDeletionConfigAnnotation(pod)
+	if err != nil {
+		return true
+	}
+	return !isPodForceDeletionConfigAnnotated(pod, value)
 }
 
 // enqueuePodChange determines if the pod requires processing based on whether the pod has a PV created by us (driver.longhorn.io)
@@ -738,7 +1494,7 @@ func (kc *KubernetesPodController) enqueuePodChange(obj interface{}) {
 
 	if isCSIPluginPod(pod) {
 		if pod.Spec.NodeName == kc.controllerID {
-			kc.queue.Add(key)
+			kc.enqueuePodKeyPaced(key)
 		}
 		return
 	}
@@ -766,18 +1522,172 @@ func (kc *KubernetesPodController) enqueuePodChange(obj interface{}) {
 			return
 		}
 
-		if pv.Spec.CSI != nil && pv.Spec.CSI.Driver == types.LonghornDriverName {
-			kc.queue.Add(key)
+		if datastore.IsLonghornPersistentVolume(pv) {
+			kc.enqueuePodKeyPaced(key)
 			break
 		}
 	}
 }
 
+// handleLonghornNodeChange forgets the cached evaluation of the changed node, so the next pod on it sees the change.
+// A node no longer down, or deleted, is fenced again the next time it is down.
+func (kc *KubernetesPodController) handleLonghornNodeChange(obj interface{}) {
+	node, ok := obj.(*longhorn.Node)
+	if !ok {
+		deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("received unexpected obj: %#v", obj))
+			return
+		}
+
+		node, ok = deletedState.Obj.(*longhorn.Node)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("DeletedFinalStateUnknown contained invalid object: %#v", deletedState.Obj))
+			return
+		}
+	}
+
+	kc.nodeDownCache.Invalidate(node.Name)
+	if node.DeletionTimestamp != nil || !datastore.IsNodeDown(node) {
+		kc.nodeFencer.Forget(node.Name)
+	}
+}
+
 func (kc *KubernetesPodController) getAssociatedPersistentVolume(pvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolume, error) {
 	pvName := pvc.Spec.VolumeName
 	return kc.ds.GetPersistentVolumeRO(pvName)
 }
 
+// getNonLonghornPersistentVolumeClaimOfPod returns the first persistent volume claim of the pod, including the claims
+// of generic ephemeral volumes, that is not bound to a Longhorn volume, or an empty string if there is none.
+func (kc *KubernetesPodController) getNonLonghornPersistentVolumeClaimOfPod(pod *corev1.Pod) (string, error) {
+	for _, v := range pod.Spec.Volumes {
+		claimName := ""
+		switch {
+		case v.PersistentVolumeClaim != nil:
+			claimName = v.PersistentVolumeClaim.ClaimName
+		case v.Ephemeral != nil:
+			claimName = pod.Name + "-" + v.Name
+		default:
+			continue
+		}
+
+		pvc, err := kc.ds.GetPersistentVolumeClaimRO(pod.Namespace, claimName)
+		if datastore.ErrorIsNotFound(err) {
+			return claimName, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if pvc.Spec.VolumeName == "" {
+			return claimName, nil
+		}
+
+		pv, err := kc.getAssociatedPersistentVolume(pvc)
+		if datastore.ErrorIsNotFound(err) {
+			return claimName, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if !datastore.IsLonghornPersistentVolume(pv) {
+			return claimName, nil
+		}
+	}
+	return "", nil
+}
+
+// getLonghornPersistentVolumesOfPod returns the Longhorn PVs bound to the persistent volume claims of the pod.
+func (kc *KubernetesPodController) getLonghornPersistentVolumesOfPod(pod *corev1.Pod) ([]*corev1.PersistentVolume, error) {
+	var pvs []*corev1.PersistentVolume
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim == nil {
+			continue
+		}
+
+		pvc, err := kc.ds.GetPersistentVolumeClaimRO(pod.Namespace, v.PersistentVolumeClaim.ClaimName)
+		if datastore.ErrorIsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		pv, err := kc.getAssociatedPersistentVolume(pvc)
+		if datastore.ErrorIsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !datastore.IsLonghornPersistentVolume(pv) {
+			continue
+		}
+		pvs = append(pvs, pv)
+	}
+	return pvs, nil
+}
+
+// getNodeDownPodDeletionDisabledPersistentVolume returns the name of a Longhorn PV of the pod whose volume attributes
+// opt out of the force deletion on down nodes, or an empty string if there is none.
+func (kc *KubernetesPodController) getNodeDownPodDeletionDisabledPersistentVolume(pod *corev1.Pod) (string, error) {
+	pvs, err := kc.getLonghornPersistentVolumesOfPod(pod)
+	if err != nil {
+		return "", err
+	}
+	return getNodeDownPodDeletionDisabledPersistentVolumeName(pvs), nil
+}
+
+// getNodeDownPodDeletionPolicyOfPod returns the node down pod deletion policy annotated on the persistent volume claims
+// of the pod, overriding the given policy of the setting. An annotation with an unknown policy is ignored, and the
+// policy of the setting is kept if the claims are annotated with different policies.
+func (kc *KubernetesPodController) getNodeDownPodDeletionPolicyOfPod(pod *corev1.Pod, settingPolicy types.NodeDownPodDeletionPolicy) (types.NodeDownPodDeletionPolicy, error) {
+	var annotatedPolicy types.NodeDownPodDeletionPolicy
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim == nil {
+			continue
+		}
+
+		pvc, err := kc.ds.GetPersistentVolumeClaimRO(pod.Namespace, v.PersistentVolumeClaim.ClaimName)
+		if datastore.ErrorIsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		value, ok := pvc.Annotations[types.PVCAnnotationNodeDownPodDeletionPolicy]
+		if !ok {
+			continue
+		}
+
+		policy, err := types.ParseNodeDownPodDeletionPolicy(value)
+		if err != nil {
+			kc.logger.WithError(err).Warnf("Ignoring annotation %v of PVC %v for pod %v", types.PVCAnnotationNodeDownPodDeletionPolicy, pvc.Name, pod.Name)
+			continue
+		}
+		if annotatedPolicy != "" && annotatedPolicy != policy {
+			kc.logger.Warnf("PVCs of pod %v are annotated with different node down pod deletion policies %v and %v, using the setting policy %v",
+				pod.Name, annotatedPolicy, policy, settingPolicy)
+			return settingPolicy, nil
+		}
+		annotatedPolicy = policy
+	}
+
+	if annotatedPolicy == "" {
+		return settingPolicy, nil
+	}
+	return annotatedPolicy, nil
+}
+
+func getNodeDownPodDeletionDisabledPersistentVolumeName(pvs []*corev1.PersistentVolume) string {
+	for _, pv := range pvs {
+		if datastore.IsNodeDownPodDeletionDisabledForPV(pv) {
+			return pv.Name
+		}
+	}
+	return ""
+}
+
 func (kc *KubernetesPodController) getAssociatedVolumes(pod *corev1.Pod) ([]*longhorn.Volume, error) {
 	log := getLoggerForPod(kc.logger, pod)
 	var volumeList []*longhorn.Volume
@@ -804,7 +1714,7 @@ func (kc *KubernetesPodController) getAssociatedVolumes(pod *corev1.Pod) ([]*lon
 			return nil, err
 		}
 
-		if pv.Spec.CSI != nil && pv.Spec.CSI.Driver == types.LonghornDriverName {
+		if datastore.IsLonghornPersistentVolume(pv) {
 			vol, err := kc.ds.GetVolume(pv.Spec.CSI.VolumeHandle)
 			if datastore.ErrorIsNotFound(err) {
 				log.WithError(err).Warn("Cannot auto-delete Pod when the associated Volume is not found")
@@ -0,0 +1,1286 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+	"k8s.io/utils/ptr"
+
+	metadatafake "k8s.io/client-go/metadata/fake"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	lhfake "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned/fake"
+	poddeletionmetrics "github.com/longhorn/longhorn-manager/metrics_collector/poddeletion"
+	workqueuemetrics "github.com/longhorn/longhorn-manager/metrics_collector/workqueue"
+)
+
+// podControllerFixture is a KubernetesPodController backed by fake clients, with the fake clients and event recorder
+// exposed for the assertions.
+type podControllerFixture struct {
+	kc           *KubernetesPodController
+	kubeClient   *fake.Clientset
+	lhClient     *lhfake.Clientset
+	fakeRecorder *record.FakeRecorder
+}
+
+// newTestKubernetesPodController returns a controller with the given settings and objects. The Longhorn objects,
+// Kubernetes nodes, persistent volumes and claims are added to the informer indexers read by the datastore,
+// while the other Kubernetes objects are added to the fake clientset.
+func newTestKubernetesPodController(t testing.TB, settings map[types.SettingName]string, objs ...runtime.Object) *podControllerFixture {
+	kubeClient := fake.NewSimpleClientset() // nolint: staticcheck
+	lhClient := lhfake.NewSimpleClientset() // nolint: staticcheck
+	extensionsClient := apiextensionsfake.NewSimpleClientset()
+	informerFactories := util.NewInformerFactories(TestNamespace, kubeClient, lhClient, controller.NoResyncPeriodFunc())
+
+	ds := datastore.NewDataStore(TestNamespace, lhClient, kubeClient, extensionsClient, informerFactories)
+	kc, err := NewKubernetesPodController(logrus.StandardLogger(), ds, scheme.Scheme, kubeClient, TestNode1)
+	require.NoError(t, err)
+	fakeRecorder := record.NewFakeRecorder(100)
+	kc.eventRecorder = fakeRecorder
+
+	lhInformers := informerFactories.LhInformerFactory.Longhorn().V1beta2()
+	kubeInformers := informerFactories.KubeInformerFactory.Core().V1()
+	storageInformers := informerFactories.KubeInformerFactory.Storage().V1()
+	for name, value := range settings {
+		setting, err := lhClient.LonghornV1beta2().Settings(TestNamespace).Create(context.TODO(), newSetting(string(name), value), metav1.CreateOptions{})
+		require.NoError(t, err)
+		require.NoError(t, lhInformers.Settings().Informer().GetIndexer().Add(setting))
+	}
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *longhorn.Volume:
+			require.NoError(t, lhInformers.Volumes().Informer().GetIndexer().Add(o))
+		case *longhorn.Replica:
+			require.NoError(t, lhInformers.Replicas().Informer().GetIndexer().Add(o))
+		case *longhorn.Node:
+			require.NoError(t, lhInformers.Nodes().Informer().GetIndexer().Add(o))
+		case *corev1.Node:
+			require.NoError(t, kubeInformers.Nodes().Informer().GetIndexer().Add(o))
+		case *corev1.PersistentVolume:
+			require.NoError(t, kubeInformers.PersistentVolumes().Informer().GetIndexer().Add(o))
+		case *corev1.PersistentVolumeClaim:
+			require.NoError(t, kubeInformers.PersistentVolumeClaims().Informer().GetIndexer().Add(o))
+		case *storagev1.VolumeAttachment:
+			// The volume attachments are listed from the informer and deleted through the clientset.
+			require.NoError(t, storageInformers.VolumeAttachments().Informer().GetIndexer().Add(o))
+			require.NoError(t, kubeClient.Tracker().Add(o))
+		default:
+			require.NoError(t, kubeClient.Tracker().Add(obj))
+		}
+	}
+
+	return &podControllerFixture{
+		kc:           kc,
+		kubeClient:   kubeClient,
+		lhClient:     lhClient,
+		fakeRecorder: fakeRecorder,
+	}
+}
+
+// newTestTerminatingPod returns a pod of a StatefulSet on the node, whose deletion timestamp is at the offset from now,
+// mounting the given persistent volume claims.
+func newTestTerminatingPod(nodeID string, deletionOffset time.Duration, claimNames ...string) *corev1.Pod {
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-statefulset",
+			Namespace: TestNamespace,
+			UID:       "test-statefulset-uid",
+		},
+	}
+	deletionTimestamp := metav1.NewTime(time.Now().Add(deletionOffset))
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "test-pod",
+			Namespace:         TestNamespace,
+			DeletionTimestamp: &deletionTimestamp,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(statefulSet, appsv1.SchemeGroupVersion.WithKind(types.KubernetesKindStatefulSet)),
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeID,
+		},
+	}
+	for _, claimName := range claimNames {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: claimName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+			},
+		})
+	}
+	return pod
+}
+
+// newTestBoundClaim returns a persistent volume claim bound to a persistent volume of the CSI driver,
+// along with the persistent volume.
+func newTestBoundClaim(claimName, driver, volumeHandle string) []runtime.Object {
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: claimName + "-pv",
+		},
+		Spec: corev1.PersistentVolumeSpec{
+			PersistentVolumeSource: corev1.PersistentVolumeSource{
+				CSI: &corev1.CSIPersistentVolumeSource{
+					Driver:       driver,
+					VolumeHandle: volumeHandle,
+				},
+			},
+		},
+	}
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claimName,
+			Namespace: TestNamespace,
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			VolumeName: pv.Name,
+		},
+	}
+	return []runtime.Object{pv, pvc}
+}
+
+// newTestVolumeAttachment returns a Longhorn volume attachment of the persistent volume to the node.
+func newTestVolumeAttachment(nodeID, pvName string) *storagev1.VolumeAttachment {
+	return &storagev1.VolumeAttachment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-va-" + pvName,
+		},
+		Spec: storagev1.VolumeAttachmentSpec{
+			Attacher: types.LonghornDriverName,
+			NodeName: nodeID,
+			Source: storagev1.VolumeAttachmentSource{
+				PersistentVolumeName: ptr.To(pvName),
+			},
+		},
+	}
+}
+
+// newTestPodDisruptionBudget returns a PodDisruptionBudget selecting every pod of the test namespace.
+func newTestPodDisruptionBudget(disruptionsAllowed int32) *policyv1.PodDisruptionBudget {
+	return &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pdb",
+			Namespace: TestNamespace,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{},
+		},
+		Status: policyv1.PodDisruptionBudgetStatus{
+			DisruptionsAllowed: disruptionsAllowed,
+		},
+	}
+}
+
+func TestIsOwnedByKindsWithTwoLevelOwnership(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-statefulset",
+			Namespace: TestNamespace,
+			UID:       "test-statefulset-uid",
+		},
+	}
+	// The intermediate owner of the pod, which is in turn controlled by the StatefulSet.
+	intermediate := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-intermediate",
+			Namespace: TestNamespace,
+			UID:       "test-intermediate-uid",
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(statefulSet, appsv1.SchemeGroupVersion.WithKind(types.KubernetesKindStatefulSet)),
+			},
+		},
+	}
+	newPodOwnedBy := func(owner *appsv1.ReplicaSet) *corev1.Pod {
+		ownerRef := metav1.NewControllerRef(owner, appsv1.SchemeGroupVersion.WithKind(types.KubernetesKindReplicaSet))
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-pod",
+				Namespace:       TestNamespace,
+				OwnerReferences: []metav1.OwnerReference{*ownerRef},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		pod      *corev1.Pod
+		depth    int
+		expected bool
+	}{
+		"direct owner only": {
+			pod:      newPodOwnedBy(intermediate),
+			depth:    1,
+			expected: false,
+		},
+		"two levels": {
+			pod:      newPodOwnedBy(intermediate),
+			depth:    2,
+			expected: true,
+		},
+		"more levels than the chain": {
+			pod:      newPodOwnedBy(intermediate),
+			depth:    5,
+			expected: true,
+		},
+		"stale intermediate owner": {
+			pod: newPodOwnedBy(func() *appsv1.ReplicaSet {
+				recreated := intermediate.DeepCopy()
+				recreated.UID = "test-recreated-intermediate-uid"
+				return recreated
+			}()),
+			depth:    2,
+			expected: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newTestKubernetesPodController(t, nil, statefulSet, intermediate)
+
+			owned, err := isOwnedByKinds(f.kc.ds, tc.pod, tc.depth, types.KubernetesKindStatefulSet)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, owned)
+		})
+	}
+}
+
+func TestIsOwnedByKindsWithCustomResourceOwner(t *testing.T) {
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-statefulset",
+			Namespace: TestNamespace,
+			UID:       "test-statefulset-uid",
+		},
+	}
+	// The custom resource owning the pod, which is in turn controlled by the StatefulSet.
+	intermediate := &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "example.com/v1",
+			Kind:       "Widget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-widget",
+			Namespace: TestNamespace,
+			UID:       "test-widget-uid",
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(statefulSet, appsv1.SchemeGroupVersion.WithKind(types.KubernetesKindStatefulSet)),
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: TestNamespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(intermediate, intermediate.GroupVersionKind()),
+			},
+		},
+	}
+
+	f := newTestKubernetesPodController(t, nil)
+	f.kubeClient.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "widgets", Kind: "Widget", Namespaced: true},
+				{Name: "widgets/status", Kind: "Widget", Namespaced: true},
+			},
+		},
+	}
+
+	metadataScheme := metadatafake.NewTestScheme()
+	require.NoError(t, metav1.AddMetaToScheme(metadataScheme))
+	metadataClient := metadatafake.NewSimpleMetadataClient(metadataScheme, intermediate)
+	f.kc.ds.SetMetadataClient(metadataClient)
+	kc := f.kc
+
+	owned, err := isOwnedByKinds(kc.ds, pod, 1, types.KubernetesKindStatefulSet)
+	require.NoError(t, err)
+	assert.False(t, owned)
+
+	// The discovery information is cached, so the chain is followed by one discovery only.
+	for i := 0; i < 3; i++ {
+		owned, err = isOwnedByKinds(kc.ds, pod, 2, types.KubernetesKindStatefulSet)
+		require.NoError(t, err)
+		assert.True(t, owned)
+	}
+	discoveries := 0
+	for _, action := range f.kubeClient.Actions() {
+		if action.GetVerb() == "get" && action.GetResource().Resource == "resource" {
+			discoveries++
+		}
+	}
+	assert.Equal(t, 1, discoveries)
+	assert.Len(t, metadataClient.Actions(), 3)
+}
+
+func TestIsPodOwnedByDeletionPolicyForJobPod(t *testing.T) {
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cronjob",
+			Namespace: TestNamespace,
+			UID:       "test-cronjob-uid",
+		},
+	}
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cronjob-28000000",
+			Namespace: TestNamespace,
+			UID:       "test-job-uid",
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind(types.KubernetesKindCronJob)),
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: TestNamespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind(types.KubernetesKindJob)),
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		deletionPolicy types.NodeDownPodDeletionPolicy
+		expected       bool
+	}{
+		"job policy": {
+			deletionPolicy: types.NodeDownPodDeletionPolicyDeleteJobPod,
+			expected:       true,
+		},
+		"statefulset and deployment policy": {
+			deletionPolicy: types.NodeDownPodDeletionPolicyDeleteBothStatefulsetAndDeploymentPod,
+			expected:       false,
+		},
+		"do-nothing policy": {
+			deletionPolicy: types.NodeDownPodDeletionPolicyDoNothing,
+			expected:       false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newTestKubernetesPodController(t, nil, cronJob, job)
+
+			owned, err := isPodOwnedByDeletionPolicy(f.kc.ds, pod, tc.deletionPolicy)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, owned)
+		})
+	}
+}
+
+func TestHandlePodDeletionIfNodeDown(t *testing.T) {
+	lastHeartbeat := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	notReadyNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: TestNode2,
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{
+					Type:               corev1.NodeReady,
+					Status:             corev1.ConditionUnknown,
+					Reason:             "NodeStatusUnknown",
+					LastHeartbeatTime:  metav1.NewTime(lastHeartbeat),
+					LastTransitionTime: metav1.NewTime(lastHeartbeat.Add(40 * time.Second)),
+				},
+			},
+		},
+	}
+	newVolume := func(lastHealthyAt string) *longhorn.Volume {
+		return &longhorn.Volume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-volume",
+				Namespace: TestNamespace,
+			},
+			Status: longhorn.VolumeStatus{
+				LastHealthyAt: lastHealthyAt,
+			},
+		}
+	}
+	newOwnedVolume := func(ownerID string) *longhorn.Volume {
+		v := newVolume(util.Now())
+		v.Status.OwnerID = ownerID
+		return v
+	}
+	// The pod of the mixed volumes cases uses a Longhorn volume and a volume of another storage provider.
+	mixedClaims := append(newTestBoundClaim("longhorn-claim", types.LonghornDriverName, "longhorn-volume"),
+		newTestBoundClaim("other-claim", "other.csi.example.com", "other-volume")...)
+	newReplica := func(name, nodeID, healthyAt, failedAt string) *longhorn.Replica {
+		return &longhorn.Replica{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: TestNamespace,
+				Labels:    types.GetVolumeLabels("test-volume"),
+			},
+			Spec: longhorn.ReplicaSpec{
+				InstanceSpec: longhorn.InstanceSpec{
+					NodeID: nodeID,
+				},
+				HealthyAt: healthyAt,
+				FailedAt:  failedAt,
+			},
+		}
+	}
+	// The volume of the replica topology cases lost a replica with the down node.
+	newReplicaTopology := func(replicas ...runtime.Object) []runtime.Object {
+		objs := append(newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"), newVolume(util.Now()),
+			newReplica("test-replica-down", TestNode2, util.Now(), ""))
+		return append(objs, replicas...)
+	}
+	// newPolicyClaim returns a claim of the Longhorn volume annotated with the node down pod deletion policy.
+	newPolicyClaim := func(claimName, policy string) []runtime.Object {
+		objs := newTestBoundClaim(claimName, types.LonghornDriverName, "test-volume")
+		objs[1].(*corev1.PersistentVolumeClaim).Annotations = map[string]string{
+			types.PVCAnnotationNodeDownPodDeletionPolicy: policy,
+		}
+		return objs
+	}
+
+	tests := map[string]struct {
+		settings   map[types.SettingName]string
+		objs       []runtime.Object
+		claims     []string
+		startedAgo time.Duration
+
+		expectDeleted bool
+		// expectedEvent are the substrings of the only event expected on the pod, or no event is expected if empty
+		expectedEvent []string
+	}{
+		"force deleted with the node condition": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			},
+			objs:          []runtime.Object{notReadyNode},
+			expectDeleted: true,
+			expectedEvent: []string{
+				constant.EventReasonForceDeleted,
+				"Ready condition is Unknown with reason NodeStatusUnknown",
+				"since 2024-01-02T03:04:45Z",
+				"last heartbeat at 2024-01-02T03:04:05Z",
+			},
+		},
+		"paused with do-nothing policy": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDoNothing),
+				types.SettingNameNodeDownPodDeletionPaused: "true",
+			},
+		},
+		"paused with statefulset policy": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionPaused: "true",
+			},
+		},
+		"paused with deployment policy": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteDeploymentPod),
+				types.SettingNameNodeDownPodDeletionPaused: "true",
+			},
+		},
+		"paused with statefulset and deployment policy": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteBothStatefulsetAndDeploymentPod),
+				types.SettingNameNodeDownPodDeletionPaused: "true",
+			},
+		},
+		"dry run": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionDryRun: "true",
+			},
+			expectedEvent: []string{
+				constant.EventReasonForceDeletionDryRun,
+				fmt.Sprintf("pod test-pod on downed node %v", TestNode2),
+			},
+		},
+		"dry run skipped by an exhausted PodDisruptionBudget": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:  string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionDryRun:  "true",
+				types.SettingNameNodeDownPodDeletionPDBMode: string(types.NodeDownPodDeletionPDBModeSkip),
+			},
+			objs:          []runtime.Object{newTestPodDisruptionBudget(0)},
+			expectedEvent: []string{constant.EventReasonPodDeletionSkipped, "PodDisruptionBudget test-pdb allows no more disruption"},
+		},
+		"dry run without fencing the node": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:          string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionDryRun:          "true",
+				types.SettingNameNodeDownPodDeletionFencingEndpoint: "http://127.0.0.1:1",
+			},
+			expectedEvent: []string{constant.EventReasonForceDeletionDryRun},
+		},
+		"within startup observe period": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:               string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionStartupObservePeriod: "300",
+			},
+			startedAgo:    time.Minute,
+			expectedEvent: []string{constant.EventReasonForceDeletionDryRun, poddeletionmetrics.ObservedModeStartup},
+		},
+		"after startup observe period": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:               string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionStartupObservePeriod: "300",
+			},
+			startedAgo:    10 * time.Minute,
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"mixed volumes without requiring all Longhorn volumes": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:                    string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionRequireAllLonghornVolumes: "false",
+			},
+			objs:          mixedClaims,
+			claims:        []string{"longhorn-claim", "other-claim"},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"mixed volumes requiring all Longhorn volumes": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:                    string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionRequireAllLonghornVolumes: "true",
+			},
+			objs:          mixedClaims,
+			claims:        []string{"longhorn-claim", "other-claim"},
+			expectedEvent: []string{constant.EventReasonPodDeletionSkipped, "persistent volume claim other-claim is not bound to a Longhorn volume"},
+		},
+		"persistent volume opting out": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			},
+			objs: func() []runtime.Object {
+				objs := append(newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"), newVolume(util.Now()))
+				pv := objs[0].(*corev1.PersistentVolume)
+				pv.Spec.CSI.VolumeAttributes = map[string]string{types.PVVolumeAttributeDisableNodeDownPodDeletion: "true"}
+				return objs
+			}(),
+			claims: []string{"test-claim"},
+			expectedEvent: []string{
+				constant.EventReasonPodDeletionSkipped,
+				"persistent volume test-claim-pv disables the force deletion by volume attribute disableNodeDownPodDeletion",
+			},
+		},
+		"never healthy volume": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			},
+			objs:          append(newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"), newVolume("")),
+			claims:        []string{"test-claim"},
+			expectedEvent: []string{constant.EventReasonPodDeletionSkipped, "volume test-volume has never been healthy"},
+		},
+		"previously healthy volume": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			},
+			objs:          append(newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"), newVolume(util.Now())),
+			claims:        []string{"test-claim"},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"volume owned by this manager": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			},
+			objs:          append(newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"), newOwnedVolume(TestNode1)),
+			claims:        []string{"test-claim"},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"volume owned by another manager": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			},
+			objs:   append(newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"), newOwnedVolume("test-node-3")),
+			claims: []string{"test-claim"},
+		},
+		"volume still owned by the downed node": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			},
+			objs:   append(newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"), newOwnedVolume(TestNode2)),
+			claims: []string{"test-claim"},
+		},
+		"pod in the deletion namespaces": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:     string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionNamespaces: "tenant-a," + TestNamespace,
+			},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"pod not in the deletion namespaces": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:     string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionNamespaces: "tenant-a,tenant-b",
+			},
+			expectedEvent: []string{constant.EventReasonPodDeletionSkipped, fmt.Sprintf("namespace %v is not in the deletion namespaces", TestNamespace)},
+		},
+		"invalid deletion namespaces": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:     string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionNamespaces: "Tenant_A",
+			},
+		},
+		"do-nothing policy overridden by the claim annotation": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDoNothing),
+			},
+			objs:          append(newPolicyClaim("test-claim", string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod)), newVolume(util.Now())),
+			claims:        []string{"test-claim"},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"statefulset policy overridden by the claim annotation": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			},
+			objs:   append(newPolicyClaim("test-claim", string(types.NodeDownPodDeletionPolicyDoNothing)), newVolume(util.Now())),
+			claims: []string{"test-claim"},
+		},
+		"invalid claim annotation": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			},
+			objs:          append(newPolicyClaim("test-claim", "statefulset"), newVolume(util.Now())),
+			claims:        []string{"test-claim"},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"conflicting claim annotations": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDoNothing),
+			},
+			objs: append(append(newPolicyClaim("test-claim", string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod)),
+				newPolicyClaim("other-claim", string(types.NodeDownPodDeletionPolicyDoNothing))...), newVolume(util.Now())),
+			claims: []string{"test-claim", "other-claim"},
+		},
+		"pdb allowing disruption with skip mode": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:  string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionPDBMode: string(types.NodeDownPodDeletionPDBModeSkip),
+			},
+			objs:          []runtime.Object{newTestPodDisruptionBudget(1)},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"pdb exhausted with skip mode": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:  string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionPDBMode: string(types.NodeDownPodDeletionPDBModeSkip),
+			},
+			objs:          []runtime.Object{newTestPodDisruptionBudget(0)},
+			expectedEvent: []string{corev1.EventTypeWarning, constant.EventReasonPodDeletionSkipped, "PodDisruptionBudget test-pdb allows no more disruption"},
+		},
+		"pdb exhausted with ignore mode": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:  string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionPDBMode: string(types.NodeDownPodDeletionPDBModeIgnore),
+			},
+			objs:          []runtime.Object{newTestPodDisruptionBudget(0)},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"pdb of other pods exhausted with skip mode": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:  string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionPDBMode: string(types.NodeDownPodDeletionPDBModeSkip),
+			},
+			objs: []runtime.Object{func() *policyv1.PodDisruptionBudget {
+				pdb := newTestPodDisruptionBudget(0)
+				pdb.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "other"}}
+				return pdb
+			}()},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"healthy replica off the down node": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:                string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionRequireHealthyReplica: "true",
+			},
+			objs:          newReplicaTopology(newReplica("test-replica-healthy", TestNode1, util.Now(), "")),
+			claims:        []string{"test-claim"},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"only failed replica off the down node": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:                string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionRequireHealthyReplica: "true",
+			},
+			objs:   newReplicaTopology(newReplica("test-replica-failed", TestNode1, util.Now(), util.Now())),
+			claims: []string{"test-claim"},
+			expectedEvent: []string{
+				corev1.EventTypeWarning,
+				constant.EventReasonPodDeletionSkipped,
+				fmt.Sprintf("volume test-volume has no healthy replica off downed node %v", TestNode2),
+			},
+		},
+		"only rebuilding replica off the down node": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:                string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionRequireHealthyReplica: "true",
+			},
+			objs:          newReplicaTopology(newReplica("test-replica-rebuilding", TestNode1, "", "")),
+			claims:        []string{"test-claim"},
+			expectedEvent: []string{corev1.EventTypeWarning, "has no healthy replica"},
+		},
+		"only replica on the down node without requiring healthy replica": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			},
+			objs:          newReplicaTopology(),
+			claims:        []string{"test-claim"},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"within grace period": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:      string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionGracePeriod: "300",
+			},
+			expectedEvent: []string{constant.EventReasonPodDeletionSkipped, "is not reached"},
+		},
+		"after grace period": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:      string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionGracePeriod: "30",
+			},
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := newTestTerminatingPod(TestNode2, -time.Minute, tc.claims...)
+			f := newTestKubernetesPodController(t, tc.settings, append([]runtime.Object{pod}, tc.objs...)...)
+			f.kc.startTime = time.Now().Add(-tc.startedAgo)
+
+			require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+			_, err := f.kubeClient.CoreV1().Pods(TestNamespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+			if tc.expectDeleted {
+				assert.True(t, datastore.ErrorIsNotFound(err))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if len(tc.expectedEvent) == 0 {
+				assert.Empty(t, f.fakeRecorder.Events)
+				return
+			}
+			require.Len(t, f.fakeRecorder.Events, 1)
+			event := <-f.fakeRecorder.Events
+			for _, expected := range tc.expectedEvent {
+				assert.Contains(t, event, expected)
+			}
+		})
+	}
+}
+
+func TestPodDeletionSkippedKeepsVolumeAttachments(t *testing.T) {
+	volume := &longhorn.Volume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-volume",
+			Namespace: TestNamespace,
+		},
+		Status: longhorn.VolumeStatus{
+			LastHealthyAt: util.Now(),
+		},
+	}
+	// The only replica of the volume is on the down node, so no rebuilding has started.
+	replica := &longhorn.Replica{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-replica-down",
+			Namespace: TestNamespace,
+			Labels:    types.GetVolumeLabels("test-volume"),
+		},
+		Spec: longhorn.ReplicaSpec{
+			InstanceSpec: longhorn.InstanceSpec{
+				NodeID: TestNode2,
+			},
+			HealthyAt: util.Now(),
+		},
+	}
+
+	tests := map[string]struct {
+		settings map[types.SettingName]string
+		objs     []runtime.Object
+		setup    func(kc *KubernetesPodController)
+
+		expectedEvent string
+	}{
+		"waiting for rebuild": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:         string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionWaitForRebuild: "true",
+			},
+			objs:          []runtime.Object{volume, replica},
+			expectedEvent: "waiting for volume test-volume to start rebuilding",
+		},
+		"within grace period": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:      string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionGracePeriod: "300",
+			},
+			expectedEvent: "force deletion time",
+		},
+		"quarantined node": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			},
+			setup: func(kc *KubernetesPodController) {
+				for i := 0; i < forceDeletionQuarantineFailureThreshold; i++ {
+					kc.forceDeletionQuarantine.RecordFailure(TestNode2, time.Now())
+				}
+			},
+			expectedEvent: fmt.Sprintf("node %v is quarantined from force deletion", TestNode2),
+		},
+		"pdb exhausted with skip mode": {
+			settings: map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:  string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionPDBMode: string(types.NodeDownPodDeletionPDBModeSkip),
+			},
+			objs:          []runtime.Object{newTestPodDisruptionBudget(0)},
+			expectedEvent: "PodDisruptionBudget test-pdb allows no more disruption",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := newTestTerminatingPod(TestNode2, -time.Minute, "test-claim")
+			va := newTestVolumeAttachment(TestNode2, "test-claim-pv")
+			objs := append(newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"), pod, va)
+			f := newTestKubernetesPodController(t, tc.settings, append(objs, tc.objs...)...)
+			if tc.setup != nil {
+				tc.setup(f.kc)
+			}
+
+			require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+			_, err := f.kubeClient.StorageV1().VolumeAttachments().Get(context.TODO(), va.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+			require.Len(t, f.fakeRecorder.Events, 1)
+			event := <-f.fakeRecorder.Events
+			assert.Contains(t, event, constant.EventReasonPodDeletionSkipped)
+			assert.Contains(t, event, tc.expectedEvent)
+		})
+	}
+}
+
+func TestPodForceDeletionWarnsOfExhaustedPDB(t *testing.T) {
+	pod := newTestTerminatingPod(TestNode2, -time.Minute)
+	f := newTestKubernetesPodController(t, map[types.SettingName]string{
+		types.SettingNameNodeDownPodDeletionPolicy:  string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+		types.SettingNameNodeDownPodDeletionPDBMode: string(types.NodeDownPodDeletionPDBModeWarn),
+	}, pod, newTestPodDisruptionBudget(0))
+
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+	_, err := f.kubeClient.CoreV1().Pods(TestNamespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	assert.True(t, datastore.ErrorIsNotFound(err))
+
+	require.Len(t, f.fakeRecorder.Events, 2)
+	event := <-f.fakeRecorder.Events
+	assert.Contains(t, event, corev1.EventTypeWarning)
+	assert.Contains(t, event, constant.EventReasonPodDisruptionBudgetExhausted)
+	assert.Contains(t, event, "PodDisruptionBudget test-pdb allows no more disruption")
+	assert.Contains(t, <-f.fakeRecorder.Events, constant.EventReasonForceDeleted)
+}
+
+func TestPodForceDeletionKeptByFinalizers(t *testing.T) {
+	pod := newTestTerminatingPod(TestNode2, -time.Minute)
+	f := newTestKubernetesPodController(t, map[types.SettingName]string{
+		types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+	}, pod)
+	var gracePeriods []int64
+	f.kubeClient.PrependReactor("delete", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		gracePeriods = append(gracePeriods, *action.(clienttesting.DeleteAction).GetDeleteOptions().GracePeriodSeconds)
+		// The pod is kept as if a finalizer were pending.
+		return true, nil, nil
+	})
+
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+	assert.Equal(t, []int64{0}, gracePeriods)
+	// The force deletion is not reported until the pod is gone.
+	assert.Empty(t, f.fakeRecorder.Events)
+}
+
+// testLogHook keeps the log entries fired to it, for the tests to check their fields.
+type testLogHook struct {
+	entries []*logrus.Entry
+}
+
+func (h *testLogHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *testLogHook) Fire(entry *logrus.Entry) error {
+	h.entries = append(h.entries, entry)
+	return nil
+}
+
+func TestPodForceDeletionLogFields(t *testing.T) {
+	pod := newTestTerminatingPod(TestNode2, -time.Minute)
+	f := newTestKubernetesPodController(t, map[types.SettingName]string{
+		types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+	}, pod)
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := &testLogHook{}
+	logger.AddHook(hook)
+	f.kc.logger = logrus.NewEntry(logger)
+
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+
+	require.NotEmpty(t, hook.entries)
+	entry := hook.entries[len(hook.entries)-1]
+	assert.Contains(t, entry.Message, "Forcefully deleted pod test-pod on downed node "+TestNode2)
+	assert.Equal(t, logrus.Fields{
+		"controllerID": TestNode1,
+		"namespace":    TestNamespace,
+		"pod":          pod.Name,
+		"node":         TestNode2,
+		"policy":       types.NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+	}, entry.Data)
+}
+
+func TestPodDeletionDryRunLogsGates(t *testing.T) {
+	pod := newTestTerminatingPod(TestNode2, -time.Minute)
+	f := newTestKubernetesPodController(t, map[types.SettingName]string{
+		types.SettingNameNodeDownPodDeletionPolicy:      string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+		types.SettingNameNodeDownPodDeletionDryRun:      "true",
+		types.SettingNameNodeDownPodDeletionPDBMode:     string(types.NodeDownPodDeletionPDBModeWarn),
+		types.SettingNameNodeDownPodDeletionGracePeriod: "0",
+	}, pod, newTestPodDisruptionBudget(0))
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := &testLogHook{}
+	logger.AddHook(hook)
+	f.kc.logger = logrus.NewEntry(logger)
+
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+	_, err := f.kubeClient.CoreV1().Pods(TestNamespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	require.NotEmpty(t, hook.entries)
+	message := hook.entries[len(hook.entries)-1].Message
+	for _, gate := range []string{
+		"would have forcefully deleted pod test-pod in namespace " + TestNamespace + " on downed node " + TestNode2,
+		"grace period is 0s",
+		"deletion policy is " + string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+		"node " + TestNode2 + " is down",
+		"pod is eligible by the policy",
+		"node " + TestNode2 + " is not quarantined",
+		"PodDisruptionBudget test-pdb allows no more disruption, which PDB mode warn only warns of",
+		"namespace " + TestNamespace + " is not rate limited",
+		"deletion is approved",
+		"no fencing endpoint is configured",
+	} {
+		assert.Contains(t, message, gate)
+	}
+	// The dry run does not warn of the force deletion on the exhausted PodDisruptionBudget.
+	require.Len(t, f.fakeRecorder.Events, 1)
+	assert.Contains(t, <-f.fakeRecorder.Events, constant.EventReasonForceDeletionDryRun)
+}
+
+// BenchmarkHandlePodDeletionIfNodeDownBurst handles a burst of terminating pods on the same node,
+// and reports how many times the node is evaluated per pod with and without the node-down cache.
+func BenchmarkHandlePodDeletionIfNodeDownBurst(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		ttl  time.Duration
+	}{
+		{name: "uncached", ttl: 0},
+		{name: "cached", ttl: nodeDownCacheTTL},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			// The pods are not due for deletion yet, so the handling stops right after the node evaluation.
+			pod := newTestTerminatingPod(TestNode2, time.Hour)
+			f := newTestKubernetesPodController(b, map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			}, newNode(TestNode2, TestNamespace, true, longhorn.ConditionStatusTrue, ""))
+			evaluations := 0
+			f.kc.nodeDownCache = newNodeDownCache(bc.ttl, func(node string) (bool, error) {
+				evaluations++
+				return f.kc.ds.IsNodeDownOrDeleted(node)
+			})
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(evaluations)/float64(b.N), "evaluations/op")
+		})
+	}
+}
+
+func TestPodDeletionSkippedEvent(t *testing.T) {
+	tests := map[string]struct {
+		policy         types.NodeDownPodDeletionPolicy
+		nodeID         string
+		deletionOffset time.Duration
+
+		expectedReason string
+	}{
+		"deletion policy is do-nothing": {
+			policy:         types.NodeDownPodDeletionPolicyDoNothing,
+			nodeID:         TestNode2,
+			deletionOffset: -time.Minute,
+		},
+		"node is not down": {
+			policy:         types.NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+			nodeID:         TestNode1,
+			deletionOffset: -time.Minute,
+			expectedReason: fmt.Sprintf("node %v is not down", TestNode1),
+		},
+		"pod is not owned by the policy": {
+			policy:         types.NodeDownPodDeletionPolicyDeleteDeploymentPod,
+			nodeID:         TestNode2,
+			deletionOffset: -time.Minute,
+			expectedReason: "pod is not owned by a workload of deletion policy delete-deployment-pod",
+		},
+		"force deletion time is not reached": {
+			policy:         types.NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+			nodeID:         TestNode2,
+			deletionOffset: time.Minute,
+			expectedReason: "is not reached",
+		},
+		"pod terminating normally on a running node": {
+			policy:         types.NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+			nodeID:         TestNode1,
+			deletionOffset: time.Minute,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Only the node 1 is running, the node 2 is deleted.
+			pod := newTestTerminatingPod(tc.nodeID, tc.deletionOffset)
+			f := newTestKubernetesPodController(t, map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:            string(tc.policy),
+				types.SettingNameNodeDownPodDeletionCloudEventSinkURL: "http://sink.example.com",
+			}, newNode(TestNode1, TestNamespace, true, longhorn.ConditionStatusTrue, ""), pod)
+
+			// The event and the CloudEvent of the same unmet condition are reported once.
+			require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, tc.nodeID, TestNamespace))
+			require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, tc.nodeID, TestNamespace))
+			if tc.expectedReason == "" {
+				assert.Empty(t, f.fakeRecorder.Events)
+				assert.Empty(t, f.kc.cloudEventEmitter.queue)
+				return
+			}
+			require.Len(t, f.fakeRecorder.Events, 1)
+			event := <-f.fakeRecorder.Events
+			assert.Contains(t, event, constant.EventReasonPodDeletionSkipped)
+			assert.Contains(t, event, tc.expectedReason)
+			require.Len(t, f.kc.cloudEventEmitter.queue, 1)
+			delivery := <-f.kc.cloudEventEmitter.queue
+			assert.Equal(t, cloudEventTypePodForceDeletionSkipped, delivery.event.Type)
+			assert.Contains(t, delivery.event.Data.(*podForceDeletionEventData).Reason, tc.expectedReason)
+		})
+	}
+}
+
+func TestIsPodSelectedForDeletion(t *testing.T) {
+	tests := map[string]struct {
+		selector string
+		expected bool
+	}{
+		"empty selector": {
+			selector: "",
+			expected: true,
+		},
+		"matching selector": {
+			selector: "tier=best-effort,app!=database",
+			expected: true,
+		},
+		"not matching selector": {
+			selector: "tier=critical",
+			expected: false,
+		},
+		"malformed selector": {
+			selector: "tier in (best-effort",
+			expected: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newTestKubernetesPodController(t, map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionSelector: tc.selector,
+			})
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-pod",
+					Namespace: TestNamespace,
+					Labels: map[string]string{
+						"tier": "best-effort",
+						"app":  "cache",
+					},
+				},
+			}
+			assert.Equal(t, tc.expected, f.kc.isPodSelectedForDeletion(pod))
+		})
+	}
+}
+
+func TestEnqueuePodChangeIfSyncNeeded(t *testing.T) {
+	newPod := func(nodeID string, terminating bool) *corev1.Pod {
+		pod := newTestTerminatingPod(nodeID, -time.Minute, "longhorn-claim")
+		if !terminating {
+			pod.DeletionTimestamp = nil
+		}
+		return pod
+	}
+
+	tests := map[string]struct {
+		pod      *corev1.Pod
+		settings map[types.SettingName]string
+		// annotateCurrent annotates the pod with its config by the settings
+		annotateCurrent bool
+
+		expectEnqueued bool
+	}{
+		"running pod on another node": {
+			pod: newPod(TestNode2, false),
+		},
+		"terminating pod on another node": {
+			pod:            newPod(TestNode2, true),
+			expectEnqueued: true,
+		},
+		"running pod on the node of the manager": {
+			pod:            newPod(TestNode1, false),
+			expectEnqueued: true,
+		},
+		"pod not scheduled yet": {
+			pod: newPod("", false),
+		},
+		"running pod with the config annotation enabled": {
+			pod:            newPod(TestNode2, false),
+			settings:       map[types.SettingName]string{types.SettingNameNodeDownPodDeletionConfigAnnotation: "true"},
+			expectEnqueued: true,
+		},
+		"running pod annotated with the config annotation disabled": {
+			pod: func() *corev1.Pod {
+				pod := newPod(TestNode2, false)
+				pod.Annotations = map[string]string{types.PodAnnotationNodeDownPodDeletionConfig: "{}"}
+				return pod
+			}(),
+			expectEnqueued: true,
+		},
+		"running pod annotated with an outdated config": {
+			pod: func() *corev1.Pod {
+				pod := newPod(TestNode2, false)
+				pod.Annotations = map[string]string{types.PodAnnotationNodeDownPodDeletionConfig: "{}"}
+				return pod
+			}(),
+			settings:       map[types.SettingName]string{types.SettingNameNodeDownPodDeletionConfigAnnotation: "true"},
+			expectEnqueued: true,
+		},
+		"running pod annotated with the current config": {
+			pod:             newPod(TestNode2, false),
+			settings:        map[types.SettingName]string{types.SettingNameNodeDownPodDeletionConfigAnnotation: "true"},
+			annotateCurrent: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newTestKubernetesPodController(t, tc.settings, newTestBoundClaim("longhorn-claim", types.LonghornDriverName, TestVolumeName)...)
+			defer f.kc.queue.ShutDown()
+
+			if tc.annotateCurrent {
+				value, err := f.kc.getPodForceDeletionConfigAnnotation(tc.pod)
+				require.NoError(t, err)
+				tc.pod.Annotations = map[string]string{types.PodAnnotationNodeDownPodDeletionConfig: value}
+			}
+
+			f.kc.enqueuePodChangeIfSyncNeeded(tc.pod)
+			if tc.expectEnqueued {
+				assert.Equal(t, 1, f.kc.queue.Len())
+			} else {
+				assert.Zero(t, f.kc.queue.Len())
+			}
+
+			// The deleted pods are always enqueued.
+			f.kc.enqueuePodChange(tc.pod)
+			assert.Equal(t, 1, f.kc.queue.Len())
+		})
+	}
+}
+
+func TestKubernetesPodControllerQueueMetrics(t *testing.T) {
+	f := newTestKubernetesPodController(t, nil)
+	defer f.kc.queue.ShutDown()
+
+	getDepth := func() float64 {
+		metric := &dto.Metric{}
+		require.NoError(t, workqueuemetrics.MetricsProvider().NewDepthMetric(f.kc.name).(prometheus.Gauge).Write(metric))
+		return metric.GetGauge().GetValue()
+	}
+
+	depth := getDepth()
+	f.kc.queue.Add(TestNamespace + "/test-queue-metrics-pod")
+	assert.Equal(t, depth+1, getDepth())
+}
+
+func TestKubernetesPodControllerMaxRetries(t *testing.T) {
+	// countRequeues returns how many times the failing key is requeued before it is dropped out of the queue.
+	countRequeues := func(kc *KubernetesPodController) int {
+		key := TestNamespace + "/test-pod"
+		requeues := 0
+		for i := 0; i < 100; i++ {
+			kc.handleErr(fmt.Errorf("failed to sync"), key)
+			if kc.queue.NumRequeues(key) == 0 {
+				return requeues
+			}
+			requeues++
+		}
+		return requeues
+	}
+
+	f := newTestKubernetesPodController(t, nil)
+	defer f.kc.queue.ShutDown()
+	assert.Equal(t, maxRetries, f.kc.maxRetries)
+	assert.Equal(t, maxRetries, countRequeues(f.kc))
+
+	f = newTestKubernetesPodController(t, nil)
+	defer f.kc.queue.ShutDown()
+	f.kc.maxRetries = maxRetries + 5
+	assert.Equal(t, maxRetries+5, countRequeues(f.kc))
+}
@@ -0,0 +1,381 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientset "k8s.io/client-go/kubernetes"
+	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+const (
+	defaultRecreatedPVFSType = "ext4"
+
+	storageClassParameterFSType       = "fsType"
+	storageClassParameterCSIFSTypeKey = "csi.storage.k8s.io/fstype"
+
+	pvcAnnotationStorageProvisioner     = "volume.kubernetes.io/storage-provisioner"
+	pvcAnnotationBetaStorageProvisioner = "volume.beta.kubernetes.io/storage-provisioner"
+)
+
+type KubernetesPVCController struct {
+	*baseController
+
+	// use as the OwnerID of the controller
+	controllerID string
+
+	kubeClient    clientset.Interface
+	eventRecorder record.EventRecorder
+
+	ds *datastore.DataStore
+
+	cacheSyncs []cache.InformerSynced
+}
+
+func NewKubernetesPVCController(
+	logger logrus.FieldLogger,
+	ds *datastore.DataStore,
+	scheme *runtime.Scheme,
+	kubeClient clientset.Interface,
+	controllerID string) (*KubernetesPVCController, error) {
+
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logrus.Infof)
+	// TODO: remove the wrapper when every clients have moved to use the clientset.
+	eventBroadcaster.StartRecordingToSink(&v1core.EventSinkImpl{Interface: v1core.New(kubeClient.CoreV1().RESTClient()).Events("")})
+
+	kc := &KubernetesPVCController{
+		baseController: newBaseController("longhorn-kubernetes-pvc", logger),
+
+		controllerID: controllerID,
+
+		ds: ds,
+
+		kubeClient:    kubeClient,
+		eventRecorder: eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: "longhorn-kubernetes-pvc-controller"}),
+	}
+
+	var err error
+	if _, err = ds.PersistentVolumeClaimInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    kc.enqueuePersistentVolumeClaim,
+		UpdateFunc: func(old, cur interface{}) { kc.enqueuePersistentVolumeClaim(cur) },
+	}); err != nil {
+		return nil, err
+	}
+	kc.cacheSyncs = append(kc.cacheSyncs, ds.PersistentVolumeClaimInformer.HasSynced)
+
+	if _, err = ds.PersistentVolumeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		DeleteFunc: kc.enqueuePersistentVolumeDeletion,
+	}); err != nil {
+		return nil, err
+	}
+	kc.cacheSyncs = append(kc.cacheSyncs, ds.PersistentVolumeInformer.HasSynced)
+
+	return kc, nil
+}
+
+func (kc *KubernetesPVCController) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer kc.queue.ShutDown()
+
+	kc.logger.Info("Starting Kubernetes PVC controller")
+	defer kc.logger.Info("Shut down kubernetes PVC controller")
+
+	if !cache.WaitForNamedCacheSync("kubernetes-pvc", stopCh, kc.cacheSyncs...) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(kc.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (kc *KubernetesPVCController) worker() {
+	for kc.processNextWorkItem() {
+	}
+}
+
+func (kc *KubernetesPVCController) processNextWorkItem() bool {
+	key, quit := kc.queue.Get()
+	if quit {
+		return false
+	}
+	defer kc.queue.Done(key)
+
+	err := kc.syncPersistentVolumeClaim(key.(string))
+	kc.handleErr(err, key)
+
+	return true
+}
+
+func (kc *KubernetesPVCController) handleErr(err error, key interface{}) {
+	if err == nil {
+		kc.queue.Forget(key)
+		return
+	}
+
+	log := kc.logger.WithField("PersistentVolumeClaim", key)
+	if kc.queue.NumRequeues(key) < maxRetries {
+		handleReconcileErrorLogging(log, err, "Failed to sync PVC")
+		kc.queue.AddRateLimited(key)
+		return
+	}
+
+	utilruntime.HandleError(err)
+	handleReconcileErrorLogging(log, err, "Dropping PVC out of the queue")
+	kc.queue.Forget(key)
+}
+
+func (kc *KubernetesPVCController) syncPersistentVolumeClaim(key string) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "failed to sync PVC %v", key)
+	}()
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	pvc, err := kc.ds.GetPersistentVolumeClaimRO(namespace, name)
+	if err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get PVC %v", key)
+	}
+
+	if err := kc.recreateMissingPersistentVolume(pvc); err != nil {
+		return err
+	}
+
+	return kc.annotateVolumeName(pvc)
+}
+
+// annotateVolumeName annotates the PVC bound to a Longhorn PV with the name of the Longhorn volume
+// resolved from the CSI volume handle of the PV, so that tooling starting from the PVC does not
+// need to look up the PV.
+func (kc *KubernetesPVCController) annotateVolumeName(pvc *corev1.PersistentVolumeClaim) error {
+	if pvc.Spec.VolumeName == "" || !pvc.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	pv, err := kc.ds.GetPersistentVolumeRO(pvc.Spec.VolumeName)
+	if err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != types.LonghornDriverName || pv.Spec.CSI.VolumeHandle == "" {
+		return nil
+	}
+	volumeName := pv.Spec.CSI.VolumeHandle
+	if pvc.Annotations[types.PVCAnnotationLonghornVolumeName] == volumeName {
+		return nil
+	}
+
+	volume, err := kc.ds.GetVolumeRO(volumeName)
+	if err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// Only the owner of the volume annotates the PVC to avoid conflicting with the other managers.
+	if volume.Status.OwnerID != kc.controllerID {
+		return nil
+	}
+
+	pvc = pvc.DeepCopy()
+	if pvc.Annotations == nil {
+		pvc.Annotations = map[string]string{}
+	}
+	pvc.Annotations[types.PVCAnnotationLonghornVolumeName] = volumeName
+	if _, err := kc.ds.UpdatePersistentVolumeClaim(pvc.Namespace, pvc); err != nil {
+		return errors.Wrapf(err, "failed to annotate PVC %v/%v with volume %v", pvc.Namespace, pvc.Name, volumeName)
+	}
+	return nil
+}
+
+// recreateMissingPersistentVolume recreates the PV of a bound PVC when the PV is gone
+// but the Longhorn volume it was provisioned for still exists. This happens in disaster
+// recovery when PVCs are restored without their PVs.
+// Kubernetes marks such a PVC as Lost shortly after its PV disappears, and binds it again
+// once the PV with the matching claim reference is back.
+func (kc *KubernetesPVCController) recreateMissingPersistentVolume(pvc *corev1.PersistentVolumeClaim) error {
+	if pvc.Status.Phase != corev1.ClaimBound && pvc.Status.Phase != corev1.ClaimLost {
+		return nil
+	}
+	if pvc.Spec.VolumeName == "" || !pvc.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	if _, err := kc.ds.GetPersistentVolumeRO(pvc.Spec.VolumeName); err == nil {
+		return nil
+	} else if !datastore.ErrorIsNotFound(err) {
+		return err
+	}
+
+	volume, err := kc.ds.GetVolumeRO(pvc.Spec.VolumeName)
+	if err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// Only the owner of the volume recreates the PV to avoid racing with the other managers.
+	if volume.Status.OwnerID != kc.controllerID {
+		return nil
+	}
+
+	enabled, err := kc.ds.GetSettingAsBool(types.SettingNameRecreateMissingPVForBoundPVC)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return nil
+	}
+
+	// Make sure the PVC was provisioned by Longhorn, so a PVC of another provisioner
+	// cannot claim a Longhorn volume by the name.
+	if !kc.isProvisionedByLonghorn(pvc) {
+		return nil
+	}
+
+	log := getLoggerForVolume(kc.logger, volume).WithFields(logrus.Fields{
+		"namespace": pvc.Namespace,
+		"pvc":       pvc.Name,
+		"pv":        pvc.Spec.VolumeName,
+	})
+
+	// Make sure the PVC is the one the volume was used by, so a PVC cannot claim an unrelated volume.
+	ks := volume.Status.KubernetesStatus
+	if ks.PVCName != "" && (ks.PVCName != pvc.Name || ks.Namespace != pvc.Namespace) {
+		log.Warnf("Skipping PV recreation since the volume was used by PVC %v/%v", ks.Namespace, ks.PVCName)
+		return nil
+	}
+
+	if volume.Spec.Encrypted {
+		log.Warn("Skipping PV recreation for the encrypted volume since the secret of the original PV is unknown")
+		return nil
+	}
+
+	storageClassName := ""
+	if pvc.Spec.StorageClassName != nil {
+		storageClassName = *pvc.Spec.StorageClassName
+	}
+
+	pv := datastore.NewPVManifestForVolume(volume, pvc.Spec.VolumeName, storageClassName, getFSTypeForStorageClass(kc.ds, storageClassName), nil, "", nil, nil)
+	pv.Spec.ClaimRef = &corev1.ObjectReference{
+		Kind:       types.KubernetesKindPersistentVolumeClaim,
+		APIVersion: "v1",
+		Namespace:  pvc.Namespace,
+		Name:       pvc.Name,
+		UID:        pvc.UID,
+	}
+
+	if _, err := kc.ds.CreatePersistentVolume(pv); err != nil {
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to recreate PV %v for PVC %v/%v", pv.Name, pvc.Namespace, pvc.Name)
+	}
+
+	log.Info("Recreated missing PV for the bound PVC")
+	kc.eventRecorder.Eventf(volume, corev1.EventTypeNormal, constant.EventReasonCreated,
+		"Recreated missing PersistentVolume %v for bound PersistentVolumeClaim %v/%v", pv.Name, pvc.Namespace, pvc.Name)
+
+	return nil
+}
+
+// isProvisionedByLonghorn checks the provisioner annotations of the PVC, or the provisioner of its StorageClass.
+func (kc *KubernetesPVCController) isProvisionedByLonghorn(pvc *corev1.PersistentVolumeClaim) bool {
+	for _, key := range []string{pvcAnnotationStorageProvisioner, pvcAnnotationBetaStorageProvisioner} {
+		if provisioner, ok := pvc.Annotations[key]; ok {
+			return provisioner == types.LonghornDriverName
+		}
+	}
+
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return false
+	}
+	storageClass, err := kc.ds.GetStorageClassRO(*pvc.Spec.StorageClassName)
+	if err != nil {
+		return false
+	}
+	return storageClass.Provisioner == types.LonghornDriverName
+}
+
+// getFSTypeForStorageClass returns the filesystem type configured in the StorageClass,
+// or the default filesystem type if it cannot be determined.
+func getFSTypeForStorageClass(ds *datastore.DataStore, storageClassName string) string {
+	if storageClassName == "" {
+		return defaultRecreatedPVFSType
+	}
+
+	storageClass, err := ds.GetStorageClassRO(storageClassName)
+	if err != nil {
+		return defaultRecreatedPVFSType
+	}
+
+	for _, key := range []string{storageClassParameterCSIFSTypeKey, storageClassParameterFSType} {
+		if fsType := storageClass.Parameters[key]; fsType != "" {
+			return fsType
+		}
+	}
+	return defaultRecreatedPVFSType
+}
+
+func (kc *KubernetesPVCController) enqueuePersistentVolumeClaim(obj interface{}) {
+	key, err := controller.KeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to get key for object %#v: %v", obj, err))
+		return
+	}
+	kc.queue.Add(key)
+}
+
+// enqueuePersistentVolumeDeletion enqueues the PVC claiming a deleted Longhorn PV,
+// so that the PV is recreated if the PVC is still bound to it.
+func (kc *KubernetesPVCController) enqueuePersistentVolumeDeletion(obj interface{}) {
+	pv, ok := obj.(*corev1.PersistentVolume)
+	if !ok {
+		deletedState, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("received unexpected obj: %#v", obj))
+			return
+		}
+
+		// use the last known state, to enqueue, dependent objects
+		pv, ok = deletedState.Obj.(*corev1.PersistentVolume)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("DeletedFinalStateUnknown contained invalid object: %#v", deletedState.Obj))
+			return
+		}
+	}
+
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != types.LonghornDriverName || pv.Spec.ClaimRef == nil {
+		return
+	}
+
+	kc.queue.Add(pv.Spec.ClaimRef.Namespace + "/" + pv.Spec.ClaimRef.Name)
+}
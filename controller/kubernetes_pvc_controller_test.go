@@ -0,0 +1,246 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	lhfake "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned/fake"
+)
+
+func TestRecreateMissingPersistentVolume(t *testing.T) {
+	newBoundPVC := func() *corev1.PersistentVolumeClaim {
+		pvc := newPVC()
+		pvc.Namespace = TestNamespace
+		pvc.UID = "test-pvc-uid"
+		pvc.Spec.VolumeName = TestVolumeName
+		pvc.Status.Phase = corev1.ClaimBound
+		return pvc
+	}
+	newVolumeUsedBy := func(namespace, pvcName string) *longhorn.Volume {
+		v := newVolume(TestVolumeName, 2)
+		v.Status.KubernetesStatus = longhorn.KubernetesStatus{
+			Namespace: namespace,
+			PVCName:   pvcName,
+		}
+		return v
+	}
+
+	tests := map[string]struct {
+		settingValue string
+		provisioner  string
+		pvc          *corev1.PersistentVolumeClaim
+		volume       *longhorn.Volume
+
+		expectPVCreated bool
+	}{
+		"recreate missing pv": {
+			settingValue:    "true",
+			pvc:             newBoundPVC(),
+			volume:          newVolumeUsedBy(TestNamespace, TestPVCName),
+			expectPVCreated: true,
+		},
+		"recreate missing pv for lost pvc": {
+			settingValue: "true",
+			pvc: func() *corev1.PersistentVolumeClaim {
+				pvc := newBoundPVC()
+				pvc.Status.Phase = corev1.ClaimLost
+				return pvc
+			}(),
+			volume:          newVolumeUsedBy(TestNamespace, TestPVCName),
+			expectPVCreated: true,
+		},
+		"recreate missing pv for pvc with provisioner annotation": {
+			settingValue: "true",
+			provisioner:  "another.csi.driver",
+			pvc: func() *corev1.PersistentVolumeClaim {
+				pvc := newBoundPVC()
+				pvc.Annotations = map[string]string{pvcAnnotationStorageProvisioner: types.LonghornDriverName}
+				return pvc
+			}(),
+			volume:          newVolumeUsedBy(TestNamespace, TestPVCName),
+			expectPVCreated: true,
+		},
+		"pvc of another provisioner": {
+			settingValue: "true",
+			provisioner:  "another.csi.driver",
+			pvc:          newBoundPVC(),
+			volume:       newVolumeUsedBy("", ""),
+		},
+		"setting disabled": {
+			settingValue: "false",
+			pvc:          newBoundPVC(),
+			volume:       newVolumeUsedBy(TestNamespace, TestPVCName),
+		},
+		"volume used by another pvc": {
+			settingValue: "true",
+			pvc:          newBoundPVC(),
+			volume:       newVolumeUsedBy(TestNamespace, "another-pvc"),
+		},
+		"volume not found": {
+			settingValue: "true",
+			pvc:          newBoundPVC(),
+		},
+		"pvc not bound": {
+			settingValue: "true",
+			pvc: func() *corev1.PersistentVolumeClaim {
+				pvc := newBoundPVC()
+				pvc.Status.Phase = corev1.ClaimPending
+				return pvc
+			}(),
+			volume: newVolumeUsedBy(TestNamespace, TestPVCName),
+		},
+		"volume owned by another node": {
+			settingValue: "true",
+			pvc:          newBoundPVC(),
+			volume: func() *longhorn.Volume {
+				v := newVolumeUsedBy(TestNamespace, TestPVCName)
+				v.Status.OwnerID = TestNode2
+				return v
+			}(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset() // nolint: staticcheck
+			lhClient := lhfake.NewSimpleClientset() // nolint: staticcheck
+			extensionsClient := apiextensionsfake.NewSimpleClientset()
+			informerFactories := util.NewInformerFactories(TestNamespace, kubeClient, lhClient, controller.NoResyncPeriodFunc())
+
+			ds := datastore.NewDataStore(TestNamespace, lhClient, kubeClient, extensionsClient, informerFactories)
+			kc, err := NewKubernetesPVCController(logrus.StandardLogger(), ds, scheme.Scheme, kubeClient, TestNode1)
+			require.NoError(t, err)
+			kc.eventRecorder = record.NewFakeRecorder(100)
+
+			storageClass, err := kubeClient.StorageV1().StorageClasses().Create(context.TODO(), newStorageClass(TestStorageClassName, tc.provisioner), metav1.CreateOptions{})
+			require.NoError(t, err)
+			require.NoError(t, informerFactories.KubeInformerFactory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(storageClass))
+
+			setting := newSetting(string(types.SettingNameRecreateMissingPVForBoundPVC), tc.settingValue)
+			setting, err = lhClient.LonghornV1beta2().Settings(TestNamespace).Create(context.TODO(), setting, metav1.CreateOptions{})
+			require.NoError(t, err)
+			require.NoError(t, informerFactories.LhInformerFactory.Longhorn().V1beta2().Settings().Informer().GetIndexer().Add(setting))
+
+			if tc.volume != nil {
+				v, err := lhClient.LonghornV1beta2().Volumes(TestNamespace).Create(context.TODO(), tc.volume, metav1.CreateOptions{})
+				require.NoError(t, err)
+				require.NoError(t, informerFactories.LhInformerFactory.Longhorn().V1beta2().Volumes().Informer().GetIndexer().Add(v))
+			}
+
+			pvc, err := kubeClient.CoreV1().PersistentVolumeClaims(TestNamespace).Create(context.TODO(), tc.pvc, metav1.CreateOptions{})
+			require.NoError(t, err)
+			require.NoError(t, informerFactories.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc))
+
+			require.NoError(t, kc.syncPersistentVolumeClaim(TestNamespace+"/"+TestPVCName))
+
+			pv, err := kubeClient.CoreV1().PersistentVolumes().Get(context.TODO(), TestVolumeName, metav1.GetOptions{})
+			if !tc.expectPVCreated {
+				assert.True(t, datastore.ErrorIsNotFound(err))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, types.LonghornDriverName, pv.Spec.CSI.Driver)
+			assert.Equal(t, TestVolumeName, pv.Spec.CSI.VolumeHandle)
+			assert.Equal(t, TestStorageClassName, pv.Spec.StorageClassName)
+			assert.Equal(t, defaultRecreatedPVFSType, pv.Spec.CSI.FSType)
+			require.NotNil(t, pv.Spec.ClaimRef)
+			assert.Equal(t, TestNamespace, pv.Spec.ClaimRef.Namespace)
+			assert.Equal(t, TestPVCName, pv.Spec.ClaimRef.Name)
+			assert.Equal(t, pvc.UID, pv.Spec.ClaimRef.UID)
+		})
+	}
+}
+
+func TestAnnotateVolumeName(t *testing.T) {
+	tests := map[string]struct {
+		pv     *corev1.PersistentVolume
+		volume *longhorn.Volume
+
+		expectedAnnotation string
+	}{
+		"annotate pvc of longhorn pv": {
+			pv:                 newPV(),
+			volume:             newVolume(TestVolumeName, 2),
+			expectedAnnotation: TestVolumeName,
+		},
+		"pv of another driver": {
+			pv: func() *corev1.PersistentVolume {
+				pv := newPV()
+				pv.Spec.CSI.Driver = "another.csi.driver"
+				return pv
+			}(),
+			volume: newVolume(TestVolumeName, 2),
+		},
+		"pv not found": {
+			volume: newVolume(TestVolumeName, 2),
+		},
+		"volume not found": {
+			pv: newPV(),
+		},
+		"volume owned by another node": {
+			pv: newPV(),
+			volume: func() *longhorn.Volume {
+				v := newVolume(TestVolumeName, 2)
+				v.Status.OwnerID = TestNode2
+				return v
+			}(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset() // nolint: staticcheck
+			lhClient := lhfake.NewSimpleClientset() // nolint: staticcheck
+			extensionsClient := apiextensionsfake.NewSimpleClientset()
+			informerFactories := util.NewInformerFactories(TestNamespace, kubeClient, lhClient, controller.NoResyncPeriodFunc())
+
+			ds := datastore.NewDataStore(TestNamespace, lhClient, kubeClient, extensionsClient, informerFactories)
+			kc, err := NewKubernetesPVCController(logrus.StandardLogger(), ds, scheme.Scheme, kubeClient, TestNode1)
+			require.NoError(t, err)
+			kc.eventRecorder = record.NewFakeRecorder(100)
+
+			if tc.pv != nil {
+				pv, err := kubeClient.CoreV1().PersistentVolumes().Create(context.TODO(), tc.pv, metav1.CreateOptions{})
+				require.NoError(t, err)
+				require.NoError(t, informerFactories.KubeInformerFactory.Core().V1().PersistentVolumes().Informer().GetIndexer().Add(pv))
+			}
+
+			if tc.volume != nil {
+				v, err := lhClient.LonghornV1beta2().Volumes(TestNamespace).Create(context.TODO(), tc.volume, metav1.CreateOptions{})
+				require.NoError(t, err)
+				require.NoError(t, informerFactories.LhInformerFactory.Longhorn().V1beta2().Volumes().Informer().GetIndexer().Add(v))
+			}
+
+			pvc := newPVC()
+			pvc.Namespace = TestNamespace
+			pvc.Status.Phase = corev1.ClaimBound
+			pvc, err = kubeClient.CoreV1().PersistentVolumeClaims(TestNamespace).Create(context.TODO(), pvc, metav1.CreateOptions{})
+			require.NoError(t, err)
+			require.NoError(t, informerFactories.KubeInformerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(pvc))
+
+			require.NoError(t, kc.syncPersistentVolumeClaim(TestNamespace+"/"+TestPVCName))
+
+			pvc, err = kubeClient.CoreV1().PersistentVolumeClaims(TestNamespace).Get(context.TODO(), TestPVCName, metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAnnotation, pvc.Annotations[types.PVCAnnotationLonghornVolumeName])
+		})
+	}
+}
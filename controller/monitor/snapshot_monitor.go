@@ -79,6 +79,11 @@ type SnapshotMonitor struct {
 	existingDataIntegrityCronJobs map[longhorn.DataEngineType]string
 	scheduledJobs                 map[longhorn.DataEngineType]*gocron.Job
 
+	// volumeCheckScheduler runs the checks of the volumes with their own data integrity cron job,
+	// which are tagged by the volume names and skipped by the checks of the data engines.
+	volumeCheckScheduler                *gocron.Scheduler
+	existingVolumeDataIntegrityCronJobs map[string]string
+
 	syncCallback func(key string)
 
 	proxyConnCounter util.Counter
@@ -111,6 +116,9 @@ func NewSnapshotMonitor(logger logrus.FieldLogger, ds *datastore.DataStore, node
 		existingDataIntegrityCronJobs: make(map[longhorn.DataEngineType]string),
 		scheduledJobs:                 make(map[longhorn.DataEngineType]*gocron.Job),
 
+		volumeCheckScheduler:                gocron.NewScheduler(time.Local),
+		existingVolumeDataIntegrityCronJobs: make(map[string]string),
+
 		syncCallback:     syncCallback,
 		proxyConnCounter: util.NewAtomicCounter(),
 	}
@@ -122,6 +130,7 @@ func NewSnapshotMonitor(logger logrus.FieldLogger, ds *datastore.DataStore, node
 		m.checkSchedulers[dataEngine] = gocron.NewScheduler(time.Local)
 		m.checkSchedulers[dataEngine].SingletonModeAll()
 	}
+	m.volumeCheckScheduler.SingletonModeAll()
 
 	go m.Start()
 
@@ -177,12 +186,41 @@ func (m *SnapshotMonitor) checkSnapshots(dataEngine longhorn.DataEngineType) {
 
 	for _, engine := range engines {
 		if engine.Spec.DataEngine == dataEngine {
+			if m.hasVolumeDataIntegrityCronJob(engine.Spec.VolumeName) {
+				continue
+			}
 			m.logger.WithField("monitor", monitorName).Infof("Populating engine %v snapshots for engine type %v", engine.Name, dataEngine)
 			m.populateEngineSnapshots(engine)
 		}
 	}
 }
 
+// checkVolumeSnapshots checks the snapshots of a volume with its own data integrity cron job.
+func (m *SnapshotMonitor) checkVolumeSnapshots(volumeName string) {
+	m.logger.WithField("monitor", monitorName).Infof("Starting checking snapshots of volume %v", volumeName)
+	defer m.logger.WithField("monitor", monitorName).Infof("Finished checking snapshots of volume %v", volumeName)
+
+	engines, err := m.ds.ListEnginesByNodeRO(m.nodeName)
+	if err != nil {
+		m.logger.WithField("monitor", monitorName).WithError(err).Errorf("failed to list engines on node %v", m.nodeName)
+		return
+	}
+
+	for _, engine := range engines {
+		if engine.Spec.VolumeName == volumeName {
+			m.populateEngineSnapshots(engine)
+		}
+	}
+}
+
+func (m *SnapshotMonitor) hasVolumeDataIntegrityCronJob(volumeName string) bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	_, ok := m.existingVolumeDataIntegrityCronJobs[volumeName]
+	return ok
+}
+
 func (m *SnapshotMonitor) populateEngineSnapshots(engine *longhorn.Engine) {
 	snapshots := engine.Status.Snapshots
 	for _, snapshot := range snapshots {
@@ -263,6 +301,7 @@ func (m *SnapshotMonitor) Stop() {
 	} {
 		m.checkSchedulers[dataEngine].Stop()
 	}
+	m.volumeCheckScheduler.Stop()
 	m.quit()
 }
 
@@ -271,6 +310,10 @@ func (m *SnapshotMonitor) RunOnce() error {
 }
 
 func (m *SnapshotMonitor) UpdateConfiguration(map[string]interface{}) error {
+	if err := m.updateVolumeDataIntegrityCronJobs(); err != nil {
+		return err
+	}
+
 	dataIntegrityCronJobs := make(map[longhorn.DataEngineType]string)
 
 	for _, dataEngine := range []longhorn.DataEngineType{
@@ -332,6 +375,60 @@ func (m *SnapshotMonitor) UpdateConfiguration(map[string]interface{}) error {
 	return nil
 }
 
+// updateVolumeDataIntegrityCronJobs schedules the checks of the volumes on this node
+// that have their own data integrity cron job, instead of the one of the data engine.
+func (m *SnapshotMonitor) updateVolumeDataIntegrityCronJobs() error {
+	engines, err := m.ds.ListEnginesByNodeRO(m.nodeName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list engines on node %v", m.nodeName)
+	}
+
+	volumeDataIntegrityCronJobs := make(map[string]string)
+	for _, engine := range engines {
+		volume, err := m.ds.GetVolumeRO(engine.Spec.VolumeName)
+		if err != nil {
+			if datastore.ErrorIsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to get volume %v", engine.Spec.VolumeName)
+		}
+		if volume.Spec.SnapshotDataIntegrityCronJob != "" {
+			volumeDataIntegrityCronJobs[volume.Name] = volume.Spec.SnapshotDataIntegrityCronJob
+		}
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	for volumeName, cronJob := range m.existingVolumeDataIntegrityCronJobs {
+		if volumeDataIntegrityCronJobs[volumeName] == cronJob {
+			continue
+		}
+		if err := m.volumeCheckScheduler.RemoveByTag(volumeName); err != nil && !errors.Is(err, gocron.ErrJobNotFoundWithTag) {
+			return errors.Wrapf(err, "failed to remove snapshot check job of volume %v", volumeName)
+		}
+		delete(m.existingVolumeDataIntegrityCronJobs, volumeName)
+	}
+
+	for volumeName, cronJob := range volumeDataIntegrityCronJobs {
+		if _, ok := m.existingVolumeDataIntegrityCronJobs[volumeName]; ok {
+			continue
+		}
+		if _, err := m.volumeCheckScheduler.Cron(cronJob).Tag(volumeName).Do(m.checkVolumeSnapshots, volumeName); err != nil {
+			return errors.Wrapf(err, "failed to schedule snapshot check job of volume %v", volumeName)
+		}
+		m.existingVolumeDataIntegrityCronJobs[volumeName] = cronJob
+
+		m.logger.WithField("monitor", monitorName).Infof("Cron is set to %v for volume %v", cronJob, volumeName)
+	}
+
+	if len(m.existingVolumeDataIntegrityCronJobs) > 0 {
+		m.volumeCheckScheduler.StartAsync()
+	}
+
+	return nil
+}
+
 func (m *SnapshotMonitor) GetCollectedData() (interface{}, error) {
 	m.RLock()
 	defer m.RUnlock()
@@ -406,7 +406,7 @@ func (nc *NodeController) syncNode(key string) (err error) {
 				string(longhorn.NodeConditionReasonKubernetesNodeGone),
 				fmt.Sprintf("Kubernetes node missing: node %v has been removed from the cluster", node.Name),
 				nc.eventRecorder, node, corev1.EventTypeWarning)
-			return nil
+			return nc.syncNodeDownPodDeletionStatus(node)
 		}
 		return err
 	}
@@ -415,6 +415,11 @@ func (nc *NodeController) syncNode(key string) (err error) {
 		return err
 	}
 
+	// The manager on a down node is not running, so every manager reports it before the ownership check.
+	if err = nc.syncNodeDownPodDeletionStatus(node); err != nil {
+		return err
+	}
+
 	// Set any RWX leases to non-delinquent if owned by not-ready node.
 	// Usefulness of delinquent state has passed.
 	if err = nc.clearDelinquentLeasesIfNodeNotReady(node); err != nil {
@@ -2061,6 +2066,46 @@ func (nc *NodeController) SetSchedulableCondition(node *longhorn.Node, kubeNode
 	}
 }
 
+// syncNodeDownPodDeletionStatus reports whether the node is down, and how many terminating pods on it are eligible
+// for the force deletion by the node down pod deletion policy.
+func (nc *NodeController) syncNodeDownPodDeletionStatus(node *longhorn.Node) error {
+	status := longhorn.NodeDownPodDeletionStatus{}
+	defer func() {
+		node.Status.NodeDownPodDeletion = status
+	}()
+
+	if !datastore.IsNodeDown(node) {
+		return nil
+	}
+	status.Down = true
+
+	deletionPolicy := types.NodeDownPodDeletionPolicyDoNothing
+	if deletionSetting, err := nc.ds.GetSettingValueExisted(types.SettingNameNodeDownPodDeletionPolicy); err == nil {
+		deletionPolicy = types.NodeDownPodDeletionPolicy(deletionSetting)
+	}
+	if deletionPolicy == types.NodeDownPodDeletionPolicyDoNothing {
+		return nil
+	}
+
+	pods, err := nc.ds.ListPodsRO("")
+	if err != nil {
+		return errors.Wrapf(err, "failed to list pods for the node down pod deletion status of node %v", node.Name)
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != node.Name || pod.DeletionTimestamp == nil || isCSIPluginPod(pod) {
+			continue
+		}
+		owned, err := isPodOwnedByDeletionPolicy(nc.ds, pod, deletionPolicy)
+		if err != nil {
+			return errors.Wrapf(err, "failed to evaluate pod %v for the node down pod deletion status of node %v", pod.Name, node.Name)
+		}
+		if owned {
+			status.PendingPodForceDeletions++
+		}
+	}
+	return nil
+}
+
 func (nc *NodeController) clearDelinquentLeasesIfNodeNotReady(node *longhorn.Node) error {
 	enabled, err := nc.ds.GetSettingAsBool(types.SettingNameRWXVolumeFastFailover)
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	. "gopkg.in/check.v1"
@@ -396,6 +397,89 @@ func (s *NodeControllerSuite) TestKubeNodeDown(c *C) {
 	s.checkOrphans(c, expectation)
 }
 
+func (s *NodeControllerSuite) TestNodeDownPodDeletionStatus(c *C) {
+	var err error
+
+	terminatingPodOnNode1 := newTestTerminatingPod(TestNode1, -time.Minute)
+	terminatingPodOnNode1.Name = "test-terminating-pod-1"
+	terminatingPodOnNode2 := newTestTerminatingPod(TestNode2, -time.Minute)
+	terminatingPodOnNode2.Name = "test-terminating-pod-2"
+	unownedTerminatingPodOnNode2 := newTestTerminatingPod(TestNode2, -time.Minute)
+	unownedTerminatingPodOnNode2.Name = "test-unowned-terminating-pod-2"
+	unownedTerminatingPodOnNode2.OwnerReferences = nil
+	runningPodOnNode2 := newTestTerminatingPod(TestNode2, 0)
+	runningPodOnNode2.Name = "test-running-pod-2"
+	runningPodOnNode2.DeletionTimestamp = nil
+
+	fixture := &NodeControllerFixture{
+		lhNodes: map[string]*longhorn.Node{
+			TestNode1: newNode(TestNode1, TestNamespace, true, longhorn.ConditionStatusUnknown, ""),
+			TestNode2: newNode(TestNode2, TestNamespace, true, longhorn.ConditionStatusUnknown, ""),
+		},
+		lhSettings: map[string]*longhorn.Setting{
+			string(types.SettingNameDefaultInstanceManagerImage): newDefaultInstanceManagerImageSetting(),
+			string(types.SettingNameNodeDownPodDeletionPolicy):   newSetting(string(types.SettingNameNodeDownPodDeletionPolicy), string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod)),
+		},
+		lhInstanceManagers: map[string]*longhorn.InstanceManager{
+			TestInstanceManagerName: DefaultInstanceManagerTestNode1,
+		},
+		lhOrphans: map[string]*longhorn.Orphan{
+			DefaultOrphanTestNode1.Name: DefaultOrphanTestNode1,
+		},
+		pods: map[string]*corev1.Pod{
+			TestDaemon1:                       newDaemonPod(corev1.PodRunning, TestDaemon1, TestNamespace, TestNode1, TestIP1, &MountPropagationBidirectional),
+			TestDaemon2:                       newDaemonPod(corev1.PodRunning, TestDaemon2, TestNamespace, TestNode2, TestIP2, &MountPropagationBidirectional),
+			terminatingPodOnNode1.Name:        terminatingPodOnNode1,
+			terminatingPodOnNode2.Name:        terminatingPodOnNode2,
+			unownedTerminatingPodOnNode2.Name: unownedTerminatingPodOnNode2,
+			runningPodOnNode2.Name:            runningPodOnNode2,
+		},
+		nodes: map[string]*corev1.Node{
+			TestNode1: newKubernetesNode(
+				TestNode1,
+				corev1.ConditionTrue,
+				corev1.ConditionFalse,
+				corev1.ConditionFalse,
+				corev1.ConditionFalse,
+				corev1.ConditionFalse,
+				corev1.ConditionTrue,
+			),
+			TestNode2: newKubernetesNode(
+				TestNode2,
+				corev1.ConditionFalse,
+				corev1.ConditionFalse,
+				corev1.ConditionFalse,
+				corev1.ConditionFalse,
+				corev1.ConditionFalse,
+				corev1.ConditionTrue,
+			),
+		},
+	}
+
+	expectedStatus := map[string]longhorn.NodeDownPodDeletionStatus{
+		TestNode1: {},
+		TestNode2: {Down: true, PendingPodForceDeletions: 1},
+	}
+
+	s.initTest(c, fixture)
+
+	for _, node := range fixture.lhNodes {
+		if s.controller.controllerID == node.Name {
+			err = s.controller.diskMonitor.RunOnce()
+			c.Assert(err, IsNil)
+			err = s.controller.environmentCheckMonitor.RunOnce()
+			c.Assert(err, IsNil)
+		}
+
+		err = s.controller.syncNode(getKey(node, c))
+		c.Assert(err, IsNil)
+
+		n, err := s.lhClient.LonghornV1beta2().Nodes(TestNamespace).Get(context.TODO(), node.Name, metav1.GetOptions{})
+		c.Assert(err, IsNil)
+		c.Assert(n.Status.NodeDownPodDeletion, DeepEquals, expectedStatus[node.Name])
+	}
+}
+
 func (s *NodeControllerSuite) TestKubeNodePressure(c *C) {
 	var err error
 
@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+// NodeDownSignal reports whether a node is down from a single source, like its Ready condition or its heartbeat.
+//
+// This is the extension point for the bespoke node down definitions. The pod controller combines the signals named
+// by the node down signals setting with the operator of the node down signals operator setting. A manager compiled
+// with custom signals registers them by RegisterNodeDownSignal before the controllers are created, and they can be
+// named in the setting like the built-in ones.
+type NodeDownSignal interface {
+	// Name identifies the signal in the node down signals setting.
+	Name() string
+	// IsNodeDown returns whether the node is down at now. An error requeues the pods of the node to evaluate it again.
+	IsNodeDown(nodeID string, now time.Time) (bool, error)
+}
+
+var (
+	nodeDownSignalsLock sync.RWMutex
+	nodeDownSignals     []NodeDownSignal
+)
+
+// RegisterNodeDownSignal adds a custom signal available to the pod controllers created afterwards.
+func RegisterNodeDownSignal(signal NodeDownSignal) {
+	nodeDownSignalsLock.Lock()
+	defer nodeDownSignalsLock.Unlock()
+
+	nodeDownSignals = append(nodeDownSignals, signal)
+}
+
+func getRegisteredNodeDownSignals() []NodeDownSignal {
+	nodeDownSignalsLock.RLock()
+	defer nodeDownSignalsLock.RUnlock()
+
+	return append([]NodeDownSignal{}, nodeDownSignals...)
+}
+
+// nodeDownEvaluator decides whether a node is down by combining the signals of the node down signals setting.
+type nodeDownEvaluator struct {
+	ds      *datastore.DataStore
+	signals map[types.NodeDownSignal]NodeDownSignal
+}
+
+func newNodeDownEvaluator(ds *datastore.DataStore, kubeClient clientset.Interface) *nodeDownEvaluator {
+	e := &nodeDownEvaluator{
+		ds:      ds,
+		signals: map[types.NodeDownSignal]NodeDownSignal{},
+	}
+	builtInSignals := []NodeDownSignal{
+		&readyConditionNodeDownSignal{ds: ds},
+		&heartbeatNodeDownSignal{ds: ds, kubeClient: kubeClient},
+		&taintNodeDownSignal{ds: ds},
+		&instanceManagerNodeDownSignal{ds: ds},
+	}
+	// The custom signals cannot replace the built-in ones of the same name.
+	for _, signal := range append(getRegisteredNodeDownSignals(), builtInSignals...) {
+		e.signals[types.NodeDownSignal(signal.Name())] = signal
+	}
+	return e
+}
+
+// IsNodeDownOrDeleted returns whether the node is deleted, or down by the combination of the signals.
+func (e *nodeDownEvaluator) IsNodeDownOrDeleted(nodeID string) (bool, error) {
+	return e.evaluate(nodeID, time.Now())
+}
+
+func (e *nodeDownEvaluator) evaluate(nodeID string, now time.Time) (bool, error) {
+	if _, err := e.ds.GetNodeRO(nodeID); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	signalsSetting, err := e.ds.GetSettingValueExisted(types.SettingNameNodeDownSignals)
+	if err != nil {
+		return false, err
+	}
+	signalNames, err := types.UnmarshalNodeDownSignals(signalsSetting)
+	if err != nil {
+		return false, err
+	}
+	operator, err := e.ds.GetSettingValueExisted(types.SettingNameNodeDownSignalsOperator)
+	if err != nil {
+		return false, err
+	}
+
+	for _, name := range signalNames {
+		signal, ok := e.signals[name]
+		if !ok {
+			return false, fmt.Errorf("unknown node down signal %v", name)
+		}
+		down, err := signal.IsNodeDown(nodeID, now)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to evaluate node down signal %v", name)
+		}
+		switch types.NodeDownSignalsOperator(operator) {
+		case types.NodeDownSignalsOperatorAll:
+			if !down {
+				return false, nil
+			}
+		default:
+			if down {
+				return true, nil
+			}
+		}
+	}
+	return types.NodeDownSignalsOperator(operator) == types.NodeDownSignalsOperatorAll, nil
+}
+
+// readyConditionNodeDownSignal reports the node down by the Ready condition of the Longhorn node.
+type readyConditionNodeDownSignal struct {
+	ds *datastore.DataStore
+}
+
+func (s *readyConditionNodeDownSignal) Name() string {
+	return string(types.NodeDownSignalReadyCondition)
+}
+
+func (s *readyConditionNodeDownSignal) IsNodeDown(nodeID string, now time.Time) (bool, error) {
+	return s.ds.IsNodeDownOrDeleted(nodeID)
+}
+
+// heartbeatNodeDownSignal reports the node down once the kubelet stops renewing the lease of the node.
+type heartbeatNodeDownSignal struct {
+	ds         *datastore.DataStore
+	kubeClient clientset.Interface
+}
+
+func (s *heartbeatNodeDownSignal) Name() string {
+	return string(types.NodeDownSignalHeartbeat)
+}
+
+func (s *heartbeatNodeDownSignal) IsNodeDown(nodeID string, now time.Time) (bool, error) {
+	timeout, err := s.ds.GetSettingAsInt(types.SettingNameNodeDownHeartbeatTimeout)
+	if err != nil {
+		return false, err
+	}
+
+	// The node leases are not in the namespace watched by the datastore, and are only read once per node down
+	// cache TTL.
+	lease, err := s.kubeClient.CoordinationV1().Leases(corev1.NamespaceNodeLease).Get(context.TODO(), nodeID, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if lease.Spec.RenewTime == nil {
+		return true, nil
+	}
+	return now.Sub(lease.Spec.RenewTime.Time) > time.Duration(timeout)*time.Second, nil
+}
+
+// taintNodeDownSignal reports the node down once the Kubernetes node has the taint of the node down taint key.
+type taintNodeDownSignal struct {
+	ds *datastore.DataStore
+}
+
+func (s *taintNodeDownSignal) Name() string {
+	return string(types.NodeDownSignalTaint)
+}
+
+func (s *taintNodeDownSignal) IsNodeDown(nodeID string, now time.Time) (bool, error) {
+	taintKeySetting, err := s.ds.GetSettingWithAutoFillingRO(types.SettingNameNodeDownTaintKey)
+	if err != nil {
+		return false, err
+	}
+	taintKey := taintKeySetting.Value
+	if taintKey == "" {
+		return false, nil
+	}
+
+	kubeNode, err := s.ds.GetKubernetesNodeRO(nodeID)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	for _, taint := range kubeNode.Spec.Taints {
+		if taint.Key == taintKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// instanceManagerNodeDownSignal reports the node down once none of its instance manager pods is running and ready.
+type instanceManagerNodeDownSignal struct {
+	ds *datastore.DataStore
+}
+
+func (s *instanceManagerNodeDownSignal) Name() string {
+	return string(types.NodeDownSignalInstanceManager)
+}
+
+func (s *instanceManagerNodeDownSignal) IsNodeDown(nodeID string, now time.Time) (bool, error) {
+	pods, err := s.ds.ListInstanceManagerPods()
+	if err != nil {
+		return false, err
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != nodeID || pod.Status.Phase != corev1.PodRunning {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
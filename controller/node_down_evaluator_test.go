@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/kubernetes/pkg/controller"
+
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	lhfake "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned/fake"
+)
+
+// alwaysDownNodeDownSignal is a custom signal reporting every node down.
+type alwaysDownNodeDownSignal struct{}
+
+func (s *alwaysDownNodeDownSignal) Name() string {
+	return "test-always-down"
+}
+
+func (s *alwaysDownNodeDownSignal) IsNodeDown(nodeID string, now time.Time) (bool, error) {
+	return true, nil
+}
+
+func newTestNodeDownEvaluator(t *testing.T, settings map[types.SettingName]string, objs ...runtime.Object) *nodeDownEvaluator {
+	kubeClient := fake.NewSimpleClientset() // nolint: staticcheck
+	lhClient := lhfake.NewSimpleClientset() // nolint: staticcheck
+	extensionsClient := apiextensionsfake.NewSimpleClientset()
+	informerFactories := util.NewInformerFactories(TestNamespace, kubeClient, lhClient, controller.NoResyncPeriodFunc())
+	ds := datastore.NewDataStore(TestNamespace, lhClient, kubeClient, extensionsClient, informerFactories)
+
+	lhInformers := informerFactories.LhInformerFactory.Longhorn().V1beta2()
+	kubeInformers := informerFactories.KubeInformerFactory.Core().V1()
+	for name, value := range settings {
+		require.NoError(t, lhInformers.Settings().Informer().GetIndexer().Add(newSetting(string(name), value)))
+	}
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *longhorn.Node:
+			require.NoError(t, lhInformers.Nodes().Informer().GetIndexer().Add(o))
+		case *corev1.Node:
+			require.NoError(t, kubeInformers.Nodes().Informer().GetIndexer().Add(o))
+		case *corev1.Pod:
+			require.NoError(t, kubeInformers.Pods().Informer().GetIndexer().Add(o))
+		default:
+			require.NoError(t, kubeClient.Tracker().Add(obj))
+		}
+	}
+	return newNodeDownEvaluator(ds, kubeClient)
+}
+
+func newTestNodeLease(nodeID string, renewedSince time.Duration) *coordinationv1.Lease {
+	renewTime := metav1.NewMicroTime(time.Now().Add(-renewedSince))
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nodeID,
+			Namespace: corev1.NamespaceNodeLease,
+		},
+		Spec: coordinationv1.LeaseSpec{
+			RenewTime: &renewTime,
+		},
+	}
+}
+
+func newTestTaintedKubeNode(nodeID string, taintKeys ...string) *corev1.Node {
+	kubeNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: nodeID,
+		},
+	}
+	for _, key := range taintKeys {
+		kubeNode.Spec.Taints = append(kubeNode.Spec.Taints, corev1.Taint{Key: key, Effect: corev1.TaintEffectNoExecute})
+	}
+	return kubeNode
+}
+
+func newTestInstanceManagerPod(nodeID string, ready bool) *corev1.Pod {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "instance-manager-" + nodeID,
+			Namespace: TestNamespace,
+			Labels:    types.GetInstanceManagerLabels(nodeID, "", longhorn.InstanceManagerTypeAllInOne, longhorn.DataEngineTypeV1),
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeID,
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: readyStatus},
+			},
+		},
+	}
+}
+
+func TestNodeDownEvaluator(t *testing.T) {
+	RegisterNodeDownSignal(&alwaysDownNodeDownSignal{})
+
+	readyNode := newNode(TestNode1, TestNamespace, true, longhorn.ConditionStatusTrue, "")
+	notReadyNode := newNode(TestNode1, TestNamespace, true, longhorn.ConditionStatusFalse, string(longhorn.NodeConditionReasonKubernetesNodeNotReady))
+
+	tests := map[string]struct {
+		signals  string
+		operator types.NodeDownSignalsOperator
+		taintKey *string
+		objs     []runtime.Object
+
+		expectDown  bool
+		expectError bool
+	}{
+		"default ready condition down": {
+			objs:       []runtime.Object{notReadyNode},
+			expectDown: true,
+		},
+		"default ready condition up": {
+			objs: []runtime.Object{readyNode},
+		},
+		"longhorn node deleted": {
+			signals:    string(types.NodeDownSignalTaint),
+			objs:       []runtime.Object{newTestTaintedKubeNode(TestNode1)},
+			expectDown: true,
+		},
+		"heartbeat stale": {
+			signals:    string(types.NodeDownSignalHeartbeat),
+			objs:       []runtime.Object{readyNode, newTestNodeLease(TestNode1, time.Minute)},
+			expectDown: true,
+		},
+		"heartbeat fresh": {
+			signals: string(types.NodeDownSignalHeartbeat),
+			objs:    []runtime.Object{readyNode, newTestNodeLease(TestNode1, time.Second)},
+		},
+		"heartbeat lease missing": {
+			signals:    string(types.NodeDownSignalHeartbeat),
+			objs:       []runtime.Object{readyNode},
+			expectDown: true,
+		},
+		"default taint": {
+			signals:    string(types.NodeDownSignalTaint),
+			objs:       []runtime.Object{readyNode, newTestTaintedKubeNode(TestNode1, corev1.TaintNodeOutOfService)},
+			expectDown: true,
+		},
+		"custom taint": {
+			signals:    string(types.NodeDownSignalTaint),
+			taintKey:   &[]string{"example.com/down"}[0],
+			objs:       []runtime.Object{readyNode, newTestTaintedKubeNode(TestNode1, corev1.TaintNodeOutOfService, "example.com/down")},
+			expectDown: true,
+		},
+		"taint missing": {
+			signals: string(types.NodeDownSignalTaint),
+			objs:    []runtime.Object{readyNode, newTestTaintedKubeNode(TestNode1, corev1.TaintNodeUnreachable)},
+		},
+		"taint key empty": {
+			signals:  string(types.NodeDownSignalTaint),
+			taintKey: &[]string{""}[0],
+			objs:     []runtime.Object{readyNode, newTestTaintedKubeNode(TestNode1, corev1.TaintNodeOutOfService)},
+		},
+		"instance manager ready": {
+			signals: string(types.NodeDownSignalInstanceManager),
+			objs:    []runtime.Object{readyNode, newTestInstanceManagerPod(TestNode1, true), newTestInstanceManagerPod(TestNode2, false)},
+		},
+		"instance manager not ready": {
+			signals:    string(types.NodeDownSignalInstanceManager),
+			objs:       []runtime.Object{readyNode, newTestInstanceManagerPod(TestNode1, false), newTestInstanceManagerPod(TestNode2, true)},
+			expectDown: true,
+		},
+		"any with one signal down": {
+			signals:    "ready-condition, heartbeat",
+			operator:   types.NodeDownSignalsOperatorAny,
+			objs:       []runtime.Object{readyNode, newTestNodeLease(TestNode1, time.Minute)},
+			expectDown: true,
+		},
+		"any with no signal down": {
+			signals:  "ready-condition,heartbeat",
+			operator: types.NodeDownSignalsOperatorAny,
+			objs:     []runtime.Object{readyNode, newTestNodeLease(TestNode1, time.Second)},
+		},
+		"all with one signal down": {
+			signals:  "ready-condition,heartbeat",
+			operator: types.NodeDownSignalsOperatorAll,
+			objs:     []runtime.Object{notReadyNode, newTestNodeLease(TestNode1, time.Second)},
+		},
+		"all with every signal down": {
+			signals:  "ready-condition,heartbeat,taint,instance-manager",
+			operator: types.NodeDownSignalsOperatorAll,
+			objs: []runtime.Object{notReadyNode, newTestNodeLease(TestNode1, time.Minute),
+				newTestTaintedKubeNode(TestNode1, corev1.TaintNodeOutOfService), newTestInstanceManagerPod(TestNode1, false)},
+			expectDown: true,
+		},
+		"custom signal": {
+			signals:    "ready-condition,test-always-down",
+			operator:   types.NodeDownSignalsOperatorAll,
+			objs:       []runtime.Object{notReadyNode},
+			expectDown: true,
+		},
+		"unknown signal": {
+			signals:     "unknown",
+			objs:        []runtime.Object{readyNode},
+			expectError: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			settings := map[types.SettingName]string{}
+			if tc.signals != "" {
+				settings[types.SettingNameNodeDownSignals] = tc.signals
+			}
+			if tc.operator != "" {
+				settings[types.SettingNameNodeDownSignalsOperator] = string(tc.operator)
+			}
+			if tc.taintKey != nil {
+				settings[types.SettingNameNodeDownTaintKey] = *tc.taintKey
+			}
+			e := newTestNodeDownEvaluator(t, settings, tc.objs...)
+
+			down, err := e.evaluate(TestNode1, time.Now())
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectDown, down)
+		})
+	}
+}
+
+func TestPodDeletionUsesNodeDownSignals(t *testing.T) {
+	pod := newTestTerminatingPod(TestNode1, -time.Minute)
+	settings := map[types.SettingName]string{
+		types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+		types.SettingNameNodeDownSignals:           string(types.NodeDownSignalTaint),
+	}
+	f := newTestKubernetesPodController(t, settings, pod,
+		newNode(TestNode1, TestNamespace, true, longhorn.ConditionStatusTrue, ""),
+		newTestTaintedKubeNode(TestNode1, corev1.TaintNodeOutOfService))
+
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode1, TestNamespace))
+	_, err := f.kubeClient.CoreV1().Pods(TestNamespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	assert.True(t, datastore.ErrorIsNotFound(err))
+
+	require.Len(t, f.fakeRecorder.Events, 1)
+	assert.Contains(t, <-f.fakeRecorder.Events, constant.EventReasonForceDeleted)
+}
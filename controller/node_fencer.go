@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+// nodeFencingRequest is sent to the fencing endpoint to power fence a down node.
+type nodeFencingRequest struct {
+	Node string `json:"node"`
+}
+
+// nodeFencingResponse is the response of the fencing endpoint.
+type nodeFencingResponse struct {
+	Fenced bool   `json:"fenced"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// nodeFencer asks the fencing endpoint to power fence the down nodes before their pods are force deleted,
+// and remembers the fenced nodes so that the endpoint is asked once per node until it comes back.
+type nodeFencer struct {
+	client *http.Client
+
+	lock   sync.Mutex
+	fenced map[string]time.Time
+}
+
+func newNodeFencer(client *http.Client) *nodeFencer {
+	return &nodeFencer{
+		client: client,
+		fenced: map[string]time.Time{},
+	}
+}
+
+// Fence returns whether the node is fenced and the reason of the decision. The fencing fails closed: the node is
+// not considered fenced unless the endpoint confirms it in time. The endpoint is asked without holding the lock,
+// since fencing a node may take a while, so it must tolerate concurrent requests for the same node.
+func (f *nodeFencer) Fence(endpoint string, timeout time.Duration, nodeID string, now time.Time) (bool, string) {
+	if fencedAt, ok := f.getFencedAt(nodeID); ok {
+		return true, fmt.Sprintf("node fenced at %v", fencedAt.UTC().Format(time.RFC3339))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	response, err := requestNodeFencing(ctx, f.client, endpoint, &nodeFencingRequest{Node: nodeID})
+	if err != nil {
+		return false, fmt.Sprintf("fencing endpoint failed: %v", err)
+	}
+	if !response.Fenced {
+		return false, fmt.Sprintf("fencing not confirmed by fencing endpoint: %v", response.Reason)
+	}
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.fenced[nodeID] = now
+	return true, response.Reason
+}
+
+func (f *nodeFencer) getFencedAt(nodeID string) (time.Time, bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	fencedAt, ok := f.fenced[nodeID]
+	return fencedAt, ok
+}
+
+// Forget drops the fencing of the node, so that it is fenced again the next time it is down.
+func (f *nodeFencer) Forget(nodeID string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	delete(f.fenced, nodeID)
+}
+
+func requestNodeFencing(ctx context.Context, client *http.Client, endpoint string, request *nodeFencingRequest) (*nodeFencingResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			logrus.WithError(errClose).Warn("Failed to close the response body of the fencing endpoint")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	response := &nodeFencingResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode the response")
+	}
+	return response, nil
+}
+
+// fenceNode asks the fencing endpoint, if configured, to power fence the down node before its pods are force deleted.
+func (kc *KubernetesPodController) fenceNode(nodeID string) (fenced bool, reason string, err error) {
+	endpointSetting, err := kc.ds.GetSettingWithAutoFillingRO(types.SettingNameNodeDownPodDeletionFencingEndpoint)
+	if err != nil {
+		return false, "", err
+	}
+	endpoint := endpointSetting.Value
+	if endpoint == "" {
+		return true, "", nil
+	}
+
+	timeout, err := kc.ds.GetSettingAsInt(types.SettingNameNodeDownPodDeletionFencingTimeout)
+	if err != nil {
+		return false, "", err
+	}
+
+	fenced, reason = kc.nodeFencer.Fence(endpoint, time.Duration(timeout)*time.Second, nodeID, time.Now())
+	return fenced, reason, nil
+}
+
+// describeNodeFencing returns how the down node would be fenced before its pods are force deleted, for the dry run.
+func (kc *KubernetesPodController) describeNodeFencing(nodeID string) string {
+	endpointSetting, err := kc.ds.GetSettingWithAutoFillingRO(types.SettingNameNodeDownPodDeletionFencingEndpoint)
+	if err != nil {
+		return fmt.Sprintf("fencing of node %v is unknown: %v", nodeID, err)
+	}
+	if endpointSetting.Value == "" {
+		return "no fencing endpoint is configured"
+	}
+	return fmt.Sprintf("node %v would be fenced by %v", nodeID, endpointSetting.Value)
+}
@@ -0,0 +1,196 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+func TestNodeFencerFence(t *testing.T) {
+	tests := map[string]struct {
+		handler      http.HandlerFunc
+		expectFenced bool
+	}{
+		"fenced": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(&nodeFencingResponse{Fenced: true})
+			},
+			expectFenced: true,
+		},
+		"not fenced": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(&nodeFencingResponse{Fenced: false, Reason: "power controller unreachable"})
+			},
+		},
+		"unexpected status": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+		},
+		"invalid response": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("fenced"))
+			},
+		},
+		"timeout": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+				_ = json.NewEncoder(w).Encode(&nodeFencingResponse{Fenced: true})
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			fencer := newNodeFencer(server.Client())
+			fenced, reason := fencer.Fence(server.URL, 50*time.Millisecond, TestNode2, time.Now())
+			assert.Equal(t, tc.expectFenced, fenced, reason)
+		})
+	}
+}
+
+func TestNodeFencerRemembersFencedNodes(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		request := &nodeFencingRequest{}
+		if err := json.NewDecoder(r.Body).Decode(request); err != nil || request.Node != TestNode2 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&nodeFencingResponse{Fenced: true})
+	}))
+	defer server.Close()
+
+	fencer := newNodeFencer(server.Client())
+	now := time.Now()
+
+	fenced, _ := fencer.Fence(server.URL, time.Second, TestNode2, now)
+	require.True(t, fenced)
+	fenced, _ = fencer.Fence(server.URL, time.Second, TestNode2, now)
+	require.True(t, fenced)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// A node forgotten once back is fenced again the next time it is down.
+	fencer.Forget(TestNode2)
+	fenced, _ = fencer.Fence(server.URL, time.Second, TestNode2, now)
+	require.True(t, fenced)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestPodDeletionWaitsForNodeFencing(t *testing.T) {
+	tests := map[string]struct {
+		fenced bool
+
+		expectDeleted bool
+		expectedEvent []string
+	}{
+		"fencing confirmed": {
+			fenced:        true,
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"fencing not confirmed": {
+			expectedEvent: []string{constant.EventReasonPodDeletionSkipped, "fencing not confirmed by fencing endpoint: power controller unreachable"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				_ = json.NewEncoder(w).Encode(&nodeFencingResponse{Fenced: tc.fenced, Reason: "power controller unreachable"})
+			}))
+			defer server.Close()
+
+			pod := newTestTerminatingPod(TestNode2, -time.Minute)
+			f := newTestKubernetesPodController(t, map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:          string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionFencingEndpoint: server.URL,
+			}, pod)
+
+			require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+			assert.Equal(t, int32(1), requests.Load())
+			_, err := f.kubeClient.CoreV1().Pods(TestNamespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+			if tc.expectDeleted {
+				assert.True(t, datastore.ErrorIsNotFound(err))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			require.Len(t, f.fakeRecorder.Events, 1)
+			event := <-f.fakeRecorder.Events
+			for _, expected := range tc.expectedEvent {
+				assert.Contains(t, event, expected)
+			}
+		})
+	}
+}
+
+func TestPodDeletionKeepsVolumeAttachmentsUntilFenced(t *testing.T) {
+	tests := map[string]struct {
+		handler http.HandlerFunc
+
+		expectVolumeAttachmentDeleted bool
+	}{
+		"fencing confirmed": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(&nodeFencingResponse{Fenced: true})
+			},
+			expectVolumeAttachmentDeleted: true,
+		},
+		"fencing not confirmed": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewEncoder(w).Encode(&nodeFencingResponse{Fenced: false, Reason: "power controller unreachable"})
+			},
+		},
+		"fencing failed": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			pod := newTestTerminatingPod(TestNode2, -time.Minute, "test-claim")
+			va := newTestVolumeAttachment(TestNode2, "test-claim-pv")
+			objs := append(newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"), pod, va)
+			f := newTestKubernetesPodController(t, map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:          string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionFencingEndpoint: server.URL,
+			}, objs...)
+
+			require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+			_, err := f.kubeClient.StorageV1().VolumeAttachments().Get(context.TODO(), va.Name, metav1.GetOptions{})
+			if tc.expectVolumeAttachmentDeleted {
+				assert.True(t, datastore.ErrorIsNotFound(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			// The pod is only deleted once its volume attachments are gone.
+			_, err = f.kubeClient.CoreV1().Pods(TestNamespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+		})
+	}
+}
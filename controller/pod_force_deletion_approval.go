@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+// podDeletionApprovalRequest is sent to the pod deletion approval webhook before force deleting a pod on a down node.
+type podDeletionApprovalRequest struct {
+	Pod       string `json:"pod"`
+	Namespace string `json:"namespace"`
+	UID       string `json:"uid"`
+	Node      string `json:"node"`
+	Policy    string `json:"policy"`
+	DryRun    bool   `json:"dryRun,omitempty"`
+}
+
+// podDeletionApprovalResponse is the response of the pod deletion approval webhook.
+type podDeletionApprovalResponse struct {
+	Approved bool   `json:"approved"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// checkPodDeletionApproval returns whether the webhook approves the force deletion and the reason of the decision.
+// If the webhook fails to respond with a decision in time, the deletion is approved only if failOpen is set.
+func checkPodDeletionApproval(client *http.Client, webhookURL string, timeout time.Duration, failOpen bool, request *podDeletionApprovalRequest) (bool, string) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	response, err := requestPodDeletionApproval(ctx, client, webhookURL, request)
+	if err != nil {
+		if failOpen {
+			return true, fmt.Sprintf("approval webhook failed and fail open is set: %v", err)
+		}
+		return false, fmt.Sprintf("approval webhook failed: %v", err)
+	}
+	if !response.Approved {
+		return false, fmt.Sprintf("denied by approval webhook: %v", response.Reason)
+	}
+	return true, response.Reason
+}
+
+func requestPodDeletionApproval(ctx context.Context, client *http.Client, webhookURL string, request *podDeletionApprovalRequest) (*podDeletionApprovalResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if errClose := resp.Body.Close(); errClose != nil {
+			logrus.WithError(errClose).Warn("Failed to close the response body of the approval webhook")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	response := &podDeletionApprovalResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode the response")
+	}
+	return response, nil
+}
+
+// isPodDeletionApproved asks the pod deletion approval webhook, if configured, whether the pod can be force deleted.
+// The request tells the webhook whether the pod is deleted or only evaluated by the dry run.
+func (kc *KubernetesPodController) isPodDeletionApproved(pod *corev1.Pod, nodeID string, deletionPolicy types.NodeDownPodDeletionPolicy, dryRun bool) (approved bool, reason string, err error) {
+	webhookURLSetting, err := kc.ds.GetSettingWithAutoFillingRO(types.SettingNameNodeDownPodDeletionApprovalWebhookURL)
+	if err != nil {
+		return false, "", err
+	}
+	webhookURL := webhookURLSetting.Value
+	if webhookURL == "" {
+		return true, "", nil
+	}
+
+	timeout, err := kc.ds.GetSettingAsInt(types.SettingNameNodeDownPodDeletionApprovalWebhookTimeout)
+	if err != nil {
+		return false, "", err
+	}
+	failOpen, err := kc.ds.GetSettingAsBool(types.SettingNameNodeDownPodDeletionApprovalWebhookFailOpen)
+	if err != nil {
+		return false, "", err
+	}
+
+	request := &podDeletionApprovalRequest{
+		Pod:       pod.Name,
+		Namespace: pod.Namespace,
+		UID:       string(pod.UID),
+		Node:      nodeID,
+		Policy:    string(deletionPolicy),
+		DryRun:    dryRun,
+	}
+	approved, reason = checkPodDeletionApproval(kc.approvalHTTPClient, webhookURL, time.Duration(timeout)*time.Second, failOpen, request)
+	return approved, reason, nil
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+func TestPodDeletionApproval(t *testing.T) {
+	request := &podDeletionApprovalRequest{
+		Pod:       "test-pod",
+		Namespace: TestNamespace,
+		UID:       "test-pod-uid",
+		Node:      TestNode1,
+		Policy:    string(types.NodeDownPodDeletionPolicyDeleteBothStatefulsetAndDeploymentPod),
+	}
+
+	newHandler := func(delay time.Duration, statusCode int, response *podDeletionApprovalResponse) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			received := &podDeletionApprovalRequest{}
+			if err := json.NewDecoder(r.Body).Decode(received); err != nil || *received != *request {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+			w.WriteHeader(statusCode)
+			if response != nil {
+				_ = json.NewEncoder(w).Encode(response)
+			}
+		}
+	}
+
+	tests := map[string]struct {
+		handler  http.HandlerFunc
+		timeout  time.Duration
+		failOpen bool
+
+		expectApproved bool
+	}{
+		"approve": {
+			handler:        newHandler(0, http.StatusOK, &podDeletionApprovalResponse{Approved: true}),
+			timeout:        time.Second,
+			expectApproved: true,
+		},
+		"deny": {
+			handler:  newHandler(0, http.StatusOK, &podDeletionApprovalResponse{Approved: false, Reason: "maintenance"}),
+			timeout:  time.Second,
+			failOpen: true,
+		},
+		"timeout with fail closed": {
+			handler: newHandler(time.Second, http.StatusOK, &podDeletionApprovalResponse{Approved: true}),
+			timeout: 50 * time.Millisecond,
+		},
+		"timeout with fail open": {
+			handler:        newHandler(time.Second, http.StatusOK, &podDeletionApprovalResponse{Approved: false}),
+			timeout:        50 * time.Millisecond,
+			failOpen:       true,
+			expectApproved: true,
+		},
+		"server error with fail closed": {
+			handler: newHandler(0, http.StatusInternalServerError, nil),
+			timeout: time.Second,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			approved, reason := checkPodDeletionApproval(server.Client(), server.URL, tc.timeout, tc.failOpen, request)
+			assert.Equal(t, tc.expectApproved, approved, reason)
+		})
+	}
+}
+
+func TestPodDeletionApprovalAfterRateLimit(t *testing.T) {
+	var approvals atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		approvals.Add(1)
+		_ = json.NewEncoder(w).Encode(&podDeletionApprovalResponse{Approved: true})
+	}))
+	defer server.Close()
+
+	firstPod := newTestTerminatingPod(TestNode2, -time.Minute)
+	firstPod.Name = "test-pod-1"
+	secondPod := newTestTerminatingPod(TestNode2, -time.Minute)
+	secondPod.Name = "test-pod-2"
+	f := newTestKubernetesPodController(t, map[types.SettingName]string{
+		types.SettingNameNodeDownPodDeletionPolicy:             string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+		types.SettingNameNodeDownPodDeletionNamespaceRateLimit: "1",
+		types.SettingNameNodeDownPodDeletionApprovalWebhookURL: server.URL,
+	}, firstPod, secondPod)
+
+	// The first deletion is approved and consumes the only token of the namespace.
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(firstPod, TestNode2, TestNamespace))
+	assert.Equal(t, int32(1), approvals.Load())
+	assert.Len(t, f.fakeRecorder.Events, 1)
+
+	// The rate limited deletion is requeued without asking the approval webhook.
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(secondPod, TestNode2, TestNamespace))
+	assert.Equal(t, int32(1), approvals.Load())
+	_, err := f.kubeClient.CoreV1().Pods(TestNamespace).Get(context.TODO(), secondPod.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestPodDeletionDeniedQuarantinesNode(t *testing.T) {
+	var approvals atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		approvals.Add(1)
+		_ = json.NewEncoder(w).Encode(&podDeletionApprovalResponse{Approved: false, Reason: "maintenance"})
+	}))
+	defer server.Close()
+
+	pod := newTestTerminatingPod(TestNode2, -time.Minute)
+	f := newTestKubernetesPodController(t, map[types.SettingName]string{
+		types.SettingNameNodeDownPodDeletionPolicy:             string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+		types.SettingNameNodeDownPodDeletionApprovalWebhookURL: server.URL,
+	}, pod)
+
+	// Each denial counts as a failure, and the node is quarantined once they reach the threshold.
+	for i := 0; i < forceDeletionQuarantineFailureThreshold; i++ {
+		require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+	}
+	assert.Equal(t, int32(forceDeletionQuarantineFailureThreshold), approvals.Load())
+	require.Len(t, f.fakeRecorder.Events, 2)
+	event := <-f.fakeRecorder.Events
+	assert.Contains(t, event, constant.EventReasonPodDeletionSkipped)
+	event = <-f.fakeRecorder.Events
+	assert.Contains(t, event, constant.EventReasonQuarantined)
+	assert.Contains(t, event, "denied by approval webhook: maintenance")
+	remaining, _ := f.kc.forceDeletionQuarantine.Remaining(TestNode2, time.Now())
+	assert.Greater(t, remaining, time.Duration(0))
+
+	// The quarantined node is not asked for approval again until the cooldown is over.
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+	assert.Equal(t, int32(forceDeletionQuarantineFailureThreshold), approvals.Load())
+	_, err := f.kubeClient.CoreV1().Pods(TestNamespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+func TestPodDeletionDeniedKeepsVolumeAttachments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(&podDeletionApprovalResponse{Approved: false, Reason: "maintenance"})
+	}))
+	defer server.Close()
+
+	pod := newTestTerminatingPod(TestNode2, -time.Minute, "test-claim")
+	va := newTestVolumeAttachment(TestNode2, "test-claim-pv")
+	objs := append(newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"), pod, va)
+	f := newTestKubernetesPodController(t, map[types.SettingName]string{
+		types.SettingNameNodeDownPodDeletionPolicy:             string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+		types.SettingNameNodeDownPodDeletionApprovalWebhookURL: server.URL,
+	}, objs...)
+
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+	_, err := f.kubeClient.StorageV1().VolumeAttachments().Get(context.TODO(), va.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	require.Len(t, f.fakeRecorder.Events, 1)
+	assert.Contains(t, <-f.fakeRecorder.Events, "denied by approval webhook: maintenance")
+}
@@ -0,0 +1,63 @@
+package controller
+
+import (
+	"sync"
+	"time"
+)
+
+// nodeDeletionBatcher bounds the force deletions in flight for each node, and spaces out the batches of them.
+type nodeDeletionBatcher struct {
+	lock sync.Mutex
+
+	// nodes are kept once seen, since there are at most as many as the nodes of the cluster
+	nodes map[string]*nodeDeletionBatchState
+}
+
+type nodeDeletionBatchState struct {
+	inFlight int
+	// started is the number of the deletions started in the current batch
+	started     int
+	nextBatchAt time.Time
+}
+
+func newNodeDeletionBatcher() *nodeDeletionBatcher {
+	return &nodeDeletionBatcher{
+		nodes: map[string]*nodeDeletionBatchState{},
+	}
+}
+
+// Acquire takes a slot of the current batch of the node at now, and returns the function giving back the slot once
+// the deletion is done. Otherwise, it returns how long the caller should wait before retrying: until the interval
+// after the last batch is over, or a while if size deletions are still in flight. A non-positive size disables batching.
+func (b *nodeDeletionBatcher) Acquire(node string, size int, interval time.Duration, now time.Time) (func(), time.Duration) {
+	if size <= 0 {
+		return func() {}, 0
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	state, ok := b.nodes[node]
+	if !ok {
+		state = &nodeDeletionBatchState{}
+		b.nodes[node] = state
+	}
+	if now.Before(state.nextBatchAt) {
+		return nil, state.nextBatchAt.Sub(now)
+	}
+	if state.inFlight >= size {
+		return nil, forceDeletionBatchRetryInterval
+	}
+
+	state.inFlight++
+	state.started++
+	if state.started >= size {
+		state.started = 0
+		state.nextBatchAt = now.Add(interval)
+	}
+	return func() {
+		b.lock.Lock()
+		defer b.lock.Unlock()
+		state.inFlight--
+	}, 0
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+func TestNodeDeletionBatcherInterval(t *testing.T) {
+	now := time.Now()
+	b := newNodeDeletionBatcher()
+
+	for i := 0; i < 2; i++ {
+		release, delay := b.Acquire(TestNode1, 2, time.Second, now)
+		require.Zero(t, delay)
+		release()
+	}
+	// The batch is full until the interval is over, while the batches of the other nodes are not.
+	_, delay := b.Acquire(TestNode1, 2, time.Second, now)
+	assert.Equal(t, time.Second, delay)
+	release, delay := b.Acquire(TestNode2, 2, time.Second, now)
+	assert.Zero(t, delay)
+	release()
+
+	release, delay = b.Acquire(TestNode1, 2, time.Second, now.Add(time.Second))
+	assert.Zero(t, delay)
+	release()
+
+	// Batching is disabled without a size.
+	for i := 0; i < 10; i++ {
+		_, delay = b.Acquire(TestNode1, 0, time.Second, now)
+		assert.Zero(t, delay)
+	}
+}
+
+func TestNodeDeletionBatcherBoundsConcurrency(t *testing.T) {
+	const (
+		pods      = 100
+		batchSize = 5
+	)
+	b := newNodeDeletionBatcher()
+
+	var inFlight, maxInFlight, deleted int32
+	var wg sync.WaitGroup
+	for i := 0; i < pods; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				release, delay := b.Acquire(TestNode1, batchSize, 0, time.Now())
+				if delay > 0 {
+					time.Sleep(time.Millisecond)
+					continue
+				}
+				current := atomic.AddInt32(&inFlight, 1)
+				for {
+					observed := atomic.LoadInt32(&maxInFlight)
+					if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&inFlight, -1)
+				atomic.AddInt32(&deleted, 1)
+				release()
+				return
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(pods), deleted)
+	assert.LessOrEqual(t, maxInFlight, int32(batchSize))
+}
+
+func TestPodDeletionBatchesOnNode(t *testing.T) {
+	const (
+		pods      = 100
+		batchSize = 10
+	)
+	objs := []runtime.Object{}
+	for i := 0; i < pods; i++ {
+		pod := newTestTerminatingPod(TestNode2, -time.Minute)
+		pod.Name = fmt.Sprintf("test-pod-%d", i)
+		pod.UID = k8stypes.UID(pod.Name)
+		objs = append(objs, pod)
+	}
+	f := newTestKubernetesPodController(t, map[types.SettingName]string{
+		types.SettingNameNodeDownPodDeletionPolicy:        string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+		types.SettingNameNodeDownPodDeletionBatchSize:     fmt.Sprint(batchSize),
+		types.SettingNameNodeDownPodDeletionBatchInterval: "60000",
+	}, objs...)
+
+	for _, obj := range objs {
+		require.NoError(t, f.kc.handlePodDeletionIfNodeDown(obj.(*corev1.Pod), TestNode2, TestNamespace))
+	}
+
+	// Only the first batch is deleted, the other pods wait for the interval after it.
+	remaining, err := f.kubeClient.CoreV1().Pods(TestNamespace).List(context.TODO(), metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, remaining.Items, pods-batchSize)
+}
@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+// podForceDeletionConfig is the force deletion configuration resolved for a pod, reported by the annotation of the pod.
+type podForceDeletionConfig struct {
+	Policy types.NodeDownPodDeletionPolicy `json:"policy"`
+	// DisabledBy is the persistent volume opting the pod out of the force deletion.
+	DisabledBy string `json:"disabledBy,omitempty"`
+	// PriorityClassGracePeriodSeconds replaces the termination grace period of the pod, by its priority class.
+	PriorityClassGracePeriodSeconds *int64 `json:"priorityClassGracePeriodSeconds,omitempty"`
+	// GracePeriodSeconds is waited out after the termination or priority class grace period.
+	GracePeriodSeconds int64 `json:"gracePeriodSeconds"`
+	// GraceWindowSeconds is how long after the deletion is requested the pod can be force deleted.
+	GraceWindowSeconds int64 `json:"graceWindowSeconds"`
+}
+
+// getPodForceDeletionConfig resolves the force deletion configuration of the pod using the Longhorn PVs from the
+// settings and the volume attributes of the PVs, the same way handlePodDeletionIfNodeDown does.
+func (kc *KubernetesPodController) getPodForceDeletionConfig(pod *corev1.Pod, pvs []*corev1.PersistentVolume) (*podForceDeletionConfig, error) {
+	config := &podForceDeletionConfig{
+		Policy:             types.NodeDownPodDeletionPolicyDoNothing,
+		DisabledBy:         getNodeDownPodDeletionDisabledPersistentVolumeName(pvs),
+		GracePeriodSeconds: kc.getPodForceDeletionGracePeriod(),
+	}
+	if deletionSetting, err := kc.ds.GetSettingValueExisted(types.SettingNameNodeDownPodDeletionPolicy); err == nil {
+		config.Policy = types.NodeDownPodDeletionPolicy(deletionSetting)
+	}
+	policy, err := kc.getNodeDownPodDeletionPolicyOfPod(pod, config.Policy)
+	if err != nil {
+		return nil, err
+	}
+	config.Policy = policy
+
+	gracePeriodsSetting, err := kc.ds.GetSettingWithAutoFillingRO(types.SettingNameNodeDownPodDeletionPriorityClassGracePeriods)
+	if err != nil {
+		return nil, err
+	}
+	gracePeriods, err := types.UnmarshalPriorityClassGracePeriods(gracePeriodsSetting.Value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse setting %v", types.SettingNameNodeDownPodDeletionPriorityClassGracePeriods)
+	}
+
+	graceWindow := int64(corev1.DefaultTerminationGracePeriodSeconds)
+	switch {
+	case pod.DeletionGracePeriodSeconds != nil:
+		graceWindow = *pod.DeletionGracePeriodSeconds
+	case pod.Spec.TerminationGracePeriodSeconds != nil:
+		graceWindow = *pod.Spec.TerminationGracePeriodSeconds
+	}
+	if gracePeriod, ok := gracePeriods[pod.Spec.PriorityClassName]; ok && pod.Spec.PriorityClassName != "" {
+		graceWindow = int64(gracePeriod / time.Second)
+		config.PriorityClassGracePeriodSeconds = ptr.To(graceWindow)
+	}
+	config.GraceWindowSeconds = graceWindow + config.GracePeriodSeconds
+	return config, nil
+}
+
+// getPodForceDeletionConfigAnnotation returns the value of the force deletion config annotation of the pod by the
+// current settings, or an empty string if the pod should not be annotated.
+func (kc *KubernetesPodController) getPodForceDeletionConfigAnnotation(pod *corev1.Pod) (string, error) {
+	enabled, err := kc.ds.GetSettingAsBool(types.SettingNameNodeDownPodDeletionConfigAnnotation)
+	if err != nil {
+		return "", err
+	}
+	if !enabled {
+		return "", nil
+	}
+
+	pvs, err := kc.getLonghornPersistentVolumesOfPod(pod)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the Longhorn persistent volumes of pod %v", pod.Name)
+	}
+	if len(pvs) == 0 {
+		return "", nil
+	}
+	config, err := kc.getPodForceDeletionConfig(pod, pvs)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve the force deletion config of pod %v", pod.Name)
+	}
+	configBytes, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(configBytes), nil
+}
+
+// isPodForceDeletionConfigAnnotated returns true if the force deletion config annotation of the pod has the value,
+// or the pod has no annotation if the value is empty.
+func isPodForceDeletionConfigAnnotated(pod *corev1.Pod, value string) bool {
+	current, annotated := pod.Annotations[types.PodAnnotationNodeDownPodDeletionConfig]
+	return current == value && (value != "" || !annotated)
+}
+
+// syncPodForceDeletionConfigAnnotation annotates the pod using Longhorn volumes with its resolved force deletion
+// configuration if the setting is enabled, and removes the annotation otherwise. Only the manager acting on the force
+// deletion of the pod writes the annotation, so the managers do not race on it. If the volumes of the pod have no owner,
+// every manager writes the annotation, which resolves to the same value.
+func (kc *KubernetesPodController) syncPodForceDeletionConfigAnnotation(pod *corev1.Pod) error {
+	owner, err := kc.getPodDeletionOwner(pod)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the force deletion owner of pod %v", pod.Name)
+	}
+	if owner != "" && owner != kc.controllerID {
+		return nil
+	}
+
+	value, err := kc.getPodForceDeletionConfigAnnotation(pod)
+	if err != nil {
+		return err
+	}
+	if isPodForceDeletionConfigAnnotated(pod, value) {
+		return nil
+	}
+
+	// A null value removes the annotation in a merge patch.
+	var annotation interface{}
+	if value != "" {
+		annotation = value
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				types.PodAnnotationNodeDownPodDeletionConfig: annotation,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := kc.kubeClient.CoreV1().Pods(pod.Namespace).Patch(context.TODO(), pod.Name, k8stypes.MergePatchType, patch, metav1.PatchOptions{}); err != nil && !datastore.ErrorIsNotFound(err) {
+		return errors.Wrapf(err, "failed to patch the force deletion config annotation of pod %v", pod.Name)
+	}
+	return nil
+}
+
+// isPodForceDeletionConfigSetting returns true if the setting is resolved into the force deletion config annotation
+// of the pods.
+func isPodForceDeletionConfigSetting(obj interface{}) bool {
+	setting, ok := obj.(*longhorn.Setting)
+	if !ok {
+		return false
+	}
+	switch types.SettingName(setting.Name) {
+	case types.SettingNameNodeDownPodDeletionConfigAnnotation,
+		types.SettingNameNodeDownPodDeletionPolicy,
+		types.SettingNameNodeDownPodDeletionGracePeriod,
+		types.SettingNameNodeDownPodDeletionPriorityClassGracePeriods:
+		return true
+	}
+	return false
+}
+
+// enqueuePodsForSettingChange enqueues the pods whose force deletion config annotation is outdated by the setting change.
+func (kc *KubernetesPodController) enqueuePodsForSettingChange(obj interface{}) {
+	if _, ok := obj.(*longhorn.Setting); !ok {
+		utilruntime.HandleError(fmt.Errorf("received unexpected obj: %#v", obj))
+		return
+	}
+
+	pods, err := kc.ds.ListPodsRO(corev1.NamespaceAll)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list pods: %v", err))
+		return
+	}
+	for _, pod := range pods {
+		kc.enqueuePodChangeIfSyncNeeded(pod)
+	}
+}
@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+func TestSyncPodForceDeletionConfigAnnotation(t *testing.T) {
+	optOutClaim := newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume")
+	optOutClaim[0].(*corev1.PersistentVolume).Spec.CSI.VolumeAttributes = map[string]string{
+		types.PVVolumeAttributeDisableNodeDownPodDeletion: "true",
+	}
+
+	tests := map[string]struct {
+		disabled          bool
+		annotation        string
+		priorityClassName string
+		ownerID           string
+		unowned           bool
+		objs              []runtime.Object
+
+		expected *podForceDeletionConfig
+	}{
+		"merged config": {
+			objs: newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"),
+			expected: &podForceDeletionConfig{
+				Policy:             types.NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+				GracePeriodSeconds: 10,
+				GraceWindowSeconds: 40,
+			},
+		},
+		"persistent volume opt-out": {
+			objs: optOutClaim,
+			expected: &podForceDeletionConfig{
+				Policy:             types.NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+				DisabledBy:         "test-claim-pv",
+				GracePeriodSeconds: 10,
+				GraceWindowSeconds: 40,
+			},
+		},
+		"priority class grace period": {
+			priorityClassName: "critical",
+			objs:              newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"),
+			expected: &podForceDeletionConfig{
+				Policy:                          types.NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+				PriorityClassGracePeriodSeconds: ptr.To(int64(120)),
+				GracePeriodSeconds:              10,
+				GraceWindowSeconds:              130,
+			},
+		},
+		"stale annotation outdated": {
+			annotation: `{"policy":"do-nothing","gracePeriodSeconds":0,"graceWindowSeconds":30}`,
+			objs:       newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"),
+			expected: &podForceDeletionConfig{
+				Policy:             types.NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+				GracePeriodSeconds: 10,
+				GraceWindowSeconds: 40,
+			},
+		},
+		"disabled removes annotation": {
+			disabled:   true,
+			annotation: `{"policy":"do-nothing","gracePeriodSeconds":0,"graceWindowSeconds":30}`,
+			objs:       newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"),
+		},
+		"non longhorn pod": {
+			objs: newTestBoundClaim("test-claim", "other.csi.example.com", "other-volume"),
+		},
+		"volume owned by another manager": {
+			ownerID: TestNode2,
+			objs:    newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"),
+		},
+		"volume without owner": {
+			unowned: true,
+			objs:    newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume"),
+			expected: &podForceDeletionConfig{
+				Policy:             types.NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+				GracePeriodSeconds: 10,
+				GraceWindowSeconds: 40,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := newTestTerminatingPod(TestNode1, time.Minute, "test-claim")
+			pod.DeletionTimestamp = nil
+			pod.Spec.PriorityClassName = tc.priorityClassName
+			if tc.annotation != "" {
+				pod.Annotations = map[string]string{types.PodAnnotationNodeDownPodDeletionConfig: tc.annotation}
+			}
+			settings := map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionConfigAnnotation:          fmt.Sprint(!tc.disabled),
+				types.SettingNameNodeDownPodDeletionPolicy:                    string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionGracePeriod:               "10",
+				types.SettingNameNodeDownPodDeletionPriorityClassGracePeriods: "critical:120",
+			}
+			ownerID := tc.ownerID
+			if ownerID == "" && !tc.unowned {
+				ownerID = TestNode1
+			}
+			volume := &longhorn.Volume{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-volume", Namespace: TestNamespace},
+				Status:     longhorn.VolumeStatus{OwnerID: ownerID},
+			}
+			f := newTestKubernetesPodController(t, settings, append(tc.objs, volume, pod)...)
+
+			require.NoError(t, f.kc.syncPodForceDeletionConfigAnnotation(pod))
+
+			updated, err := f.kubeClient.CoreV1().Pods(TestNamespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			value, ok := updated.Annotations[types.PodAnnotationNodeDownPodDeletionConfig]
+			if tc.expected == nil {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			config := &podForceDeletionConfig{}
+			require.NoError(t, json.Unmarshal([]byte(value), config))
+			assert.Equal(t, tc.expected, config)
+		})
+	}
+}
+
+func TestEnqueuePodsForSettingChange(t *testing.T) {
+	tests := map[string]struct {
+		setting types.SettingName
+		value   string
+
+		expectEnqueued bool
+	}{
+		"grace period changed": {
+			setting:        types.SettingNameNodeDownPodDeletionGracePeriod,
+			value:          "20",
+			expectEnqueued: true,
+		},
+		"grace period unchanged": {
+			setting: types.SettingNameNodeDownPodDeletionGracePeriod,
+			value:   "10",
+		},
+		"config annotation disabled": {
+			setting:        types.SettingNameNodeDownPodDeletionConfigAnnotation,
+			value:          "false",
+			expectEnqueued: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newTestKubernetesPodController(t, map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionConfigAnnotation: "true",
+				types.SettingNameNodeDownPodDeletionPolicy:           string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionGracePeriod:      "10",
+			}, newTestBoundClaim("test-claim", types.LonghornDriverName, "test-volume")...)
+			defer f.kc.queue.ShutDown()
+
+			// The pod is annotated with its config by the settings before the change.
+			pod := newTestTerminatingPod(TestNode2, 0, "test-claim")
+			pod.DeletionTimestamp = nil
+			value, err := f.kc.getPodForceDeletionConfigAnnotation(pod)
+			require.NoError(t, err)
+			pod.Annotations = map[string]string{types.PodAnnotationNodeDownPodDeletionConfig: value}
+			require.NoError(t, f.kc.ds.PodInformer.GetStore().Add(pod))
+
+			setting, err := f.kc.ds.GetSettingExact(tc.setting)
+			require.NoError(t, err)
+			setting.Value = tc.value
+			require.NoError(t, f.kc.ds.SettingInformer.GetStore().Update(setting))
+
+			require.True(t, isPodForceDeletionConfigSetting(setting))
+			f.kc.enqueuePodsForSettingChange(setting)
+			if tc.expectEnqueued {
+				assert.Equal(t, 1, f.kc.queue.Len())
+			} else {
+				assert.Zero(t, f.kc.queue.Len())
+			}
+		})
+	}
+}
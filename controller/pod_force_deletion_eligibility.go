@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+// ForceDeleteEligibilityEvaluator decides whether a terminating pod on a down node is eligible for the force deletion.
+//
+// This is the extension point for the bespoke force deletion rules. The built-in evaluator checks the owners of
+// the pod against the node down pod deletion policy. A manager compiled with custom rules registers its evaluators by
+// RegisterForceDeleteEligibilityEvaluator before the controllers are created, and the pod controller consults them
+// after the built-in one. A pod is force deleted only if every evaluator allows it, while the other checks of the pod
+// controller, like the deletion selector and the opt-out of the volumes, still apply.
+type ForceDeleteEligibilityEvaluator interface {
+	// Name identifies the evaluator in the skipped force deletion events.
+	Name() string
+	// IsEligible returns whether the pod on the down node can be force deleted under the deletion policy,
+	// and the reason if it cannot. An error requeues the pod to evaluate it again.
+	IsEligible(pod *corev1.Pod, nodeID string, deletionPolicy types.NodeDownPodDeletionPolicy) (eligible bool, reason string, err error)
+}
+
+var (
+	forceDeleteEligibilityEvaluatorsLock sync.RWMutex
+	forceDeleteEligibilityEvaluators     []ForceDeleteEligibilityEvaluator
+)
+
+// RegisterForceDeleteEligibilityEvaluator adds a custom evaluator consulted by the pod controllers created afterwards.
+func RegisterForceDeleteEligibilityEvaluator(evaluator ForceDeleteEligibilityEvaluator) {
+	forceDeleteEligibilityEvaluatorsLock.Lock()
+	defer forceDeleteEligibilityEvaluatorsLock.Unlock()
+
+	forceDeleteEligibilityEvaluators = append(forceDeleteEligibilityEvaluators, evaluator)
+}
+
+func getRegisteredForceDeleteEligibilityEvaluators() []ForceDeleteEligibilityEvaluator {
+	forceDeleteEligibilityEvaluatorsLock.RLock()
+	defer forceDeleteEligibilityEvaluatorsLock.RUnlock()
+
+	return append([]ForceDeleteEligibilityEvaluator{}, forceDeleteEligibilityEvaluators...)
+}
+
+// ownerKindEligibilityEvaluator is the built-in evaluator, allowing the pods owned by a workload of the deletion policy.
+type ownerKindEligibilityEvaluator struct {
+	kc *KubernetesPodController
+}
+
+func (e *ownerKindEligibilityEvaluator) Name() string {
+	return "owner-kind"
+}
+
+func (e *ownerKindEligibilityEvaluator) IsEligible(pod *corev1.Pod, nodeID string, deletionPolicy types.NodeDownPodDeletionPolicy) (bool, string, error) {
+	owned, err := isPodOwnedByDeletionPolicy(e.kc.ds, pod, deletionPolicy)
+	if err != nil || owned {
+		return owned, "", err
+	}
+	return false, fmt.Sprintf("pod is not owned by a workload of deletion policy %v", deletionPolicy), nil
+}
+
+// isPodEligibleForForceDeletion consults the evaluators in order, and returns the reason of the first one denying
+// the force deletion of the pod.
+func (kc *KubernetesPodController) isPodEligibleForForceDeletion(pod *corev1.Pod, nodeID string, deletionPolicy types.NodeDownPodDeletionPolicy) (bool, string, error) {
+	for _, evaluator := range kc.eligibilityEvaluators {
+		eligible, reason, err := evaluator.IsEligible(pod, nodeID, deletionPolicy)
+		if err != nil {
+			return false, "", errors.Wrapf(err, "failed to evaluate the force deletion eligibility by %v", evaluator.Name())
+		}
+		if eligible {
+			continue
+		}
+		if reason == "" {
+			reason = fmt.Sprintf("denied by force deletion eligibility evaluator %v", evaluator.Name())
+		}
+		return false, reason, nil
+	}
+	return true, "", nil
+}
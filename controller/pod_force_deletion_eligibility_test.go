@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+// testEligibilityEvaluator allows the pods in the allowed namespace, and counts its evaluations.
+type testEligibilityEvaluator struct {
+	allowedNamespace string
+	evaluations      int
+}
+
+func (e *testEligibilityEvaluator) Name() string {
+	return "test"
+}
+
+func (e *testEligibilityEvaluator) IsEligible(pod *corev1.Pod, nodeID string, deletionPolicy types.NodeDownPodDeletionPolicy) (bool, string, error) {
+	e.evaluations++
+	if pod.Namespace == e.allowedNamespace {
+		return true, "", nil
+	}
+	return false, "", nil
+}
+
+func TestCustomForceDeleteEligibilityEvaluator(t *testing.T) {
+	tests := map[string]struct {
+		deletionPolicy   types.NodeDownPodDeletionPolicy
+		allowedNamespace string
+
+		expectDeleted     bool
+		expectEvaluations int
+		expectedEvent     []string
+	}{
+		"allowed by the custom evaluator": {
+			deletionPolicy:    types.NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+			allowedNamespace:  TestNamespace,
+			expectDeleted:     true,
+			expectEvaluations: 1,
+			expectedEvent:     []string{constant.EventReasonForceDeleted},
+		},
+		"denied by the custom evaluator": {
+			deletionPolicy:    types.NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+			allowedNamespace:  "other-namespace",
+			expectEvaluations: 1,
+			expectedEvent:     []string{constant.EventReasonPodDeletionSkipped, "denied by force deletion eligibility evaluator test"},
+		},
+		"denied by the built-in evaluator first": {
+			deletionPolicy:   types.NodeDownPodDeletionPolicyDeleteDeploymentPod,
+			allowedNamespace: TestNamespace,
+			expectedEvent:    []string{constant.EventReasonPodDeletionSkipped, "pod is not owned by a workload of deletion policy delete-deployment-pod"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			evaluator := &testEligibilityEvaluator{allowedNamespace: tc.allowedNamespace}
+			RegisterForceDeleteEligibilityEvaluator(evaluator)
+			t.Cleanup(func() {
+				forceDeleteEligibilityEvaluatorsLock.Lock()
+				defer forceDeleteEligibilityEvaluatorsLock.Unlock()
+				forceDeleteEligibilityEvaluators = nil
+			})
+
+			pod := newTestTerminatingPod(TestNode2, -time.Minute)
+			f := newTestKubernetesPodController(t, map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(tc.deletionPolicy),
+			}, pod)
+
+			require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+			_, err := f.kubeClient.CoreV1().Pods(TestNamespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+			if tc.expectDeleted {
+				assert.True(t, datastore.ErrorIsNotFound(err))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectEvaluations, evaluator.evaluations)
+
+			require.Len(t, f.fakeRecorder.Events, 1)
+			event := <-f.fakeRecorder.Events
+			for _, expected := range tc.expectedEvent {
+				assert.Contains(t, event, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+// forceDeletionSummary counts the force deletions per node in batches. A batch starts with the first
+// deletion on the node and collects the following deletions until its window is over.
+type forceDeletionSummary struct {
+	lock sync.Mutex
+
+	window  time.Duration
+	batches map[string]*forceDeletionBatch
+}
+
+type forceDeletionBatch struct {
+	count   int
+	startAt time.Time
+	// nodeCondition is the node condition snapshot of the last deletion of the batch
+	nodeCondition string
+}
+
+func newForceDeletionSummary(window time.Duration) *forceDeletionSummary {
+	return &forceDeletionSummary{
+		window:  window,
+		batches: map[string]*forceDeletionBatch{},
+	}
+}
+
+// Add counts a force deletion on the node at now.
+func (s *forceDeletionSummary) Add(node, nodeCondition string, now time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	batch, ok := s.batches[node]
+	if !ok {
+		batch = &forceDeletionBatch{startAt: now}
+		s.batches[node] = batch
+	}
+	batch.count++
+	batch.nodeCondition = nodeCondition
+}
+
+// Flush removes the batches whose window is over at now and returns them by node.
+func (s *forceDeletionSummary) Flush(now time.Time) map[string]forceDeletionBatch {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	batches := map[string]forceDeletionBatch{}
+	for node, batch := range s.batches {
+		if now.Sub(batch.startAt) < s.window {
+			continue
+		}
+		batches[node] = *batch
+		delete(s.batches, node)
+	}
+	return batches
+}
+
+// eventThrottle allows an event once per interval for each key.
+type eventThrottle struct {
+	lock sync.Mutex
+
+	interval   time.Duration
+	recordedAt map[string]time.Time
+}
+
+func newEventThrottle(interval time.Duration) *eventThrottle {
+	return &eventThrottle{
+		interval:   interval,
+		recordedAt: map[string]time.Time{},
+	}
+}
+
+// Allow returns whether the event of the key can be recorded at now, and marks it recorded if so.
+func (t *eventThrottle) Allow(key string, now time.Time) bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if recordedAt, ok := t.recordedAt[key]; ok && now.Sub(recordedAt) < t.interval {
+		return false
+	}
+	t.recordedAt[key] = now
+	return true
+}
+
+// Prune forgets the keys whose interval is over at now.
+func (t *eventThrottle) Prune(now time.Time) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for key, recordedAt := range t.recordedAt {
+		if now.Sub(recordedAt) >= t.interval {
+			delete(t.recordedAt, key)
+		}
+	}
+}
+
+// recordForceDeletionEvent records an event on the force deleted pod, or counts the deletion
+// in the summary of the node, depending on the event mode setting.
+func (kc *KubernetesPodController) recordForceDeletionEvent(pod *corev1.Pod, nodeID, nodeCondition string) {
+	mode := types.NodeDownPodDeletionEventModePerPod
+	if value, err := kc.ds.GetSettingValueExisted(types.SettingNameNodeDownPodDeletionEventMode); err == nil {
+		mode = types.NodeDownPodDeletionEventMode(value)
+	}
+
+	if mode == types.NodeDownPodDeletionEventModeSummary {
+		kc.forceDeletionSummary.Add(nodeID, nodeCondition, time.Now())
+		return
+	}
+	kc.eventRecorder.Eventf(pod, corev1.EventTypeNormal, constant.EventReasonForceDeleted, "Forcefully deleted pod %v on downed node %v: %v", pod.Name, nodeID, nodeCondition)
+}
+
+// recordForceDeletionSummaries records a single event on the Longhorn node for each failover batch whose window is over.
+func (kc *KubernetesPodController) recordForceDeletionSummaries() {
+	for nodeID, batch := range kc.forceDeletionSummary.Flush(time.Now()) {
+		node, err := kc.ds.GetNodeRO(nodeID)
+		if err != nil {
+			kc.logger.WithError(err).Warnf("%v: failed to get node %v to record the summary of %v force deleted pods", controllerAgentName, nodeID, batch.count)
+			continue
+		}
+		kc.eventRecorder.Eventf(node, corev1.EventTypeNormal, constant.EventReasonForceDeleted, "Forcefully deleted %v pods on downed node %v: %v", batch.count, nodeID, batch.nodeCondition)
+	}
+}
+
+// getNodeConditionSnapshot describes why the node is considered down, from the Ready condition of the Kubernetes node.
+func (kc *KubernetesPodController) getNodeConditionSnapshot(nodeID string) string {
+	node, err := kc.ds.GetKubernetesNodeRO(nodeID)
+	if err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return "Kubernetes node is not found"
+		}
+		return fmt.Sprintf("failed to get Kubernetes node: %v", err)
+	}
+	return describeNodeReadyCondition(node)
+}
+
+func describeNodeReadyCondition(node *corev1.Node) string {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type != corev1.NodeReady {
+			continue
+		}
+		return fmt.Sprintf("Ready condition is %v with reason %v since %v, last heartbeat at %v", cond.Status, cond.Reason,
+			cond.LastTransitionTime.UTC().Format(time.RFC3339), cond.LastHeartbeatTime.UTC().Format(time.RFC3339))
+	}
+	return "Ready condition is not reported"
+}
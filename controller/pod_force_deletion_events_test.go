@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForceDeletionSummaryRollup(t *testing.T) {
+	window := 30 * time.Second
+	summary := newForceDeletionSummary(window)
+	now := time.Now()
+
+	// The deletions on a node are counted in the batch started by the first one.
+	summary.Add("node-1", "condition-1", now)
+	summary.Add("node-1", "condition-2", now.Add(10*time.Second))
+	summary.Add("node-2", "condition-3", now.Add(20*time.Second))
+	summary.Add("node-1", "condition-4", now.Add(25*time.Second))
+
+	// No batch is reported before its window is over.
+	assert.Empty(t, summary.Flush(now.Add(29*time.Second)))
+
+	// Only the batches whose window is over are reported, once, with the latest node condition.
+	batches := summary.Flush(now.Add(window))
+	require.Len(t, batches, 1)
+	assert.Equal(t, 3, batches["node-1"].count)
+	assert.Equal(t, "condition-4", batches["node-1"].nodeCondition)
+	assert.Empty(t, summary.Flush(now.Add(window)))
+
+	// A deletion after the flush starts a new batch on the node.
+	summary.Add("node-1", "condition-5", now.Add(40*time.Second))
+	batches = summary.Flush(now.Add(50 * time.Second))
+	require.Len(t, batches, 1)
+	assert.Equal(t, 1, batches["node-2"].count)
+	batches = summary.Flush(now.Add(70 * time.Second))
+	require.Len(t, batches, 1)
+	assert.Equal(t, 1, batches["node-1"].count)
+	assert.Equal(t, "condition-5", batches["node-1"].nodeCondition)
+}
+
+func TestEventThrottle(t *testing.T) {
+	interval := 10 * time.Minute
+	throttle := newEventThrottle(interval)
+	now := time.Now()
+
+	assert.True(t, throttle.Allow("pod-1/reason-1", now))
+	assert.False(t, throttle.Allow("pod-1/reason-1", now.Add(time.Minute)))
+	assert.True(t, throttle.Allow("pod-1/reason-2", now.Add(time.Minute)))
+	assert.True(t, throttle.Allow("pod-1/reason-1", now.Add(interval)))
+
+	throttle.Prune(now.Add(interval + time.Minute))
+	assert.Len(t, throttle.recordedAt, 1)
+	throttle.Prune(now.Add(2 * interval))
+	assert.Empty(t, throttle.recordedAt)
+}
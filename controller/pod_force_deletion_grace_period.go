@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+// getPodForceDeletionGracePeriod returns the grace period in seconds to wait before force deleting a pod on a down node,
+// which is 0 if the setting is missing or invalid.
+func (kc *KubernetesPodController) getPodForceDeletionGracePeriod() int64 {
+	value, err := kc.ds.GetSettingValueExisted(types.SettingNameNodeDownPodDeletionGracePeriod)
+	if err != nil {
+		kc.logger.WithError(err).Warnf("%v: failed to get setting %v, force deleting pods without grace period", controllerAgentName, types.SettingNameNodeDownPodDeletionGracePeriod)
+		return 0
+	}
+	gracePeriod, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		kc.logger.WithError(err).Warnf("%v: failed to parse setting %v, force deleting pods without grace period", controllerAgentName, types.SettingNameNodeDownPodDeletionGracePeriod)
+		return 0
+	}
+	if gracePeriod < 0 {
+		kc.logger.Warnf("%v: invalid negative setting %v value %v, force deleting pods without grace period", controllerAgentName, types.SettingNameNodeDownPodDeletionGracePeriod, gracePeriod)
+		return 0
+	}
+	return gracePeriod
+}
+
+// getPodForceDeletionTimeBySetting returns when the pod on a down node can be force deleted with the grace periods
+// by priority class of the setting, after the grace period of the force deletion.
+func (kc *KubernetesPodController) getPodForceDeletionTimeBySetting(pod *corev1.Pod) (time.Time, error) {
+	gracePeriodsSetting, err := kc.ds.GetSettingWithAutoFillingRO(types.SettingNameNodeDownPodDeletionPriorityClassGracePeriods)
+	if err != nil {
+		return time.Time{}, err
+	}
+	gracePeriods, err := types.UnmarshalPriorityClassGracePeriods(gracePeriodsSetting.Value)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to parse setting %v", types.SettingNameNodeDownPodDeletionPriorityClassGracePeriods)
+	}
+	gracePeriod := time.Duration(kc.getPodForceDeletionGracePeriod()) * time.Second
+	return getPodForceDeletionTime(pod, gracePeriods).Add(gracePeriod), nil
+}
+
+// getPodForceDeletionTime returns when the pod on a down node can be force deleted, which is when its termination
+// grace period is over by default. A grace period mapped to the priority class of the pod replaces the termination
+// grace period, counted from when the deletion was requested.
+func getPodForceDeletionTime(pod *corev1.Pod, gracePeriods map[string]time.Duration) time.Time {
+	deletionTime := pod.DeletionTimestamp.Time
+	if pod.Spec.PriorityClassName == "" {
+		return deletionTime
+	}
+	gracePeriod, ok := gracePeriods[pod.Spec.PriorityClassName]
+	if !ok {
+		return deletionTime
+	}
+
+	requestedAt := deletionTime
+	if pod.DeletionGracePeriodSeconds != nil {
+		requestedAt = deletionTime.Add(-time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second)
+	}
+	return requestedAt.Add(gracePeriod)
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/utils/ptr"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+func TestGetPodForceDeletionTime(t *testing.T) {
+	requestedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	terminationGracePeriod := int64(30)
+	gracePeriods := map[string]time.Duration{
+		"critical":    0,
+		"best-effort": 10 * time.Minute,
+	}
+
+	tests := map[string]struct {
+		priorityClassName string
+		expected          time.Time
+	}{
+		"no priority class": {
+			expected: requestedAt.Add(30 * time.Second),
+		},
+		"unmapped priority class": {
+			priorityClassName: "normal",
+			expected:          requestedAt.Add(30 * time.Second),
+		},
+		"critical priority class": {
+			priorityClassName: "critical",
+			expected:          requestedAt,
+		},
+		"best-effort priority class": {
+			priorityClassName: "best-effort",
+			expected:          requestedAt.Add(10 * time.Minute),
+		},
+	}
+
+	for name, tc := range tests {
+		deletionTimestamp := metav1.NewTime(requestedAt.Add(time.Duration(terminationGracePeriod) * time.Second))
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				DeletionTimestamp:          &deletionTimestamp,
+				DeletionGracePeriodSeconds: &terminationGracePeriod,
+			},
+			Spec: corev1.PodSpec{PriorityClassName: tc.priorityClassName},
+		}
+		assert.True(t, tc.expected.Equal(getPodForceDeletionTime(pod, gracePeriods)), name)
+	}
+}
+
+func TestGetPodForceDeletionGracePeriod(t *testing.T) {
+	tests := map[string]struct {
+		value    *string
+		expected int64
+	}{
+		"missing": {
+			expected: 0,
+		},
+		"configured": {
+			value:    ptr.To("30"),
+			expected: 30,
+		},
+		"negative": {
+			value:    ptr.To("-5"),
+			expected: 0,
+		},
+		"unparseable": {
+			value:    ptr.To("30s"),
+			expected: 0,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			settings := map[types.SettingName]string{}
+			if tc.value != nil {
+				settings[types.SettingNameNodeDownPodDeletionGracePeriod] = *tc.value
+			}
+			f := newTestKubernetesPodController(t, settings)
+
+			assert.Equal(t, tc.expected, f.kc.getPodForceDeletionGracePeriod())
+		})
+	}
+}
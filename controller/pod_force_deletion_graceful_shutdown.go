@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+// getGracefulNodeShutdownRemaining returns how long the kubelet is still left to terminate the pod during the graceful
+// shutdown of the node, or 0 when the node is not shutting down gracefully or the shutdown has timed out.
+func (kc *KubernetesPodController) getGracefulNodeShutdownRemaining(pod *corev1.Pod, nodeID string, now time.Time) (time.Duration, error) {
+	timeout, err := kc.ds.GetSettingAsInt(types.SettingNameNodeDownPodDeletionGracefulShutdownTimeout)
+	if err != nil {
+		return 0, err
+	}
+	if timeout <= 0 {
+		return 0, nil
+	}
+
+	kubeNode, err := kc.ds.GetKubernetesNodeRO(nodeID)
+	if err != nil {
+		if !datastore.ErrorIsNotFound(err) {
+			return 0, err
+		}
+		kubeNode = nil
+	}
+
+	startedAt, ok := getGracefulNodeShutdownStartTime(pod, kubeNode)
+	if !ok {
+		return 0, nil
+	}
+	if remaining := startedAt.Add(time.Duration(timeout) * time.Second).Sub(now); remaining > 0 {
+		return remaining, nil
+	}
+	return 0, nil
+}
+
+// getGracefulNodeShutdownStartTime returns when the graceful shutdown of the node started, from the disruption
+// condition the kubelet sets on the pods it terminates, or from the not ready condition of the node.
+func getGracefulNodeShutdownStartTime(pod *corev1.Pod, kubeNode *corev1.Node) (time.Time, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.DisruptionTarget && condition.Status == corev1.ConditionTrue &&
+			condition.Reason == corev1.PodReasonTerminationByKubelet {
+			return condition.LastTransitionTime.Time, true
+		}
+	}
+	if kubeNode == nil {
+		return time.Time{}, false
+	}
+	for _, condition := range kubeNode.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue &&
+			strings.Contains(condition.Message, kubeNodeShutdownMessage) {
+			return condition.LastTransitionTime.Time, true
+		}
+	}
+	return time.Time{}, false
+}
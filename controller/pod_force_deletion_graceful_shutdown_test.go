@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+func TestPodDeletionDefersToGracefulNodeShutdown(t *testing.T) {
+	newShuttingDownNode := func(since time.Duration) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: TestNode2,
+			},
+			Status: corev1.NodeStatus{
+				Conditions: []corev1.NodeCondition{
+					{
+						Type:               corev1.NodeReady,
+						Status:             corev1.ConditionFalse,
+						Reason:             "KubeletNotReady",
+						Message:            kubeNodeShutdownMessage,
+						LastTransitionTime: metav1.NewTime(time.Now().Add(-since)),
+					},
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		kubeNode         *corev1.Node
+		terminatedSince  time.Duration
+		shutdownDeferral string
+
+		expectDeleted bool
+		expectedEvent []string
+	}{
+		"node shutting down": {
+			kubeNode:      newShuttingDownNode(time.Minute),
+			expectedEvent: []string{constant.EventReasonPodDeletionSkipped, "node " + TestNode2 + " is shutting down gracefully"},
+		},
+		"pod terminated by the kubelet": {
+			terminatedSince: time.Minute,
+			expectedEvent:   []string{constant.EventReasonPodDeletionSkipped, "node " + TestNode2 + " is shutting down gracefully"},
+		},
+		"node shutdown timed out": {
+			kubeNode:      newShuttingDownNode(10 * time.Minute),
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+		"graceful shutdown not waited for": {
+			kubeNode:         newShuttingDownNode(time.Minute),
+			shutdownDeferral: "0",
+			expectDeleted:    true,
+			expectedEvent:    []string{constant.EventReasonForceDeleted},
+		},
+		"node not shutting down": {
+			expectDeleted: true,
+			expectedEvent: []string{constant.EventReasonForceDeleted},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pod := newTestTerminatingPod(TestNode2, -time.Minute)
+			if tc.terminatedSince > 0 {
+				pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+					Type:               corev1.DisruptionTarget,
+					Status:             corev1.ConditionTrue,
+					Reason:             corev1.PodReasonTerminationByKubelet,
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-tc.terminatedSince)),
+				})
+			}
+			settings := map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy: string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+			}
+			if tc.shutdownDeferral != "" {
+				settings[types.SettingNameNodeDownPodDeletionGracefulShutdownTimeout] = tc.shutdownDeferral
+			}
+			objs := []runtime.Object{pod}
+			if tc.kubeNode != nil {
+				objs = append(objs, tc.kubeNode)
+			}
+			f := newTestKubernetesPodController(t, settings, objs...)
+
+			require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+			_, err := f.kubeClient.CoreV1().Pods(TestNamespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+			if tc.expectDeleted {
+				assert.True(t, datastore.ErrorIsNotFound(err))
+			} else {
+				assert.NoError(t, err)
+			}
+
+			require.Len(t, f.fakeRecorder.Events, 1)
+			event := <-f.fakeRecorder.Events
+			for _, expected := range tc.expectedEvent {
+				assert.Contains(t, event, expected)
+			}
+		})
+	}
+}
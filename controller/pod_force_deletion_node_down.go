@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// nodeDownCache caches whether the nodes are down or deleted for the TTL.
+type nodeDownCache struct {
+	lock sync.Mutex
+
+	ttl      time.Duration
+	evaluate func(node string) (bool, error)
+	entries  map[string]nodeDownCacheEntry
+}
+
+type nodeDownCacheEntry struct {
+	down        bool
+	evaluatedAt time.Time
+}
+
+func newNodeDownCache(ttl time.Duration, evaluate func(node string) (bool, error)) *nodeDownCache {
+	return &nodeDownCache{
+		ttl:      ttl,
+		evaluate: evaluate,
+		entries:  map[string]nodeDownCacheEntry{},
+	}
+}
+
+// IsNodeDownOrDeleted returns whether the node is down or deleted at now, evaluating it again only if
+// the cached result has expired. Failed evaluations are not cached.
+func (c *nodeDownCache) IsNodeDownOrDeleted(node string, now time.Time) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if entry, ok := c.entries[node]; ok && now.Sub(entry.evaluatedAt) < c.ttl {
+		return entry.down, nil
+	}
+	// Expired entries of the other nodes are dropped here, since a node that is gone is not evaluated again.
+	for name, entry := range c.entries {
+		if now.Sub(entry.evaluatedAt) >= c.ttl {
+			delete(c.entries, name)
+		}
+	}
+
+	down, err := c.evaluate(node)
+	if err != nil {
+		return false, err
+	}
+	c.entries[node] = nodeDownCacheEntry{down: down, evaluatedAt: now}
+	return down, nil
+}
+
+// Invalidate forgets the cached result of the node.
+func (c *nodeDownCache) Invalidate(node string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.entries, node)
+}
+
+// downNodePodTracker tracks the pods observed terminating on down nodes, and reports the number of them per node
+// on every change.
+type downNodePodTracker struct {
+	lock sync.Mutex
+
+	report func(node string, count int)
+	pods   map[string]string
+	counts map[string]int
+}
+
+func newDownNodePodTracker(report func(node string, count int)) *downNodePodTracker {
+	return &downNodePodTracker{
+		report: report,
+		pods:   map[string]string{},
+		counts: map[string]int{},
+	}
+}
+
+// Observe records the pod of the key terminating on the down node.
+func (t *downNodePodTracker) Observe(key, node string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.pods[key] == node {
+		return
+	}
+	t.forget(key)
+	t.pods[key] = node
+	t.counts[node]++
+	t.report(node, t.counts[node])
+}
+
+// Forget removes the pod of the key, which is gone or no longer terminating on a down node.
+func (t *downNodePodTracker) Forget(key string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.forget(key)
+}
+
+func (t *downNodePodTracker) forget(key string) {
+	node, ok := t.pods[key]
+	if !ok {
+		return
+	}
+	delete(t.pods, key)
+	t.counts[node]--
+	if t.counts[node] == 0 {
+		delete(t.counts, node)
+	}
+	t.report(node, t.counts[node])
+}
+
+// trackTerminatingPodOnDownNode counts the pod in the terminating pods on down nodes, whatever the deletion policy is.
+func (kc *KubernetesPodController) trackTerminatingPodOnDownNode(key string, pod *corev1.Pod, nodeID string) {
+	if pod.DeletionTimestamp == nil {
+		kc.terminatingPodsOnDownNodes.Forget(key)
+		return
+	}
+	isNodeDown, err := kc.nodeDownCache.IsNodeDownOrDeleted(nodeID, time.Now())
+	if err != nil {
+		// The pod is kept as it is until the node can be evaluated again.
+		return
+	}
+	if !isNodeDown {
+		kc.terminatingPodsOnDownNodes.Forget(key)
+		return
+	}
+	kc.terminatingPodsOnDownNodes.Observe(key, nodeID)
+}
@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeDownCache(t *testing.T) {
+	evaluations := 0
+	down := false
+	c := newNodeDownCache(nodeDownCacheTTL, func(node string) (bool, error) {
+		evaluations++
+		return down, nil
+	})
+	now := time.Now()
+
+	isDown, err := c.IsNodeDownOrDeleted(TestNode1, now)
+	require.NoError(t, err)
+	assert.False(t, isDown)
+
+	// The cached result is reused within the TTL even if the node has changed since.
+	down = true
+	isDown, err = c.IsNodeDownOrDeleted(TestNode1, now.Add(nodeDownCacheTTL/2))
+	require.NoError(t, err)
+	assert.False(t, isDown)
+	assert.Equal(t, 1, evaluations)
+
+	// The node is evaluated again once invalidated or expired.
+	c.Invalidate(TestNode1)
+	isDown, err = c.IsNodeDownOrDeleted(TestNode1, now.Add(nodeDownCacheTTL/2))
+	require.NoError(t, err)
+	assert.True(t, isDown)
+	assert.Equal(t, 2, evaluations)
+	down = false
+	isDown, err = c.IsNodeDownOrDeleted(TestNode1, now.Add(nodeDownCacheTTL))
+	require.NoError(t, err)
+	assert.True(t, isDown)
+	isDown, err = c.IsNodeDownOrDeleted(TestNode1, now.Add(nodeDownCacheTTL*2))
+	require.NoError(t, err)
+	assert.False(t, isDown)
+	assert.Equal(t, 3, evaluations)
+
+	// Failed evaluations are not cached.
+	c = newNodeDownCache(nodeDownCacheTTL, func(node string) (bool, error) {
+		evaluations++
+		return false, fmt.Errorf("failed to get node %v", node)
+	})
+	_, err = c.IsNodeDownOrDeleted(TestNode1, now)
+	assert.Error(t, err)
+	assert.Empty(t, c.entries)
+}
+
+func TestDownNodePodTracker(t *testing.T) {
+	reported := map[string]int{}
+	tracker := newDownNodePodTracker(func(node string, count int) {
+		reported[node] = count
+	})
+
+	tracker.Observe("ns/pod-1", TestNode1)
+	tracker.Observe("ns/pod-2", TestNode1)
+	tracker.Observe("ns/pod-2", TestNode1)
+	tracker.Observe("ns/pod-3", TestNode2)
+	assert.Equal(t, map[string]int{TestNode1: 2, TestNode2: 1}, reported)
+
+	// A pod rescheduled on another down node moves to it.
+	tracker.Observe("ns/pod-3", TestNode1)
+	assert.Equal(t, map[string]int{TestNode1: 3, TestNode2: 0}, reported)
+
+	tracker.Forget("ns/pod-1")
+	tracker.Forget("ns/pod-1")
+	tracker.Forget("ns/pod-unknown")
+	assert.Equal(t, map[string]int{TestNode1: 2, TestNode2: 0}, reported)
+	assert.Equal(t, map[string]int{TestNode1: 2}, tracker.counts)
+}
@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/longhorn/longhorn-manager/constant"
+)
+
+// nodeQuarantine tracks consecutive failures per node, and quarantines a node for
+// a cooldown period once the failures reach the threshold.
+type nodeQuarantine struct {
+	lock sync.Mutex
+
+	failureThreshold int
+	cooldown         time.Duration
+	nodes            map[string]*nodeQuarantineState
+}
+
+type nodeQuarantineState struct {
+	consecutiveFailures int
+	quarantinedUntil    time.Time
+}
+
+func newNodeQuarantine(failureThreshold int, cooldown time.Duration) *nodeQuarantine {
+	return &nodeQuarantine{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		nodes:            map[string]*nodeQuarantineState{},
+	}
+}
+
+// Remaining returns how long the node is still quarantined at now, or zero if it is not quarantined.
+// lifted is true for the first call after the cooldown of the node has passed.
+func (q *nodeQuarantine) Remaining(node string, now time.Time) (remaining time.Duration, lifted bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	state, ok := q.nodes[node]
+	if !ok || state.quarantinedUntil.IsZero() {
+		return 0, false
+	}
+	if state.quarantinedUntil.After(now) {
+		return state.quarantinedUntil.Sub(now), false
+	}
+	state.quarantinedUntil = time.Time{}
+	return 0, true
+}
+
+// RecordFailure records a failure on the node and returns true if the node is quarantined because of it.
+// The failures are not reset by the quarantine, so a failure right after the cooldown quarantines the node again.
+func (q *nodeQuarantine) RecordFailure(node string, now time.Time) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	state, ok := q.nodes[node]
+	if !ok {
+		state = &nodeQuarantineState{}
+		q.nodes[node] = state
+	}
+	state.consecutiveFailures++
+	if state.consecutiveFailures < q.failureThreshold {
+		return false
+	}
+	state.quarantinedUntil = now.Add(q.cooldown)
+	return true
+}
+
+// RecordSuccess resets the failures of the node.
+func (q *nodeQuarantine) RecordSuccess(node string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	delete(q.nodes, node)
+}
+
+// quarantineNodeOnFailure records a failed force deletion of the pod on the node, either denied or failed to delete,
+// and returns true if the node is quarantined because of it. The pod is then requeued after the cooldown.
+func (kc *KubernetesPodController) quarantineNodeOnFailure(pod *corev1.Pod, nodeID, reason string) bool {
+	if !kc.forceDeletionQuarantine.RecordFailure(nodeID, time.Now()) {
+		return false
+	}
+	kc.logger.Warnf("%v: quarantined downed node %v from force deletion for %v after %v consecutive failures, last failure: %v",
+		controllerAgentName, nodeID, forceDeletionQuarantineCooldown, forceDeletionQuarantineFailureThreshold, reason)
+	kc.eventRecorder.Eventf(pod, corev1.EventTypeWarning, constant.EventReasonQuarantined,
+		"Quarantined downed node %v from force deletion for %v after %v consecutive failures, last failure: %v",
+		nodeID, forceDeletionQuarantineCooldown, forceDeletionQuarantineFailureThreshold, reason)
+	kc.enqueuePodAfter(pod, forceDeletionQuarantineCooldown)
+	return true
+}
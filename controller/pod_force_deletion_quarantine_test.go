@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeQuarantineEnterAndExit(t *testing.T) {
+	cooldown := 10 * time.Minute
+	quarantine := newNodeQuarantine(3, cooldown)
+	now := time.Now()
+
+	// Failures below the threshold do not quarantine the node.
+	assert.False(t, quarantine.RecordFailure("node-1", now))
+	assert.False(t, quarantine.RecordFailure("node-1", now))
+	remaining, _ := quarantine.Remaining("node-1", now)
+	assert.Equal(t, time.Duration(0), remaining)
+
+	// Reaching the threshold quarantines the node for the cooldown period.
+	assert.True(t, quarantine.RecordFailure("node-1", now))
+	remaining, lifted := quarantine.Remaining("node-1", now)
+	assert.Equal(t, cooldown, remaining)
+	assert.False(t, lifted)
+
+	// The other nodes are not affected.
+	remaining, _ = quarantine.Remaining("node-2", now)
+	assert.Equal(t, time.Duration(0), remaining)
+
+	// The quarantine is lifted once after the cooldown.
+	remaining, lifted = quarantine.Remaining("node-1", now.Add(cooldown))
+	assert.Equal(t, time.Duration(0), remaining)
+	assert.True(t, lifted)
+	_, lifted = quarantine.Remaining("node-1", now.Add(cooldown))
+	assert.False(t, lifted)
+
+	// A failure of the retry after the cooldown quarantines the node again.
+	assert.True(t, quarantine.RecordFailure("node-1", now.Add(cooldown)))
+	remaining, _ = quarantine.Remaining("node-1", now.Add(cooldown))
+	assert.Equal(t, cooldown, remaining)
+}
+
+func TestNodeQuarantineResetOnSuccess(t *testing.T) {
+	quarantine := newNodeQuarantine(2, time.Minute)
+	now := time.Now()
+
+	assert.False(t, quarantine.RecordFailure("node-1", now))
+	quarantine.RecordSuccess("node-1")
+
+	// The consecutive failures start over after a success.
+	assert.False(t, quarantine.RecordFailure("node-1", now))
+	assert.True(t, quarantine.RecordFailure("node-1", now))
+}
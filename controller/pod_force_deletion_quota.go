@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+// namespaceQuota counts the force deletions per namespace within a sliding window, so the failover of a namespace
+// cannot consume all the force deletions. It reports the number of them per namespace on every change.
+type namespaceQuota struct {
+	lock sync.Mutex
+
+	report    func(namespace string, count int)
+	deletions map[string][]time.Time
+}
+
+func newNamespaceQuota(report func(namespace string, count int)) *namespaceQuota {
+	return &namespaceQuota{
+		report:    report,
+		deletions: map[string][]time.Time{},
+	}
+}
+
+// Reserve counts a force deletion of the namespace at now and returns zero if the quota allows it.
+// Otherwise, it returns how long the caller should wait before retrying, without counting the deletion.
+// A non-positive quota disables the quota.
+func (q *namespaceQuota) Reserve(namespace string, quota int, window time.Duration, now time.Time) time.Duration {
+	if quota <= 0 {
+		return 0
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	deletions := q.prune(namespace, window, now)
+	if len(deletions) >= quota {
+		return deletions[len(deletions)-quota].Add(window).Sub(now)
+	}
+	q.deletions[namespace] = append(deletions, now)
+	q.report(namespace, len(q.deletions[namespace]))
+	return 0
+}
+
+// Release gives back the deletion of the namespace reserved at reservedAt, since the pod is not force deleted.
+func (q *namespaceQuota) Release(namespace string, reservedAt time.Time) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	deletions := q.deletions[namespace]
+	for i := len(deletions) - 1; i >= 0; i-- {
+		if deletions[i].Equal(reservedAt) {
+			q.deletions[namespace] = append(deletions[:i], deletions[i+1:]...)
+			q.report(namespace, len(q.deletions[namespace]))
+			return
+		}
+	}
+}
+
+// prune drops the deletions of the namespace out of the window, and returns the remaining ones.
+func (q *namespaceQuota) prune(namespace string, window time.Duration, now time.Time) []time.Time {
+	deletions := q.deletions[namespace]
+	expired := 0
+	for expired < len(deletions) && now.Sub(deletions[expired]) >= window {
+		expired++
+	}
+	if expired == 0 {
+		return deletions
+	}
+
+	deletions = deletions[expired:]
+	if len(deletions) == 0 {
+		delete(q.deletions, namespace)
+	} else {
+		q.deletions[namespace] = deletions
+	}
+	q.report(namespace, len(deletions))
+	return deletions
+}
+
+// reserveNamespaceQuota counts a force deletion against the quota of the namespace, if any, and returns how long to
+// wait before retrying if the quota is exceeded.
+func (kc *KubernetesPodController) reserveNamespaceQuota(namespace string, now time.Time) (reserved bool, delay time.Duration, err error) {
+	quotasSetting, err := kc.ds.GetSettingWithAutoFillingRO(types.SettingNameNodeDownPodDeletionNamespaceQuotas)
+	if err != nil {
+		return false, 0, err
+	}
+	quotas, err := types.UnmarshalNamespaceForceDeletionQuotas(quotasSetting.Value)
+	if err != nil {
+		return false, 0, err
+	}
+	quota, ok := quotas[namespace]
+	if !ok {
+		quota = quotas[types.NamespaceForceDeletionQuotaDefault]
+	}
+	if quota <= 0 {
+		return false, 0, nil
+	}
+
+	window, err := kc.ds.GetSettingAsInt(types.SettingNameNodeDownPodDeletionNamespaceQuotaWindow)
+	if err != nil {
+		return false, 0, err
+	}
+
+	delay = kc.forceDeletionQuota.Reserve(namespace, quota, time.Duration(window)*time.Second, now)
+	return delay == 0, delay, nil
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+)
+
+func TestNamespaceQuota(t *testing.T) {
+	reported := map[string]int{}
+	quota := newNamespaceQuota(func(namespace string, count int) { reported[namespace] = count })
+	now := time.Now()
+	window := 10 * time.Minute
+
+	// Each namespace has its own quota.
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, time.Duration(0), quota.Reserve("tenant-a", 2, window, now.Add(time.Duration(i)*time.Minute)))
+	}
+	assert.Equal(t, 2, reported["tenant-a"])
+	assert.Equal(t, 8*time.Minute, quota.Reserve("tenant-a", 2, window, now.Add(2*time.Minute)))
+	assert.Equal(t, 2, reported["tenant-a"])
+	assert.Equal(t, time.Duration(0), quota.Reserve("tenant-b", 2, window, now))
+	assert.Equal(t, 1, reported["tenant-b"])
+
+	// A released deletion gives the quota back.
+	quota.Release("tenant-a", now.Add(time.Minute))
+	assert.Equal(t, 1, reported["tenant-a"])
+	assert.Equal(t, time.Duration(0), quota.Reserve("tenant-a", 2, window, now.Add(2*time.Minute)))
+
+	// The deletions out of the window no longer count.
+	assert.Equal(t, time.Duration(0), quota.Reserve("tenant-a", 2, window, now.Add(window)))
+	assert.Equal(t, 2, reported["tenant-a"])
+
+	// A non-positive quota disables the quota.
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, time.Duration(0), quota.Reserve("tenant-c", 0, window, now))
+	}
+	assert.NotContains(t, reported, "tenant-c")
+}
+
+func TestPodDeletionNamespaceQuota(t *testing.T) {
+	newPod := func(name, namespace string) *corev1.Pod {
+		pod := newTestTerminatingPod(TestNode2, -time.Minute)
+		pod.Name = name
+		pod.Namespace = namespace
+		return pod
+	}
+	firstPod := newPod("test-pod-1", "tenant-a")
+	secondPod := newPod("test-pod-2", "tenant-a")
+	otherTenantPod := newPod("test-pod-3", "tenant-b")
+	unlimitedPods := []*corev1.Pod{newPod("test-pod-4", "tenant-c"), newPod("test-pod-5", "tenant-c")}
+	f := newTestKubernetesPodController(t, map[types.SettingName]string{
+		types.SettingNameNodeDownPodDeletionPolicy:          string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+		types.SettingNameNodeDownPodDeletionNamespaceQuotas: "*:1;tenant-c:0",
+	}, firstPod, secondPod, otherTenantPod, unlimitedPods[0], unlimitedPods[1])
+
+	isDeleted := func(pod *corev1.Pod) bool {
+		_, err := f.kubeClient.CoreV1().Pods(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+		if err != nil {
+			require.True(t, datastore.ErrorIsNotFound(err))
+			return true
+		}
+		return false
+	}
+
+	// The first deletion uses the quota of the namespace, and the second one is deferred.
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(firstPod, TestNode2, firstPod.Namespace))
+	assert.True(t, isDeleted(firstPod))
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(secondPod, TestNode2, secondPod.Namespace))
+	assert.False(t, isDeleted(secondPod))
+
+	// The quota of a namespace does not defer the deletions of the other namespaces.
+	require.NoError(t, f.kc.handlePodDeletionIfNodeDown(otherTenantPod, TestNode2, otherTenantPod.Namespace))
+	assert.True(t, isDeleted(otherTenantPod))
+	for _, pod := range unlimitedPods {
+		require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, pod.Namespace))
+		assert.True(t, isDeleted(pod))
+	}
+
+	var skipped []string
+	for len(f.fakeRecorder.Events) > 0 {
+		if event := <-f.fakeRecorder.Events; strings.Contains(event, constant.EventReasonPodDeletionSkipped) {
+			skipped = append(skipped, event)
+		}
+	}
+	require.Len(t, skipped, 1)
+	assert.Contains(t, skipped[0], "force deletion quota of namespace tenant-a is exceeded")
+}
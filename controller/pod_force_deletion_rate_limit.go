@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// namespaceRateLimiter keeps an independent token bucket per namespace, so a
+// namespace with many pods to handle cannot consume the budget of the others.
+type namespaceRateLimiter struct {
+	lock sync.Mutex
+
+	// limit is the number of events allowed per minute in each namespace
+	limit    int
+	limiters map[string]*rate.Limiter
+}
+
+func newNamespaceRateLimiter() *namespaceRateLimiter {
+	return &namespaceRateLimiter{
+		limiters: map[string]*rate.Limiter{},
+	}
+}
+
+// Delay consumes a token of the namespace and returns zero if the event is allowed at now.
+// Otherwise, it returns how long the caller should wait before retrying, without consuming a token.
+// A non-positive limit disables rate limiting.
+func (l *namespaceRateLimiter) Delay(namespace string, limit int, now time.Time) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.limit != limit {
+		l.limit = limit
+		l.limiters = map[string]*rate.Limiter{}
+	}
+
+	limiter, ok := l.limiters[namespace]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(float64(limit)/time.Minute.Seconds()), limit)
+		l.limiters[namespace] = limiter
+	}
+
+	if limiter.AllowN(now, 1) {
+		return 0
+	}
+
+	reservation := limiter.ReserveN(now, 1)
+	delay := reservation.DelayFrom(now)
+	reservation.CancelAt(now)
+	return delay
+}
+
+// enqueuePacer is a token bucket handing out the delays to spread a flood of events over time.
+type enqueuePacer struct {
+	limiter *rate.Limiter
+}
+
+func newEnqueuePacer(ratePerSecond, burst int) *enqueuePacer {
+	return &enqueuePacer{
+		limiter: rate.NewLimiter(rate.Limit(ratePerSecond), burst),
+	}
+}
+
+// Delay reserves a turn at now and returns how long the caller should wait for it.
+// Unlike namespaceRateLimiter.Delay, the turn is always consumed, so the following
+// callers are delayed further and the events are spread evenly.
+func (p *enqueuePacer) Delay(now time.Time) time.Duration {
+	return p.limiter.ReserveN(now, 1).DelayFrom(now)
+}
+
+// enqueuePodKeyPaced adds the pod to the queue right away while within the burst of the pacer,
+// otherwise it delays the pod until its turn.
+func (kc *KubernetesPodController) enqueuePodKeyPaced(key string) {
+	if delay := kc.enqueuePacer.Delay(time.Now()); delay > 0 {
+		kc.queue.AddAfter(key, delay)
+		return
+	}
+	kc.queue.Add(key)
+}
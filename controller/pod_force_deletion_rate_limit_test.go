@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceRateLimiterFairness(t *testing.T) {
+	limiter := newNamespaceRateLimiter()
+	now := time.Now()
+	limit := 2
+
+	// Both namespaces have many pods waiting, interleaved as the workqueue would hand them out.
+	allowed := map[string]int{}
+	for i := 0; i < 10; i++ {
+		for _, namespace := range []string{"busy-a", "busy-b"} {
+			if limiter.Delay(namespace, limit, now) == 0 {
+				allowed[namespace]++
+			}
+		}
+	}
+	assert.Equal(t, limit, allowed["busy-a"])
+	assert.Equal(t, limit, allowed["busy-b"])
+
+	// A namespace which exhausted its budget must not block a namespace that did not.
+	assert.Greater(t, limiter.Delay("busy-a", limit, now), time.Duration(0))
+	assert.Equal(t, time.Duration(0), limiter.Delay("quiet", limit, now))
+
+	// The budget of each namespace refills independently.
+	later := now.Add(time.Minute)
+	assert.Equal(t, time.Duration(0), limiter.Delay("busy-a", limit, later))
+	assert.Equal(t, time.Duration(0), limiter.Delay("busy-b", limit, later))
+}
+
+func TestNamespaceRateLimiterDisabled(t *testing.T) {
+	limiter := newNamespaceRateLimiter()
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, time.Duration(0), limiter.Delay("busy-a", 0, now))
+	}
+}
+
+func TestEnqueuePacerSpreadsRelist(t *testing.T) {
+	burst, ratePerSecond, pods := 10, 100, 1000
+	pacer := newEnqueuePacer(ratePerSecond, burst)
+	now := time.Now()
+
+	// A relist delivers all the pods at the same time.
+	delays := make([]time.Duration, pods)
+	for i := range delays {
+		delays[i] = pacer.Delay(now)
+	}
+
+	for i := 0; i < burst; i++ {
+		assert.Zero(t, delays[i], "pod %d within the burst should not be delayed", i)
+	}
+	interval := time.Second / time.Duration(ratePerSecond)
+	for i := burst; i < pods; i++ {
+		assert.InDelta(t, time.Duration(i-burst+1)*interval, delays[i], float64(time.Millisecond), "pod %d", i)
+	}
+}
+
+func TestEnqueuePodKeyPacedOnRelist(t *testing.T) {
+	burst, pods := 10, 1000
+	kc := &KubernetesPodController{
+		baseController: newBaseController("longhorn-kubernetes-pod-test", logrus.StandardLogger()),
+		enqueuePacer:   newEnqueuePacer(1, burst),
+	}
+	defer kc.queue.ShutDown()
+
+	for i := 0; i < pods; i++ {
+		kc.enqueuePodKeyPaced(fmt.Sprintf("%v/test-pod-%d", TestNamespace, i))
+	}
+
+	// Only the burst is ready to be processed, the others are waiting for their turn.
+	assert.Equal(t, burst, kc.queue.Len())
+}
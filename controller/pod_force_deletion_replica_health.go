@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"github.com/cockroachdb/errors"
+
+	corev1 "k8s.io/api/core/v1"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+// getNeverHealthyVolume returns the name of a Longhorn volume of the pod that has never been healthy, or an empty string
+// if all the volumes of the pod have been healthy.
+func (kc *KubernetesPodController) getNeverHealthyVolume(pod *corev1.Pod) (string, error) {
+	volumes, err := kc.getAssociatedVolumes(pod)
+	if err != nil {
+		return "", err
+	}
+	for _, v := range volumes {
+		lastHealthyAt, err := kc.ds.GetVolumeLastHealthyTime(v.Name)
+		if err != nil {
+			return "", err
+		}
+		if lastHealthyAt.IsZero() {
+			return v.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// getVolumeWithoutHealthyReplica returns the first volume of the pod without a healthy replica off the down node,
+// or an empty string if there is none.
+func (kc *KubernetesPodController) getVolumeWithoutHealthyReplica(pod *corev1.Pod, nodeID string) (string, error) {
+	volumes, err := kc.getAssociatedVolumes(pod)
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range volumes {
+		replicas, err := kc.ds.ListVolumeReplicasRO(v.Name)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to list replicas of volume %v", v.Name)
+		}
+		if !hasHealthyReplicaOffNode(replicas, nodeID) {
+			return v.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// hasHealthyReplicaOffNode returns true if a replica that is not on the down node has been healthy and has not failed since.
+// The replica does not need to be running, since the replicas are stopped while the volume is detached from the down node.
+func hasHealthyReplicaOffNode(replicas map[string]*longhorn.Replica, downNodeID string) bool {
+	for _, r := range replicas {
+		if r.Spec.NodeID == downNodeID || r.DeletionTimestamp != nil {
+			continue
+		}
+		if r.Spec.HealthyAt != "" && r.Spec.FailedAt == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// getVolumeWaitingForRebuild returns the first volume of the pod which lost a replica with the down node
+// and has not started rebuilding it on a surviving node yet, or an empty string if there is none.
+func (kc *KubernetesPodController) getVolumeWaitingForRebuild(pod *corev1.Pod, nodeID string) (string, error) {
+	volumes, err := kc.getAssociatedVolumes(pod)
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range volumes {
+		replicas, err := kc.ds.ListVolumeReplicasRO(v.Name)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to list replicas of volume %v", v.Name)
+		}
+		if !isReplicaRebuildStarted(replicas, nodeID) {
+			return v.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// isReplicaRebuildStarted returns true if no replica is on the down node, or if a replica is being rebuilt on a surviving node.
+// A replica being rebuilt is running but has never been healthy.
+func isReplicaRebuildStarted(replicas map[string]*longhorn.Replica, downNodeID string) bool {
+	hasReplicaOnDownNode := false
+	for _, r := range replicas {
+		if r.Spec.NodeID == downNodeID {
+			hasReplicaOnDownNode = true
+			continue
+		}
+		if r.DeletionTimestamp == nil && r.Spec.HealthyAt == "" && r.Spec.FailedAt == "" &&
+			r.Status.CurrentState == longhorn.InstanceStateRunning {
+			return true
+		}
+	}
+	return !hasReplicaOnDownNode
+}
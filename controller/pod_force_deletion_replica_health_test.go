@@ -0,0 +1,131 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+func TestHasHealthyReplicaOffNode(t *testing.T) {
+	newReplica := func(nodeID, healthyAt, failedAt string) *longhorn.Replica {
+		r := &longhorn.Replica{}
+		r.Spec.NodeID = nodeID
+		r.Spec.HealthyAt = healthyAt
+		r.Spec.FailedAt = failedAt
+		return r
+	}
+	healthyAt := "2026-01-01T00:00:00Z"
+	deletingReplica := newReplica(TestNode2, healthyAt, "")
+	deletingReplica.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	tests := map[string]struct {
+		replicas map[string]*longhorn.Replica
+		expected bool
+	}{
+		"healthy replicas on the down node and a surviving node": {
+			replicas: map[string]*longhorn.Replica{
+				"r-1": newReplica(TestNode1, healthyAt, ""),
+				"r-2": newReplica(TestNode2, healthyAt, ""),
+			},
+			expected: true,
+		},
+		"healthy replica only on the down node": {
+			replicas: map[string]*longhorn.Replica{
+				"r-1": newReplica(TestNode1, healthyAt, ""),
+			},
+			expected: false,
+		},
+		"failed replica on a surviving node": {
+			replicas: map[string]*longhorn.Replica{
+				"r-1": newReplica(TestNode1, healthyAt, ""),
+				"r-2": newReplica(TestNode2, healthyAt, healthyAt),
+			},
+			expected: false,
+		},
+		"never healthy replica on a surviving node": {
+			replicas: map[string]*longhorn.Replica{
+				"r-1": newReplica(TestNode1, healthyAt, ""),
+				"r-2": newReplica(TestNode2, "", ""),
+			},
+			expected: false,
+		},
+		"deleting replica on a surviving node": {
+			replicas: map[string]*longhorn.Replica{
+				"r-1": newReplica(TestNode1, healthyAt, ""),
+				"r-2": deletingReplica,
+			},
+			expected: false,
+		},
+		"no replica": {
+			replicas: map[string]*longhorn.Replica{},
+			expected: false,
+		},
+	}
+
+	for name, tc := range tests {
+		assert.Equal(t, tc.expected, hasHealthyReplicaOffNode(tc.replicas, TestNode1), name)
+	}
+}
+
+func TestIsReplicaRebuildStarted(t *testing.T) {
+	newReplica := func(nodeID, healthyAt, failedAt string, state longhorn.InstanceState) *longhorn.Replica {
+		r := &longhorn.Replica{}
+		r.Spec.NodeID = nodeID
+		r.Spec.HealthyAt = healthyAt
+		r.Spec.FailedAt = failedAt
+		r.Status.CurrentState = state
+		return r
+	}
+	healthyAt := "2026-01-01T00:00:00Z"
+
+	tests := map[string]struct {
+		replicas map[string]*longhorn.Replica
+		expected bool
+	}{
+		"no replica on the down node": {
+			replicas: map[string]*longhorn.Replica{
+				"r-1": newReplica(TestNode2, healthyAt, "", longhorn.InstanceStateRunning),
+			},
+			expected: true,
+		},
+		"replica lost with the down node and no rebuild": {
+			replicas: map[string]*longhorn.Replica{
+				"r-1": newReplica(TestNode1, healthyAt, "", longhorn.InstanceStateUnknown),
+				"r-2": newReplica(TestNode2, healthyAt, "", longhorn.InstanceStateRunning),
+			},
+			expected: false,
+		},
+		"new replica scheduled but not running": {
+			replicas: map[string]*longhorn.Replica{
+				"r-1": newReplica(TestNode1, healthyAt, "", longhorn.InstanceStateUnknown),
+				"r-2": newReplica(TestNode2, healthyAt, "", longhorn.InstanceStateRunning),
+				"r-3": newReplica(TestNode2, "", "", longhorn.InstanceStateStopped),
+			},
+			expected: false,
+		},
+		"failed replica on a surviving node": {
+			replicas: map[string]*longhorn.Replica{
+				"r-1": newReplica(TestNode1, healthyAt, "", longhorn.InstanceStateUnknown),
+				"r-2": newReplica(TestNode2, "", healthyAt, longhorn.InstanceStateRunning),
+			},
+			expected: false,
+		},
+		"replica rebuilding on a surviving node": {
+			replicas: map[string]*longhorn.Replica{
+				"r-1": newReplica(TestNode1, healthyAt, "", longhorn.InstanceStateUnknown),
+				"r-2": newReplica(TestNode2, healthyAt, "", longhorn.InstanceStateRunning),
+				"r-3": newReplica(TestNode2, "", "", longhorn.InstanceStateRunning),
+			},
+			expected: true,
+		},
+	}
+
+	for name, tc := range tests {
+		assert.Equal(t, tc.expected, isReplicaRebuildStarted(tc.replicas, TestNode1), name)
+	}
+}
@@ -0,0 +1,191 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	poddeletionmetrics "github.com/longhorn/longhorn-manager/metrics_collector/poddeletion"
+)
+
+// podForceDeletionVerification is the outcome of a pod force deletion left to verify.
+type podForceDeletionVerification struct {
+	uid    k8stypes.UID
+	nodeID string
+	// volumes maps the claims of the pod to their Longhorn volumes
+	volumes  map[string]string
+	verifyAt time.Time
+}
+
+// podForceDeletionVerifier keeps the force deleted pods by key until their verification is due.
+type podForceDeletionVerifier struct {
+	lock sync.Mutex
+
+	verifications map[string]podForceDeletionVerification
+}
+
+func newPodForceDeletionVerifier() *podForceDeletionVerifier {
+	return &podForceDeletionVerifier{
+		verifications: map[string]podForceDeletionVerification{},
+	}
+}
+
+// Add schedules the verification of the force deleted pod of the key, replacing a former one of the same key.
+func (v *podForceDeletionVerifier) Add(key string, verification podForceDeletionVerification) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	v.verifications[key] = verification
+}
+
+// PopDue returns and removes the verification of the key if it is due by now.
+func (v *podForceDeletionVerifier) PopDue(key string, now time.Time) (podForceDeletionVerification, bool) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	verification, ok := v.verifications[key]
+	if !ok || now.Before(verification.verifyAt) {
+		return podForceDeletionVerification{}, false
+	}
+	delete(v.verifications, key)
+	return verification, true
+}
+
+// schedulePodForceDeletionVerification requeues the force deleted pod after the verification delay setting,
+// if the verification is enabled.
+func (kc *KubernetesPodController) schedulePodForceDeletionVerification(pod *corev1.Pod, nodeID string) error {
+	delaySeconds, err := kc.ds.GetSettingAsInt(types.SettingNameNodeDownPodDeletionVerificationDelay)
+	if err != nil {
+		return err
+	}
+	if delaySeconds <= 0 {
+		return nil
+	}
+	delay := time.Duration(delaySeconds) * time.Second
+
+	pvs, err := kc.getLonghornPersistentVolumesOfPod(pod)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the Longhorn persistent volumes of pod %v to verify its force deletion", pod.Name)
+	}
+	volumeHandles := map[string]string{}
+	for _, pv := range pvs {
+		volumeHandles[pv.Name] = pv.Spec.CSI.VolumeHandle
+	}
+	volumes := map[string]string{}
+	for _, v := range pod.Spec.Volumes {
+		if v.PersistentVolumeClaim == nil {
+			continue
+		}
+		// The claims were just resolved to the persistent volumes, so a claim failing here has no Longhorn volume.
+		pvc, err := kc.ds.GetPersistentVolumeClaimRO(pod.Namespace, v.PersistentVolumeClaim.ClaimName)
+		if err != nil {
+			continue
+		}
+		if volumeHandle, ok := volumeHandles[pvc.Spec.VolumeName]; ok {
+			volumes[pvc.Name] = volumeHandle
+		}
+	}
+
+	key := pod.Namespace + "/" + pod.Name
+	kc.podForceDeletionVerifier.Add(key, podForceDeletionVerification{
+		uid:      pod.UID,
+		nodeID:   nodeID,
+		volumes:  volumes,
+		verifyAt: time.Now().Add(delay),
+	})
+	kc.enqueuePodAfter(pod, delay)
+	return nil
+}
+
+// verifyPodForceDeletion checks the outcome of the force deletion of the pod of the key once its verification is due.
+// A pod still present is counted as unverified. Otherwise, the pending replacement pods using the volumes of the
+// pod are reported if a volume of theirs is faulted or not attached to their node.
+func (kc *KubernetesPodController) verifyPodForceDeletion(key, namespace, name string) error {
+	verification, ok := kc.podForceDeletionVerifier.PopDue(key, time.Now())
+	if !ok {
+		return nil
+	}
+
+	pods, err := kc.kubeClient.CoreV1().Pods(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		kc.podForceDeletionVerifier.Add(key, verification)
+		return errors.Wrapf(err, "failed to list pods to verify the force deletion of pod %v", name)
+	}
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.UID != verification.uid {
+			continue
+		}
+		poddeletionmetrics.IncVerifications(poddeletionmetrics.VerificationResultUnverified)
+		kc.logger.Warnf("%v: pod %v force deleted on downed node %v is still present at the verification", controllerAgentName, name, verification.nodeID)
+		kc.eventRecorder.Eventf(pod, corev1.EventTypeWarning, constant.EventReasonForceDeletionUnverified,
+			"Pod %v force deleted on downed node %v is still present at the verification", name, verification.nodeID)
+		return nil
+	}
+	poddeletionmetrics.IncVerifications(poddeletionmetrics.VerificationResultVerified)
+
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		for _, v := range pod.Spec.Volumes {
+			if v.PersistentVolumeClaim == nil {
+				continue
+			}
+			volumeName, ok := verification.volumes[v.PersistentVolumeClaim.ClaimName]
+			if !ok {
+				continue
+			}
+			issue, err := kc.getReplacementPodVolumeIssue(pod, volumeName)
+			if err != nil {
+				return err
+			}
+			if issue == "" {
+				continue
+			}
+			kc.eventRecorder.Eventf(pod, corev1.EventTypeWarning, constant.EventReasonReplacementPodPending,
+				"Replacement pod %v of pod %v force deleted on downed node %v is pending: %v", pod.Name, name, verification.nodeID, issue)
+			break
+		}
+	}
+	return nil
+}
+
+// getReplacementPodVolumeIssue returns why the Longhorn volume may keep the pending pod from starting,
+// or an empty string if it does not.
+func (kc *KubernetesPodController) getReplacementPodVolumeIssue(pod *corev1.Pod, volumeName string) (string, error) {
+	volume, err := kc.ds.GetVolumeRO(volumeName)
+	if err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return fmt.Sprintf("volume %v is not found", volumeName), nil
+		}
+		return "", errors.Wrapf(err, "failed to get volume %v of replacement pod %v", volumeName, pod.Name)
+	}
+
+	if volume.Status.Robustness == longhorn.VolumeRobustnessFaulted {
+		return fmt.Sprintf("volume %v is faulted", volumeName), nil
+	}
+	// The volume is only attached once the pod is scheduled to a node.
+	if pod.Spec.NodeName == "" {
+		return "", nil
+	}
+	if volume.Status.State != longhorn.VolumeStateAttached || volume.Status.CurrentNodeID != pod.Spec.NodeName {
+		return fmt.Sprintf("volume %v is %v on node %q instead of attached to node %v",
+			volumeName, volume.Status.State, volume.Status.CurrentNodeID, pod.Spec.NodeName), nil
+	}
+	return "", nil
+}
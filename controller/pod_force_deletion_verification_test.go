@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/longhorn/longhorn-manager/constant"
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+func TestPodForceDeletionVerifierPopDue(t *testing.T) {
+	now := time.Now()
+	v := newPodForceDeletionVerifier()
+	v.Add("default/test-pod", podForceDeletionVerification{uid: "test-uid", verifyAt: now.Add(time.Minute)})
+
+	_, ok := v.PopDue("default/test-pod", now)
+	assert.False(t, ok, "verification popped before it is due")
+
+	verification, ok := v.PopDue("default/test-pod", now.Add(time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, "test-uid", string(verification.uid))
+
+	_, ok = v.PopDue("default/test-pod", now.Add(time.Minute))
+	assert.False(t, ok, "verification popped twice")
+}
+
+func TestSchedulePodForceDeletionVerification(t *testing.T) {
+	for name, delay := range map[string]string{"disabled": "0", "enabled": "60"} {
+		t.Run(name, func(t *testing.T) {
+			pod := newTestTerminatingPod(TestNode2, -time.Minute, "longhorn-claim")
+			objs := append([]runtime.Object{pod}, newTestBoundClaim("longhorn-claim", types.LonghornDriverName, TestVolumeName)...)
+			f := newTestKubernetesPodController(t, map[types.SettingName]string{
+				types.SettingNameNodeDownPodDeletionPolicy:            string(types.NodeDownPodDeletionPolicyDeleteStatefulSetPod),
+				types.SettingNameNodeDownPodDeletionVerificationDelay: delay,
+			}, objs...)
+
+			require.NoError(t, f.kc.handlePodDeletionIfNodeDown(pod, TestNode2, TestNamespace))
+
+			verification, ok := f.kc.podForceDeletionVerifier.verifications[TestNamespace+"/"+pod.Name]
+			if delay == "0" {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, TestNode2, verification.nodeID)
+			assert.Equal(t, map[string]string{"longhorn-claim": TestVolumeName}, verification.volumes)
+		})
+	}
+}
+
+func TestVerifyPodForceDeletion(t *testing.T) {
+	newReplacementPod := func(nodeID string, phase corev1.PodPhase) *corev1.Pod {
+		pod := newTestTerminatingPod(nodeID, 0, "longhorn-claim")
+		pod.UID = "replacement-uid"
+		pod.DeletionTimestamp = nil
+		pod.Status.Phase = phase
+		return pod
+	}
+	newVolume := func(state longhorn.VolumeState, robustness longhorn.VolumeRobustness, nodeID string) *longhorn.Volume {
+		return &longhorn.Volume{
+			ObjectMeta: metav1.ObjectMeta{Name: TestVolumeName, Namespace: TestNamespace},
+			Status: longhorn.VolumeStatus{
+				State:         state,
+				Robustness:    robustness,
+				CurrentNodeID: nodeID,
+			},
+		}
+	}
+	forceDeletedPod := newTestTerminatingPod(TestNode2, -time.Minute, "longhorn-claim")
+	forceDeletedPod.UID = "force-deleted-uid"
+
+	tests := map[string]struct {
+		objs          []runtime.Object
+		expectedEvent string
+	}{
+		"pod gone without replacement": {},
+		"pod still present": {
+			objs:          []runtime.Object{forceDeletedPod},
+			expectedEvent: constant.EventReasonForceDeletionUnverified,
+		},
+		"replacement running": {
+			objs: []runtime.Object{
+				newReplacementPod(TestNode1, corev1.PodRunning),
+				newVolume(longhorn.VolumeStateAttached, longhorn.VolumeRobustnessHealthy, TestNode1),
+			},
+		},
+		"replacement pending on attached volume": {
+			objs: []runtime.Object{
+				newReplacementPod(TestNode1, corev1.PodPending),
+				newVolume(longhorn.VolumeStateAttached, longhorn.VolumeRobustnessHealthy, TestNode1),
+			},
+		},
+		"replacement pending before scheduling": {
+			objs: []runtime.Object{
+				newReplacementPod("", corev1.PodPending),
+				newVolume(longhorn.VolumeStateDetached, longhorn.VolumeRobustnessUnknown, ""),
+			},
+		},
+		"replacement pending on detached volume": {
+			objs: []runtime.Object{
+				newReplacementPod(TestNode1, corev1.PodPending),
+				newVolume(longhorn.VolumeStateDetached, longhorn.VolumeRobustnessUnknown, ""),
+			},
+			expectedEvent: constant.EventReasonReplacementPodPending,
+		},
+		"replacement pending on faulted volume": {
+			objs: []runtime.Object{
+				newReplacementPod("", corev1.PodPending),
+				newVolume(longhorn.VolumeStateDetached, longhorn.VolumeRobustnessFaulted, ""),
+			},
+			expectedEvent: constant.EventReasonReplacementPodPending,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			f := newTestKubernetesPodController(t, nil, tc.objs...)
+			key := TestNamespace + "/" + forceDeletedPod.Name
+			f.kc.podForceDeletionVerifier.Add(key, podForceDeletionVerification{
+				uid:      forceDeletedPod.UID,
+				nodeID:   TestNode2,
+				volumes:  map[string]string{"longhorn-claim": TestVolumeName},
+				verifyAt: time.Now(),
+			})
+
+			require.NoError(t, f.kc.verifyPodForceDeletion(key, TestNamespace, forceDeletedPod.Name))
+			_, ok := f.kc.podForceDeletionVerifier.verifications[key]
+			assert.False(t, ok)
+
+			if tc.expectedEvent == "" {
+				assert.Empty(t, f.fakeRecorder.Events)
+				return
+			}
+			require.Len(t, f.fakeRecorder.Events, 1)
+			assert.Contains(t, <-f.fakeRecorder.Events, tc.expectedEvent)
+		})
+	}
+}
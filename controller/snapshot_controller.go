@@ -3,6 +3,7 @@ package controller
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"time"
 
@@ -22,6 +23,8 @@ import (
 	clientset "k8s.io/client-go/kubernetes"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 
+	etypes "github.com/longhorn/longhorn-engine/pkg/types"
+
 	"github.com/longhorn/longhorn-manager/constant"
 	"github.com/longhorn/longhorn-manager/datastore"
 	"github.com/longhorn/longhorn-manager/engineapi"
@@ -738,6 +741,10 @@ func (sc *SnapshotController) handleSnapshotCreate(snapshot *longhorn.Snapshot,
 		return err
 	}
 	if snapshotInfo == nil {
+		if err := sc.checkSnapshotMaxSize(snapshot, engine); err != nil {
+			return err
+		}
+
 		sc.logger.Infof("Creating snapshot %v of volume %v", snapshot.Name, snapshot.Spec.Volume)
 		_, err = engineClientProxy.SnapshotCreate(engine, snapshot.Name, snapshot.Spec.Labels, freezeFilesystem)
 		if err != nil {
@@ -747,6 +754,73 @@ func (sc *SnapshotController) handleSnapshotCreate(snapshot *longhorn.Snapshot,
 	return nil
 }
 
+// checkSnapshotMaxSize applies the SnapshotMaxSizeAction of the volume before creating a new snapshot,
+// by either failing the new snapshot or deleting the oldest snapshots to make room for it.
+func (sc *SnapshotController) checkSnapshotMaxSize(snapshot *longhorn.Snapshot, engine *longhorn.Engine) error {
+	volume, err := sc.ds.GetVolumeRO(snapshot.Spec.Volume)
+	if err != nil {
+		return err
+	}
+
+	prunedSnapshots, err := enforceSnapshotMaxSize(engine.Status.Snapshots, volume.Spec.SnapshotMaxSize, volume.Spec.SnapshotMaxSizeAction)
+	for _, name := range prunedSnapshots {
+		sc.logger.Infof("Pruning snapshot %v of volume %v to make room for snapshot %v", name, snapshot.Spec.Volume, snapshot.Name)
+		if err := sc.ds.DeleteSnapshot(name); err != nil && !datastore.ErrorIsNotFound(err) {
+			return errors.Wrapf(err, "failed to prune snapshot %v", name)
+		}
+	}
+	return err
+}
+
+// enforceSnapshotMaxSize returns the oldest snapshots to prune to bring the snapshot space usage below
+// snapshotMaxSize, and an error if the new snapshot cannot be created until the space is reclaimed.
+func enforceSnapshotMaxSize(snapshots map[string]*longhorn.SnapshotInfo, snapshotMaxSize int64, action longhorn.SnapshotMaxSizeAction) ([]string, error) {
+	if snapshotMaxSize == 0 {
+		return nil, nil
+	}
+
+	usage := int64(0)
+	reclaimable := int64(0)
+	var candidates []*longhorn.SnapshotInfo
+	for name, snapshot := range snapshots {
+		if name == etypes.VolumeHeadName || snapshot == nil {
+			continue
+		}
+		size, err := strconv.ParseInt(snapshot.Size, 10, 64)
+		if err != nil {
+			continue
+		}
+		usage += size
+		if snapshot.Removed {
+			reclaimable += size
+			continue
+		}
+		candidates = append(candidates, snapshot)
+	}
+	if usage < snapshotMaxSize {
+		return nil, nil
+	}
+
+	if action != longhorn.SnapshotMaxSizeActionPrune {
+		return nil, fmt.Errorf("snapshot space usage %v reached SnapshotMaxSize %v", usage, snapshotMaxSize)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Created < candidates[j].Created
+	})
+	var prunedSnapshots []string
+	remaining := usage - reclaimable
+	for _, snapshot := range candidates {
+		if remaining < snapshotMaxSize {
+			break
+		}
+		size, _ := strconv.ParseInt(snapshot.Size, 10, 64)
+		remaining -= size
+		prunedSnapshots = append(prunedSnapshots, snapshot.Name)
+	}
+	return prunedSnapshots, fmt.Errorf("waiting for the space of the pruned snapshots to be reclaimed since snapshot space usage %v reached SnapshotMaxSize %v", usage, snapshotMaxSize)
+}
+
 // handleSnapshotDeletion reaches out to engine process to check and delete the snapshot
 func (sc *SnapshotController) handleSnapshotDeletion(snapshot *longhorn.Snapshot, engine *longhorn.Engine) error {
 	engineCliClient, err := GetBinaryClientForEngine(engine, sc.engineClientCollection, engine.Status.CurrentImage)
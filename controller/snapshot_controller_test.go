@@ -3,6 +3,12 @@ package controller
 import (
 	"fmt"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	etypes "github.com/longhorn/longhorn-engine/pkg/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 )
 
 func TestShouldUpdateObject(t *testing.T) {
@@ -28,3 +34,74 @@ func TestShouldUpdateObject(t *testing.T) {
 		t.Fatal("reconcileErr1 must be non-updatable error")
 	}
 }
+
+func TestEnforceSnapshotMaxSize(t *testing.T) {
+	snapshots := map[string]*longhorn.SnapshotInfo{
+		"snap-1":              {Name: "snap-1", Size: "300", Created: "2024-01-01T00:00:00Z"},
+		"snap-2":              {Name: "snap-2", Size: "400", Created: "2024-01-02T00:00:00Z"},
+		"snap-3":              {Name: "snap-3", Size: "300", Created: "2024-01-03T00:00:00Z"},
+		etypes.VolumeHeadName: {Name: etypes.VolumeHeadName, Size: "5000"},
+	}
+
+	tests := map[string]struct {
+		snapshots       map[string]*longhorn.SnapshotInfo
+		snapshotMaxSize int64
+		action          longhorn.SnapshotMaxSizeAction
+
+		expectPruned []string
+		expectError  bool
+	}{
+		"no limit": {
+			snapshots: snapshots,
+			action:    longhorn.SnapshotMaxSizeActionBlock,
+		},
+		"block below the limit": {
+			snapshots:       snapshots,
+			snapshotMaxSize: 1001,
+			action:          longhorn.SnapshotMaxSizeActionBlock,
+		},
+		"block at the limit": {
+			snapshots:       snapshots,
+			snapshotMaxSize: 1000,
+			action:          longhorn.SnapshotMaxSizeActionBlock,
+			expectError:     true,
+		},
+		"prune below the limit": {
+			snapshots:       snapshots,
+			snapshotMaxSize: 1001,
+			action:          longhorn.SnapshotMaxSizeActionPrune,
+		},
+		"prune the oldest snapshot": {
+			snapshots:       snapshots,
+			snapshotMaxSize: 1000,
+			action:          longhorn.SnapshotMaxSizeActionPrune,
+			expectPruned:    []string{"snap-1"},
+			expectError:     true,
+		},
+		"prune the oldest snapshots": {
+			snapshots:       snapshots,
+			snapshotMaxSize: 500,
+			action:          longhorn.SnapshotMaxSizeActionPrune,
+			expectPruned:    []string{"snap-1", "snap-2"},
+			expectError:     true,
+		},
+		"prune waits for removed snapshots": {
+			snapshots: map[string]*longhorn.SnapshotInfo{
+				"snap-1": {Name: "snap-1", Size: "300", Created: "2024-01-01T00:00:00Z", Removed: true},
+				"snap-2": {Name: "snap-2", Size: "400", Created: "2024-01-02T00:00:00Z"},
+				"snap-3": {Name: "snap-3", Size: "300", Created: "2024-01-03T00:00:00Z"},
+			},
+			snapshotMaxSize: 1000,
+			action:          longhorn.SnapshotMaxSizeActionPrune,
+			expectError:     true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pruned, err := enforceSnapshotMaxSize(tc.snapshots, tc.snapshotMaxSize, tc.action)
+			assert.Equal(t, tc.expectPruned, pruned)
+			assert.Equal(t, tc.expectError, err != nil)
+		})
+	}
+}
@@ -3,6 +3,9 @@ package controller
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -15,6 +18,7 @@ import (
 	"k8s.io/kubernetes/pkg/controller"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientset "k8s.io/client-go/kubernetes"
@@ -41,6 +45,10 @@ type VolumeAttachmentController struct {
 
 	ds *datastore.DataStore
 
+	// attachmentConflicts holds the last reported conflicting nodes of the volumes
+	attachmentConflictsLock sync.Mutex
+	attachmentConflicts     map[string]string
+
 	cacheSyncs []cache.InformerSynced
 }
 
@@ -70,6 +78,8 @@ func NewLonghornVolumeAttachmentController(
 
 		kubeClient:    kubeClient,
 		eventRecorder: eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: "longhorn-volume-attachment-controller"}),
+
+		attachmentConflicts: map[string]string{},
 	}
 
 	var err error
@@ -107,6 +117,14 @@ func NewLonghornVolumeAttachmentController(
 	}
 	vac.cacheSyncs = append(vac.cacheSyncs, ds.KubeNodeInformer.HasSynced)
 
+	if _, err = ds.VolumeAttachmentInformer.AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
+		AddFunc:    vac.enqueueKubernetesVolumeAttachmentChange,
+		UpdateFunc: func(old, cur interface{}) { vac.enqueueKubernetesVolumeAttachmentChange(cur) },
+	}, 0); err != nil {
+		return nil, err
+	}
+	vac.cacheSyncs = append(vac.cacheSyncs, ds.VolumeAttachmentInformer.HasSynced)
+
 	return vac, nil
 }
 
@@ -213,6 +231,39 @@ func (vac *VolumeAttachmentController) enqueueNodeChange(obj interface{}) {
 	}
 }
 
+func (vac *VolumeAttachmentController) enqueueKubernetesVolumeAttachmentChange(obj interface{}) {
+	kubeVA, ok := obj.(*storagev1.VolumeAttachment)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("received unexpected obj: %#v", obj))
+		return
+	}
+
+	if kubeVA.Spec.Attacher != types.LonghornDriverName || kubeVA.Spec.Source.PersistentVolumeName == nil {
+		return
+	}
+
+	pv, err := vac.ds.GetPersistentVolumeRO(*kubeVA.Spec.Source.PersistentVolumeName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			utilruntime.HandleError(fmt.Errorf("failed to get PV of Kubernetes VolumeAttachment %v: %v", kubeVA.Name, err))
+		}
+		return
+	}
+	if pv.Spec.CSI == nil || pv.Spec.CSI.Driver != types.LonghornDriverName {
+		return
+	}
+
+	volumeAttachments, err := vac.ds.ListLonghornVolumeAttachmentByVolumeRO(pv.Spec.CSI.VolumeHandle)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list Longhorn VolumeAttachment of volume %v: %v", pv.Spec.CSI.VolumeHandle, err))
+		return
+	}
+
+	for _, va := range volumeAttachments {
+		vac.enqueueVolumeAttachment(va)
+	}
+}
+
 func (vac *VolumeAttachmentController) Run(workers int, stopCh <-chan struct{}) {
 	defer utilruntime.HandleCrash()
 	defer vac.queue.ShutDown()
@@ -279,6 +330,7 @@ func (vac *VolumeAttachmentController) reconcile(vaName string) (err error) {
 		if !apierrors.IsNotFound(err) {
 			return err
 		}
+		vac.updateReportedAttachmentConflict(vaName, "")
 		return nil
 	}
 
@@ -330,15 +382,101 @@ func (vac *VolumeAttachmentController) reconcile(vaName string) (err error) {
 
 	vac.handleNodeCordoned(va, vol)
 
+	vac.handlePinnedNodeAttachment(va, vol)
+
 	vac.handleVolumeDetachment(va, vol)
 
 	vac.handleVolumeAttachment(va, vol)
 
 	vac.handleVolumeMigration(va, vol)
 
+	vac.handleAttachmentConflict(va, vol)
+
 	return vac.handleVAStatusUpdate(va, vol)
 }
 
+// handleAttachmentConflict reports a RWO volume that Kubernetes considers attached to more than one node,
+// which can happen when a flaky node comes back with a stale attachment. Only the changes of the conflict are
+// reported, so that a lasting conflict does not flood the events.
+func (vac *VolumeAttachmentController) handleAttachmentConflict(va *longhorn.VolumeAttachment, vol *longhorn.Volume) {
+	log := getLoggerForLHVolumeAttachment(vac.logger, va)
+
+	var nodes []string
+	if pvName := vol.Status.KubernetesStatus.PVName; pvName != "" {
+		kubeVAs, err := vac.ds.ListVolumeAttachmentsByPersistentVolumeRO(pvName)
+		if err != nil {
+			log.WithError(err).Warn("Failed to list Kubernetes VolumeAttachments for attachment conflict detection")
+			return
+		}
+		nodes = GetConflictingAttachmentNodes(vol, kubeVAs)
+	}
+
+	conflict := strings.Join(nodes, ",")
+	if !vac.updateReportedAttachmentConflict(vol.Name, conflict) {
+		return
+	}
+
+	if conflict == "" {
+		vac.eventRecorder.Eventf(vol, corev1.EventTypeNormal, constant.EventReasonAttachmentConflict,
+			"Volume %v is no longer attached to multiple nodes", vol.Name)
+		return
+	}
+	vac.eventRecorder.Eventf(vol, corev1.EventTypeWarning, constant.EventReasonAttachmentConflict,
+		"Volume %v is attached to multiple nodes %v while its access mode is %v", vol.Name, nodes, vol.Spec.AccessMode)
+}
+
+// updateReportedAttachmentConflict records the conflicting nodes of the volume
+// and returns true if they are different from the last reported ones.
+func (vac *VolumeAttachmentController) updateReportedAttachmentConflict(volumeName, conflict string) bool {
+	vac.attachmentConflictsLock.Lock()
+	defer vac.attachmentConflictsLock.Unlock()
+
+	if vac.attachmentConflicts[volumeName] == conflict {
+		return false
+	}
+	if conflict == "" {
+		delete(vac.attachmentConflicts, volumeName)
+	} else {
+		vac.attachmentConflicts[volumeName] = conflict
+	}
+	return true
+}
+
+// GetConflictingAttachmentNodes returns the nodes a RWO volume is attached to
+// according to the Kubernetes VolumeAttachments, if there is more than one.
+func GetConflictingAttachmentNodes(vol *longhorn.Volume, kubeVAs []*storagev1.VolumeAttachment) []string {
+	if vol.Spec.AccessMode != longhorn.AccessModeReadWriteOnce || vol.Spec.Migratable {
+		return nil
+	}
+
+	nodeSet := map[string]struct{}{}
+	for _, kubeVA := range kubeVAs {
+		if isActiveAttachmentOfVolume(kubeVA, vol) {
+			nodeSet[kubeVA.Spec.NodeName] = struct{}{}
+		}
+	}
+	if len(nodeSet) < 2 {
+		return nil
+	}
+
+	nodes := make([]string, 0, len(nodeSet))
+	for node := range nodeSet {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+func isActiveAttachmentOfVolume(kubeVA *storagev1.VolumeAttachment, vol *longhorn.Volume) bool {
+	pvName := vol.Status.KubernetesStatus.PVName
+	return pvName != "" &&
+		kubeVA.Spec.Attacher == types.LonghornDriverName &&
+		kubeVA.Spec.Source.PersistentVolumeName != nil &&
+		*kubeVA.Spec.Source.PersistentVolumeName == pvName &&
+		kubeVA.Status.Attached &&
+		kubeVA.DeletionTimestamp == nil
+}
+
 // handleNodeCordoned delete ui attachment ticket from the va when the target node is cordened
 func (vac *VolumeAttachmentController) handleNodeCordoned(va *longhorn.VolumeAttachment, vol *longhorn.Volume) {
 	log := getLoggerForLHVolumeAttachment(vac.logger, va)
@@ -376,6 +514,26 @@ func (vac *VolumeAttachmentController) handleNodeCordoned(va *longhorn.VolumeAtt
 	}
 }
 
+// handlePinnedNodeAttachment deletes the attachment ticket of the node the volume is pinned to at creation once a
+// workload requests the volume, so the pinned node is only the initial attachment target and does not outrank the
+// CSI attachment ticket.
+func (vac *VolumeAttachmentController) handlePinnedNodeAttachment(va *longhorn.VolumeAttachment, vol *longhorn.Volume) {
+	log := getLoggerForLHVolumeAttachment(vac.logger, va)
+
+	pinnedNodeTicketID := getPinnedNodeAttachmentTicketID(vol.Name)
+	if _, ok := va.Spec.AttachmentTickets[pinnedNodeTicketID]; !ok {
+		return
+	}
+
+	for _, attachmentTicket := range va.Spec.AttachmentTickets {
+		if isCSIAttacherTicket(attachmentTicket) {
+			log.Infof("Deleting pinned node attachment ticket %v since the volume is requested by CSI attachment ticket %v", pinnedNodeTicketID, attachmentTicket.ID)
+			delete(va.Spec.AttachmentTickets, pinnedNodeTicketID)
+			return
+		}
+	}
+}
+
 func (vac *VolumeAttachmentController) handleVolumeMigration(va *longhorn.VolumeAttachment, vol *longhorn.Volume) {
 	if !util.IsMigratableVolume(vol) {
 		return
@@ -926,6 +1084,12 @@ func getLoggerForMigratingLHVolumeAttachment(logger logrus.FieldLogger, va *long
 	)
 }
 
+// getPinnedNodeAttachmentTicketID returns the ID of the attachment ticket that attaches a volume to the node it is
+// pinned to at creation.
+func getPinnedNodeAttachmentTicketID(volumeName string) string {
+	return longhorn.GetAttachmentTicketID(longhorn.AttacherTypeLonghornAPI, "pinned-node-"+volumeName)
+}
+
 func isCSIAttacherTicketOfRegularRWXVolume(attachmentTicket *longhorn.AttachmentTicket, v *longhorn.Volume) bool {
 	return isRegularRWXVolume(v) && isCSIAttacherTicket(attachmentTicket)
 }
@@ -989,14 +1153,22 @@ func hasCSIAttachmentTicketRequestingNode(nodeID string, va *longhorn.VolumeAtta
 	return false
 }
 
+// getCSIAttachmentTicketNotRequestingNode prefers the ticket requesting the migration target node of the volume,
+// if any, over the tickets requesting other nodes.
 func getCSIAttachmentTicketNotRequestingNode(nodeID string, va *longhorn.VolumeAttachment, vol *longhorn.Volume) *longhorn.AttachmentTicket {
+	var candidate *longhorn.AttachmentTicket
 	for _, attachmentTicket := range va.Spec.AttachmentTickets {
 		if attachmentTicket.Type != longhorn.AttacherTypeCSIAttacher {
 			continue
 		}
 		if attachmentTicket.NodeID != nodeID && verifyAttachmentParameters(attachmentTicket.Parameters, vol) {
-			return attachmentTicket
+			if attachmentTicket.NodeID == vol.Spec.MigrationTargetNodeID {
+				return attachmentTicket
+			}
+			if candidate == nil {
+				candidate = attachmentTicket
+			}
 		}
 	}
-	return nil
+	return candidate
 }
@@ -10,6 +10,7 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/tools/record"
 
+	storagev1 "k8s.io/api/storage/v1"
 	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -409,6 +410,57 @@ func (s *TestSuite) TestVolumeAttachmentLifeCycle(c *C) {
 	testCases["test case 10: ticket with higher priority interrupts ticket with lower priority"] = tc
 	///////////////////////////////////////////////////////////////////
 
+	///////////////////////////////////////////////////////////////////
+	tc = generateVolumeAttachmentTestCaseTemplate(TestVolumeName)
+	pinnedNodeTicketID := getPinnedNodeAttachmentTicketID(TestVolumeName)
+	tc.volAttachment.Status.AttachmentTicketStatuses = map[string]*longhorn.AttachmentTicketStatus{
+		pinnedNodeTicketID: &longhorn.AttachmentTicketStatus{
+			ID:        pinnedNodeTicketID,
+			Satisfied: true,
+			Conditions: types.SetConditionWithoutTimestamp([]longhorn.Condition{},
+				longhorn.AttachmentStatusConditionTypeSatisfied, longhorn.ConditionStatusTrue, "", ""),
+			Generation: 0,
+		},
+	}
+	tc.volAttachment.Spec.AttachmentTickets = map[string]*longhorn.AttachmentTicket{
+		pinnedNodeTicketID: &longhorn.AttachmentTicket{
+			ID:     pinnedNodeTicketID,
+			Type:   longhorn.AttacherTypeLonghornAPI,
+			NodeID: TestNode1,
+			Parameters: map[string]string{
+				longhorn.AttachmentParameterDisableFrontend: longhorn.FalseValue,
+			},
+			Generation: 0,
+		},
+		"attachment-02": &longhorn.AttachmentTicket{
+			ID:         "attachment-02",
+			Type:       longhorn.AttacherTypeCSIAttacher,
+			NodeID:     TestNode2,
+			Parameters: map[string]string{},
+			Generation: 0,
+		},
+	}
+	tc.vol.Status.OwnerID = TestNode1
+	tc.vol.Spec.NodeID = TestNode1
+	tc.vol.Spec.DisableFrontend = false
+	tc.vol.Status.CurrentNodeID = TestNode1
+	tc.vol.Status.State = longhorn.VolumeStateAttached
+	tc.copyCurrentToExpect()
+	delete(tc.expectedVolAttachment.Spec.AttachmentTickets, pinnedNodeTicketID)
+	tc.expectedVolAttachment.Status.AttachmentTicketStatuses = map[string]*longhorn.AttachmentTicketStatus{
+		"attachment-02": &longhorn.AttachmentTicketStatus{
+			ID:        "attachment-02",
+			Satisfied: false,
+			Conditions: types.SetConditionWithoutTimestamp([]longhorn.Condition{},
+				longhorn.AttachmentStatusConditionTypeSatisfied, longhorn.ConditionStatusFalse, "",
+				fmt.Sprintf("the volume is currently attached to different node %v ", TestNode1)),
+			Generation: 0,
+		},
+	}
+	tc.expectedVol.Spec.NodeID = ""
+	testCases["test case 11: attach: pinned node ticket is deleted once the volume is attached from another node"] = tc
+	///////////////////////////////////////////////////////////////////
+
 	for name, tc := range testCases {
 		//uncomment this block to test individual test case
 		//if name != "test case 10: ticket with higher priority interrupts ticket with lower priority" {
@@ -420,6 +472,111 @@ func (s *TestSuite) TestVolumeAttachmentLifeCycle(c *C) {
 
 }
 
+func (s *TestSuite) TestGetConflictingAttachmentNodes(c *C) {
+	newKubeVA := func(name, pvName, node string, attached bool) *storagev1.VolumeAttachment {
+		return &storagev1.VolumeAttachment{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: storagev1.VolumeAttachmentSpec{
+				Attacher: types.LonghornDriverName,
+				NodeName: node,
+				Source:   storagev1.VolumeAttachmentSource{PersistentVolumeName: &pvName},
+			},
+			Status: storagev1.VolumeAttachmentStatus{Attached: attached},
+		}
+	}
+
+	vol := newVolume(TestVolumeName, 2)
+	vol.Spec.AccessMode = longhorn.AccessModeReadWriteOnce
+	vol.Status.KubernetesStatus.PVName = TestPVName
+
+	// attached to a single node
+	kubeVAs := []*storagev1.VolumeAttachment{
+		newKubeVA("csi-1", TestPVName, TestNode1, true),
+	}
+	c.Assert(GetConflictingAttachmentNodes(vol, kubeVAs), IsNil)
+
+	// the attachment on the other node is not attached yet
+	kubeVAs = append(kubeVAs, newKubeVA("csi-2", TestPVName, TestNode2, false))
+	c.Assert(GetConflictingAttachmentNodes(vol, kubeVAs), IsNil)
+
+	// the attachment on the other node belongs to another PV
+	kubeVAs = append(kubeVAs, newKubeVA("csi-3", "other-pv", TestNode2, true))
+	c.Assert(GetConflictingAttachmentNodes(vol, kubeVAs), IsNil)
+
+	// attached to two nodes
+	kubeVAs = append(kubeVAs, newKubeVA("csi-4", TestPVName, TestNode2, true))
+	c.Assert(GetConflictingAttachmentNodes(vol, kubeVAs), DeepEquals, []string{TestNode1, TestNode2})
+
+	// a deleting attachment does not count
+	now := metav1.Now()
+	kubeVAs[3].DeletionTimestamp = &now
+	c.Assert(GetConflictingAttachmentNodes(vol, kubeVAs), IsNil)
+	kubeVAs[3].DeletionTimestamp = nil
+
+	// RWX and migratable volumes can be attached to multiple nodes
+	vol.Spec.AccessMode = longhorn.AccessModeReadWriteMany
+	c.Assert(GetConflictingAttachmentNodes(vol, kubeVAs), IsNil)
+	vol.Spec.AccessMode = longhorn.AccessModeReadWriteOnce
+	vol.Spec.Migratable = true
+	c.Assert(GetConflictingAttachmentNodes(vol, kubeVAs), IsNil)
+}
+
+func (s *TestSuite) TestUpdateReportedAttachmentConflict(c *C) {
+	vac := &VolumeAttachmentController{attachmentConflicts: map[string]string{}}
+
+	// no conflict is not reported
+	c.Assert(vac.updateReportedAttachmentConflict(TestVolumeName, ""), Equals, false)
+
+	// a new conflict is reported once
+	c.Assert(vac.updateReportedAttachmentConflict(TestVolumeName, TestNode1+","+TestNode2), Equals, true)
+	c.Assert(vac.updateReportedAttachmentConflict(TestVolumeName, TestNode1+","+TestNode2), Equals, false)
+
+	// the resolution of the conflict is reported once
+	c.Assert(vac.updateReportedAttachmentConflict(TestVolumeName, ""), Equals, true)
+	c.Assert(vac.updateReportedAttachmentConflict(TestVolumeName, ""), Equals, false)
+	c.Assert(vac.attachmentConflicts, HasLen, 0)
+}
+
+func (s *TestSuite) TestGetCSIAttachmentTicketNotRequestingNode(c *C) {
+	vol := newVolume(TestVolumeName, 2)
+	vol.Spec.AccessMode = longhorn.AccessModeReadWriteMany
+	vol.Spec.Migratable = true
+
+	va := newVolumeAttachment(TestVolumeName)
+	va.Spec.AttachmentTickets = map[string]*longhorn.AttachmentTicket{}
+	for _, nodeID := range []string{TestNode1, TestNode2, "test-node-name-3", "test-node-name-4"} {
+		ticketID := longhorn.GetAttachmentTicketID(longhorn.AttacherTypeCSIAttacher, nodeID)
+		createOrUpdateAttachmentTicket(va, ticketID, nodeID, longhorn.FalseValue, longhorn.AttacherTypeCSIAttacher)
+	}
+
+	// without a migration target node, any other node is a migration target
+	for i := 0; i < 10; i++ {
+		attachmentTicket := getCSIAttachmentTicketNotRequestingNode(TestNode1, va, vol)
+		c.Assert(attachmentTicket, NotNil)
+		c.Assert(attachmentTicket.NodeID, Not(Equals), TestNode1)
+	}
+
+	// the migration target node is preferred over the other nodes
+	vol.Spec.MigrationTargetNodeID = "test-node-name-3"
+	for i := 0; i < 10; i++ {
+		attachmentTicket := getCSIAttachmentTicketNotRequestingNode(TestNode1, va, vol)
+		c.Assert(attachmentTicket, NotNil)
+		c.Assert(attachmentTicket.NodeID, Equals, "test-node-name-3")
+	}
+
+	// the migration target node is ignored while it does not request the volume
+	vol.Spec.MigrationTargetNodeID = "test-node-name-5"
+	attachmentTicket := getCSIAttachmentTicketNotRequestingNode(TestNode1, va, vol)
+	c.Assert(attachmentTicket, NotNil)
+	c.Assert(attachmentTicket.NodeID, Not(Equals), TestNode1)
+
+	// the migration target node is not a migration target if it is the current node
+	vol.Spec.MigrationTargetNodeID = TestNode1
+	attachmentTicket = getCSIAttachmentTicketNotRequestingNode(TestNode1, va, vol)
+	c.Assert(attachmentTicket, NotNil)
+	c.Assert(attachmentTicket.NodeID, Not(Equals), TestNode1)
+}
+
 func (s *TestSuite) runVolumeAttachmentTestCase(c *C, tc *volumeAttachmentTestCase) {
 	kubeClient := fake.NewSimpleClientset()
 	lhClient := lhfake.NewSimpleClientset()
@@ -438,6 +438,11 @@ func (c *VolumeController) syncVolume(key string) (err error) {
 		}
 
 		// now snapshots, replicas, and engines are deleted
+		// The backing image cleanup is best effort, and does not block the volume deletion.
+		if err := c.cleanupBackingImageIfUnused(volume); err != nil {
+			log.WithError(err).Warnf("Failed to clean up backing image %v of the deleted volume", volume.Spec.BackingImage)
+		}
+
 		return c.ds.RemoveFinalizerForVolume(volume)
 	}
 
@@ -512,6 +517,10 @@ func (c *VolumeController) syncVolume(key string) (err error) {
 		return err
 	}
 
+	if downgraded, err := c.handleAutoDowngradeFromRWX(volume); err != nil || downgraded {
+		return err
+	}
+
 	if err := c.upgradeEngineForVolume(volume, engines, replicas); err != nil {
 		return err
 	}
@@ -543,6 +552,96 @@ func (c *VolumeController) syncVolume(key string) (err error) {
 	return nil
 }
 
+// handleAutoDowngradeFromRWX downgrades the access mode of the volume from rwx to rwo if it is requested
+// by the volume and the volume has been used by a single consumer for the auto downgrade period.
+func (c *VolumeController) handleAutoDowngradeFromRWX(v *longhorn.Volume) (downgraded bool, err error) {
+	if !v.Spec.AutoDowngradeFromRWX {
+		v.Status.SingleConsumerSince = ""
+		return false, nil
+	}
+
+	periodMinutes, err := c.ds.GetSettingAsInt(types.SettingNameAutoDowngradeFromRWXPeriod)
+	if err != nil {
+		return false, err
+	}
+	if !shouldAutoDowngradeFromRWX(v, c.nowHandler(), time.Duration(periodMinutes)*time.Minute) {
+		return false, nil
+	}
+
+	v.Spec.AccessMode = longhorn.AccessModeReadWriteOnce
+	updatedVolume, err := c.ds.UpdateVolume(v)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to downgrade access mode of volume %v", v.Name)
+	}
+	// Carry on with the updated volume, so the status update at the end of the sync does not conflict with the downgrade.
+	updatedVolume.Status = v.Status
+	*v = *updatedVolume
+
+	getLoggerForVolume(c.logger, v).Infof("Downgraded volume access mode from %v to %v since it has had a single consumer since %v",
+		longhorn.AccessModeReadWriteMany, longhorn.AccessModeReadWriteOnce, v.Status.SingleConsumerSince)
+	c.eventRecorder.Eventf(v, corev1.EventTypeNormal, constant.EventReasonUpdate,
+		"Downgraded access mode from %v to %v since the volume has had a single consumer since %v",
+		longhorn.AccessModeReadWriteMany, longhorn.AccessModeReadWriteOnce, v.Status.SingleConsumerSince)
+	return true, nil
+}
+
+// shouldAutoDowngradeFromRWX tracks the consumers of the rwx volume in the volume status,
+// and returns true once the volume has had a single consumer for the period and is detached,
+// since the access mode can only be changed while the volume is detached.
+//
+// A downgraded volume cannot be shared again until it is detached, so only a volume claimed by a
+// StatefulSet volume claim template is downgraded. No other pod of the workload consumes it, so a
+// scale-up of the workload never waits for the volume to be detached.
+func shouldAutoDowngradeFromRWX(v *longhorn.Volume, now string, period time.Duration) bool {
+	if !v.Spec.AutoDowngradeFromRWX || !isRegularRWXVolume(v) {
+		v.Status.SingleConsumerSince = ""
+		return false
+	}
+
+	consumers := 0
+	ks := v.Status.KubernetesStatus
+	if ks.LastPodRefAt == "" {
+		consumers = len(ks.WorkloadsStatus)
+	}
+	switch {
+	case consumers > 1:
+		v.Status.SingleConsumerSince = ""
+		return false
+	case consumers == 1 && !isVolumeClaimTemplateConsumer(ks, ks.WorkloadsStatus[0]):
+		v.Status.SingleConsumerSince = ""
+		return false
+	case consumers == 1 && v.Status.SingleConsumerSince == "":
+		v.Status.SingleConsumerSince = now
+	}
+	// Keep tracking while there is no consumer, since the volume is detached when the single consumer restarts.
+	if v.Status.SingleConsumerSince == "" {
+		return false
+	}
+
+	if v.Spec.NodeID != "" || v.Status.State != longhorn.VolumeStateDetached {
+		return false
+	}
+
+	since, err := util.ParseTime(v.Status.SingleConsumerSince)
+	if err != nil {
+		v.Status.SingleConsumerSince = ""
+		return false
+	}
+	current, err := util.ParseTime(now)
+	if err != nil {
+		return false
+	}
+	return !current.Before(since.Add(period))
+}
+
+// isVolumeClaimTemplateConsumer returns true if the consumer is a StatefulSet pod using the claim created for
+// it from a volume claim template, which is named <template>-<pod name>.
+func isVolumeClaimTemplateConsumer(ks longhorn.KubernetesStatus, ws longhorn.WorkloadStatus) bool {
+	return ws.WorkloadType == types.KubernetesKindStatefulSet &&
+		ws.PodName != "" &&
+		strings.HasSuffix(ks.PVCName, "-"+ws.PodName)
+}
+
 // handleConditionLastTransitionTime rollback to the existing condition object if condition's values hasn't changed
 func handleConditionLastTransitionTime(existingStatus, newStatus *longhorn.VolumeStatus) {
 	for i, newCondition := range newStatus.Conditions {
@@ -605,6 +704,11 @@ func (c *VolumeController) handleVolumeAttachmentCreation(v *longhorn.Volume) er
 				Volume:            v.Name,
 			},
 		}
+		// The volume is pinned to a node at creation. It is attached there until a workload requests it,
+		// see handlePinnedNodeAttachment.
+		if v.Spec.NodeID != "" {
+			createOrUpdateAttachmentTicket(&va, getPinnedNodeAttachmentTicketID(v.Name), v.Spec.NodeID, longhorn.FalseValue, longhorn.AttacherTypeLonghornAPI)
+		}
 		if _, err := c.ds.CreateLHVolumeAttachment(&va); err != nil {
 			return err
 		}
@@ -805,6 +909,9 @@ func (c *VolumeController) ReconcileEngineReplicaState(v *longhorn.Volume, es ma
 		if oldRobustness == longhorn.VolumeRobustnessDegraded {
 			c.eventRecorder.Eventf(v, corev1.EventTypeNormal, constant.EventReasonHealthy, "volume %v became healthy", v.Name)
 		}
+		if oldRobustness != longhorn.VolumeRobustnessHealthy || v.Status.LastHealthyAt == "" {
+			v.Status.LastHealthyAt = c.nowHandler()
+		}
 
 		if isMigratingDone {
 			// Evict replicas for the volume
@@ -979,6 +1086,16 @@ func isSafeAsLastReplica(r *longhorn.Replica) bool {
 	return true
 }
 
+// shouldSkipReplicaRebuilding returns true if the replica rebuilding is disabled for the volume.
+// The toggle is overridden when the volume is degraded down to a single healthy replica, for example
+// after the other replicas failed on down nodes, so the last copy of the data is not left alone.
+func shouldSkipReplicaRebuilding(rebuildingEnabled bool, rs map[string]*longhorn.Replica) bool {
+	if rebuildingEnabled {
+		return false
+	}
+	return getHealthyAndActiveReplicaCount(rs, false) > 1
+}
+
 func getHealthyAndActiveReplicaCount(rs map[string]*longhorn.Replica, includeMarkedForDeletion bool) int {
 	count := 0
 	for _, r := range rs {
@@ -1265,6 +1382,101 @@ func (c *VolumeController) isUnmapMarkSnapChainRemovedEnabled(v *longhorn.Volume
 	return c.ds.GetSettingAsBool(types.SettingNameRemoveSnapshotsDuringFilesystemTrim)
 }
 
+// cleanupBackingImageIfUnused deletes the backing image of a deleted volume when
+// its BackingImageCleanupPolicy is delete and nothing else still uses it.
+func (c *VolumeController) cleanupBackingImageIfUnused(v *longhorn.Volume) error {
+	if v.Spec.BackingImage == "" || v.Spec.BackingImageCleanupPolicy != longhorn.BackingImageCleanupPolicyDelete {
+		return nil
+	}
+
+	log := getLoggerForVolume(c.logger, v).WithField("backingImage", v.Spec.BackingImage)
+
+	bi, err := c.ds.GetBackingImageRO(v.Spec.BackingImage)
+	if err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get backing image %v for cleanup", v.Spec.BackingImage)
+	}
+	if bi.DeletionTimestamp != nil {
+		return nil
+	}
+
+	user, err := c.getBackingImageUser(bi, v)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check the users of backing image %v for cleanup", bi.Name)
+	}
+	if user != "" {
+		log.Debugf("Skipping backing image cleanup since it is still used by %v", user)
+		return nil
+	}
+
+	if err := c.ds.DeleteBackingImage(bi.Name); err != nil {
+		if datastore.ErrorIsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete backing image %v", bi.Name)
+	}
+	log.Info("Deleted backing image since the last volume referencing it was deleted")
+
+	return nil
+}
+
+// getBackingImageUser returns a description of the first object other than the
+// deleted volume v that still uses the backing image, or an empty string if
+// there is none.
+func (c *VolumeController) getBackingImageUser(bi *longhorn.BackingImage, v *longhorn.Volume) (string, error) {
+	// Replicas are labeled with their backing image on creation, so this also
+	// covers the volumes that are still being created.
+	replicas, err := c.ds.ListReplicasByBackingImage(bi.Name, "")
+	if err != nil {
+		return "", err
+	}
+	for _, r := range replicas {
+		if r.Spec.VolumeName != v.Name {
+			return fmt.Sprintf("replica %v", r.Name), nil
+		}
+	}
+
+	volumes, err := c.ds.ListVolumesRO()
+	if err != nil {
+		return "", err
+	}
+	for _, other := range volumes {
+		if other.Name == v.Name || other.DeletionTimestamp != nil {
+			continue
+		}
+		if other.Spec.BackingImage == bi.Name {
+			return fmt.Sprintf("volume %v", other.Name), nil
+		}
+	}
+
+	// A backing image cloned from this one reads its file until the transfer
+	// is done.
+	backingImages, err := c.ds.ListBackingImagesRO()
+	if err != nil {
+		return "", err
+	}
+	for _, clone := range backingImages {
+		if clone.Name == bi.Name || clone.DeletionTimestamp != nil {
+			continue
+		}
+		if clone.Spec.SourceType != longhorn.BackingImageDataSourceTypeClone ||
+			clone.Spec.SourceParameters[longhorn.DataSourceTypeCloneParameterBackingImage] != bi.Name {
+			continue
+		}
+		bids, err := c.ds.GetBackingImageDataSource(clone.Name)
+		if err != nil && !datastore.ErrorIsNotFound(err) {
+			return "", err
+		}
+		if bids == nil || !bids.Spec.FileTransferred {
+			return fmt.Sprintf("backing image %v being cloned from it", clone.Name), nil
+		}
+	}
+
+	return "", nil
+}
+
 func (c *VolumeController) syncVolumeUnmapMarkSnapChainRemovedSetting(v *longhorn.Volume, es map[string]*longhorn.Engine, rs map[string]*longhorn.Replica) error {
 	if es == nil && rs == nil {
 		return nil
@@ -2291,6 +2503,16 @@ func (c *VolumeController) replenishReplicas(v *longhorn.Volume, e *longhorn.Eng
 		return nil
 	}
 
+	if len(rs) != 0 {
+		rebuildingEnabled, err := c.ds.IsVolumeReplicaRebuildingEnabled(v)
+		if err != nil {
+			return err
+		}
+		if shouldSkipReplicaRebuilding(rebuildingEnabled, rs) {
+			return nil
+		}
+	}
+
 	if util.IsVolumeMigrating(v) {
 		return nil
 	}
@@ -2504,7 +2726,7 @@ func (c *VolumeController) getReplicaCountForAutoBalanceBestEffort(v *longhorn.V
 	}
 
 	if adjustCount == 0 {
-		replicasUnderDiskPressure, err := c.getReplicasUnderDiskPressure()
+		replicasUnderDiskPressure, err := c.getReplicasUnderDiskPressure(v)
 		if err != nil {
 			log.WithError(err).Warn("Failed to get replicas in disk pressure")
 			return 0, nil, []string{}
@@ -2536,13 +2758,13 @@ func (c *VolumeController) getReplicaCountForAutoBalanceBestEffort(v *longhorn.V
 	return adjustCount, mostExtraRList, leastExtraROwners
 }
 
-func (c *VolumeController) getReplicasUnderDiskPressure() (map[string]bool, error) {
-	settingDiskPressurePercentage, err := c.ds.GetSettingAsInt(types.SettingNameReplicaAutoBalanceDiskPressurePercentage)
+func (c *VolumeController) getReplicasUnderDiskPressure(volume *longhorn.Volume) (map[string]bool, error) {
+	diskPressurePercentage, err := c.ds.GetReplicaAutoBalanceDiskPressurePercentage(volume)
 	if err != nil {
 		return nil, err
 	}
 
-	if settingDiskPressurePercentage == 0 {
+	if diskPressurePercentage == 0 {
 		return nil, nil
 	}
 
@@ -2566,7 +2788,7 @@ func (c *VolumeController) getReplicasUnderDiskPressure() (map[string]bool, erro
 				return nil, err
 			}
 
-			if c.scheduler.IsDiskUnderPressure(settingDiskPressurePercentage, diskInfo) {
+			if c.scheduler.IsDiskUnderPressure(diskPressurePercentage, diskInfo) {
 				for replicaName := range diskStatus.ScheduledReplica {
 					replicasInPressure[replicaName] = true
 				}
@@ -2581,9 +2803,11 @@ func (c *VolumeController) checkDiskPressuredReplicaIsFirstCandidate(replica *lo
 		return errors.New("node is nil in checkDiskPressuredReplicaIsFirstCandidate")
 	}
 
+	var replicaScheduledDiskName string
 	var replicaScheduledDiskStatus *longhorn.DiskStatus
-	for _, diskStatus := range node.Status.DiskStatus {
+	for diskName, diskStatus := range node.Status.DiskStatus {
 		if _, exist := diskStatus.ScheduledReplica[replica.Name]; exist {
+			replicaScheduledDiskName = diskName
 			replicaScheduledDiskStatus = diskStatus
 			break
 		}
@@ -2593,6 +2817,11 @@ func (c *VolumeController) checkDiskPressuredReplicaIsFirstCandidate(replica *lo
 		return errors.Errorf("replica %v is not scheduled on any disk", replica.Name)
 	}
 
+	diskInfo, err := c.scheduler.GetDiskSchedulingInfo(node.Spec.Disks[replicaScheduledDiskName], replicaScheduledDiskStatus)
+	if err != nil {
+		return err
+	}
+
 	log := getLoggerForReplica(c.logger, replica)
 
 	logSkip := func(replicaName string, err error) {
@@ -2601,7 +2830,8 @@ func (c *VolumeController) checkDiskPressuredReplicaIsFirstCandidate(replica *lo
 		)
 	}
 
-	// isVolumeHealthy checks if the volume associated with the replica is healthy.
+	// isVolumeHealthy checks if the volume associated with the replica is healthy and considers the disk under
+	// pressure, since the volumes may have different disk pressure percentages.
 	isVolumeHealthy := func(replicaName string) bool {
 		scheduledReplica, err := c.ds.GetReplicaRO(replicaName)
 		if err != nil {
@@ -2615,7 +2845,16 @@ func (c *VolumeController) checkDiskPressuredReplicaIsFirstCandidate(replica *lo
 			return false
 		}
 
-		return replicaVolume.Status.Robustness == longhorn.VolumeRobustnessHealthy
+		if replicaVolume.Status.Robustness != longhorn.VolumeRobustnessHealthy {
+			return false
+		}
+
+		diskPressurePercentage, err := c.ds.GetReplicaAutoBalanceDiskPressurePercentage(replicaVolume)
+		if err != nil {
+			logSkip(replicaName, err)
+			return false
+		}
+		return diskPressurePercentage > 0 && c.scheduler.IsDiskUnderPressure(diskPressurePercentage, diskInfo)
 	}
 
 	healthyVolumeReplicas := make(map[string]struct{})
@@ -2644,13 +2883,13 @@ func (c *VolumeController) checkReplicaDiskPressuredSchedulableCandidates(volume
 		"replica": replica.Name,
 	})
 
-	diskPressurePercentage, err := c.ds.GetSettingAsInt(types.SettingNameReplicaAutoBalanceDiskPressurePercentage)
+	diskPressurePercentage, err := c.ds.GetReplicaAutoBalanceDiskPressurePercentage(volume)
 	if err != nil {
 		return err
 	}
 
 	if diskPressurePercentage == 0 {
-		return errors.Errorf("%v setting is 0, skip auto-balance replicas in disk pressure", types.SettingNameReplicaAutoBalanceDiskPressurePercentage)
+		return errors.New("replica auto-balance disk pressure percentage is 0, skip auto-balance replicas in disk pressure")
 	}
 
 	nodes, err := c.ds.ListNodesRO()
@@ -5011,9 +5250,45 @@ func (c *VolumeController) ReconcileBackupVolumeState(volume *longhorn.Volume) e
 	// Set last backup
 	volume.Status.LastBackup = bv.Status.LastBackupName
 	volume.Status.LastBackupAt = bv.Status.LastBackupAt
+
+	return c.requestBackupVolumeSyncForStandbyVolume(volume, bv)
+}
+
+// requestBackupVolumeSyncForStandbyVolume requests the backup volume of the DR volume to be synced
+// every StandbyRestoreInterval, so the DR volume can restore new backups without waiting for the poll
+// interval of the backup target.
+func (c *VolumeController) requestBackupVolumeSyncForStandbyVolume(volume *longhorn.Volume, bv *longhorn.BackupVolume) error {
+	if !volume.Status.IsStandby || volume.Spec.StandbyRestoreInterval <= 0 {
+		return nil
+	}
+
+	interval := time.Duration(volume.Spec.StandbyRestoreInterval) * time.Second
+	if delay := getStandbyBackupVolumeSyncDelay(bv, interval, time.Now()); delay > 0 {
+		c.enqueueVolumeAfter(volume, delay)
+		return nil
+	}
+
+	bv = bv.DeepCopy()
+	bv.Spec.SyncRequestedAt = metav1.Time{Time: time.Now().UTC()}
+	if _, err := c.ds.UpdateBackupVolume(bv); err != nil {
+		return errors.Wrapf(err, "failed to request sync of backup volume %v for DR volume %v", bv.Name, volume.Name)
+	}
+	c.enqueueVolumeAfter(volume, interval)
 	return nil
 }
 
+// getStandbyBackupVolumeSyncDelay returns how long to wait before requesting the next sync of the backup volume.
+// There is no need to request a sync if the previous request is not handled yet.
+func getStandbyBackupVolumeSyncDelay(bv *longhorn.BackupVolume, interval time.Duration, now time.Time) time.Duration {
+	if bv.Status.LastSyncedAt.IsZero() || bv.Spec.SyncRequestedAt.After(bv.Status.LastSyncedAt.Time) {
+		return interval
+	}
+	if delay := bv.Status.LastSyncedAt.Add(interval).Sub(now); delay > 0 {
+		return delay
+	}
+	return 0
+}
+
 // TODO: this block of code is duplicated of CreateSnapshot in MANAGER package.
 // Once we have Snapshot CR, we should refactor this
 
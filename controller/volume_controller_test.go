@@ -261,6 +261,7 @@ func (s *TestSuite) TestVolumeLifeCycle(c *C) {
 	tc.copyCurrentToExpect()
 	tc.expectVolume.Status.State = longhorn.VolumeStateAttached
 	tc.expectVolume.Status.Robustness = longhorn.VolumeRobustnessHealthy
+	tc.expectVolume.Status.LastHealthyAt = getTestNow()
 	tc.expectVolume.Status.CurrentImage = tc.volume.Spec.Image
 	tc.expectVolume.Status.CurrentNodeID = tc.volume.Spec.NodeID
 	for _, r := range tc.expectReplicas {
@@ -362,6 +363,7 @@ func (s *TestSuite) TestVolumeLifeCycle(c *C) {
 	tc.expectVolume.Status.State = longhorn.VolumeStateAttached
 	tc.expectVolume.Status.CurrentNodeID = TestNode1
 	tc.expectVolume.Status.Robustness = longhorn.VolumeRobustnessHealthy
+	tc.expectVolume.Status.LastHealthyAt = getTestNow()
 	tc.expectVolume.Status.Conditions = setVolumeConditionWithoutTimestamp(tc.volume.Status.Conditions,
 		longhorn.VolumeConditionTypeRestore, longhorn.ConditionStatusTrue, longhorn.VolumeConditionReasonRestoreInProgress, "")
 	testCases["newly restored volume attaching to attached"] = tc
@@ -376,6 +378,7 @@ func (s *TestSuite) TestVolumeLifeCycle(c *C) {
 	tc.volume.Status.OwnerID = TestNode1
 	tc.volume.Status.State = longhorn.VolumeStateAttached
 	tc.volume.Status.Robustness = longhorn.VolumeRobustnessHealthy
+	tc.volume.Status.LastHealthyAt = getTestNow()
 	tc.volume.Status.CurrentImage = TestEngineImage
 	tc.volume.Status.FrontendDisabled = true
 	tc.volume.Status.RestoreRequired = true
@@ -423,6 +426,7 @@ func (s *TestSuite) TestVolumeLifeCycle(c *C) {
 	tc.volume.Status.OwnerID = TestNode1
 	tc.volume.Status.State = longhorn.VolumeStateAttached
 	tc.volume.Status.Robustness = longhorn.VolumeRobustnessHealthy
+	tc.volume.Status.LastHealthyAt = getTestNow()
 	tc.volume.Status.CurrentNodeID = TestNode1
 	tc.volume.Status.CurrentImage = TestEngineImage
 	tc.volume.Status.FrontendDisabled = true
@@ -543,6 +547,7 @@ func (s *TestSuite) TestVolumeLifeCycle(c *C) {
 	tc.volume.Status.OwnerID = TestNode1
 	tc.volume.Status.State = longhorn.VolumeStateAttached
 	tc.volume.Status.Robustness = longhorn.VolumeRobustnessHealthy
+	tc.volume.Status.LastHealthyAt = getTestNow()
 	tc.volume.Status.CurrentNodeID = TestNode1
 	tc.volume.Status.CurrentImage = TestEngineImage
 	tc.volume.Status.FrontendDisabled = true
@@ -624,6 +629,7 @@ func (s *TestSuite) TestVolumeLifeCycle(c *C) {
 	tc.volume.Status.OwnerID = TestNode1
 	tc.volume.Status.State = longhorn.VolumeStateAttached
 	tc.volume.Status.Robustness = longhorn.VolumeRobustnessHealthy
+	tc.volume.Status.LastHealthyAt = getTestNow()
 	tc.volume.Status.CurrentImage = TestEngineImage
 	tc.volume.Status.RestoreInitiated = true
 	tc.volume.Status.RestoreRequired = true
@@ -767,6 +773,7 @@ func (s *TestSuite) TestVolumeLifeCycle(c *C) {
 	tc.copyCurrentToExpect()
 	tc.expectVolume.Status.State = longhorn.VolumeStateAttached
 	tc.expectVolume.Status.Robustness = longhorn.VolumeRobustnessHealthy
+	tc.expectVolume.Status.LastHealthyAt = getTestNow()
 	testCases["standby volume is not automatically detached"] = tc
 
 	// volume detaching - stop engine
@@ -801,6 +808,7 @@ func (s *TestSuite) TestVolumeLifeCycle(c *C) {
 	tc.copyCurrentToExpect()
 	tc.expectVolume.Status.State = longhorn.VolumeStateDetaching
 	tc.expectVolume.Status.Robustness = longhorn.VolumeRobustnessUnknown
+	tc.expectVolume.Status.LastHealthyAt = getTestNow()
 	tc.expectVolume.Status.CurrentImage = tc.volume.Spec.Image
 	tc.expectVolume.Status.Conditions = setVolumeConditionWithoutTimestamp(tc.volume.Status.Conditions,
 		longhorn.VolumeConditionTypeRestore, longhorn.ConditionStatusFalse, "", "")
@@ -1571,3 +1579,374 @@ func (s *TestSuite) runTestCases(c *C, testCases map[string]*VolumeTestCase) {
 		}
 	}
 }
+
+func (s *TestSuite) TestShouldAutoDowngradeFromRWX(c *C) {
+	period := 10 * time.Minute
+	now := time.Now().UTC()
+	sustained := now.Add(-period).Format(time.RFC3339)
+	recent := now.Add(-time.Minute).Format(time.RFC3339)
+
+	newRWXVolume := func(state longhorn.VolumeState, consumers int, singleConsumerSince string) *longhorn.Volume {
+		v := newVolume(TestVolumeName, 2)
+		v.Spec.AccessMode = longhorn.AccessModeReadWriteMany
+		v.Spec.AutoDowngradeFromRWX = true
+		v.Spec.NodeID = ""
+		v.Status.State = state
+		v.Status.SingleConsumerSince = singleConsumerSince
+		v.Status.KubernetesStatus.PVCName = "data-test-pod-0"
+		for i := 0; i < consumers; i++ {
+			v.Status.KubernetesStatus.WorkloadsStatus = append(v.Status.KubernetesStatus.WorkloadsStatus,
+				longhorn.WorkloadStatus{
+					PodName:      fmt.Sprintf("test-pod-%d", i),
+					WorkloadName: "test",
+					WorkloadType: types.KubernetesKindStatefulSet,
+				})
+		}
+		return v
+	}
+
+	testCases := map[string]struct {
+		volume                      *longhorn.Volume
+		expectedDowngrade           bool
+		expectedSingleConsumerSince string
+	}{
+		"single consumer detected": {
+			volume:                      newRWXVolume(longhorn.VolumeStateAttached, 1, ""),
+			expectedDowngrade:           false,
+			expectedSingleConsumerSince: now.Format(time.RFC3339),
+		},
+		"single consumer sustained but attached": {
+			volume:                      newRWXVolume(longhorn.VolumeStateAttached, 1, sustained),
+			expectedDowngrade:           false,
+			expectedSingleConsumerSince: sustained,
+		},
+		"single consumer sustained and detached": {
+			volume:                      newRWXVolume(longhorn.VolumeStateDetached, 1, sustained),
+			expectedDowngrade:           true,
+			expectedSingleConsumerSince: sustained,
+		},
+		"single consumer not sustained": {
+			volume:                      newRWXVolume(longhorn.VolumeStateDetached, 1, recent),
+			expectedDowngrade:           false,
+			expectedSingleConsumerSince: recent,
+		},
+		"multiple consumers": {
+			volume:                      newRWXVolume(longhorn.VolumeStateAttached, 2, sustained),
+			expectedDowngrade:           false,
+			expectedSingleConsumerSince: "",
+		},
+		"single consumer of a scalable workload": {
+			volume: func() *longhorn.Volume {
+				v := newRWXVolume(longhorn.VolumeStateDetached, 1, sustained)
+				v.Status.KubernetesStatus.WorkloadsStatus[0].WorkloadType = "ReplicaSet"
+				return v
+			}(),
+			expectedDowngrade:           false,
+			expectedSingleConsumerSince: "",
+		},
+		"single StatefulSet consumer of a claim shared by its pods": {
+			volume: func() *longhorn.Volume {
+				v := newRWXVolume(longhorn.VolumeStateDetached, 1, sustained)
+				v.Status.KubernetesStatus.PVCName = "shared-data"
+				return v
+			}(),
+			expectedDowngrade:           false,
+			expectedSingleConsumerSince: "",
+		},
+		"auto downgrade disabled": {
+			volume: func() *longhorn.Volume {
+				v := newRWXVolume(longhorn.VolumeStateDetached, 1, sustained)
+				v.Spec.AutoDowngradeFromRWX = false
+				return v
+			}(),
+			expectedDowngrade:           false,
+			expectedSingleConsumerSince: "",
+		},
+		"no consumer ever detected": {
+			volume:                      newRWXVolume(longhorn.VolumeStateDetached, 0, ""),
+			expectedDowngrade:           false,
+			expectedSingleConsumerSince: "",
+		},
+	}
+
+	for name, tc := range testCases {
+		fmt.Printf("testing %v\n", name)
+		downgrade := shouldAutoDowngradeFromRWX(tc.volume, now.Format(time.RFC3339), period)
+		c.Assert(downgrade, Equals, tc.expectedDowngrade, Commentf("test case %v", name))
+		c.Assert(tc.volume.Status.SingleConsumerSince, Equals, tc.expectedSingleConsumerSince, Commentf("test case %v", name))
+	}
+}
+
+func (s *TestSuite) TestAutoDowngradeFromRWXScaleUpWhileAttached(c *C) {
+	period := 10 * time.Minute
+	start := time.Now().UTC()
+
+	testCases := map[string]struct {
+		workloadType      string
+		pvcName           string
+		expectedDowngrade bool
+	}{
+		"deployment scaled up while attached": {
+			workloadType:      "ReplicaSet",
+			pvcName:           "shared-data",
+			expectedDowngrade: false,
+		},
+		"statefulset with a shared claim scaled up while attached": {
+			workloadType:      types.KubernetesKindStatefulSet,
+			pvcName:           "shared-data",
+			expectedDowngrade: false,
+		},
+		"statefulset with a volume claim template scaled up while attached": {
+			workloadType:      types.KubernetesKindStatefulSet,
+			pvcName:           "data-test-0",
+			expectedDowngrade: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		fmt.Printf("testing %v\n", name)
+		v := newVolume(TestVolumeName, 2)
+		v.Spec.AccessMode = longhorn.AccessModeReadWriteMany
+		v.Spec.AutoDowngradeFromRWX = true
+		v.Status.KubernetesStatus.PVCName = tc.pvcName
+		v.Status.KubernetesStatus.WorkloadsStatus = []longhorn.WorkloadStatus{
+			{PodName: "test-0", WorkloadName: "test", WorkloadType: tc.workloadType},
+		}
+
+		// The single consumer is detected while it is running.
+		c.Assert(shouldAutoDowngradeFromRWX(v, start.Format(time.RFC3339), period), Equals, false, Commentf("test case %v", name))
+
+		// The single consumer restarts after the period, so the volume is detached.
+		v.Spec.NodeID = ""
+		v.Status.State = longhorn.VolumeStateDetached
+		downgrade := shouldAutoDowngradeFromRWX(v, start.Add(period).Format(time.RFC3339), period)
+		c.Assert(downgrade, Equals, tc.expectedDowngrade, Commentf("test case %v", name))
+
+		// The workload is scaled up once the single consumer is running again.
+		v.Spec.NodeID = TestNode1
+		v.Status.State = longhorn.VolumeStateAttached
+		if downgrade {
+			// The new pod gets its own claim from the volume claim template, so the downgraded volume is never
+			// requested by another node while it is attached.
+			continue
+		}
+		// The volume is still rwx, so the share manager serves the new pod on another node right away.
+		v.Status.KubernetesStatus.WorkloadsStatus = append(v.Status.KubernetesStatus.WorkloadsStatus,
+			longhorn.WorkloadStatus{PodName: "test-1", WorkloadName: "test", WorkloadType: tc.workloadType})
+		c.Assert(shouldAutoDowngradeFromRWX(v, start.Add(2*period).Format(time.RFC3339), period), Equals, false, Commentf("test case %v", name))
+		c.Assert(v.Spec.AccessMode, Equals, longhorn.AccessModeReadWriteMany, Commentf("test case %v", name))
+		c.Assert(v.Status.SingleConsumerSince, Equals, "", Commentf("test case %v", name))
+	}
+}
+
+func (s *TestSuite) TestShouldSkipReplicaRebuilding(c *C) {
+	// newReplicas returns 3 replicas of a volume, of which the ones on the down nodes are failed.
+	newReplicas := func(downNodes ...string) map[string]*longhorn.Replica {
+		v := newVolume(TestVolumeName, 3)
+		e := newEngineForVolume(v)
+		rs := map[string]*longhorn.Replica{}
+		for _, nodeID := range []string{TestNode1, TestNode2, "test-node-name-3"} {
+			r := newReplicaForVolume(v, e, nodeID, TestDiskID1)
+			r.Spec.HealthyAt = util.Now()
+			for _, downNode := range downNodes {
+				if nodeID == downNode {
+					r.Spec.FailedAt = util.Now()
+				}
+			}
+			rs[r.Name] = r
+		}
+		return rs
+	}
+
+	testCases := map[string]struct {
+		rebuildingEnabled bool
+		downNodes         []string
+		expectedSkip      bool
+	}{
+		"rebuilding enabled": {
+			rebuildingEnabled: true,
+			downNodes:         []string{TestNode1},
+			expectedSkip:      false,
+		},
+		"rebuilding disabled after one node down": {
+			rebuildingEnabled: false,
+			downNodes:         []string{TestNode1},
+			expectedSkip:      true,
+		},
+		"rebuilding disabled but overridden with a single healthy replica left": {
+			rebuildingEnabled: false,
+			downNodes:         []string{TestNode1, TestNode2},
+			expectedSkip:      false,
+		},
+	}
+
+	for name, tc := range testCases {
+		fmt.Printf("testing %v\n", name)
+		rs := newReplicas(tc.downNodes...)
+		c.Assert(shouldSkipReplicaRebuilding(tc.rebuildingEnabled, rs), Equals, tc.expectedSkip, Commentf("test case %v", name))
+	}
+}
+
+func (s *TestSuite) TestGetStandbyBackupVolumeSyncDelay(c *C) {
+	now := time.Now().UTC()
+	interval := 5 * time.Minute
+
+	newBackupVolume := func(syncRequestedAt, lastSyncedAt time.Time) *longhorn.BackupVolume {
+		return &longhorn.BackupVolume{
+			Spec:   longhorn.BackupVolumeSpec{SyncRequestedAt: metav1.Time{Time: syncRequestedAt}},
+			Status: longhorn.BackupVolumeStatus{LastSyncedAt: metav1.Time{Time: lastSyncedAt}},
+		}
+	}
+
+	testCases := map[string]struct {
+		backupVolume  *longhorn.BackupVolume
+		expectedDelay time.Duration
+	}{
+		"never synced": {
+			backupVolume:  newBackupVolume(time.Time{}, time.Time{}),
+			expectedDelay: interval,
+		},
+		"sync request pending": {
+			backupVolume:  newBackupVolume(now, now.Add(-time.Minute)),
+			expectedDelay: interval,
+		},
+		"synced recently": {
+			backupVolume:  newBackupVolume(now.Add(-2*time.Minute), now.Add(-time.Minute)),
+			expectedDelay: 4 * time.Minute,
+		},
+		"interval elapsed": {
+			backupVolume:  newBackupVolume(now.Add(-10*time.Minute), now.Add(-interval)),
+			expectedDelay: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		fmt.Printf("testing %v\n", name)
+		delay := getStandbyBackupVolumeSyncDelay(tc.backupVolume, interval, now)
+		c.Assert(delay, Equals, tc.expectedDelay, Commentf("test case %v", name))
+	}
+}
+
+func (s *TestSuite) TestCleanupBackingImageIfUnused(c *C) {
+	const cloneName = "test-backing-image-clone"
+
+	newDeletedVolume := func() *longhorn.Volume {
+		v := newVolume(TestVolumeName, 2)
+		v.Spec.BackingImage = TestBackingImage
+		v.Spec.BackingImageCleanupPolicy = longhorn.BackingImageCleanupPolicyDelete
+		return v
+	}
+	newReplicaUsingBackingImage := func(volumeName string) *longhorn.Replica {
+		v := newVolume(volumeName, 2)
+		r := newReplicaForVolume(v, newEngineForVolume(v), TestNode1, TestDiskID1)
+		r.Namespace = TestNamespace
+		r.Labels[types.GetLonghornLabelKey(types.LonghornLabelBackingImage)] = TestBackingImage
+		return r
+	}
+	newClone := func() *longhorn.BackingImage {
+		bi := newBackingIamge(cloneName, longhorn.BackingImageDataSourceTypeClone)
+		bi.Spec.SourceParameters = map[string]string{longhorn.DataSourceTypeCloneParameterBackingImage: TestBackingImage}
+		return bi
+	}
+	newCloneDataSource := func(fileTransferred bool) *longhorn.BackingImageDataSource {
+		return &longhorn.BackingImageDataSource{
+			ObjectMeta: metav1.ObjectMeta{Name: cloneName, Namespace: TestNamespace},
+			Spec:       longhorn.BackingImageDataSourceSpec{FileTransferred: fileTransferred},
+		}
+	}
+	otherVolume := newVolume("test-volume-other", 2)
+	otherVolume.Namespace = TestNamespace
+	otherVolume.Spec.BackingImage = TestBackingImage
+
+	testCases := map[string]struct {
+		volume          *longhorn.Volume
+		volumes         []*longhorn.Volume
+		replicas        []*longhorn.Replica
+		backingImages   []*longhorn.BackingImage
+		dataSources     []*longhorn.BackingImageDataSource
+		expectedDeleted bool
+	}{
+		"retain policy": {
+			volume: func() *longhorn.Volume {
+				v := newDeletedVolume()
+				v.Spec.BackingImageCleanupPolicy = longhorn.BackingImageCleanupPolicyRetain
+				return v
+			}(),
+			expectedDeleted: false,
+		},
+		"unused": {
+			volume:          newDeletedVolume(),
+			replicas:        []*longhorn.Replica{newReplicaUsingBackingImage(TestVolumeName)},
+			expectedDeleted: true,
+		},
+		"used by another volume": {
+			volume:          newDeletedVolume(),
+			volumes:         []*longhorn.Volume{otherVolume},
+			expectedDeleted: false,
+		},
+		"used by a replica of a volume being created": {
+			volume:          newDeletedVolume(),
+			replicas:        []*longhorn.Replica{newReplicaUsingBackingImage("test-volume-creating")},
+			expectedDeleted: false,
+		},
+		"clone without data source yet": {
+			volume:          newDeletedVolume(),
+			backingImages:   []*longhorn.BackingImage{newClone()},
+			expectedDeleted: false,
+		},
+		"clone in progress": {
+			volume:          newDeletedVolume(),
+			backingImages:   []*longhorn.BackingImage{newClone()},
+			dataSources:     []*longhorn.BackingImageDataSource{newCloneDataSource(false)},
+			expectedDeleted: false,
+		},
+		"clone done": {
+			volume:          newDeletedVolume(),
+			backingImages:   []*longhorn.BackingImage{newClone()},
+			dataSources:     []*longhorn.BackingImageDataSource{newCloneDataSource(true)},
+			expectedDeleted: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		fmt.Printf("testing %v\n", name)
+
+		kubeClient := fake.NewSimpleClientset()
+		lhClient := lhfake.NewSimpleClientset()
+		extensionsClient := apiextensionsfake.NewSimpleClientset()
+		informerFactories := util.NewInformerFactories(TestNamespace, kubeClient, lhClient, controller.NoResyncPeriodFunc())
+
+		vIndexer := informerFactories.LhInformerFactory.Longhorn().V1beta2().Volumes().Informer().GetIndexer()
+		rIndexer := informerFactories.LhInformerFactory.Longhorn().V1beta2().Replicas().Informer().GetIndexer()
+		biIndexer := informerFactories.LhInformerFactory.Longhorn().V1beta2().BackingImages().Informer().GetIndexer()
+		bidsIndexer := informerFactories.LhInformerFactory.Longhorn().V1beta2().BackingImageDataSources().Informer().GetIndexer()
+
+		vc, err := newTestVolumeController(lhClient, kubeClient, extensionsClient, informerFactories, TestOwnerID1)
+		c.Assert(err, IsNil)
+
+		for _, bi := range append([]*longhorn.BackingImage{newBackingIamge(TestBackingImage, longhorn.BackingImageDataSourceTypeDownload)}, tc.backingImages...) {
+			bi, err = lhClient.LonghornV1beta2().BackingImages(TestNamespace).Create(context.TODO(), bi, metav1.CreateOptions{})
+			c.Assert(err, IsNil)
+			c.Assert(biIndexer.Add(bi), IsNil)
+		}
+		for _, bids := range tc.dataSources {
+			c.Assert(bidsIndexer.Add(bids), IsNil)
+		}
+		for _, v := range tc.volumes {
+			c.Assert(vIndexer.Add(v), IsNil)
+		}
+		for _, r := range tc.replicas {
+			c.Assert(rIndexer.Add(r), IsNil)
+		}
+
+		err = vc.cleanupBackingImageIfUnused(tc.volume)
+		c.Assert(err, IsNil, Commentf("test case %v", name))
+
+		_, err = lhClient.LonghornV1beta2().BackingImages(TestNamespace).Get(context.TODO(), TestBackingImage, metav1.GetOptions{})
+		if tc.expectedDeleted {
+			c.Assert(datastore.ErrorIsNotFound(err), Equals, true, Commentf("test case %v", name))
+		} else {
+			c.Assert(err, IsNil, Commentf("test case %v", name))
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/kubernetes/pkg/controller"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientset "k8s.io/client-go/kubernetes"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+)
+
+// VolumeIdleController reports the volumes without any consuming pod for longer than the volume idle threshold
+// setting by their Idle condition.
+type VolumeIdleController struct {
+	*baseController
+
+	// which namespace controller is running with
+	namespace string
+	// use as the OwnerID of the controller
+	controllerID string
+
+	kubeClient    clientset.Interface
+	eventRecorder record.EventRecorder
+
+	ds         *datastore.DataStore
+	cacheSyncs []cache.InformerSynced
+
+	nowHandler func() time.Time
+}
+
+func NewVolumeIdleController(
+	logger logrus.FieldLogger,
+	ds *datastore.DataStore,
+	scheme *runtime.Scheme,
+	kubeClient clientset.Interface,
+	controllerID string,
+	namespace string,
+) (*VolumeIdleController, error) {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartLogging(logrus.Infof)
+
+	vic := &VolumeIdleController{
+		baseController: newBaseController("longhorn-volume-idle", logger),
+
+		namespace:    namespace,
+		controllerID: controllerID,
+
+		ds: ds,
+
+		kubeClient:    kubeClient,
+		eventRecorder: eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: "longhorn-volume-idle-controller"}),
+
+		nowHandler: time.Now,
+	}
+
+	var err error
+	if _, err = ds.VolumeInformer.AddEventHandlerWithResyncPeriod(cache.ResourceEventHandlerFuncs{
+		AddFunc:    vic.enqueueVolume,
+		UpdateFunc: func(old, cur interface{}) { vic.enqueueVolume(cur) },
+	}, 0); err != nil {
+		return nil, err
+	}
+	vic.cacheSyncs = append(vic.cacheSyncs, ds.VolumeInformer.HasSynced)
+
+	if _, err = ds.SettingInformer.AddEventHandlerWithResyncPeriod(cache.FilteringResourceEventHandler{
+		FilterFunc: isSettingVolumeIdleThreshold,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    vic.enqueueSetting,
+			UpdateFunc: func(old, cur interface{}) { vic.enqueueSetting(cur) },
+		},
+	}, 0); err != nil {
+		return nil, err
+	}
+	vic.cacheSyncs = append(vic.cacheSyncs, ds.SettingInformer.HasSynced)
+
+	return vic, nil
+}
+
+func (vic *VolumeIdleController) enqueueVolume(obj interface{}) {
+	key, err := controller.KeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to get key for object %#v: %v", obj, err))
+		return
+	}
+
+	vic.queue.Add(key)
+}
+
+func (vic *VolumeIdleController) enqueueVolumeAfter(obj interface{}, duration time.Duration) {
+	key, err := controller.KeyFunc(obj)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("enqueueVolumeAfter: failed to get key for object %#v: %v", obj, err))
+		return
+	}
+
+	vic.queue.AddAfter(key, duration)
+}
+
+func isSettingVolumeIdleThreshold(obj interface{}) bool {
+	setting, ok := obj.(*longhorn.Setting)
+	if !ok {
+		return false
+	}
+	return setting.Name == string(types.SettingNameVolumeIdleThreshold)
+}
+
+func (vic *VolumeIdleController) enqueueSetting(obj interface{}) {
+	_, ok := obj.(*longhorn.Setting)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("received unexpected obj: %#v", obj))
+		return
+	}
+
+	vs, err := vic.ds.ListVolumesRO()
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list volumes: %v", err))
+		return
+	}
+
+	for _, v := range vs {
+		vic.enqueueVolume(v)
+	}
+}
+
+func (vic *VolumeIdleController) Run(workers int, stopCh <-chan struct{}) {
+	defer utilruntime.HandleCrash()
+	defer vic.queue.ShutDown()
+
+	vic.logger.Info("Starting Longhorn volume idle controller")
+	defer vic.logger.Info("Shut down Longhorn volume idle controller")
+
+	if !cache.WaitForNamedCacheSync(vic.name, stopCh, vic.cacheSyncs...) {
+		return
+	}
+
+	for i := 0; i < workers; i++ {
+		go wait.Until(vic.worker, time.Second, stopCh)
+	}
+
+	<-stopCh
+}
+
+func (vic *VolumeIdleController) worker() {
+	for vic.processNextWorkItem() {
+	}
+}
+
+func (vic *VolumeIdleController) processNextWorkItem() bool {
+	key, quit := vic.queue.Get()
+	if quit {
+		return false
+	}
+	defer vic.queue.Done(key)
+	err := vic.syncHandler(key.(string))
+	vic.handleErr(err, key)
+	return true
+}
+
+func (vic *VolumeIdleController) handleErr(err error, key interface{}) {
+	if err == nil {
+		vic.queue.Forget(key)
+		return
+	}
+
+	log := vic.logger.WithField("Volume", key)
+	handleReconcileErrorLogging(log, err, "Failed to sync Longhorn volume")
+	vic.queue.AddRateLimited(key)
+}
+
+func (vic *VolumeIdleController) syncHandler(key string) (err error) {
+	defer func() {
+		err = errors.Wrapf(err, "%v: failed to sync volume %v", vic.name, key)
+	}()
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	if namespace != vic.namespace {
+		return nil
+	}
+	return vic.reconcile(name)
+}
+
+func (vic *VolumeIdleController) reconcile(volName string) (err error) {
+	vol, err := vic.ds.GetVolume(volName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	if vic.controllerID != vol.Status.OwnerID || !vol.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	existingVol := vol.DeepCopy()
+	defer func() {
+		if err != nil {
+			return
+		}
+		if !reflect.DeepEqual(existingVol.Status.Conditions, vol.Status.Conditions) {
+			_, err = vic.ds.UpdateVolumeStatus(vol)
+			return
+		}
+	}()
+
+	thresholdDays, err := vic.ds.GetSettingAsInt(types.SettingNameVolumeIdleThreshold)
+	if err != nil {
+		return err
+	}
+	if thresholdDays <= 0 {
+		vol.Status.Conditions = types.RemoveCondition(vol.Status.Conditions, longhorn.VolumeConditionTypeIdle)
+		return nil
+	}
+	threshold := time.Duration(thresholdDays) * 24 * time.Hour
+
+	lastConsumedAt, err := getVolumeLastConsumedAt(vol)
+	if err != nil {
+		return err
+	}
+	if lastConsumedAt.IsZero() {
+		vol.Status.Conditions = types.SetCondition(vol.Status.Conditions, longhorn.VolumeConditionTypeIdle, longhorn.ConditionStatusFalse, "", "")
+		return nil
+	}
+
+	idleFor := vic.nowHandler().Sub(lastConsumedAt)
+	if idleFor < threshold {
+		vol.Status.Conditions = types.SetCondition(vol.Status.Conditions, longhorn.VolumeConditionTypeIdle, longhorn.ConditionStatusFalse, "", "")
+		vic.enqueueVolumeAfter(vol, threshold-idleFor)
+		return nil
+	}
+
+	vol.Status.Conditions = types.SetCondition(vol.Status.Conditions, longhorn.VolumeConditionTypeIdle, longhorn.ConditionStatusTrue,
+		longhorn.VolumeConditionReasonNoConsumer,
+		fmt.Sprintf("No pod has consumed the volume since %v, over the idle threshold of %v days",
+			lastConsumedAt.UTC().Format(time.RFC3339), thresholdDays))
+	return nil
+}
+
+// getVolumeLastConsumedAt returns when a pod last consumed the volume, by the Kubernetes status that the PV controller
+// keeps from the pods using the PVC of the volume. It returns the zero time if a pod still consumes the volume, and
+// the creation time of the volume if no pod ever consumed it.
+func getVolumeLastConsumedAt(vol *longhorn.Volume) (time.Time, error) {
+	ks := vol.Status.KubernetesStatus
+	if ks.LastPodRefAt != "" {
+		return util.ParseTime(ks.LastPodRefAt)
+	}
+	if len(ks.WorkloadsStatus) != 0 {
+		return time.Time{}, nil
+	}
+	return vol.CreationTimestamp.Time, nil
+}
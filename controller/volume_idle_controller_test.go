@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/kubernetes/pkg/controller"
+
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/longhorn/longhorn-manager/datastore"
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	lhfake "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned/fake"
+)
+
+func TestVolumeIdleControllerReconcile(t *testing.T) {
+	now := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time {
+		return now.Add(-time.Duration(days) * 24 * time.Hour)
+	}
+
+	tests := map[string]struct {
+		threshold      string
+		createdAt      time.Time
+		lastPodRefAt   time.Time
+		workloads      []longhorn.WorkloadStatus
+		conditions     []longhorn.Condition
+		ownerID        string
+		expectedStatus longhorn.ConditionStatus
+	}{
+		"consumed": {
+			threshold:      "7",
+			createdAt:      daysAgo(30),
+			workloads:      []longhorn.WorkloadStatus{{PodName: "test-pod", PodStatus: "Running"}},
+			expectedStatus: longhorn.ConditionStatusFalse,
+		},
+		"released within the threshold": {
+			threshold:      "7",
+			createdAt:      daysAgo(30),
+			lastPodRefAt:   daysAgo(3),
+			workloads:      []longhorn.WorkloadStatus{{PodName: "test-pod", PodStatus: "Succeeded"}},
+			expectedStatus: longhorn.ConditionStatusFalse,
+		},
+		"released over the threshold": {
+			threshold:      "7",
+			createdAt:      daysAgo(30),
+			lastPodRefAt:   daysAgo(8),
+			workloads:      []longhorn.WorkloadStatus{{PodName: "test-pod", PodStatus: "Succeeded"}},
+			expectedStatus: longhorn.ConditionStatusTrue,
+		},
+		"never consumed within the threshold": {
+			threshold:      "7",
+			createdAt:      daysAgo(1),
+			expectedStatus: longhorn.ConditionStatusFalse,
+		},
+		"never consumed over the threshold": {
+			threshold:      "7",
+			createdAt:      daysAgo(30),
+			expectedStatus: longhorn.ConditionStatusTrue,
+		},
+		"disabled": {
+			threshold: "0",
+			createdAt: daysAgo(30),
+			conditions: []longhorn.Condition{
+				{Type: longhorn.VolumeConditionTypeIdle, Status: longhorn.ConditionStatusTrue},
+			},
+		},
+		"owned by another node": {
+			threshold: "7",
+			createdAt: daysAgo(30),
+			ownerID:   TestNode2,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset() // nolint: staticcheck
+			lhClient := lhfake.NewSimpleClientset() // nolint: staticcheck
+			extensionsClient := apiextensionsfake.NewSimpleClientset()
+			informerFactories := util.NewInformerFactories(TestNamespace, kubeClient, lhClient, controller.NoResyncPeriodFunc())
+			ds := datastore.NewDataStore(TestNamespace, lhClient, kubeClient, extensionsClient, informerFactories)
+
+			vic, err := NewVolumeIdleController(logrus.StandardLogger(), ds, scheme.Scheme, kubeClient, TestNode1, TestNamespace)
+			require.NoError(t, err)
+			vic.nowHandler = func() time.Time { return now }
+
+			lhInformers := informerFactories.LhInformerFactory.Longhorn().V1beta2()
+			setting := newSetting(string(types.SettingNameVolumeIdleThreshold), tc.threshold)
+			require.NoError(t, lhInformers.Settings().Informer().GetIndexer().Add(setting))
+
+			ownerID := tc.ownerID
+			if ownerID == "" {
+				ownerID = TestNode1
+			}
+			volume := &longhorn.Volume{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              TestVolumeName,
+					Namespace:         TestNamespace,
+					CreationTimestamp: metav1.NewTime(tc.createdAt),
+				},
+				Status: longhorn.VolumeStatus{
+					OwnerID:    ownerID,
+					Conditions: tc.conditions,
+					KubernetesStatus: longhorn.KubernetesStatus{
+						WorkloadsStatus: tc.workloads,
+					},
+				},
+			}
+			if !tc.lastPodRefAt.IsZero() {
+				volume.Status.KubernetesStatus.LastPodRefAt = tc.lastPodRefAt.Format(time.RFC3339)
+			}
+			volume, err = lhClient.LonghornV1beta2().Volumes(TestNamespace).Create(context.TODO(), volume, metav1.CreateOptions{})
+			require.NoError(t, err)
+			require.NoError(t, lhInformers.Volumes().Informer().GetIndexer().Add(volume))
+
+			require.NoError(t, vic.reconcile(TestVolumeName))
+
+			volume, err = lhClient.LonghornV1beta2().Volumes(TestNamespace).Get(context.TODO(), TestVolumeName, metav1.GetOptions{})
+			require.NoError(t, err)
+			if tc.expectedStatus == "" {
+				for _, condition := range volume.Status.Conditions {
+					assert.NotEqual(t, longhorn.VolumeConditionTypeIdle, condition.Type)
+				}
+				return
+			}
+			condition := types.GetCondition(volume.Status.Conditions, longhorn.VolumeConditionTypeIdle)
+			assert.Equal(t, tc.expectedStatus, condition.Status)
+			if tc.expectedStatus == longhorn.ConditionStatusTrue {
+				assert.Equal(t, longhorn.VolumeConditionReasonNoConsumer, condition.Reason)
+			}
+		})
+	}
+}
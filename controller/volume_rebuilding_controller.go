@@ -463,6 +463,15 @@ func (vbc *VolumeRebuildingController) reconcile(volName string) (err error) {
 }
 
 func (vbc *VolumeRebuildingController) isVolumeOfflineRebuildEnabled(vol *longhorn.Volume) (bool, error) {
+	// The offline rebuilding only attaches the volume for the rebuilding, which is pointless if it is disabled.
+	rebuildingEnabled, err := vbc.ds.IsVolumeReplicaRebuildingEnabled(vol)
+	if err != nil {
+		return false, err
+	}
+	if !rebuildingEnabled {
+		return false, nil
+	}
+
 	if vol.Spec.OfflineRebuilding == longhorn.VolumeOfflineRebuildingEnabled {
 		return true, nil
 	}
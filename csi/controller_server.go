@@ -9,6 +9,7 @@ import (
 	"os"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,15 +21,20 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/longhorn/longhorn-manager/constant"
 	"github.com/longhorn/longhorn-manager/datastore"
 	"github.com/longhorn/longhorn-manager/types"
 	"github.com/longhorn/longhorn-manager/util"
 
+	apputil "github.com/longhorn/longhorn-manager/app/util"
 	longhornclient "github.com/longhorn/longhorn-manager/client"
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 	lhclientset "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned"
@@ -61,6 +67,8 @@ type ControllerServer struct {
 	log         *logrus.Entry
 	lhClient    lhclientset.Interface
 	lhNamespace string
+
+	eventRecorder record.EventRecorder
 }
 
 func NewControllerServer(apiClient *longhornclient.RancherClient, nodeID string) (*ControllerServer, error) {
@@ -79,6 +87,15 @@ func NewControllerServer(apiClient *longhornclient.RancherClient, nodeID string)
 		return nil, errors.Wrap(err, "failed to get longhorn clientset")
 	}
 
+	scheme := runtime.NewScheme()
+	if err := longhorn.SchemeBuilder.AddToScheme(scheme); err != nil {
+		return nil, errors.Wrap(err, "failed to create scheme")
+	}
+	eventBroadcaster, err := apputil.CreateEventBroadcaster(config)
+	if err != nil {
+		return nil, err
+	}
+
 	return &ControllerServer{
 		apiClient: apiClient,
 		nodeID:    nodeID,
@@ -100,6 +117,8 @@ func NewControllerServer(apiClient *longhornclient.RancherClient, nodeID string)
 		log:         logrus.StandardLogger().WithField("component", "csi-controller-server"),
 		lhClient:    lhClient,
 		lhNamespace: lhNamespace,
+
+		eventRecorder: eventBroadcaster.NewRecorder(scheme, corev1.EventSource{Component: "longhorn-csi-controller-server"}),
 	}, nil
 }
 
@@ -124,6 +143,14 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 	if req.GetCapacityRange() != nil {
 		reqVolSizeBytes = req.GetCapacityRange().GetRequiredBytes()
 	}
+	// The size parameter is the size of the volume when the request does not ask for a capacity.
+	if size, ok := volumeParameters["size"]; ok && reqVolSizeBytes == 0 {
+		sizeBytes, err := parseVolumeSize(size)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid parameter size: %v", err)
+		}
+		reqVolSizeBytes = sizeBytes
+	}
 	if reqVolSizeBytes < util.MinimalVolumeSize {
 		log.Infof("Volume %s requested capacity %v is smaller than minimal capacity %v, enforcing minimal capacity.", volumeID, reqVolSizeBytes, util.MinimalVolumeSize)
 		reqVolSizeBytes = util.MinimalVolumeSize
@@ -200,34 +227,25 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 			}
 		case *csi.VolumeContentSource_Volume:
 			if srcVolume := volumeSource.GetVolume(); srcVolume != nil {
-				longhornSrcVol, err := cs.apiClient.Volume.ById(srcVolume.VolumeId)
-				if err != nil {
-					return nil, status.Errorf(codes.NotFound, "failed to clone volume: source volume %s is unavailable", srcVolume.VolumeId)
-				}
-				if longhornSrcVol == nil {
-					return nil, status.Errorf(codes.NotFound, "failed to clone volume: source volume %s is not found", srcVolume.VolumeId)
-				}
-
-				// check size of source and requested
-				srcVolSizeBytes, err := strconv.ParseInt(longhornSrcVol.Size, 10, 64)
-				if err != nil {
-					return nil, status.Errorf(codes.Internal, "%v", err)
-				}
-				if reqVolSizeBytes != srcVolSizeBytes {
-					return nil, status.Errorf(codes.OutOfRange, "failed to clone volume: the requested size (%v bytes) is different than the source volume size (%v bytes)", reqVolSizeBytes, srcVolSizeBytes)
+				if err := cs.validateCloneSourceVolume(srcVolume.VolumeId, reqVolSizeBytes); err != nil {
+					return nil, err
 				}
-
 				dataSource, _ := types.NewVolumeDataSource(longhorn.VolumeDataSourceTypeVolume, map[string]string{types.VolumeNameKey: srcVolume.VolumeId})
 				volumeParameters["dataSource"] = string(dataSource)
 			}
 		default:
 			return nil, status.Errorf(codes.InvalidArgument, "%v not a proper volume source", volumeSource)
 		}
+	} else if fromVolume := volumeParameters["fromVolume"]; fromVolume != "" {
+		// The parameter fromVolume conflicting with a volume content source is refused by getVolumeOptions.
+		if err := cs.validateCloneSourceVolume(fromVolume, reqVolSizeBytes); err != nil {
+			return nil, err
+		}
 	} else {
 		// Refuse to create a NEW XFS volume smaller than 300 MiB, since mkfs.xfs will eventually fail in the node
 		// server. Don't refuse for clones/restores though, as they may have an existing filesystem.
 		for _, cap := range req.VolumeCapabilities {
-			if cap.GetMount().GetFsType() == "xfs" && reqVolSizeBytes < util.MinimalVolumeSizeXFS {
+			if cap.GetMount() != nil && getVolumeFsType(cap, volumeParameters) == "xfs" && reqVolSizeBytes < util.MinimalVolumeSizeXFS {
 				return nil, fmt.Errorf("XFS filesystems with size %d, smaller than %d, are not supported",
 					reqVolSizeBytes, util.MinimalVolumeSizeXFS)
 			}
@@ -280,15 +298,52 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 		}
 	}
 
-	vol, err := getVolumeOptions(volumeID, volumeParameters)
+	defaultRevisionCounterDisabled, err := cs.getDefaultRevisionCounterDisabled(ctx)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, err.Error())
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	settingStaleReplicaTimeout, err := cs.getDefaultStaleReplicaTimeout(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	vol, err := getVolumeOptions(volumeID, volumeParameters, defaultRevisionCounterDisabled, settingStaleReplicaTimeout)
+	if err != nil {
+		return nil, status.Error(getVolumeOptionsErrorCode(err), err.Error())
+	}
+
+	dataEngineFallbackReason := ""
+	if fallback, _ := isDataEngineFallbackToV1Requested(volumeParameters); fallback {
+		dataEngineFallbackReason, err = cs.getDataEngineV2UnsatisfiedReason(ctx, vol, reqVolSizeBytes)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if dataEngineFallbackReason != "" {
+			log.Warnf("Falling back to data engine %v for volume %v: %v", longhorn.DataEngineTypeV1, volumeID, dataEngineFallbackReason)
+			volumeParameters["dataEngine"] = string(longhorn.DataEngineTypeV1)
+			delete(volumeParameters, "dataEngineFallbackToV1")
+			if vol, err = getVolumeOptions(volumeID, volumeParameters, defaultRevisionCounterDisabled, settingStaleReplicaTimeout); err != nil {
+				return nil, status.Errorf(getVolumeOptionsErrorCode(err), "failed to fall back to data engine %v: %v", longhorn.DataEngineTypeV1, err)
+			}
+		}
 	}
 
 	if err = cs.checkAndPrepareBackingImage(volumeID, vol.BackingImage, volumeParameters, vol.DataEngine); err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
+	if err = cs.checkParameterNode(ctx, "pinnedNode", vol.NodeID); err != nil {
+		return nil, err
+	}
+
+	if err = cs.checkParameterNode(ctx, "migrationTargetNode", vol.MigrationTargetNodeID); err != nil {
+		return nil, err
+	}
+
+	if err = cs.checkBackupTarget(ctx, volumeParameters["backupTargetName"]); err != nil {
+		return nil, err
+	}
+
 	vol.Name = volumeID
 	vol.Size = fmt.Sprintf("%d", reqVolSizeBytes)
 
@@ -313,6 +368,10 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 		return nil, status.Error(codes.DeadlineExceeded, "failed to wait for volume creation to complete")
 	}
 
+	if dataEngineFallbackReason != "" {
+		cs.recordDataEngineFallback(ctx, resVol.Id, dataEngineFallbackReason)
+	}
+
 	return &csi.CreateVolumeResponse{
 		Volume: &csi.Volume{
 			VolumeId:           resVol.Id,
@@ -324,6 +383,63 @@ func (cs *ControllerServer) CreateVolume(ctx context.Context, req *csi.CreateVol
 	}, nil
 }
 
+// validateCloneSourceVolume checks that the source volume of a clone exists and has the requested size.
+func (cs *ControllerServer) validateCloneSourceVolume(srcVolumeID string, reqVolSizeBytes int64) error {
+	longhornSrcVol, err := cs.apiClient.Volume.ById(srcVolumeID)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "failed to clone volume: source volume %s is unavailable", srcVolumeID)
+	}
+	if longhornSrcVol == nil {
+		return status.Errorf(codes.NotFound, "failed to clone volume: source volume %s is not found", srcVolumeID)
+	}
+
+	// check size of source and requested
+	srcVolSizeBytes, err := strconv.ParseInt(longhornSrcVol.Size, 10, 64)
+	if err != nil {
+		return status.Errorf(codes.Internal, "%v", err)
+	}
+	if reqVolSizeBytes != srcVolSizeBytes {
+		return status.Errorf(codes.OutOfRange, "failed to clone volume: the requested size (%v bytes) is different than the source volume size (%v bytes)", reqVolSizeBytes, srcVolSizeBytes)
+	}
+	return nil
+}
+
+// checkParameterNode verifies that the node of a parameter, like pinnedNode or migrationTargetNode, exists.
+func (cs *ControllerServer) checkParameterNode(ctx context.Context, parameter, nodeID string) error {
+	if nodeID == "" {
+		return nil
+	}
+	if _, err := cs.lhClient.LonghornV1beta2().Nodes(cs.lhNamespace).Get(ctx, nodeID, metav1.GetOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return status.Errorf(codes.InvalidArgument, "invalid parameter %s: node %s not found", parameter, nodeID)
+		}
+		return status.Errorf(codes.Internal, "failed to get node %s of parameter %s: %v", nodeID, parameter, err)
+	}
+	return nil
+}
+
+// checkBackupTarget verifies that the backup target of the backupTargetName parameter exists. A namespace-qualified
+// backup target must be in the Longhorn namespace, where all the backup targets live.
+func (cs *ControllerServer) checkBackupTarget(ctx context.Context, backupTargetName string) error {
+	namespace, name, err := parseBackupTargetName(backupTargetName)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid parameter backupTargetName: %v", err)
+	}
+	if name == "" {
+		return nil
+	}
+	if namespace != "" && namespace != cs.lhNamespace {
+		return status.Errorf(codes.InvalidArgument, "invalid parameter backupTargetName: backup target %s is not in the Longhorn namespace %s", backupTargetName, cs.lhNamespace)
+	}
+	if _, err := cs.lhClient.LonghornV1beta2().BackupTargets(cs.lhNamespace).Get(ctx, name, metav1.GetOptions{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return status.Errorf(codes.InvalidArgument, "invalid parameter backupTargetName: backup target %s not found", backupTargetName)
+		}
+		return status.Errorf(codes.Internal, "failed to get backup target %s: %v", backupTargetName, err)
+	}
+	return nil
+}
+
 func (cs *ControllerServer) getBackupVolumes(volumeName string) ([]*longhornclient.BackupVolume, error) {
 	bvs := []*longhornclient.BackupVolume{}
 	log := cs.log.WithFields(logrus.Fields{"function": "getBackupVolume"})
@@ -521,12 +637,27 @@ func (cs *ControllerServer) ControllerPublishVolume(ctx context.Context, req *cs
 		return nil, status.Errorf(codes.NotFound, "volume %s not found", volumeID)
 	}
 
+	backingImageReadyTimeout, err := getBackingImageReadyTimeout(req.GetVolumeContext())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid parameter backingImageReadyTimeout: %v", err)
+	}
+	if err := cs.checkBackingImageReady(ctx, volumeID, volume.BackingImage, backingImageReadyTimeout, time.Now()); err != nil {
+		return nil, err
+	}
+
 	if volume.Frontend != string(longhorn.VolumeFrontendBlockDev) &&
 		volume.Frontend != string(longhorn.VolumeFrontendUblk) {
 		return nil, status.Errorf(codes.InvalidArgument, "volume %s invalid frontend type %s", volumeID, volume.Frontend)
 	}
 
-	if requiresSharedAccess(volume, volumeCapability) {
+	if err := ValidateAccessModeCompatibility(volume, volumeCapability); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	if requiresSharedAccess(volume, volumeCapability) && !canKeepDowngradedAccessMode(volume, nodeID) {
+		if err := checkDowngradedAccessModePromotion(volume); err != nil {
+			return nil, err
+		}
 		volume, err = cs.updateVolumeAccessMode(volume, longhorn.AccessModeReadWriteMany)
 		if err != nil {
 			return nil, err
@@ -576,6 +707,52 @@ func (cs *ControllerServer) ControllerPublishVolume(ctx context.Context, req *cs
 	})
 }
 
+// checkBackingImageReady fails the attachment of the volume rather than waiting for its backing image, once the
+// backing image is not ready on any disk within the timeout since the creation of the volume or the backing image.
+// The attachment is retried later while the timeout has not expired yet.
+func (cs *ControllerServer) checkBackingImageReady(ctx context.Context, volumeName, backingImageName string, timeout time.Duration, now time.Time) error {
+	if backingImageName == "" || timeout == 0 {
+		return nil
+	}
+
+	backingImage, err := cs.lhClient.LonghornV1beta2().BackingImages(cs.lhNamespace).Get(ctx, backingImageName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return status.Errorf(codes.FailedPrecondition, "backing image %s of volume %s not found", backingImageName, volumeName)
+		}
+		return status.Errorf(codes.Internal, "failed to get backing image %s of volume %s: %v", backingImageName, volumeName, err)
+	}
+
+	messages := []string{}
+	for diskUUID, fileStatus := range backingImage.Status.DiskFileStatusMap {
+		if fileStatus == nil {
+			continue
+		}
+		if fileStatus.State == longhorn.BackingImageStateReady {
+			return nil
+		}
+		if fileStatus.Message != "" {
+			messages = append(messages, fmt.Sprintf("disk %s is %s: %s", diskUUID, fileStatus.State, fileStatus.Message))
+		}
+	}
+	sort.Strings(messages)
+
+	waitingSince := backingImage.CreationTimestamp.Time
+	volume, err := cs.lhClient.LonghornV1beta2().Volumes(cs.lhNamespace).Get(ctx, volumeName, metav1.GetOptions{})
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to get volume %s: %v", volumeName, err)
+	}
+	if volume.CreationTimestamp.After(waitingSince) {
+		waitingSince = volume.CreationTimestamp.Time
+	}
+
+	if now.Sub(waitingSince) < timeout {
+		return status.Errorf(codes.Aborted, "backing image %s of volume %s is not ready yet", backingImageName, volumeName)
+	}
+	return status.Errorf(codes.FailedPrecondition, "backing image %s of volume %s is not ready within %v: %s",
+		backingImageName, volumeName, timeout, strings.Join(messages, ", "))
+}
+
 // We pick the same name as the volume attachment object at
 // https://github.com/kubernetes/kubernetes/blob/f1e74f77ff88abb7acf0fb0e86ba21bc0f2395c9/pkg/volume/csi/csi_attacher.go#L653-L656
 func generateAttachmentID(volName, nodeID string) string {
@@ -725,15 +902,6 @@ func (cs *ControllerServer) GetCapacity(ctx context.Context, req *csi.GetCapacit
 		}
 		return nil, status.Errorf(codes.Internal, "unexpected error: %v", err)
 	}
-	if types.GetCondition(node.Status.Conditions, longhorn.NodeConditionTypeReady).Status != longhorn.ConditionStatusTrue {
-		return &csi.GetCapacityResponse{}, nil
-	}
-	if types.GetCondition(node.Status.Conditions, longhorn.NodeConditionTypeSchedulable).Status != longhorn.ConditionStatusTrue {
-		return &csi.GetCapacityResponse{}, nil
-	}
-	if !node.Spec.AllowScheduling || node.Spec.EvictionRequested {
-		return &csi.GetCapacityResponse{}, nil
-	}
 
 	allowEmptyNodeSelectorVolume, err := cs.getSettingAsBoolean(ctx, types.SettingNameAllowEmptyNodeSelectorVolume)
 	if err != nil {
@@ -743,7 +911,7 @@ func (cs *ControllerServer) GetCapacity(ctx context.Context, req *csi.GetCapacit
 	if nodeSelectorRaw, ok := scParameters["nodeSelector"]; ok && len(nodeSelectorRaw) > 0 {
 		nodeSelector = strings.Split(nodeSelectorRaw, ",")
 	}
-	if !types.IsSelectorsInTags(node.Spec.Tags, nodeSelector, allowEmptyNodeSelectorVolume) {
+	if !isNodeSchedulable(node, nodeSelector, allowEmptyNodeSelectorVolume) {
 		return &csi.GetCapacityResponse{}, nil
 	}
 
@@ -760,8 +928,105 @@ func (cs *ControllerServer) GetCapacity(ctx context.Context, req *csi.GetCapacit
 		return nil, status.Errorf(codes.Internal, "failed to get setting %v: %v", types.SettingNameStorageOverProvisioningPercentage, err)
 	}
 
-	var v1AvailableCapacity int64 = 0
-	var v2AvailableCapacity int64 = 0
+	v1AvailableCapacity, v2AvailableCapacity := getNodeAvailableCapacity(node, diskSelector, allowEmptyDiskSelectorVolume, overProvisioningPercentage)
+
+	rsp := &csi.GetCapacityResponse{}
+	dataEngine := longhorn.DataEngineTypeV1
+	if dataEngineType, ok := scParameters["dataEngine"]; ok {
+		dataEngine = longhorn.DataEngineType(dataEngineType)
+	}
+	switch dataEngine {
+	case longhorn.DataEngineTypeV1:
+		rsp.AvailableCapacity = v1AvailableCapacity
+	case longhorn.DataEngineTypeV2:
+		rsp.AvailableCapacity = v2AvailableCapacity
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown data engine type %v", dataEngine)
+	}
+
+	log.Infof("Node: %s, DataEngine: %s, v1AvailableCapacity: %d, v2AvailableCapacity: %d", nodeID, dataEngine, v1AvailableCapacity, v2AvailableCapacity)
+	return rsp, nil
+}
+
+// getDataEngineV2UnsatisfiedReason returns why the volume cannot be created with the v2 data engine, or an empty
+// string if it can.
+func (cs *ControllerServer) getDataEngineV2UnsatisfiedReason(ctx context.Context, vol *longhornclient.Volume, size int64) (string, error) {
+	v2DataEngineEnabled, err := cs.getSettingAsBoolean(ctx, types.SettingNameV2DataEngine)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get setting %v", types.SettingNameV2DataEngine)
+	}
+	allowEmptyNodeSelectorVolume, err := cs.getSettingAsBoolean(ctx, types.SettingNameAllowEmptyNodeSelectorVolume)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get setting %v", types.SettingNameAllowEmptyNodeSelectorVolume)
+	}
+	allowEmptyDiskSelectorVolume, err := cs.getSettingAsBoolean(ctx, types.SettingNameAllowEmptyDiskSelectorVolume)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get setting %v", types.SettingNameAllowEmptyDiskSelectorVolume)
+	}
+	overProvisioningPercentage, err := cs.getSettingAsInt(ctx, types.SettingNameStorageOverProvisioningPercentage)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get setting %v", types.SettingNameStorageOverProvisioningPercentage)
+	}
+	nodes, err := cs.lhClient.LonghornV1beta2().Nodes(cs.lhNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list nodes")
+	}
+
+	return getDataEngineV2UnsatisfiedReason(v2DataEngineEnabled, nodes.Items, vol, size,
+		allowEmptyNodeSelectorVolume, allowEmptyDiskSelectorVolume, overProvisioningPercentage), nil
+}
+
+// getDataEngineV2UnsatisfiedReason decides whether the volume falls back to the v1 data engine. The v2 data engine is
+// satisfied once a schedulable node has a block disk with the storage available for a replica of the volume, the
+// same way GetCapacity reports the capacity of a node.
+func getDataEngineV2UnsatisfiedReason(v2DataEngineEnabled bool, nodes []longhorn.Node, vol *longhornclient.Volume, size int64,
+	allowEmptyNodeSelectorVolume, allowEmptyDiskSelectorVolume bool, overProvisioningPercentage int64) string {
+	if !v2DataEngineEnabled {
+		return fmt.Sprintf("setting %v is disabled", types.SettingNameV2DataEngine)
+	}
+	for i := range nodes {
+		if !isNodeSchedulable(&nodes[i], vol.NodeSelector, allowEmptyNodeSelectorVolume) {
+			continue
+		}
+		_, v2AvailableCapacity := getNodeAvailableCapacity(&nodes[i], vol.DiskSelector, allowEmptyDiskSelectorVolume, overProvisioningPercentage)
+		if v2AvailableCapacity >= size {
+			return ""
+		}
+	}
+	return fmt.Sprintf("no schedulable node has a block disk with %v bytes available", size)
+}
+
+// recordDataEngineFallback warns by an event of the volume that it falls back to the v1 data engine.
+func (cs *ControllerServer) recordDataEngineFallback(ctx context.Context, volumeName, reason string) {
+	if cs.eventRecorder == nil {
+		return
+	}
+	volume, err := cs.lhClient.LonghornV1beta2().Volumes(cs.lhNamespace).Get(ctx, volumeName, metav1.GetOptions{})
+	if err != nil {
+		cs.log.WithError(err).Warnf("Failed to get volume %v to record its data engine fallback", volumeName)
+		return
+	}
+	cs.eventRecorder.Eventf(volume, corev1.EventTypeWarning, constant.EventReasonDataEngineFallback,
+		"Created volume with data engine %v instead of %v: %v", longhorn.DataEngineTypeV1, longhorn.DataEngineTypeV2, reason)
+}
+
+// isNodeSchedulable returns whether the replicas of a volume with the node selector can be scheduled on the node.
+func isNodeSchedulable(node *longhorn.Node, nodeSelector []string, allowEmptyNodeSelectorVolume bool) bool {
+	if types.GetCondition(node.Status.Conditions, longhorn.NodeConditionTypeReady).Status != longhorn.ConditionStatusTrue {
+		return false
+	}
+	if types.GetCondition(node.Status.Conditions, longhorn.NodeConditionTypeSchedulable).Status != longhorn.ConditionStatusTrue {
+		return false
+	}
+	if !node.Spec.AllowScheduling || node.Spec.EvictionRequested {
+		return false
+	}
+	return types.IsSelectorsInTags(node.Spec.Tags, nodeSelector, allowEmptyNodeSelectorVolume)
+}
+
+// getNodeAvailableCapacity returns the largest storage schedulable on a single disk of the node for a volume with the
+// disk selector, for the v1 data engine on the filesystem disks and for the v2 data engine on the block disks.
+func getNodeAvailableCapacity(node *longhorn.Node, diskSelector []string, allowEmptyDiskSelectorVolume bool, overProvisioningPercentage int64) (v1AvailableCapacity, v2AvailableCapacity int64) {
 	for diskName, diskStatus := range node.Status.DiskStatus {
 		diskSpec, exists := node.Spec.Disks[diskName]
 		if !exists {
@@ -786,23 +1051,7 @@ func (cs *ControllerServer) GetCapacity(ctx context.Context, req *csi.GetCapacit
 			v2AvailableCapacity = max(v2AvailableCapacity, storageSchedulable)
 		}
 	}
-
-	rsp := &csi.GetCapacityResponse{}
-	dataEngine := longhorn.DataEngineTypeV1
-	if dataEngineType, ok := scParameters["dataEngine"]; ok {
-		dataEngine = longhorn.DataEngineType(dataEngineType)
-	}
-	switch dataEngine {
-	case longhorn.DataEngineTypeV1:
-		rsp.AvailableCapacity = v1AvailableCapacity
-	case longhorn.DataEngineTypeV2:
-		rsp.AvailableCapacity = v2AvailableCapacity
-	default:
-		return nil, status.Errorf(codes.InvalidArgument, "unknown data engine type %v", dataEngine)
-	}
-
-	log.Infof("Node: %s, DataEngine: %s, v1AvailableCapacity: %d, v2AvailableCapacity: %d", nodeID, dataEngine, v1AvailableCapacity, v2AvailableCapacity)
-	return rsp, nil
+	return v1AvailableCapacity, v2AvailableCapacity
 }
 
 func (cs *ControllerServer) getSettingAsBoolean(ctx context.Context, name types.SettingName) (bool, error) {
@@ -817,6 +1066,28 @@ func (cs *ControllerServer) getSettingAsBoolean(ctx context.Context, name types.
 	return value, nil
 }
 
+// getDefaultRevisionCounterDisabled returns the per data engine default of the revision counter for the new volumes.
+func (cs *ControllerServer) getDefaultRevisionCounterDisabled(ctx context.Context) (map[longhorn.DataEngineType]bool, error) {
+	obj, err := cs.lhClient.LonghornV1beta2().Settings(cs.lhNamespace).Get(ctx, string(types.SettingNameDisableRevisionCounter), metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return parseDefaultRevisionCounterDisabled(obj.Value)
+}
+
+// getDefaultStaleReplicaTimeout returns the default stale replica timeout setting for the new volumes, or 0 if the
+// setting does not exist, for example with a manager of an older version.
+func (cs *ControllerServer) getDefaultStaleReplicaTimeout(ctx context.Context) (int64, error) {
+	timeout, err := cs.getSettingAsInt(ctx, types.SettingNameDefaultStaleReplicaTimeout)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return timeout, nil
+}
+
 func (cs *ControllerServer) getSettingAsInt(ctx context.Context, name types.SettingName) (int64, error) {
 	obj, err := cs.lhClient.LonghornV1beta2().Settings(cs.lhNamespace).Get(ctx, string(name), metav1.GetOptions{})
 	if err != nil {
@@ -1298,6 +1569,21 @@ func (cs *ControllerServer) ListSnapshots(context.Context, *csi.ListSnapshotsReq
 	return nil, status.Error(codes.Unimplemented, "")
 }
 
+// checkVolumeExpansionMaxSize rejects expanding the volume beyond its max size.
+func checkVolumeExpansionMaxSize(vol *longhornclient.Volume, requestedSize int64) error {
+	if vol.MaxSize == "" {
+		return nil
+	}
+	maxSize, err := strconv.ParseInt(vol.MaxSize, 10, 64)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to parse max size %v of volume %v: %v", vol.MaxSize, vol.Name, err)
+	}
+	if maxSize > 0 && requestedSize > maxSize {
+		return status.Errorf(codes.OutOfRange, "requested size %v of volume %v exceeds the max size %v", requestedSize, vol.Name, maxSize)
+	}
+	return nil
+}
+
 func (cs *ControllerServer) ControllerExpandVolume(ctx context.Context, req *csi.ControllerExpandVolumeRequest) (*csi.ControllerExpandVolumeResponse, error) {
 	log := cs.log.WithFields(logrus.Fields{"function": "ControllerExpandVolume"})
 
@@ -1336,6 +1622,10 @@ func (cs *ControllerServer) ControllerExpandVolume(ctx context.Context, req *csi
 		return nil, status.Errorf(codes.Internal, "%v", err)
 	}
 
+	if err := checkVolumeExpansionMaxSize(existVol, requestedSize); err != nil {
+		return nil, err
+	}
+
 	isOnlineExpansion := existVol.State == string(longhorn.VolumeStateAttached)
 
 	if existVol, err = cs.apiClient.Volume.ActionExpand(existVol, &longhornclient.ExpandInput{
@@ -1398,6 +1688,41 @@ func isVolumeAvailableOn(vol *longhornclient.Volume, node string) bool {
 	return vol.State == string(longhorn.VolumeStateAttached) && isEngineOnNodeAvailable(vol, node)
 }
 
+// isDowngradedFromRWX returns true if the volume was downgraded from rwx by the access mode auto downgrade.
+func isDowngradedFromRWX(vol *longhornclient.Volume) bool {
+	return vol.AutoDowngradeFromRWX && vol.AccessMode == string(longhorn.AccessModeReadWriteOnce)
+}
+
+// canKeepDowngradedAccessMode returns true if the volume was downgraded from rwx by the access mode auto downgrade,
+// and it can be published to the node without being promoted back to rwx, since the publish comes from its single consumer.
+func canKeepDowngradedAccessMode(vol *longhornclient.Volume, node string) bool {
+	if !isDowngradedFromRWX(vol) {
+		return false
+	}
+	// Another consumer showed up, for example a pod outside of the workload, so the volume needs to be shared again.
+	if getVolumeConsumerCount(vol) > 1 {
+		return false
+	}
+	return vol.State == string(longhorn.VolumeStateDetached) || isVolumeAvailableOn(vol, node)
+}
+
+// checkDowngradedAccessModePromotion returns a retryable error if the downgraded volume cannot be promoted
+// back to rwx yet, since the access mode can only be changed while the volume is detached.
+func checkDowngradedAccessModePromotion(vol *longhornclient.Volume) error {
+	if !isDowngradedFromRWX(vol) || vol.State == string(longhorn.VolumeStateDetached) {
+		return nil
+	}
+	return status.Errorf(codes.Aborted, "volume %s downgraded from %v has to be detached before being shared by another consumer",
+		vol.Name, longhorn.AccessModeReadWriteMany)
+}
+
+func getVolumeConsumerCount(vol *longhornclient.Volume) int {
+	if vol.KubernetesStatus.LastPodRefAt != "" {
+		return 0
+	}
+	return len(vol.KubernetesStatus.WorkloadsStatus)
+}
+
 func isEngineOnNodeAvailable(vol *longhornclient.Volume, node string) bool {
 	for _, controller := range vol.Controllers {
 		if controller.HostId == node && controller.Endpoint != "" {
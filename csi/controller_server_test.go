@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/sirupsen/logrus"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/longhorn/longhorn-manager/types"
 
+	longhornclient "github.com/longhorn/longhorn-manager/client"
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 	lhfake "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned/fake"
 )
@@ -283,6 +285,329 @@ func TestGetCapacity(t *testing.T) {
 	}
 }
 
+func TestGetDataEngineV2UnsatisfiedReason(t *testing.T) {
+	newNodeWithDisks := func(name, tags string, isCondReady bool, disks ...*disk) longhorn.Node {
+		node := newNode(name, tags, isCondReady, true, true, false)
+		addDisksToNode(node, disks)
+		return *node
+	}
+
+	for _, test := range []struct {
+		testName            string
+		v2DataEngineEnabled bool
+		nodes               []longhorn.Node
+		nodeSelector        []string
+		diskSelector        []string
+		size                int64
+		expectFallback      bool
+	}{
+		{
+			testName:            "Block disk available",
+			v2DataEngineEnabled: true,
+			nodes: []longhorn.Node{
+				newNodeWithDisks("node-0", "", true, newDisk(1000, 0, 0, "", true, true, true, false)),
+			},
+			size: 500,
+		},
+		{
+			testName:            "V2 data engine disabled",
+			v2DataEngineEnabled: false,
+			nodes: []longhorn.Node{
+				newNodeWithDisks("node-0", "", true, newDisk(1000, 0, 0, "", true, true, true, false)),
+			},
+			size:           500,
+			expectFallback: true,
+		},
+		{
+			testName:            "Filesystem disk only",
+			v2DataEngineEnabled: true,
+			nodes: []longhorn.Node{
+				newNodeWithDisks("node-0", "", true, newDisk(1000, 0, 0, "", false, true, true, false)),
+			},
+			size:           500,
+			expectFallback: true,
+		},
+		{
+			testName:            "Block disk too small",
+			v2DataEngineEnabled: true,
+			nodes: []longhorn.Node{
+				newNodeWithDisks("node-0", "", true, newDisk(1000, 0, 800, "", true, true, true, false)),
+			},
+			size:           500,
+			expectFallback: true,
+		},
+		{
+			testName:            "Block disk on a node not ready",
+			v2DataEngineEnabled: true,
+			nodes: []longhorn.Node{
+				newNodeWithDisks("node-0", "", false, newDisk(1000, 0, 0, "", true, true, true, false)),
+				newNodeWithDisks("node-1", "", true, newDisk(1000, 0, 0, "", false, true, true, false)),
+			},
+			size:           500,
+			expectFallback: true,
+		},
+		{
+			testName:            "Block disk on another ready node",
+			v2DataEngineEnabled: true,
+			nodes: []longhorn.Node{
+				newNodeWithDisks("node-0", "", true, newDisk(1000, 0, 0, "", false, true, true, false)),
+				newNodeWithDisks("node-1", "", true, newDisk(1000, 0, 0, "", true, true, true, false)),
+			},
+			size: 500,
+		},
+		{
+			testName:            "Block disk not matching the disk selector",
+			v2DataEngineEnabled: true,
+			nodes: []longhorn.Node{
+				newNodeWithDisks("node-0", "", true, newDisk(1000, 0, 0, "hdd", true, true, true, false)),
+			},
+			diskSelector:   []string{"nvme"},
+			size:           500,
+			expectFallback: true,
+		},
+		{
+			testName:            "Block disk on a node not matching the node selector",
+			v2DataEngineEnabled: true,
+			nodes: []longhorn.Node{
+				newNodeWithDisks("node-0", "storage", true, newDisk(1000, 0, 0, "", true, true, true, false)),
+			},
+			nodeSelector:   []string{"fast"},
+			size:           500,
+			expectFallback: true,
+		},
+		{
+			testName:            "No node",
+			v2DataEngineEnabled: true,
+			size:                500,
+			expectFallback:      true,
+		},
+	} {
+		t.Run(test.testName, func(t *testing.T) {
+			vol := &longhornclient.Volume{
+				NodeSelector: test.nodeSelector,
+				DiskSelector: test.diskSelector,
+			}
+			reason := getDataEngineV2UnsatisfiedReason(test.v2DataEngineEnabled, test.nodes, vol, test.size, true, true, 100)
+			if test.expectFallback && reason == "" {
+				t.Errorf("expected fallback to data engine v1, but got none")
+			}
+			if !test.expectFallback && reason != "" {
+				t.Errorf("expected no fallback to data engine v1, but got: %v", reason)
+			}
+		})
+	}
+}
+
+func TestCheckParameterNode(t *testing.T) {
+	cs := &ControllerServer{
+		lhNamespace: "longhorn-system-test",
+		lhClient:    lhfake.NewSimpleClientset(),
+		log:         logrus.StandardLogger().WithField("component", "test-check-parameter-node"),
+	}
+	_, err := cs.lhClient.LonghornV1beta2().Nodes(cs.lhNamespace).Create(context.TODO(), newNode("node-0", "", true, true, true, false), metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal("failed to create node")
+	}
+
+	for _, test := range []struct {
+		parameter string
+		nodeID    string
+		err       error
+	}{
+		{
+			parameter: "pinnedNode",
+			nodeID:    "",
+		},
+		{
+			parameter: "pinnedNode",
+			nodeID:    "node-0",
+		},
+		{
+			parameter: "pinnedNode",
+			nodeID:    "node-1",
+			err:       status.Errorf(codes.InvalidArgument, "invalid parameter pinnedNode: node node-1 not found"),
+		},
+		{
+			parameter: "migrationTargetNode",
+			nodeID:    "node-0",
+		},
+		{
+			parameter: "migrationTargetNode",
+			nodeID:    "node-1",
+			err:       status.Errorf(codes.InvalidArgument, "invalid parameter migrationTargetNode: node node-1 not found"),
+		},
+	} {
+		checkError(t, test.err, cs.checkParameterNode(context.TODO(), test.parameter, test.nodeID))
+	}
+}
+
+func TestGetDefaultStaleReplicaTimeout(t *testing.T) {
+	cs := &ControllerServer{
+		lhNamespace: "longhorn-system-test",
+		lhClient:    lhfake.NewSimpleClientset(),
+		log:         logrus.StandardLogger().WithField("component", "test-get-default-stale-replica-timeout"),
+	}
+
+	// Without the setting, the compiled-in default applies.
+	timeout, err := cs.getDefaultStaleReplicaTimeout(context.TODO())
+	if err != nil {
+		t.Fatalf("failed to get the default stale replica timeout without the setting: %v", err)
+	}
+	if timeout != 0 {
+		t.Errorf("expected no default stale replica timeout without the setting, but got %v", timeout)
+	}
+
+	setting := &longhorn.Setting{
+		ObjectMeta: metav1.ObjectMeta{Name: string(types.SettingNameDefaultStaleReplicaTimeout)},
+		Value:      "60",
+	}
+	if _, err := cs.lhClient.LonghornV1beta2().Settings(cs.lhNamespace).Create(context.TODO(), setting, metav1.CreateOptions{}); err != nil {
+		t.Fatal("failed to create setting")
+	}
+	timeout, err = cs.getDefaultStaleReplicaTimeout(context.TODO())
+	if err != nil {
+		t.Fatalf("failed to get the default stale replica timeout: %v", err)
+	}
+	if timeout != 60 {
+		t.Errorf("expected the default stale replica timeout 60, but got %v", timeout)
+	}
+}
+
+func TestCheckBackingImageReady(t *testing.T) {
+	now := time.Now()
+	newBackingImage := func(name string, createdAt time.Time, states ...longhorn.BackingImageState) *longhorn.BackingImage {
+		backingImage := &longhorn.BackingImage{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(createdAt)},
+			Status: longhorn.BackingImageStatus{
+				DiskFileStatusMap: map[string]*longhorn.BackingImageDiskFileStatus{},
+			},
+		}
+		for i, state := range states {
+			backingImage.Status.DiskFileStatusMap[fmt.Sprintf("disk-%d", i)] = &longhorn.BackingImageDiskFileStatus{
+				State:   state,
+				Message: fmt.Sprintf("%v copy", state),
+			}
+		}
+		return backingImage
+	}
+
+	cs := &ControllerServer{
+		lhNamespace: "longhorn-system-test",
+		lhClient:    lhfake.NewSimpleClientset(),
+		log:         logrus.StandardLogger().WithField("component", "test-check-backing-image-ready"),
+	}
+	for _, backingImage := range []*longhorn.BackingImage{
+		newBackingImage("bi-ready", now.Add(-time.Hour), longhorn.BackingImageStateInProgress, longhorn.BackingImageStateReady),
+		newBackingImage("bi-in-progress", now.Add(-time.Hour), longhorn.BackingImageStateInProgress),
+		newBackingImage("bi-failed", now.Add(-time.Hour), longhorn.BackingImageStateFailed),
+	} {
+		if _, err := cs.lhClient.LonghornV1beta2().BackingImages(cs.lhNamespace).Create(context.TODO(), backingImage, metav1.CreateOptions{}); err != nil {
+			t.Fatal("failed to create backing image")
+		}
+	}
+	for name, createdAt := range map[string]time.Time{"vol-old": now.Add(-time.Hour), "vol-new": now.Add(-time.Minute)} {
+		volume := &longhorn.Volume{ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(createdAt)}}
+		if _, err := cs.lhClient.LonghornV1beta2().Volumes(cs.lhNamespace).Create(context.TODO(), volume, metav1.CreateOptions{}); err != nil {
+			t.Fatal("failed to create volume")
+		}
+	}
+
+	for name, test := range map[string]struct {
+		volumeName       string
+		backingImageName string
+		timeout          time.Duration
+		err              error
+	}{
+		"no backing image": {
+			volumeName: "vol-old",
+			timeout:    time.Minute,
+		},
+		"no timeout": {
+			volumeName:       "vol-old",
+			backingImageName: "bi-failed",
+		},
+		"ready": {
+			volumeName:       "vol-old",
+			backingImageName: "bi-ready",
+			timeout:          time.Minute,
+		},
+		"not ready within the timeout": {
+			volumeName:       "vol-old",
+			backingImageName: "bi-in-progress",
+			timeout:          10 * time.Minute,
+			err:              status.Errorf(codes.FailedPrecondition, "backing image bi-in-progress of volume vol-old is not ready within 10m0s: disk disk-0 is in-progress: in-progress copy"),
+		},
+		"not ready yet since the creation of the volume": {
+			volumeName:       "vol-new",
+			backingImageName: "bi-in-progress",
+			timeout:          10 * time.Minute,
+			err:              status.Errorf(codes.Aborted, "backing image bi-in-progress of volume vol-new is not ready yet"),
+		},
+		"failed": {
+			volumeName:       "vol-old",
+			backingImageName: "bi-failed",
+			timeout:          10 * time.Minute,
+			err:              status.Errorf(codes.FailedPrecondition, "backing image bi-failed of volume vol-old is not ready within 10m0s: disk disk-0 is failed: failed copy"),
+		},
+		"backing image not found": {
+			volumeName:       "vol-old",
+			backingImageName: "bi-missing",
+			timeout:          time.Minute,
+			err:              status.Errorf(codes.FailedPrecondition, "backing image bi-missing of volume vol-old not found"),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			checkError(t, test.err, cs.checkBackingImageReady(context.TODO(), test.volumeName, test.backingImageName, test.timeout, now))
+		})
+	}
+}
+
+func TestCheckBackupTarget(t *testing.T) {
+	cs := &ControllerServer{
+		lhNamespace: "longhorn-system-test",
+		lhClient:    lhfake.NewSimpleClientset(),
+		log:         logrus.StandardLogger().WithField("component", "test-check-backup-target"),
+	}
+	backupTarget := &longhorn.BackupTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "default",
+		},
+	}
+	_, err := cs.lhClient.LonghornV1beta2().BackupTargets(cs.lhNamespace).Create(context.TODO(), backupTarget, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatal("failed to create backup target")
+	}
+
+	for _, test := range []struct {
+		backupTargetName string
+		err              error
+	}{
+		{
+			backupTargetName: "",
+		},
+		{
+			backupTargetName: "default",
+		},
+		{
+			backupTargetName: "longhorn-system-test/default",
+		},
+		{
+			backupTargetName: "other",
+			err:              status.Errorf(codes.InvalidArgument, "invalid parameter backupTargetName: backup target other not found"),
+		},
+		{
+			backupTargetName: "longhorn-system-test/other",
+			err:              status.Errorf(codes.InvalidArgument, "invalid parameter backupTargetName: backup target longhorn-system-test/other not found"),
+		},
+		{
+			backupTargetName: "default-ns/default",
+			err:              status.Errorf(codes.InvalidArgument, "invalid parameter backupTargetName: backup target default-ns/default is not in the Longhorn namespace longhorn-system-test"),
+		},
+	} {
+		checkError(t, test.err, cs.checkBackupTarget(context.TODO(), test.backupTargetName))
+	}
+}
+
 func TestParseNodeID(t *testing.T) {
 	for _, test := range []struct {
 		topology *csi.Topology
@@ -323,6 +648,114 @@ func TestParseNodeID(t *testing.T) {
 	}
 }
 
+func TestCheckVolumeExpansionMaxSize(t *testing.T) {
+	for _, test := range []struct {
+		testName      string
+		maxSize       string
+		requestedSize int64
+		err           error
+	}{
+		{
+			testName:      "No max size",
+			requestedSize: 20 * 1024 * 1024 * 1024,
+		},
+		{
+			testName:      "Unlimited max size",
+			maxSize:       "0",
+			requestedSize: 20 * 1024 * 1024 * 1024,
+		},
+		{
+			testName:      "Expansion within max size",
+			maxSize:       "10737418240",
+			requestedSize: 10 * 1024 * 1024 * 1024,
+		},
+		{
+			testName:      "Expansion exceeding max size",
+			maxSize:       "10737418240",
+			requestedSize: 20 * 1024 * 1024 * 1024,
+			err:           status.Errorf(codes.OutOfRange, "requested size 21474836480 of volume test-vol exceeds the max size 10737418240"),
+		},
+	} {
+		t.Run(test.testName, func(t *testing.T) {
+			vol := &longhornclient.Volume{
+				Name:    "test-vol",
+				MaxSize: test.maxSize,
+			}
+			checkError(t, test.err, checkVolumeExpansionMaxSize(vol, test.requestedSize))
+		})
+	}
+}
+
+func TestDowngradedAccessModePromotion(t *testing.T) {
+	newDowngradedVolume := func(state longhorn.VolumeState, attachedNode string, consumers ...string) *longhornclient.Volume {
+		vol := &longhornclient.Volume{
+			Name:                 "test-vol",
+			AccessMode:           string(longhorn.AccessModeReadWriteOnce),
+			AutoDowngradeFromRWX: true,
+			State:                string(state),
+		}
+		if attachedNode != "" {
+			vol.Controllers = []longhornclient.Controller{{HostId: attachedNode, Endpoint: "/dev/longhorn/test-vol"}}
+		}
+		for _, consumer := range consumers {
+			vol.KubernetesStatus.WorkloadsStatus = append(vol.KubernetesStatus.WorkloadsStatus, longhornclient.WorkloadStatus{PodName: consumer})
+		}
+		return vol
+	}
+	promotionErr := status.Errorf(codes.Aborted, "volume test-vol downgraded from %v has to be detached before being shared by another consumer", longhorn.AccessModeReadWriteMany)
+
+	for _, test := range []struct {
+		testName   string
+		vol        *longhornclient.Volume
+		node       string
+		expectKeep bool
+		expectErr  error
+	}{
+		{
+			testName:   "Single consumer publishes the detached volume",
+			vol:        newDowngradedVolume(longhorn.VolumeStateDetached, "", "pod-0"),
+			node:       "node-0",
+			expectKeep: true,
+		},
+		{
+			testName:   "Single consumer publishes the volume attached to its node",
+			vol:        newDowngradedVolume(longhorn.VolumeStateAttached, "node-0", "pod-0"),
+			node:       "node-0",
+			expectKeep: true,
+		},
+		{
+			testName: "Second consumer publishes the detached volume",
+			vol:      newDowngradedVolume(longhorn.VolumeStateDetached, "", "pod-0", "pod-1"),
+			node:     "node-1",
+		},
+		{
+			testName:  "Second consumer publishes the volume attached to another node",
+			vol:       newDowngradedVolume(longhorn.VolumeStateAttached, "node-0", "pod-0", "pod-1"),
+			node:      "node-1",
+			expectErr: promotionErr,
+		},
+		{
+			testName: "Volume not downgraded",
+			vol: func() *longhornclient.Volume {
+				vol := newDowngradedVolume(longhorn.VolumeStateAttached, "node-0", "pod-0")
+				vol.AutoDowngradeFromRWX = false
+				return vol
+			}(),
+			node: "node-1",
+		},
+	} {
+		t.Run(test.testName, func(t *testing.T) {
+			keep := canKeepDowngradedAccessMode(test.vol, test.node)
+			if keep != test.expectKeep {
+				t.Errorf("expected keeping the downgraded access mode: %v, but got: %v", test.expectKeep, keep)
+			}
+			if !keep {
+				checkError(t, test.expectErr, checkDowngradedAccessModePromotion(test.vol))
+			}
+		})
+	}
+}
+
 func checkError(t *testing.T, expected, actual error) {
 	if expected == nil {
 		if actual != nil {
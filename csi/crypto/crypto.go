@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/cockroachdb/errors"
 	"github.com/sirupsen/logrus"
@@ -24,6 +25,13 @@ const (
 	CryptoKeyDefaultSize   = "256"
 	CryptoDefaultPBKDF     = "argon2i"
 
+	// CryptoDefaultIntegrity is the authenticated integrity algorithm of the volumes encrypted with integrity.
+	CryptoDefaultIntegrity = "hmac-sha256"
+
+	// luksFormatWithIntegrityTimeout is longer than the LUKS timeout, since formatting with integrity wipes the whole
+	// device to initialize the integrity tags.
+	luksFormatWithIntegrityTimeout = time.Hour
+
 	// Luks2MinimalVolumeSize the minimal volume size for the LUKS2format encryption.
 	//  https://gitlab.com/cryptsetup/cryptsetup/-/wikis/FrequentlyAskedQuestions
 	//  Section 10.10 What about the size of the LUKS2 header
@@ -38,6 +46,9 @@ type EncryptParams struct {
 	KeyHash     string
 	KeySize     string
 	PBKDF       string
+	// Integrity formats the device with the dm-integrity protection of LUKS2, at the cost of the write throughput
+	// and of wiping the whole device when it is formatted.
+	Integrity bool
 }
 
 func NewEncryptParams(keyProvider, keyCipher, keyHash, keySize, pbkdf string) *EncryptParams {
@@ -100,15 +111,22 @@ func EncryptVolume(devicePath, passphrase string, cryptoParams *EncryptParams) e
 	if err != nil {
 		return err
 	}
-	logrus.Infof("Encrypting device %s with LUKS", devicePath)
-	if _, err := nsexec.LuksFormat(
-		devicePath, passphrase,
-		cryptoParams.GetKeyCipher(),
-		cryptoParams.GetKeyHash(),
-		cryptoParams.GetKeySize(),
-		cryptoParams.GetPBKDF(),
-		lhtypes.LuksTimeout); err != nil {
-		return errors.Wrapf(err, "failed to encrypt device %s with LUKS", devicePath)
+	if cryptoParams.Integrity {
+		logrus.Infof("Encrypting device %s with LUKS and integrity %v", devicePath, CryptoDefaultIntegrity)
+		if _, err := nsexec.CryptsetupWithPassphrase(passphrase, getLuksFormatWithIntegrityArgs(devicePath, cryptoParams), luksFormatWithIntegrityTimeout); err != nil {
+			return errors.Wrapf(err, "failed to encrypt device %s with LUKS and integrity", devicePath)
+		}
+	} else {
+		logrus.Infof("Encrypting device %s with LUKS", devicePath)
+		if _, err := nsexec.LuksFormat(
+			devicePath, passphrase,
+			cryptoParams.GetKeyCipher(),
+			cryptoParams.GetKeyHash(),
+			cryptoParams.GetKeySize(),
+			cryptoParams.GetPBKDF(),
+			lhtypes.LuksTimeout); err != nil {
+			return errors.Wrapf(err, "failed to encrypt device %s with LUKS", devicePath)
+		}
 	}
 
 	isEncrypted, err = isDeviceEncrypted(devicePath)
@@ -125,6 +143,20 @@ func EncryptVolume(devicePath, passphrase string, cryptoParams *EncryptParams) e
 	return nil
 }
 
+// getLuksFormatWithIntegrityArgs returns the arguments of formatting the device with LUKS2 and dm-integrity.
+func getLuksFormatWithIntegrityArgs(devicePath string, cryptoParams *EncryptParams) []string {
+	return []string{
+		"-q", "luksFormat",
+		"--type", "luks2",
+		"--cipher", cryptoParams.GetKeyCipher(),
+		"--hash", cryptoParams.GetKeyHash(),
+		"--key-size", cryptoParams.GetKeySize(),
+		"--pbkdf", cryptoParams.GetPBKDF(),
+		"--integrity", CryptoDefaultIntegrity,
+		devicePath, "-d", "-",
+	}
+}
+
 // OpenVolume opens volume so that it can be used by the client.
 // devicePath is the path of the volume on the host that will be opened for instance '/dev/longhorn/volume1'
 func OpenVolume(volume, dataEngine, devicePath, passphrase string) error {
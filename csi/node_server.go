@@ -465,7 +465,7 @@ func (ns *NodeServer) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 		return nil, status.Errorf(codes.Aborted, "volume %s is not ready for workloads", volumeID)
 	}
 
-	if requiresSharedAccess(volume, volumeCapability) && !volume.Migratable {
+	if requiresSharedMount(volume, volumeCapability) {
 		if volume.AccessMode != string(longhorn.AccessModeReadWriteMany) {
 			return nil, status.Errorf(codes.FailedPrecondition, "volume %s requires shared access but is not marked for shared use", volumeID)
 		}
@@ -515,6 +515,8 @@ func (ns *NodeServer) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 		}
 
 		cryptoParams := crypto.NewEncryptParams(keyProvider, secrets[types.CryptoKeyCipher], secrets[types.CryptoKeyHash], secrets[types.CryptoKeySize], secrets[types.CryptoPBKDF])
+		// The parameter is validated on the volume creation.
+		cryptoParams.Integrity, _ = strconv.ParseBool(req.VolumeContext["encryptionIntegrity"])
 
 		// initial setup of longhorn device for crypto
 		if diskFormat == "" {
@@ -556,16 +558,8 @@ func (ns *NodeServer) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 		return &csi.NodeStageVolumeResponse{}, nil
 	}
 
-	options := volumeCapability.GetMount().GetMountFlags()
-	fsType := volumeCapability.GetMount().GetFsType()
-	if fsType == "" {
-		fsType = defaultFsType
-	}
-	if fsType == "xfs" {
-		// By default, xfs does not allow mounting of two volumes with the same filesystem uuid.
-		// Force ignore this uuid to be able to mount volume + its clone / restored snapshot on the same node.
-		options = append(options, "nouuid")
-	}
+	fsType := getVolumeFsType(volumeCapability, req.GetVolumeContext())
+	options := getNodeStageMountOptions(volumeCapability.GetMount().GetMountFlags(), fsType, req.GetVolumeContext())
 
 	formatMounter, ok := mounter.(*mount.SafeFormatAndMount)
 	if !ok {
@@ -810,7 +804,7 @@ func (ns *NodeServer) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandV
 		return nil, status.Errorf(codes.FailedPrecondition, "invalid state %v for volume %v node expansion", volume.State, volumeID)
 	}
 
-	if requiresSharedAccess(volume, volumeCapability) && !volume.Migratable {
+	if requiresSharedMount(volume, volumeCapability) {
 		if volume.AccessMode != string(longhorn.AccessModeReadWriteMany) {
 			return nil, status.Errorf(codes.FailedPrecondition, "volume %s requires shared access but is not marked for shared use", volumeID)
 		}
@@ -946,10 +940,7 @@ func getNodeServiceCapabilities(cs []csi.NodeServiceCapability_RPC_Type) []*csi.
 }
 
 func (ns *NodeServer) requireHostNamespaceMounter(volume *longhornclient.Volume, volumeCapability *csi.VolumeCapability, volumeContext map[string]string) (bool, error) {
-	if !requiresSharedAccess(volume, volumeCapability) {
-		return false, nil
-	}
-	if volume.Migratable {
+	if !requiresSharedMount(volume, volumeCapability) {
 		return false, nil
 	}
 
@@ -978,10 +969,7 @@ func (ns *NodeServer) getMounter(volume *longhornclient.Volume, volumeCapability
 
 	// mounter that can format and use hard coded filesystem params
 	if volumeCapability.GetMount() != nil {
-		fsType := volumeCapability.GetMount().GetFsType()
-		if fsType == "" {
-			fsType = defaultFsType
-		}
+		fsType := getVolumeFsType(volumeCapability, volumeContext)
 
 		// To allow users to override the default block size,
 		// put the default block size in front of other user-defined parameters.
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
@@ -16,11 +17,18 @@ import (
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
+	"google.golang.org/grpc/codes"
 
 	"k8s.io/mount-utils"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
+
 	utilexec "k8s.io/utils/exec"
 
+	"github.com/longhorn/backupstore"
+
 	"github.com/longhorn/longhorn-manager/types"
 	"github.com/longhorn/longhorn-manager/util"
 
@@ -33,6 +41,9 @@ const (
 	defaultStaleReplicaTimeout                         = 2880
 	defaultStorageClassDisableRevisionCounterParameter = true
 
+	// maxStaleReplicaTimeout set to 30 days (43200 minutes), past which a failed replica is practically never cleaned up
+	maxStaleReplicaTimeout = 43200
+
 	defaultForceUmountTimeout = 30 * time.Second
 
 	tempTestMountPointValidStatusFile = ".longhorn-volume-mount-point-test.tmp"
@@ -40,6 +51,17 @@ const (
 	nodeTopologyKey = "kubernetes.io/hostname"
 )
 
+var (
+	// ErrInvalidVolumeOptions matches every error of getVolumeOptions, which are all caused by the volume parameters.
+	ErrInvalidVolumeOptions = errors.New("invalid volume options")
+	// ErrConflictingAccessOptions is returned when the volume is requested both shared and exclusive.
+	ErrConflictingAccessOptions = errors.New("conflicting access options")
+	// ErrInvalidReplicaCount is returned when the number of replicas is invalid, or conflicts with the data locality.
+	ErrInvalidReplicaCount = errors.New("invalid replica count")
+	// ErrIncompatibleAccessMode is returned when the access mode of a volume capability cannot be served by the volume.
+	ErrIncompatibleAccessMode = errors.New("incompatible access mode")
+)
+
 // NewForcedParamsExec creates a osExecutor that allows for adding additional params to later occurring Run calls
 func NewForcedParamsExec(cmdParamMapping map[string]string) utilexec.Interface {
 	return &forcedParamsOsExec{
@@ -96,19 +118,77 @@ func updateVolumeParamsForBackingImage(volumeParameters map[string]string, backi
 	volumeParameters[longhorn.BackingImageParameterDataSourceParameters] = string(backingImageParametersStr)
 }
 
-func getVolumeOptions(volumeID string, volOptions map[string]string) (*longhornclient.Volume, error) {
-	vol := &longhornclient.Volume{}
+// parseDefaultRevisionCounterDisabled parses the value of the disable revision counter setting, either a single value
+// for all data engines or a JSON-formatted value per data engine.
+func parseDefaultRevisionCounterDisabled(value string) (map[longhorn.DataEngineType]bool, error) {
+	definition, _ := types.GetSettingDefinition(types.SettingNameDisableRevisionCounter)
+
+	var values map[longhorn.DataEngineType]any
+	var err error
+	if types.IsJSONFormat(value) {
+		values, err = types.ParseDataEngineSpecificSetting(definition, value)
+	} else {
+		values, err = types.ParseSettingSingleValue(definition, value)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse setting %v", types.SettingNameDisableRevisionCounter)
+	}
+
+	defaults := map[longhorn.DataEngineType]bool{}
+	for dataEngine, value := range values {
+		if disabled, ok := value.(bool); ok {
+			defaults[dataEngine] = disabled
+		}
+	}
+	return defaults, nil
+}
+
+// isDataEngineFallbackToV1Requested returns whether the volume options ask to fall back to the v1 data engine when
+// the requested v2 data engine cannot be satisfied.
+func isDataEngineFallbackToV1Requested(volOptions map[string]string) (bool, error) {
+	value, ok := volOptions["dataEngineFallbackToV1"]
+	if !ok {
+		return false, nil
+	}
+	fallback, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Wrap(err, "invalid parameter dataEngineFallbackToV1")
+	}
+	if fallback && !types.IsDataEngineV2(longhorn.DataEngineType(volOptions["dataEngine"])) {
+		return false, fmt.Errorf("invalid parameter dataEngineFallbackToV1: it only applies to data engine %v", longhorn.DataEngineTypeV2)
+	}
+	return fallback, nil
+}
+
+// getVolumeOptions builds the volume to create from the StorageClass parameters. defaultRevisionCounterDisabled is the
+// per data engine default of the revision counter, used when the disableRevisionCounter parameter is not set.
+// settingStaleReplicaTimeout is the default stale replica timeout setting, used when the staleReplicaTimeout parameter
+// is not set, or 0 without the setting to use defaultStaleReplicaTimeout.
+// The returned error matches ErrInvalidVolumeOptions, and the more specific sentinels where they apply.
+func getVolumeOptions(volumeID string, volOptions map[string]string, defaultRevisionCounterDisabled map[longhorn.DataEngineType]bool, settingStaleReplicaTimeout int64) (_ *longhornclient.Volume, err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Mark(err, ErrInvalidVolumeOptions)
+		}
+	}()
+
+	vol := &longhornclient.Volume{
+		StaleReplicaTimeout: defaultStaleReplicaTimeout,
+	}
+	if settingStaleReplicaTimeout > 0 {
+		vol.StaleReplicaTimeout = settingStaleReplicaTimeout
+	}
 
 	if staleReplicaTimeout, ok := volOptions["staleReplicaTimeout"]; ok {
 		srt, err := strconv.Atoi(staleReplicaTimeout)
 		if err != nil {
 			return nil, errors.Wrap(err, "invalid parameter staleReplicaTimeout")
 		}
+		if srt <= 0 || srt > maxStaleReplicaTimeout {
+			return nil, fmt.Errorf("invalid parameter staleReplicaTimeout %v, it must be between 1 and %v minutes", srt, maxStaleReplicaTimeout)
+		}
 		vol.StaleReplicaTimeout = int64(srt)
 	}
-	if vol.StaleReplicaTimeout <= 0 {
-		vol.StaleReplicaTimeout = defaultStaleReplicaTimeout
-	}
 
 	if share, ok := volOptions["share"]; ok {
 		isShared, err := strconv.ParseBool(share)
@@ -127,7 +207,7 @@ func getVolumeOptions(volumeID string, volOptions map[string]string) (*longhornc
 			return nil, errors.Wrap(err, "invalid parameter exclusive")
 		}
 		if isExclusive && vol.AccessMode == string(longhorn.AccessModeReadWriteMany) {
-			return nil, errors.New("cannot set both share and exclusive to true")
+			return nil, errors.Wrap(ErrConflictingAccessOptions, "cannot set both share and exclusive to true")
 		}
 		if isExclusive {
 			vol.AccessMode = string(longhorn.AccessModeReadWriteOncePod)
@@ -138,6 +218,17 @@ func getVolumeOptions(volumeID string, volOptions map[string]string) (*longhornc
 		vol.AccessMode = string(longhorn.AccessModeReadWriteOnce)
 	}
 
+	// shareManagerNodeSelector is applied to the share manager pod of the RWX volume by the share manager controller,
+	// which reads it from the StorageClass, so it is only validated here.
+	if shareManagerNodeSelector, ok := volOptions["shareManagerNodeSelector"]; ok {
+		if vol.AccessMode != string(longhorn.AccessModeReadWriteMany) {
+			return nil, fmt.Errorf("invalid parameter shareManagerNodeSelector, it is only supported with share=true")
+		}
+		if _, err := types.UnmarshalNodeSelector(shareManagerNodeSelector); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter shareManagerNodeSelector")
+		}
+	}
+
 	if migratable, ok := volOptions["migratable"]; ok {
 		isMigratable, err := strconv.ParseBool(migratable)
 		if err != nil {
@@ -153,6 +244,28 @@ func getVolumeOptions(volumeID string, volOptions map[string]string) (*longhornc
 		vol.Migratable = isMigratable
 	}
 
+	// migrationTargetNode is preferred as the migration target when several nodes request the migratable volume.
+	if migrationTargetNode, ok := volOptions["migrationTargetNode"]; ok {
+		if migrationTargetNode == "" {
+			return nil, fmt.Errorf("invalid parameter migrationTargetNode, it must not be empty")
+		}
+		if !vol.Migratable {
+			return nil, fmt.Errorf("invalid parameter migrationTargetNode, it is only supported with migratable=true")
+		}
+		vol.MigrationTargetNodeID = migrationTargetNode
+	}
+
+	if autoDowngradeFromRWX, ok := volOptions["autoDowngradeFromRWX"]; ok {
+		isAutoDowngradeFromRWX, err := strconv.ParseBool(autoDowngradeFromRWX)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter autoDowngradeFromRWX")
+		}
+		if err := types.ValidateAutoDowngradeFromRWX(isAutoDowngradeFromRWX, vol.Migratable, longhorn.AccessMode(vol.AccessMode)); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter autoDowngradeFromRWX")
+		}
+		vol.AutoDowngradeFromRWX = isAutoDowngradeFromRWX
+	}
+
 	if encrypted, ok := volOptions["encrypted"]; ok {
 		isEncrypted, err := strconv.ParseBool(encrypted)
 		if err != nil {
@@ -161,10 +274,38 @@ func getVolumeOptions(volumeID string, volOptions map[string]string) (*longhornc
 		vol.Encrypted = isEncrypted
 	}
 
+	// encryptionIntegrity is kept in the volume context, so the node plugin formats the encrypted volume with the
+	// dm-integrity protection of LUKS2, detecting the tampering of the encrypted data. The integrity tags and the
+	// journal of dm-integrity cost about half of the write throughput, some capacity, and the initial wipe of the
+	// device when it is formatted.
+	if encryptionIntegrity, ok := volOptions["encryptionIntegrity"]; ok {
+		isEncryptionIntegrity, err := strconv.ParseBool(encryptionIntegrity)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter encryptionIntegrity")
+		}
+		if isEncryptionIntegrity && !vol.Encrypted {
+			return nil, fmt.Errorf("invalid parameter encryptionIntegrity, it requires encrypted volumes")
+		}
+		if isEncryptionIntegrity && vol.AccessMode == string(longhorn.AccessModeReadWriteMany) && !vol.Migratable {
+			return nil, fmt.Errorf("invalid parameter encryptionIntegrity, it is not supported by shared volumes")
+		}
+	}
+
+	// fsType is kept in the volume context, so the node plugin formats the volume with it
+	// when the volume capability does not specify a filesystem.
+	if fsType, ok := volOptions["fsType"]; ok {
+		if _, supported := supportedFs[fsType]; !supported {
+			return nil, fmt.Errorf("invalid parameter fsType %v, supported filesystems are ext4 and xfs", fsType)
+		}
+	}
+
 	if numberOfReplicas, ok := volOptions["numberOfReplicas"]; ok {
 		nor, err := strconv.Atoi(numberOfReplicas)
-		if err != nil || nor < 0 {
-			return nil, errors.Wrap(err, "invalid parameter numberOfReplicas")
+		if err != nil {
+			return nil, errors.Mark(errors.Wrap(err, "invalid parameter numberOfReplicas"), ErrInvalidReplicaCount)
+		}
+		if nor < 0 {
+			return nil, errors.Wrapf(ErrInvalidReplicaCount, "invalid parameter numberOfReplicas %v, it must not be negative", nor)
 		}
 		vol.NumberOfReplicas = int64(nor)
 	}
@@ -193,28 +334,47 @@ func getVolumeOptions(volumeID string, volOptions map[string]string) (*longhornc
 		vol.ReplicaAutoBalance = replicaAutoBalance
 	}
 
+	// replicaAutoBalanceDiskPressurePercentage only takes effect with the best-effort auto-balance, so it is rejected
+	// when the volume explicitly uses another one.
+	if percentage, ok := volOptions["replicaAutoBalanceDiskPressurePercentage"]; ok {
+		p, err := strconv.ParseInt(percentage, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter replicaAutoBalanceDiskPressurePercentage")
+		}
+		if err := types.ValidateReplicaAutoBalanceDiskPressurePercentage(p); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter replicaAutoBalanceDiskPressurePercentage")
+		}
+		switch longhorn.ReplicaAutoBalance(vol.ReplicaAutoBalance) {
+		case "", longhorn.ReplicaAutoBalanceIgnored, longhorn.ReplicaAutoBalanceBestEffort:
+		default:
+			return nil, fmt.Errorf("invalid parameter replicaAutoBalanceDiskPressurePercentage, it requires replicaAutoBalance %v", longhorn.ReplicaAutoBalanceBestEffort)
+		}
+		vol.ReplicaAutoBalanceDiskPressurePercentage = p
+	}
+
 	if locality, ok := volOptions["dataLocality"]; ok {
 		if err := types.ValidateDataLocality(longhorn.DataLocality(locality)); err != nil {
 			return nil, errors.Wrap(err, "invalid parameter dataLocality")
 		}
-		vol.DataLocality = locality
-	}
-
-	if revisionCounterDisabled, ok := volOptions["disableRevisionCounter"]; ok {
-		revCounterDisabled, err := strconv.ParseBool(revisionCounterDisabled)
-		if err != nil {
-			return nil, errors.Wrap(err, "invalid parameter disableRevisionCounter")
+		// Without numberOfReplicas, the volume uses the default replica count validated on the volume creation.
+		if vol.NumberOfReplicas > 0 {
+			if err := types.ValidateDataLocalityAndReplicaCount(longhorn.DataLocality(locality), int(vol.NumberOfReplicas)); err != nil {
+				return nil, errors.Mark(errors.Wrap(err, "invalid parameter dataLocality"), ErrInvalidReplicaCount)
+			}
 		}
-		vol.RevisionCounterDisabled = revCounterDisabled
-	} else {
-		vol.RevisionCounterDisabled = defaultStorageClassDisableRevisionCounterParameter
+		if err := types.ValidateDataLocalityAndAccessMode(longhorn.DataLocality(locality), vol.Migratable, longhorn.AccessMode(vol.AccessMode)); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter dataLocality")
+		}
+		vol.DataLocality = locality
 	}
 
-	if unmapMarkSnapChainRemoved, ok := volOptions["unmapMarkSnapChainRemoved"]; ok {
-		if err := types.ValidateUnmapMarkSnapChainRemoved(longhorn.DataEngineType(vol.DataEngine), longhorn.UnmapMarkSnapChainRemoved(unmapMarkSnapChainRemoved)); err != nil {
-			return nil, errors.Wrap(err, "invalid parameter unmapMarkSnapChainRemoved")
+	// pinnedNode attaches the volume to the node right after the creation. The replica scheduler treats the
+	// pinned node as the local node, so the best-effort and strict-local data locality keep a replica there.
+	if pinnedNode, ok := volOptions["pinnedNode"]; ok {
+		if pinnedNode == "" {
+			return nil, fmt.Errorf("invalid parameter pinnedNode, it must not be empty")
 		}
-		vol.UnmapMarkSnapChainRemoved = unmapMarkSnapChainRemoved
+		vol.NodeID = pinnedNode
 	}
 
 	if replicaSoftAntiAffinity, ok := volOptions["replicaSoftAntiAffinity"]; ok {
@@ -231,6 +391,21 @@ func getVolumeOptions(volumeID string, volOptions map[string]string) (*longhornc
 		vol.ReplicaZoneSoftAntiAffinity = replicaZoneSoftAntiAffinity
 	}
 
+	// replicaZoneHardAntiAffinity disables the zone soft anti-affinity of the volume, so the replicas are never
+	// scheduled in the same zone. The volume stays degraded while there are fewer zones than replicas.
+	if replicaZoneHardAntiAffinity, ok := volOptions["replicaZoneHardAntiAffinity"]; ok {
+		hardAntiAffinity, err := strconv.ParseBool(replicaZoneHardAntiAffinity)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter replicaZoneHardAntiAffinity")
+		}
+		if hardAntiAffinity {
+			if vol.ReplicaZoneSoftAntiAffinity == string(longhorn.ReplicaZoneSoftAntiAffinityEnabled) {
+				return nil, fmt.Errorf("invalid parameter replicaZoneHardAntiAffinity: it conflicts with replicaZoneSoftAntiAffinity %v", vol.ReplicaZoneSoftAntiAffinity)
+			}
+			vol.ReplicaZoneSoftAntiAffinity = string(longhorn.ReplicaZoneSoftAntiAffinityDisabled)
+		}
+	}
+
 	if replicaDiskSoftAntiAffinity, ok := volOptions["replicaDiskSoftAntiAffinity"]; ok {
 		if err := types.ValidateReplicaDiskSoftAntiAffinity(longhorn.ReplicaDiskSoftAntiAffinity(replicaDiskSoftAntiAffinity)); err != nil {
 			return nil, errors.Wrap(err, "invalid parameter replicaDiskSoftAntiAffinity")
@@ -238,20 +413,72 @@ func getVolumeOptions(volumeID string, volOptions map[string]string) (*longhornc
 		vol.ReplicaDiskSoftAntiAffinity = replicaDiskSoftAntiAffinity
 	}
 
+	// fromBackup restores the volume from the backup URL, with the replica count of the volume rather than the one of
+	// the backed up volume.
 	if fromBackup, ok := volOptions["fromBackup"]; ok {
+		if err := validateBackupURL(fromBackup); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter fromBackup")
+		}
 		vol.FromBackup = fromBackup
 	}
 
-	if backupTargetName, ok := volOptions["backupTargetName"]; ok {
-		vol.BackupTargetName = backupTargetName
+	// replicaRebuildingEnabled overrides the replica rebuilding setting for the volume, which uses the setting if omitted.
+	if replicaRebuildingEnabled, ok := volOptions["replicaRebuildingEnabled"]; ok {
+		enabled, err := strconv.ParseBool(replicaRebuildingEnabled)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter replicaRebuildingEnabled")
+		}
+		vol.ReplicaRebuilding = string(longhorn.VolumeReplicaRebuildingDisabled)
+		if enabled {
+			vol.ReplicaRebuilding = string(longhorn.VolumeReplicaRebuildingEnabled)
+		}
 	}
 
-	if backupBlockSize, ok := volOptions["backupBlockSize"]; ok {
-		blockSize, err := util.ConvertSize(backupBlockSize)
+	// size is the size of the volume when the request does not ask for a capacity, like "10Gi".
+	var size int64
+	if sizeOption, ok := volOptions["size"]; ok {
+		size, err = parseVolumeSize(sizeOption)
 		if err != nil {
-			return nil, errors.Wrap(err, "invalid parameter backupBlockSize")
+			return nil, errors.Wrap(err, "invalid parameter size")
 		}
-		vol.BackupBlockSize = strconv.FormatInt(blockSize, 10)
+		vol.Size = strconv.FormatInt(size, 10)
+	}
+
+	if maxSize, ok := volOptions["maxSize"]; ok {
+		maxSizeBytes, err := util.ConvertSize(maxSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter maxSize")
+		}
+		if err := types.ValidateMaxSize(size, maxSizeBytes); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter maxSize")
+		}
+		vol.MaxSize = strconv.FormatInt(maxSizeBytes, 10)
+	}
+
+	if standbyRestoreInterval, ok := volOptions["standbyRestoreInterval"]; ok {
+		interval, err := strconv.Atoi(standbyRestoreInterval)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter standbyRestoreInterval")
+		}
+		if err := types.ValidateStandbyRestoreInterval(interval); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter standbyRestoreInterval")
+		}
+		vol.StandbyRestoreInterval = int64(interval)
+	}
+
+	if backupTargetName, ok := volOptions["backupTargetName"]; ok {
+		_, name, err := parseBackupTargetName(backupTargetName)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter backupTargetName")
+		}
+		vol.BackupTargetName = name
+	}
+
+	if snapshotReclaimThreshold, ok := volOptions["snapshotReclaimThreshold"]; ok {
+		if err := types.ValidateSnapshotReclaimThreshold(0, snapshotReclaimThreshold); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter snapshotReclaimThreshold")
+		}
+		vol.SnapshotReclaimThreshold = snapshotReclaimThreshold
 	}
 
 	if dataSource, ok := volOptions["dataSource"]; ok {
@@ -259,10 +486,88 @@ func getVolumeOptions(volumeID string, volOptions map[string]string) (*longhornc
 		vol.CloneMode = volOptions["cloneMode"]
 	}
 
+	// fromVolume clones the volume from the source volume, like a CSI volume content source of the volume.
+	if fromVolume, ok := volOptions["fromVolume"]; ok {
+		if vol.DataSource != "" {
+			return nil, fmt.Errorf("invalid parameter fromVolume: it conflicts with the volume content source %v", vol.DataSource)
+		}
+		if vol.FromBackup != "" {
+			return nil, fmt.Errorf("invalid parameter fromVolume: it conflicts with fromBackup %v", vol.FromBackup)
+		}
+		dataSource, err := types.NewVolumeDataSource(longhorn.VolumeDataSourceTypeVolume, map[string]string{types.VolumeNameKey: fromVolume})
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter fromVolume")
+		}
+		vol.DataSource = string(dataSource)
+		vol.CloneMode = volOptions["cloneMode"]
+	}
+
 	if backingImage, ok := volOptions[longhorn.BackingImageParameterName]; ok {
 		vol.BackingImage = backingImage
 	}
 
+	// backingImageReadyTimeout is enforced by ControllerPublishVolume, which gets it from the volume context.
+	if _, ok := volOptions["backingImageReadyTimeout"]; ok {
+		if vol.BackingImage == "" {
+			return nil, fmt.Errorf("invalid parameter backingImageReadyTimeout, it is only supported with backingImage")
+		}
+		if _, err := getBackingImageReadyTimeout(volOptions); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter backingImageReadyTimeout")
+		}
+	}
+
+	// snapshotDataIntegrity overrides the snapshot-data-integrity setting for the volume, unless it is ignored.
+	if snapshotDataIntegrity, ok := volOptions["snapshotDataIntegrity"]; ok {
+		if snapshotDataIntegrity != string(longhorn.SnapshotDataIntegrityIgnored) {
+			if err := types.ValidateSnapshotDataIntegrity(snapshotDataIntegrity); err != nil {
+				return nil, errors.Wrap(err, "invalid parameter snapshotDataIntegrity")
+			}
+		}
+		vol.SnapshotDataIntegrity = snapshotDataIntegrity
+	}
+
+	// snapshotDataIntegrityCronJob overrides the snapshot-data-integrity-cronjob setting for the volume.
+	if snapshotDataIntegrityCronJob, ok := volOptions["snapshotDataIntegrityCronJob"]; ok {
+		if err := types.ValidateSnapshotDataIntegrityCronJob(snapshotDataIntegrityCronJob); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter snapshotDataIntegrityCronJob")
+		}
+		vol.SnapshotDataIntegrityCronJob = snapshotDataIntegrityCronJob
+	}
+
+	if backingImageCleanupPolicy, ok := volOptions["backingImageCleanupPolicy"]; ok {
+		if err := types.ValidateBackingImageCleanupPolicy(longhorn.BackingImageCleanupPolicy(backingImageCleanupPolicy)); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter backingImageCleanupPolicy")
+		}
+		vol.BackingImageCleanupPolicy = backingImageCleanupPolicy
+	}
+
+	if snapshotMaxSizeAction, ok := volOptions["snapshotMaxSizeAction"]; ok {
+		if err := types.ValidateSnapshotMaxSizeAction(longhorn.SnapshotMaxSizeAction(snapshotMaxSizeAction)); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter snapshotMaxSizeAction")
+		}
+		vol.SnapshotMaxSizeAction = snapshotMaxSizeAction
+	}
+
+	// recurringSnapshotOverflowAction is applied by the recurring snapshot jobs of the volume once its snapshot count
+	// reaches SnapshotMaxCount, which leave the new snapshot to fail if omitted.
+	if recurringSnapshotOverflowAction, ok := volOptions["recurringSnapshotOverflowAction"]; ok {
+		action := longhorn.RecurringSnapshotOverflowAction(recurringSnapshotOverflowAction)
+		if action == "" {
+			return nil, fmt.Errorf("invalid parameter recurringSnapshotOverflowAction, it must not be empty")
+		}
+		if err := types.ValidateRecurringSnapshotOverflowAction(action); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter recurringSnapshotOverflowAction")
+		}
+		vol.RecurringSnapshotOverflowAction = recurringSnapshotOverflowAction
+	}
+
+	if dataEngineLogLevel, ok := volOptions["dataEngineLogLevel"]; ok {
+		if err := types.ValidateDataEngineLogLevel(dataEngineLogLevel); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter dataEngineLogLevel")
+		}
+		vol.DataEngineLogLevel = dataEngineLogLevel
+	}
+
 	recurringJobSelector := []longhornclient.VolumeRecurringJob{}
 	if jsonRecurringJobSelector, ok := volOptions["recurringJobSelector"]; ok {
 		err := json.Unmarshal([]byte(jsonRecurringJobSelector), &recurringJobSelector)
@@ -285,6 +590,75 @@ func getVolumeOptions(volumeID string, volOptions map[string]string) (*longhornc
 		vol.DataEngine = driver
 	}
 
+	// dataEngineFallbackToV1 is not a volume option, the controller server creates the volume with the v1 data engine
+	// instead when the v2 data engine cannot be satisfied.
+	if _, err := isDataEngineFallbackToV1Requested(volOptions); err != nil {
+		return nil, err
+	}
+
+	// unmapMarkSnapChainRemoved is validated against the data engine, since the v2 data engine only supports
+	// the disabled value.
+	if unmapMarkSnapChainRemoved, ok := volOptions["unmapMarkSnapChainRemoved"]; ok {
+		if err := types.ValidateUnmapMarkSnapChainRemoved(longhorn.DataEngineType(vol.DataEngine), longhorn.UnmapMarkSnapChainRemoved(unmapMarkSnapChainRemoved)); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter unmapMarkSnapChainRemoved")
+		}
+		vol.UnmapMarkSnapChainRemoved = unmapMarkSnapChainRemoved
+	}
+
+	if revisionCounterDisabled, ok := volOptions["disableRevisionCounter"]; ok {
+		revCounterDisabled, err := strconv.ParseBool(revisionCounterDisabled)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter disableRevisionCounter")
+		}
+		vol.RevisionCounterDisabled = revCounterDisabled
+	} else if revCounterDisabled, ok := defaultRevisionCounterDisabled[longhorn.DataEngineType(vol.DataEngine)]; ok {
+		vol.RevisionCounterDisabled = revCounterDisabled
+	} else {
+		vol.RevisionCounterDisabled = defaultStorageClassDisableRevisionCounterParameter
+	}
+
+	if discardEnabled, ok := volOptions["discardEnabled"]; ok {
+		enabled, err := strconv.ParseBool(discardEnabled)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter discardEnabled")
+		}
+		if enabled && !types.IsDataEngineV2(longhorn.DataEngineType(vol.DataEngine)) {
+			return nil, fmt.Errorf("invalid parameter discardEnabled: discard is only supported by data engine %v", longhorn.DataEngineTypeV2)
+		}
+	}
+
+	// disableNodeDownPodDeletion is not a volume option, it is kept in the PV volume attributes for the pod controller.
+	if disableNodeDownPodDeletion, ok := volOptions[types.PVVolumeAttributeDisableNodeDownPodDeletion]; ok {
+		if _, err := strconv.ParseBool(disableNodeDownPodDeletion); err != nil {
+			return nil, errors.Wrapf(err, "invalid parameter %v", types.PVVolumeAttributeDisableNodeDownPodDeletion)
+		}
+	}
+
+	if backupBlockSize, ok := volOptions["backupBlockSize"]; ok {
+		blockSize, err := util.ConvertSize(backupBlockSize)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter backupBlockSize")
+		}
+		if err := types.ValidateBackupBlockSizeForDataEngine(longhorn.DataEngineType(vol.DataEngine), blockSize); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter backupBlockSize")
+		}
+		vol.BackupBlockSize = strconv.FormatInt(blockSize, 10)
+	}
+
+	if instanceManagerImage, ok := volOptions["instanceManagerImage"]; ok {
+		if err := types.ValidateInstanceManagerImage(longhorn.DataEngineType(vol.DataEngine), instanceManagerImage); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter instanceManagerImage")
+		}
+		vol.InstanceManagerImage = instanceManagerImage
+	}
+
+	if engineImagePullSecret, ok := volOptions["engineImagePullSecret"]; ok {
+		if err := types.ValidateEngineImagePullSecret(engineImagePullSecret); err != nil {
+			return nil, errors.Wrap(err, "invalid parameter engineImagePullSecret")
+		}
+		vol.EngineImagePullSecret = engineImagePullSecret
+	}
+
 	if freezeFilesystemForSnapshot, ok := volOptions["freezeFilesystemForSnapshot"]; ok {
 		if err := types.ValidateFreezeFilesystemForSnapshot(longhorn.FreezeFilesystemForSnapshot(freezeFilesystemForSnapshot)); err != nil {
 			return nil, errors.Wrap(err, "invalid parameter freezeFilesystemForSnapshot")
@@ -292,11 +666,105 @@ func getVolumeOptions(volumeID string, volOptions map[string]string) (*longhornc
 		vol.FreezeFilesystemForSnapshot = freezeFilesystemForSnapshot
 	}
 
+	// An application quiescing itself before the snapshots by its own hook does not need the filesystem frozen.
+	if appManagedQuiesce, ok := volOptions["appManagedQuiesce"]; ok {
+		isAppManagedQuiesce, err := strconv.ParseBool(appManagedQuiesce)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid parameter appManagedQuiesce")
+		}
+		if isAppManagedQuiesce {
+			if vol.FreezeFilesystemForSnapshot == string(longhorn.FreezeFilesystemForSnapshotEnabled) {
+				return nil, fmt.Errorf("invalid parameter appManagedQuiesce: cannot be set with freezeFilesystemForSnapshot %v", longhorn.FreezeFilesystemForSnapshotEnabled)
+			}
+			vol.FreezeFilesystemForSnapshot = string(longhorn.FreezeFilesystemForSnapshotAppManaged)
+		}
+	}
+
 	vol.Frontend = volOptions["frontend"]
 
 	return vol, nil
 }
 
+// validateBackupURL checks that the backup URL points to a backup of a backup target, like
+// "s3://bucket@region/path?backup=backup-name&volume=volume-name".
+func validateBackupURL(backupURL string) error {
+	backupName, _, destURL, err := backupstore.DecodeBackupURL(backupURL)
+	if err != nil {
+		return err
+	}
+	if backupName == "" {
+		return fmt.Errorf("backup URL %v has no backup name", backupURL)
+	}
+	u, err := url.Parse(destURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "" {
+		return fmt.Errorf("backup URL %v has no backup target scheme", backupURL)
+	}
+	return nil
+}
+
+// getVolumeOptionsErrorCode translates the error of getVolumeOptions to the gRPC code of the CreateVolume response.
+func getVolumeOptionsErrorCode(err error) codes.Code {
+	if errors.Is(err, ErrInvalidVolumeOptions) {
+		return codes.InvalidArgument
+	}
+	return codes.Internal
+}
+
+// getBackingImageReadyTimeout returns how long the attachment of a volume waits for its backing image to be ready,
+// from the backingImageReadyTimeout parameter in seconds of the volume context. 0 means no timeout.
+func getBackingImageReadyTimeout(volumeContext map[string]string) (time.Duration, error) {
+	value, ok := volumeContext["backingImageReadyTimeout"]
+	if !ok {
+		return 0, nil
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if seconds <= 0 {
+		return 0, fmt.Errorf("backingImageReadyTimeout %v must be positive", seconds)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// parseVolumeSize parses the size parameter, which is a Kubernetes quantity like "10Gi", into bytes. Zero sizes
+// and sizes that are not a whole number of bytes, like "1.5" or "100m", are rejected.
+func parseVolumeSize(value string) (int64, error) {
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse size %q", value)
+	}
+	if quantity.Sign() <= 0 {
+		return 0, fmt.Errorf("size %q must be positive", value)
+	}
+	if _, ok := quantity.AsInt64(); !ok {
+		return 0, fmt.Errorf("size %q must be a whole number of bytes", value)
+	}
+	return quantity.Value(), nil
+}
+
+// parseBackupTargetName parses the backupTargetName parameter, which is either the name of a backup target or
+// the name qualified by the namespace of the backup target, like longhorn-system/default.
+func parseBackupTargetName(value string) (namespace, name string, err error) {
+	if value == "" {
+		return "", "", nil
+	}
+
+	namespace, name, qualified := strings.Cut(value, "/")
+	if !qualified {
+		namespace, name = "", value
+	} else if errs := utilvalidation.IsDNS1123Label(namespace); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid namespace %q: %v", namespace, strings.Join(errs, ", "))
+	}
+	if errs := utilvalidation.IsDNS1123Label(name); len(errs) > 0 {
+		return "", "", fmt.Errorf("invalid backup target name %q: %v", name, strings.Join(errs, ", "))
+	}
+	return namespace, name, nil
+}
+
 func syncMountPointDirectory(targetPath string) error {
 	d, err := os.OpenFile(targetPath, os.O_SYNC, 0750)
 	if err != nil {
@@ -506,6 +974,40 @@ func requiresSharedAccess(vol *longhornclient.Volume, cap *csi.VolumeCapability)
 		mode == csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER
 }
 
+// requiresSharedMount checks if the volume requiring shared access has to be mounted through its share manager.
+// Migratable volumes are attached to each node instead, and a volume downgraded from rwx by the access mode
+// auto downgrade is attached to the node of its single consumer until it is promoted back.
+func requiresSharedMount(vol *longhornclient.Volume, cap *csi.VolumeCapability) bool {
+	return requiresSharedAccess(vol, cap) && !vol.Migratable && !isDowngradedFromRWX(vol)
+}
+
+// getVolumeFsType returns the filesystem of a mount volume, from the volume capability, or else from the fsType
+// parameter in the volume context, or else the default ext4.
+func getVolumeFsType(volumeCapability *csi.VolumeCapability, volumeContext map[string]string) string {
+	if fsType := volumeCapability.GetMount().GetFsType(); fsType != "" {
+		return fsType
+	}
+	if fsType := volumeContext["fsType"]; fsType != "" {
+		return fsType
+	}
+	return defaultFsType
+}
+
+// getNodeStageMountOptions returns the options to mount the filesystem of the volume with. The discardEnabled
+// parameter of the StorageClass reaches the node plugin in the volume context.
+func getNodeStageMountOptions(mountFlags []string, fsType string, volumeContext map[string]string) []string {
+	options := append([]string{}, mountFlags...)
+	if fsType == "xfs" {
+		// By default, xfs does not allow mounting of two volumes with the same filesystem uuid.
+		// Force ignore this uuid to be able to mount volume + its clone / restored snapshot on the same node.
+		options = append(options, "nouuid")
+	}
+	if enabled, err := strconv.ParseBool(volumeContext["discardEnabled"]); err == nil && enabled {
+		options = append(options, "discard")
+	}
+	return options
+}
+
 func requireExclusiveAccess(vol *longhornclient.Volume, capability *csi.VolumeCapability) bool {
 	isExclusive := false
 	if vol != nil {
@@ -517,7 +1019,48 @@ func requireExclusiveAccess(vol *longhornclient.Volume, capability *csi.VolumeCa
 		mode = capability.AccessMode.Mode
 	}
 
-	return isExclusive || mode == csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER
+	if mode == csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER {
+		return true
+	}
+	// Other modes, like SINGLE_NODE_MULTI_WRITER for a raw block device shared within the node, may have multiple
+	// workloads writing to the volume, so only a rwop volume is exclusive.
+	return isExclusive
+}
+
+// ValidateAccessModeCompatibility checks that the volume can serve the access mode of the capability. The access mode
+// of a detached volume is switched on publish, to rwx for shared access or to rwop for exclusive access, but a rwop
+// volume is never shared, and a migratable volume is never exclusive since it is attached to two nodes during migration.
+func ValidateAccessModeCompatibility(vol *longhornclient.Volume, capability *csi.VolumeCapability) error {
+	if vol == nil {
+		return nil
+	}
+
+	mode := capability.GetAccessMode().GetMode()
+	accessMode := longhorn.AccessMode(vol.AccessMode)
+	isDetached := vol.State == string(longhorn.VolumeStateDetached)
+
+	if requiresSharedAccess(vol, capability) &&
+		accessMode != longhorn.AccessModeReadWriteMany && !vol.Migratable && !isDowngradedFromRWX(vol) {
+		if accessMode == longhorn.AccessModeReadWriteOncePod {
+			return errors.Wrapf(ErrIncompatibleAccessMode, "volume %v with access mode %v cannot be shared for %v", vol.Name, accessMode, mode)
+		}
+		if !isDetached {
+			return errors.Wrapf(ErrIncompatibleAccessMode, "volume %v with access mode %v cannot be switched to %v for %v while it is %v",
+				vol.Name, accessMode, longhorn.AccessModeReadWriteMany, mode, vol.State)
+		}
+	}
+
+	if requireExclusiveAccess(vol, capability) && accessMode != longhorn.AccessModeReadWriteOncePod {
+		if vol.Migratable {
+			return errors.Wrapf(ErrIncompatibleAccessMode, "migratable volume %v cannot be exclusive for %v", vol.Name, mode)
+		}
+		if !isDetached {
+			return errors.Wrapf(ErrIncompatibleAccessMode, "volume %v with access mode %v cannot be switched to %v for %v while it is %v",
+				vol.Name, accessMode, longhorn.AccessModeReadWriteOncePod, mode, vol.State)
+		}
+	}
+
+	return nil
 }
 
 func getStageBlockVolumePath(stagingTargetPath, volumeID string) string {
@@ -1,11 +1,16 @@
 package csi
 
 import (
+	"fmt"
+	"slices"
 	"testing"
+	"time"
 
+	"github.com/cockroachdb/errors"
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
 
 	longhornclient "github.com/longhorn/longhorn-manager/client"
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
@@ -13,10 +18,13 @@ import (
 
 func TestGetVolumeOptions(t *testing.T) {
 	tests := map[string]struct {
-		volumeID       string
-		volumeOptions  map[string]string
-		expectedVolume *longhornclient.Volume
-		expectedError  bool
+		volumeID                       string
+		volumeOptions                  map[string]string
+		defaultRevisionCounterDisabled map[longhorn.DataEngineType]bool
+		settingStaleReplicaTimeout     int64
+		expectedVolume                 *longhornclient.Volume
+		expectedError                  bool
+		expectedErrorIs                error
 	}{
 		"defaults": {
 			volumeID: "test-vol",
@@ -31,6 +39,58 @@ func TestGetVolumeOptions(t *testing.T) {
 				RevisionCounterDisabled: true,
 			},
 		},
+		"staleReplicaTimeout": {
+			volumeID: "test-vol-stale-replica-timeout",
+			volumeOptions: map[string]string{
+				"staleReplicaTimeout": "30",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     30,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"staleReplicaTimeout maximum": {
+			volumeID: "test-vol-stale-replica-timeout-max",
+			volumeOptions: map[string]string{
+				"staleReplicaTimeout": "43200",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     maxStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"staleReplicaTimeout zero": {
+			volumeID: "test-vol-stale-replica-timeout-zero",
+			volumeOptions: map[string]string{
+				"staleReplicaTimeout": "0",
+			},
+			expectedError: true,
+		},
+		"staleReplicaTimeout negative": {
+			volumeID: "test-vol-stale-replica-timeout-negative",
+			volumeOptions: map[string]string{
+				"staleReplicaTimeout": "-1",
+			},
+			expectedError: true,
+		},
+		"staleReplicaTimeout too large": {
+			volumeID: "test-vol-stale-replica-timeout-large",
+			volumeOptions: map[string]string{
+				"staleReplicaTimeout": "43201",
+			},
+			expectedError: true,
+		},
+		"staleReplicaTimeout invalid": {
+			volumeID: "test-vol-stale-replica-timeout-invalid",
+			volumeOptions: map[string]string{
+				"staleReplicaTimeout": "48h",
+			},
+			expectedError: true,
+		},
 		"exclusive access": {
 			volumeID: "test-vol-exclusive",
 			volumeOptions: map[string]string{
@@ -38,15 +98,1470 @@ func TestGetVolumeOptions(t *testing.T) {
 			},
 			expectedVolume: &longhornclient.Volume{
 				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
-				AccessMode:              string(longhorn.AccessModeReadWriteOncePod),
+				AccessMode:              string(longhorn.AccessModeReadWriteOncePod),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"shared access": {
+			volumeID: "test-vol-shared",
+			volumeOptions: map[string]string{
+				"share": "true",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteMany),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"exclusive and shared conflict": {
+			volumeID: "test-vol-conflict",
+			volumeOptions: map[string]string{
+				"exclusive": "true",
+				"share":     "true",
+			},
+			expectedError:   true,
+			expectedErrorIs: ErrConflictingAccessOptions,
+		},
+		"numberOfReplicas negative": {
+			volumeID: "test-vol-negative-replicas",
+			volumeOptions: map[string]string{
+				"numberOfReplicas": "-1",
+			},
+			expectedError:   true,
+			expectedErrorIs: ErrInvalidReplicaCount,
+		},
+		"numberOfReplicas invalid": {
+			volumeID: "test-vol-invalid-replicas",
+			volumeOptions: map[string]string{
+				"numberOfReplicas": "three",
+			},
+			expectedError:   true,
+			expectedErrorIs: ErrInvalidReplicaCount,
+		},
+		"migratable requires RWX": {
+			volumeID: "test-vol-migratable-no-rwx",
+			volumeOptions: map[string]string{
+				"migratable": "true",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				Migratable:              false,
+			},
+		},
+		"migratable with RWX": {
+			volumeID: "test-vol-migratable-rwx",
+			volumeOptions: map[string]string{
+				"share":      "true",
+				"migratable": "true",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteMany),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				Migratable:              true,
+			},
+		},
+		"migrationTargetNode with migratable": {
+			volumeID: "test-vol-migration-target-node",
+			volumeOptions: map[string]string{
+				"share":               "true",
+				"migratable":          "true",
+				"migrationTargetNode": "node-1",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteMany),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				Migratable:              true,
+				MigrationTargetNodeID:   "node-1",
+			},
+		},
+		"migrationTargetNode without migratable": {
+			volumeID: "test-vol-migration-target-node-not-migratable",
+			volumeOptions: map[string]string{
+				"share":               "true",
+				"migrationTargetNode": "node-1",
+			},
+			expectedError: true,
+		},
+		"migrationTargetNode with migratable downgraded to RWO": {
+			volumeID: "test-vol-migration-target-node-rwo",
+			volumeOptions: map[string]string{
+				"migratable":          "true",
+				"migrationTargetNode": "node-1",
+			},
+			expectedError: true,
+		},
+		"migrationTargetNode empty": {
+			volumeID: "test-vol-migration-target-node-empty",
+			volumeOptions: map[string]string{
+				"share":               "true",
+				"migratable":          "true",
+				"migrationTargetNode": "",
+			},
+			expectedError: true,
+		},
+		"dataEngine override to v2": {
+			volumeID: "test-vol-dataengine-v2",
+			volumeOptions: map[string]string{
+				"dataEngine": "v2",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV2),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"dataEngineFallbackToV1 with v2": {
+			volumeID: "test-vol-dataengine-fallback",
+			volumeOptions: map[string]string{
+				"dataEngine":             "v2",
+				"dataEngineFallbackToV1": "true",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV2),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"dataEngineFallbackToV1 false with v1": {
+			volumeID: "test-vol-dataengine-fallback-false",
+			volumeOptions: map[string]string{
+				"dataEngineFallbackToV1": "false",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"dataEngineFallbackToV1 with v1": {
+			volumeID: "test-vol-dataengine-fallback-v1",
+			volumeOptions: map[string]string{
+				"dataEngine":             "v1",
+				"dataEngineFallbackToV1": "true",
+			},
+			expectedError: true,
+		},
+		"dataEngineFallbackToV1 without dataEngine": {
+			volumeID: "test-vol-dataengine-fallback-default",
+			volumeOptions: map[string]string{
+				"dataEngineFallbackToV1": "true",
+			},
+			expectedError: true,
+		},
+		"dataEngineFallbackToV1 invalid": {
+			volumeID: "test-vol-dataengine-fallback-invalid",
+			volumeOptions: map[string]string{
+				"dataEngine":             "v2",
+				"dataEngineFallbackToV1": "maybe",
+			},
+			expectedError: true,
+		},
+		"backingImageCleanupPolicy delete": {
+			volumeID: "test-vol-bi-cleanup",
+			volumeOptions: map[string]string{
+				"backingImage":              "test-bi",
+				"backingImageCleanupPolicy": "delete",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:       defaultStaleReplicaTimeout,
+				AccessMode:                string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:   true,
+				BackingImage:              "test-bi",
+				BackingImageCleanupPolicy: string(longhorn.BackingImageCleanupPolicyDelete),
+			},
+		},
+		"snapshotMaxSizeAction block": {
+			volumeID: "test-vol-max-size-block",
+			volumeOptions: map[string]string{
+				"snapshotMaxSizeAction": "block",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				SnapshotMaxSizeAction:   string(longhorn.SnapshotMaxSizeActionBlock),
+			},
+		},
+		"snapshotMaxSizeAction prune": {
+			volumeID: "test-vol-max-size-prune",
+			volumeOptions: map[string]string{
+				"snapshotMaxSizeAction": "prune",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				SnapshotMaxSizeAction:   string(longhorn.SnapshotMaxSizeActionPrune),
+			},
+		},
+		"snapshotMaxSizeAction invalid": {
+			volumeID: "test-vol-max-size-invalid",
+			volumeOptions: map[string]string{
+				"snapshotMaxSizeAction": "overwrite",
+			},
+			expectedError: true,
+		},
+		"recurringSnapshotOverflowAction skip": {
+			volumeID: "test-vol-overflow-skip",
+			volumeOptions: map[string]string{
+				"recurringSnapshotOverflowAction": "skip",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:             defaultStaleReplicaTimeout,
+				AccessMode:                      string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                      string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:         true,
+				RecurringSnapshotOverflowAction: string(longhorn.RecurringSnapshotOverflowActionSkip),
+			},
+		},
+		"recurringSnapshotOverflowAction prune": {
+			volumeID: "test-vol-overflow-prune",
+			volumeOptions: map[string]string{
+				"recurringSnapshotOverflowAction": "prune",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:             defaultStaleReplicaTimeout,
+				AccessMode:                      string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                      string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:         true,
+				RecurringSnapshotOverflowAction: string(longhorn.RecurringSnapshotOverflowActionPrune),
+			},
+		},
+		"recurringSnapshotOverflowAction empty": {
+			volumeID: "test-vol-overflow-empty",
+			volumeOptions: map[string]string{
+				"recurringSnapshotOverflowAction": "",
+			},
+			expectedError: true,
+		},
+		"recurringSnapshotOverflowAction invalid": {
+			volumeID: "test-vol-overflow-invalid",
+			volumeOptions: map[string]string{
+				"recurringSnapshotOverflowAction": "block",
+			},
+			expectedError: true,
+		},
+		"backupBlockSize 16Mi with v1 data engine": {
+			volumeID: "test-vol-block-size-v1",
+			volumeOptions: map[string]string{
+				"backupBlockSize": "16Mi",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				BackupBlockSize:         "16777216",
+			},
+		},
+		"backupBlockSize 2Mi with v2 data engine": {
+			volumeID: "test-vol-block-size-v2",
+			volumeOptions: map[string]string{
+				"dataEngine":      string(longhorn.DataEngineTypeV2),
+				"backupBlockSize": "2Mi",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV2),
+				RevisionCounterDisabled: true,
+				BackupBlockSize:         "2097152",
+			},
+		},
+		"backupBlockSize 16Mi with v2 data engine": {
+			volumeID: "test-vol-block-size-v2-invalid",
+			volumeOptions: map[string]string{
+				"dataEngine":      string(longhorn.DataEngineTypeV2),
+				"backupBlockSize": "16Mi",
+			},
+			expectedError: true,
+		},
+		"backupBlockSize unsupported": {
+			volumeID: "test-vol-block-size-invalid",
+			volumeOptions: map[string]string{
+				"backupBlockSize": "4Mi",
+			},
+			expectedError: true,
+		},
+		"discardEnabled with v2 data engine": {
+			volumeID: "test-vol-discard",
+			volumeOptions: map[string]string{
+				"dataEngine":     string(longhorn.DataEngineTypeV2),
+				"discardEnabled": "true",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV2),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"discardEnabled with v1 data engine": {
+			volumeID: "test-vol-discard-v1",
+			volumeOptions: map[string]string{
+				"discardEnabled": "true",
+			},
+			expectedError: true,
+		},
+		"discardEnabled malformed": {
+			volumeID: "test-vol-discard-malformed",
+			volumeOptions: map[string]string{
+				"dataEngine":     string(longhorn.DataEngineTypeV2),
+				"discardEnabled": "sometimes",
+			},
+			expectedError: true,
+		},
+		"disableNodeDownPodDeletion": {
+			volumeID: "test-vol-disable-node-down-pod-deletion",
+			volumeOptions: map[string]string{
+				"disableNodeDownPodDeletion": "true",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"disableNodeDownPodDeletion malformed": {
+			volumeID: "test-vol-disable-node-down-pod-deletion-malformed",
+			volumeOptions: map[string]string{
+				"disableNodeDownPodDeletion": "sometimes",
+			},
+			expectedError: true,
+		},
+		"dataEngineLogLevel": {
+			volumeID: "test-vol-log-level",
+			volumeOptions: map[string]string{
+				"dataEngine":         string(longhorn.DataEngineTypeV2),
+				"dataEngineLogLevel": "Debug",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV2),
+				RevisionCounterDisabled: true,
+				DataEngineLogLevel:      "Debug",
+			},
+		},
+		"dataEngineLogLevel invalid": {
+			volumeID: "test-vol-log-level-invalid",
+			volumeOptions: map[string]string{
+				"dataEngine":         string(longhorn.DataEngineTypeV2),
+				"dataEngineLogLevel": "Verbose",
+			},
+			expectedError: true,
+		},
+		"fsType": {
+			volumeID: "test-vol-fs-type",
+			volumeOptions: map[string]string{
+				"fsType": "xfs",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"fsType unsupported": {
+			volumeID: "test-vol-fs-type-unsupported",
+			volumeOptions: map[string]string{
+				"fsType": "btrfs",
+			},
+			expectedError: true,
+		},
+		"snapshotDataIntegrityCronJob": {
+			volumeID: "test-vol-integrity-cron",
+			volumeOptions: map[string]string{
+				"snapshotDataIntegrityCronJob": "0 0 */7 * *",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:          defaultStaleReplicaTimeout,
+				AccessMode:                   string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                   string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:      true,
+				SnapshotDataIntegrityCronJob: "0 0 */7 * *",
+			},
+		},
+		"snapshotDataIntegrityCronJob invalid": {
+			volumeID: "test-vol-integrity-cron-invalid",
+			volumeOptions: map[string]string{
+				"snapshotDataIntegrityCronJob": "every day",
+			},
+			expectedError: true,
+		},
+		"snapshotDataIntegrity ignored": {
+			volumeID: "test-vol-integrity-ignored",
+			volumeOptions: map[string]string{
+				"snapshotDataIntegrity": "ignored",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				SnapshotDataIntegrity:   "ignored",
+			},
+		},
+		"snapshotDataIntegrity disabled": {
+			volumeID: "test-vol-integrity-disabled",
+			volumeOptions: map[string]string{
+				"snapshotDataIntegrity": "disabled",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				SnapshotDataIntegrity:   "disabled",
+			},
+		},
+		"snapshotDataIntegrity enabled": {
+			volumeID: "test-vol-integrity-enabled",
+			volumeOptions: map[string]string{
+				"snapshotDataIntegrity": "enabled",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				SnapshotDataIntegrity:   "enabled",
+			},
+		},
+		"snapshotDataIntegrity fast-check": {
+			volumeID: "test-vol-integrity-fast-check",
+			volumeOptions: map[string]string{
+				"snapshotDataIntegrity": "fast-check",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				SnapshotDataIntegrity:   "fast-check",
+			},
+		},
+		"snapshotDataIntegrity invalid": {
+			volumeID: "test-vol-integrity-invalid",
+			volumeOptions: map[string]string{
+				"snapshotDataIntegrity": "always",
+			},
+			expectedError: true,
+		},
+		"unmapMarkSnapChainRemoved ignored": {
+			volumeID: "test-vol-unmap-ignored",
+			volumeOptions: map[string]string{
+				"unmapMarkSnapChainRemoved": "ignored",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:       defaultStaleReplicaTimeout,
+				AccessMode:                string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:   true,
+				UnmapMarkSnapChainRemoved: "ignored",
+			},
+		},
+		"unmapMarkSnapChainRemoved enabled": {
+			volumeID: "test-vol-unmap-enabled",
+			volumeOptions: map[string]string{
+				"unmapMarkSnapChainRemoved": "enabled",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:       defaultStaleReplicaTimeout,
+				AccessMode:                string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:   true,
+				UnmapMarkSnapChainRemoved: "enabled",
+			},
+		},
+		"unmapMarkSnapChainRemoved disabled": {
+			volumeID: "test-vol-unmap-disabled",
+			volumeOptions: map[string]string{
+				"unmapMarkSnapChainRemoved": "disabled",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:       defaultStaleReplicaTimeout,
+				AccessMode:                string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:   true,
+				UnmapMarkSnapChainRemoved: "disabled",
+			},
+		},
+		"unmapMarkSnapChainRemoved invalid": {
+			volumeID: "test-vol-unmap-invalid",
+			volumeOptions: map[string]string{
+				"unmapMarkSnapChainRemoved": "sometimes",
+			},
+			expectedError: true,
+		},
+		"unmapMarkSnapChainRemoved enabled with data engine v2": {
+			volumeID: "test-vol-unmap-v2",
+			volumeOptions: map[string]string{
+				"dataEngine":                string(longhorn.DataEngineTypeV2),
+				"unmapMarkSnapChainRemoved": "enabled",
+			},
+			expectedError: true,
+		},
+		"unmapMarkSnapChainRemoved disabled with data engine v2": {
+			volumeID: "test-vol-unmap-v2-disabled",
+			volumeOptions: map[string]string{
+				"dataEngine":                string(longhorn.DataEngineTypeV2),
+				"unmapMarkSnapChainRemoved": "disabled",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:       defaultStaleReplicaTimeout,
+				AccessMode:                string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                string(longhorn.DataEngineTypeV2),
+				RevisionCounterDisabled:   true,
+				UnmapMarkSnapChainRemoved: "disabled",
+			},
+		},
+		"unknown option ignored": {
+			volumeID: "test-vol-unknown-option",
+			volumeOptions: map[string]string{
+				"snapshotDataIntegrityMode": "always",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"backingImageReadyTimeout": {
+			volumeID: "test-vol-bi-ready-timeout",
+			volumeOptions: map[string]string{
+				"backingImage":             "test-bi",
+				"backingImageReadyTimeout": "300",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				BackingImage:            "test-bi",
+			},
+		},
+		"backingImageReadyTimeout without backingImage": {
+			volumeID: "test-vol-bi-ready-timeout-no-bi",
+			volumeOptions: map[string]string{
+				"backingImageReadyTimeout": "300",
+			},
+			expectedError: true,
+		},
+		"backingImageReadyTimeout zero": {
+			volumeID: "test-vol-bi-ready-timeout-zero",
+			volumeOptions: map[string]string{
+				"backingImage":             "test-bi",
+				"backingImageReadyTimeout": "0",
+			},
+			expectedError: true,
+		},
+		"backingImageReadyTimeout invalid": {
+			volumeID: "test-vol-bi-ready-timeout-invalid",
+			volumeOptions: map[string]string{
+				"backingImage":             "test-bi",
+				"backingImageReadyTimeout": "5m",
+			},
+			expectedError: true,
+		},
+		"backingImageCleanupPolicy invalid": {
+			volumeID: "test-vol-bi-cleanup-invalid",
+			volumeOptions: map[string]string{
+				"backingImage":              "test-bi",
+				"backingImageCleanupPolicy": "sometimes",
+			},
+			expectedError: true,
+		},
+		"snapshotReclaimThreshold percentage": {
+			volumeID: "test-vol-reclaim-percentage",
+			volumeOptions: map[string]string{
+				"snapshotReclaimThreshold": "80%",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:      defaultStaleReplicaTimeout,
+				AccessMode:               string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:               string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:  true,
+				SnapshotReclaimThreshold: "80%",
+			},
+		},
+		"snapshotReclaimThreshold size": {
+			volumeID: "test-vol-reclaim-size",
+			volumeOptions: map[string]string{
+				"snapshotReclaimThreshold": "10Gi",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:      defaultStaleReplicaTimeout,
+				AccessMode:               string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:               string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:  true,
+				SnapshotReclaimThreshold: "10Gi",
+			},
+		},
+		"snapshotReclaimThreshold invalid percentage": {
+			volumeID: "test-vol-reclaim-invalid-percentage",
+			volumeOptions: map[string]string{
+				"snapshotReclaimThreshold": "120%",
+			},
+			expectedError: true,
+		},
+		"snapshotReclaimThreshold invalid size": {
+			volumeID: "test-vol-reclaim-invalid-size",
+			volumeOptions: map[string]string{
+				"snapshotReclaimThreshold": "lots",
+			},
+			expectedError: true,
+		},
+		"replicaRebuildingEnabled": {
+			volumeID: "test-vol-replica-rebuilding-enabled",
+			volumeOptions: map[string]string{
+				"replicaRebuildingEnabled": "true",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				ReplicaRebuilding:       string(longhorn.VolumeReplicaRebuildingEnabled),
+			},
+		},
+		"replicaRebuildingEnabled disabled": {
+			volumeID: "test-vol-replica-rebuilding-disabled",
+			volumeOptions: map[string]string{
+				"replicaRebuildingEnabled": "false",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				ReplicaRebuilding:       string(longhorn.VolumeReplicaRebuildingDisabled),
+			},
+		},
+		"replicaRebuildingEnabled invalid": {
+			volumeID: "test-vol-replica-rebuilding-enabled-invalid",
+			volumeOptions: map[string]string{
+				"replicaRebuildingEnabled": "maybe",
+			},
+			expectedError: true,
+		},
+		"maxSize": {
+			volumeID: "test-vol-max-size",
+			volumeOptions: map[string]string{
+				"maxSize": "10Gi",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				MaxSize:                 "10737418240",
+			},
+		},
+		"maxSize invalid": {
+			volumeID: "test-vol-max-size-invalid",
+			volumeOptions: map[string]string{
+				"maxSize": "huge",
+			},
+			expectedError: true,
+		},
+		"size 10Gi": {
+			volumeID: "test-vol-size-10gi",
+			volumeOptions: map[string]string{
+				"size": "10Gi",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				Size:                    "10737418240",
+			},
+		},
+		"size 500Mi": {
+			volumeID: "test-vol-size-500mi",
+			volumeOptions: map[string]string{
+				"size": "500Mi",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				Size:                    "524288000",
+			},
+		},
+		"size 1T": {
+			volumeID: "test-vol-size-1t",
+			volumeOptions: map[string]string{
+				"size": "1T",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				Size:                    "1000000000000",
+			},
+		},
+		"size invalid": {
+			volumeID: "test-vol-size-invalid",
+			volumeOptions: map[string]string{
+				"size": "10GB!",
+			},
+			expectedError: true,
+		},
+		"size zero": {
+			volumeID: "test-vol-size-zero",
+			volumeOptions: map[string]string{
+				"size": "0",
+			},
+			expectedError: true,
+		},
+		"size negative": {
+			volumeID: "test-vol-size-negative",
+			volumeOptions: map[string]string{
+				"size": "-1Gi",
+			},
+			expectedError: true,
+		},
+		"size fractional": {
+			volumeID: "test-vol-size-fractional",
+			volumeOptions: map[string]string{
+				"size": "1.5",
+			},
+			expectedError: true,
+		},
+		"size milli": {
+			volumeID: "test-vol-size-milli",
+			volumeOptions: map[string]string{
+				"size": "100m",
+			},
+			expectedError: true,
+		},
+		"size empty": {
+			volumeID: "test-vol-size-empty",
+			volumeOptions: map[string]string{
+				"size": "",
+			},
+			expectedError: true,
+		},
+		"size within maxSize": {
+			volumeID: "test-vol-size-within-max-size",
+			volumeOptions: map[string]string{
+				"size":    "10Gi",
+				"maxSize": "20Gi",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				Size:                    "10737418240",
+				MaxSize:                 "21474836480",
+			},
+		},
+		"size larger than maxSize": {
+			volumeID: "test-vol-size-larger-than-max-size",
+			volumeOptions: map[string]string{
+				"size":    "20Gi",
+				"maxSize": "10Gi",
+			},
+			expectedError: true,
+		},
+		"standbyRestoreInterval": {
+			volumeID: "test-vol-standby-restore-interval",
+			volumeOptions: map[string]string{
+				"standbyRestoreInterval": "300",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				StandbyRestoreInterval:  300,
+			},
+		},
+		"standbyRestoreInterval negative": {
+			volumeID: "test-vol-standby-restore-interval-negative",
+			volumeOptions: map[string]string{
+				"standbyRestoreInterval": "-1",
+			},
+			expectedError: true,
+		},
+		"standbyRestoreInterval invalid": {
+			volumeID: "test-vol-standby-restore-interval-invalid",
+			volumeOptions: map[string]string{
+				"standbyRestoreInterval": "5m",
+			},
+			expectedError: true,
+		},
+		"autoDowngradeFromRWX": {
+			volumeID: "test-vol-auto-downgrade",
+			volumeOptions: map[string]string{
+				"share":                "true",
+				"autoDowngradeFromRWX": "true",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteMany),
+				AutoDowngradeFromRWX:    true,
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"autoDowngradeFromRWX with migratable": {
+			volumeID: "test-vol-auto-downgrade-migratable",
+			volumeOptions: map[string]string{
+				"share":                "true",
+				"migratable":           "true",
+				"autoDowngradeFromRWX": "true",
+			},
+			expectedError: true,
+		},
+		"autoDowngradeFromRWX with exclusive": {
+			volumeID: "test-vol-auto-downgrade-exclusive",
+			volumeOptions: map[string]string{
+				"exclusive":            "true",
+				"autoDowngradeFromRWX": "true",
+			},
+			expectedError: true,
+		},
+		"autoDowngradeFromRWX invalid": {
+			volumeID: "test-vol-auto-downgrade-invalid",
+			volumeOptions: map[string]string{
+				"autoDowngradeFromRWX": "sometimes",
+			},
+			expectedError: true,
+		},
+		"instanceManagerImage": {
+			volumeID: "test-vol-im-image",
+			volumeOptions: map[string]string{
+				"dataEngine":           string(longhorn.DataEngineTypeV2),
+				"instanceManagerImage": "longhornio/longhorn-instance-manager:v1.10.0",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV2),
+				RevisionCounterDisabled: true,
+				InstanceManagerImage:    "longhornio/longhorn-instance-manager:v1.10.0",
+			},
+		},
+		"instanceManagerImage invalid reference": {
+			volumeID: "test-vol-im-image-invalid",
+			volumeOptions: map[string]string{
+				"dataEngine":           string(longhorn.DataEngineTypeV2),
+				"instanceManagerImage": "longhornio/Longhorn-Instance-Manager::latest",
+			},
+			expectedError: true,
+		},
+		"instanceManagerImage v1 data engine": {
+			volumeID: "test-vol-im-image-v1",
+			volumeOptions: map[string]string{
+				"instanceManagerImage": "longhornio/longhorn-instance-manager:v1.10.0",
+			},
+			expectedError: true,
+		},
+		"pinnedNode": {
+			volumeID: "test-vol-pinned-node",
+			volumeOptions: map[string]string{
+				"pinnedNode":   "node-1",
+				"dataLocality": string(longhorn.DataLocalityBestEffort),
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				DataLocality:            string(longhorn.DataLocalityBestEffort),
+				RevisionCounterDisabled: true,
+				NodeID:                  "node-1",
+			},
+		},
+		"dataLocality disabled": {
+			volumeID: "test-vol-data-locality-disabled",
+			volumeOptions: map[string]string{
+				"numberOfReplicas": "1",
+				"dataLocality":     string(longhorn.DataLocalityDisabled),
+			},
+			expectedVolume: &longhornclient.Volume{
+				NumberOfReplicas:        1,
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				DataLocality:            string(longhorn.DataLocalityDisabled),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"dataLocality best-effort": {
+			volumeID: "test-vol-data-locality-best-effort",
+			volumeOptions: map[string]string{
+				"numberOfReplicas": "1",
+				"dataLocality":     string(longhorn.DataLocalityBestEffort),
+			},
+			expectedVolume: &longhornclient.Volume{
+				NumberOfReplicas:        1,
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				DataLocality:            string(longhorn.DataLocalityBestEffort),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"dataLocality strict-local": {
+			volumeID: "test-vol-data-locality-strict-local",
+			volumeOptions: map[string]string{
+				"numberOfReplicas": "1",
+				"dataLocality":     string(longhorn.DataLocalityStrictLocal),
+			},
+			expectedVolume: &longhornclient.Volume{
+				NumberOfReplicas:        1,
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				DataLocality:            string(longhorn.DataLocalityStrictLocal),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"dataLocality invalid": {
+			volumeID: "test-vol-data-locality-invalid",
+			volumeOptions: map[string]string{
+				"dataLocality": "local",
+			},
+			expectedError: true,
+		},
+		"dataLocality strict-local with multiple replicas": {
+			volumeID: "test-vol-data-locality-strict-local-replicas",
+			volumeOptions: map[string]string{
+				"numberOfReplicas": "3",
+				"dataLocality":     string(longhorn.DataLocalityStrictLocal),
+			},
+			expectedError:   true,
+			expectedErrorIs: ErrInvalidReplicaCount,
+		},
+		"dataLocality strict-local with shared access": {
+			volumeID: "test-vol-data-locality-strict-local-shared",
+			volumeOptions: map[string]string{
+				"share":        "true",
+				"dataLocality": string(longhorn.DataLocalityStrictLocal),
+			},
+			expectedError: true,
+		},
+		"appManagedQuiesce": {
+			volumeID: "test-vol-app-managed-quiesce",
+			volumeOptions: map[string]string{
+				"appManagedQuiesce": "true",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:         defaultStaleReplicaTimeout,
+				AccessMode:                  string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                  string(longhorn.DataEngineTypeV1),
+				FreezeFilesystemForSnapshot: string(longhorn.FreezeFilesystemForSnapshotAppManaged),
+				RevisionCounterDisabled:     true,
+			},
+		},
+		"appManagedQuiesce overrides disabled freeze": {
+			volumeID: "test-vol-app-managed-quiesce-disabled",
+			volumeOptions: map[string]string{
+				"appManagedQuiesce":           "true",
+				"freezeFilesystemForSnapshot": string(longhorn.FreezeFilesystemForSnapshotDisabled),
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:         defaultStaleReplicaTimeout,
+				AccessMode:                  string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                  string(longhorn.DataEngineTypeV1),
+				FreezeFilesystemForSnapshot: string(longhorn.FreezeFilesystemForSnapshotAppManaged),
+				RevisionCounterDisabled:     true,
+			},
+		},
+		"appManagedQuiesce false": {
+			volumeID: "test-vol-app-managed-quiesce-false",
+			volumeOptions: map[string]string{
+				"appManagedQuiesce":           "false",
+				"freezeFilesystemForSnapshot": string(longhorn.FreezeFilesystemForSnapshotEnabled),
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:         defaultStaleReplicaTimeout,
+				AccessMode:                  string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                  string(longhorn.DataEngineTypeV1),
+				FreezeFilesystemForSnapshot: string(longhorn.FreezeFilesystemForSnapshotEnabled),
+				RevisionCounterDisabled:     true,
+			},
+		},
+		"appManagedQuiesce with enabled freeze": {
+			volumeID: "test-vol-app-managed-quiesce-enabled",
+			volumeOptions: map[string]string{
+				"appManagedQuiesce":           "true",
+				"freezeFilesystemForSnapshot": string(longhorn.FreezeFilesystemForSnapshotEnabled),
+			},
+			expectedError: true,
+		},
+		"appManagedQuiesce invalid": {
+			volumeID: "test-vol-app-managed-quiesce-invalid",
+			volumeOptions: map[string]string{
+				"appManagedQuiesce": "yes",
+			},
+			expectedError: true,
+		},
+		"pinnedNode empty": {
+			volumeID: "test-vol-pinned-node-empty",
+			volumeOptions: map[string]string{
+				"pinnedNode": "",
+			},
+			expectedError: true,
+		},
+		"replicaZoneHardAntiAffinity": {
+			volumeID: "test-vol-zone-hard-anti-affinity",
+			volumeOptions: map[string]string{
+				"replicaZoneHardAntiAffinity": "true",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:         defaultStaleReplicaTimeout,
+				AccessMode:                  string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                  string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:     true,
+				ReplicaZoneSoftAntiAffinity: string(longhorn.ReplicaZoneSoftAntiAffinityDisabled),
+			},
+		},
+		"replicaZoneHardAntiAffinity false": {
+			volumeID: "test-vol-zone-hard-anti-affinity-false",
+			volumeOptions: map[string]string{
+				"replicaZoneHardAntiAffinity": "false",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"replicaZoneHardAntiAffinity with soft anti-affinity": {
+			volumeID: "test-vol-zone-hard-anti-affinity-conflict",
+			volumeOptions: map[string]string{
+				"replicaZoneSoftAntiAffinity": string(longhorn.ReplicaZoneSoftAntiAffinityEnabled),
+				"replicaZoneHardAntiAffinity": "true",
+			},
+			expectedError: true,
+		},
+		"engineImagePullSecret": {
+			volumeID: "test-vol-engine-image-pull-secret",
+			volumeOptions: map[string]string{
+				"engineImagePullSecret": "registry-secret",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				EngineImagePullSecret:   "registry-secret",
+			},
+		},
+		"engineImagePullSecret invalid": {
+			volumeID: "test-vol-engine-image-pull-secret-invalid",
+			volumeOptions: map[string]string{
+				"engineImagePullSecret": "longhorn-system/registry-secret",
+			},
+			expectedError: true,
+		},
+		"backupTargetName": {
+			volumeID: "test-vol-backup-target",
+			volumeOptions: map[string]string{
+				"backupTargetName": "default",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				BackupTargetName:        "default",
+			},
+		},
+		"backupTargetName namespace-qualified": {
+			volumeID: "test-vol-backup-target-qualified",
+			volumeOptions: map[string]string{
+				"backupTargetName": "longhorn-system/default",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				BackupTargetName:        "default",
+			},
+		},
+		"backupTargetName invalid": {
+			volumeID: "test-vol-backup-target-invalid",
+			volumeOptions: map[string]string{
+				"backupTargetName": "longhorn-system/default/extra",
+			},
+			expectedError: true,
+		},
+		"backupTargetName empty": {
+			volumeID: "test-vol-backup-target-empty",
+			volumeOptions: map[string]string{
+				"backupTargetName": "",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"backupTargetName uppercase": {
+			volumeID: "test-vol-backup-target-uppercase",
+			volumeOptions: map[string]string{
+				"backupTargetName": "Default",
+			},
+			expectedError: true,
+		},
+		"backupTargetName dotted": {
+			volumeID: "test-vol-backup-target-dotted",
+			volumeOptions: map[string]string{
+				"backupTargetName": "s3.target",
+			},
+			expectedError: true,
+		},
+		"backupTargetName invalid characters": {
+			volumeID: "test-vol-backup-target-invalid-characters",
+			volumeOptions: map[string]string{
+				"backupTargetName": "s3_target",
+			},
+			expectedError: true,
+		},
+		"backupTargetName invalid namespace": {
+			volumeID: "test-vol-backup-target-invalid-namespace",
+			volumeOptions: map[string]string{
+				"backupTargetName": "longhorn.system/default",
+			},
+			expectedError: true,
+		},
+		"backupTargetName missing namespace": {
+			volumeID: "test-vol-backup-target-missing-namespace",
+			volumeOptions: map[string]string{
+				"backupTargetName": "/default",
+			},
+			expectedError: true,
+		},
+		"revision counter default of data engine v1": {
+			volumeID: "test-vol-revision-counter-v1",
+			volumeOptions: map[string]string{
+				"dataEngine": string(longhorn.DataEngineTypeV1),
+			},
+			defaultRevisionCounterDisabled: map[longhorn.DataEngineType]bool{
+				longhorn.DataEngineTypeV1: false,
+				longhorn.DataEngineTypeV2: true,
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout: defaultStaleReplicaTimeout,
+				AccessMode:          string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:          string(longhorn.DataEngineTypeV1),
+			},
+		},
+		"revision counter default of data engine v2": {
+			volumeID: "test-vol-revision-counter-v2",
+			volumeOptions: map[string]string{
+				"dataEngine": string(longhorn.DataEngineTypeV2),
+			},
+			defaultRevisionCounterDisabled: map[longhorn.DataEngineType]bool{
+				longhorn.DataEngineTypeV1: true,
+				longhorn.DataEngineTypeV2: false,
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout: defaultStaleReplicaTimeout,
+				AccessMode:          string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:          string(longhorn.DataEngineTypeV2),
+			},
+		},
+		"revision counter default missing for data engine": {
+			volumeID: "test-vol-revision-counter-missing",
+			volumeOptions: map[string]string{
+				"dataEngine": string(longhorn.DataEngineTypeV2),
+			},
+			defaultRevisionCounterDisabled: map[longhorn.DataEngineType]bool{
+				longhorn.DataEngineTypeV1: false,
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV2),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"revision counter explicitly disabled": {
+			volumeID: "test-vol-revision-counter-explicit",
+			volumeOptions: map[string]string{
+				"dataEngine":             string(longhorn.DataEngineTypeV2),
+				"disableRevisionCounter": "true",
+			},
+			defaultRevisionCounterDisabled: map[longhorn.DataEngineType]bool{
+				longhorn.DataEngineTypeV2: false,
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV2),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"revision counter explicitly enabled": {
+			volumeID: "test-vol-revision-counter-explicit-enabled",
+			volumeOptions: map[string]string{
+				"disableRevisionCounter": "false",
+			},
+			defaultRevisionCounterDisabled: map[longhorn.DataEngineType]bool{
+				longhorn.DataEngineTypeV1: true,
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout: defaultStaleReplicaTimeout,
+				AccessMode:          string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:          string(longhorn.DataEngineTypeV1),
+			},
+		},
+		"replicaZoneHardAntiAffinity invalid": {
+			volumeID: "test-vol-zone-hard-anti-affinity-invalid",
+			volumeOptions: map[string]string{
+				"replicaZoneHardAntiAffinity": "always",
+			},
+			expectedError: true,
+		},
+		"encryptionIntegrity": {
+			volumeID: "test-vol-encryption-integrity",
+			volumeOptions: map[string]string{
+				"encrypted":           "true",
+				"encryptionIntegrity": "true",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				Encrypted:               true,
+			},
+		},
+		"encryptionIntegrity false without encrypted": {
+			volumeID: "test-vol-encryption-integrity-false",
+			volumeOptions: map[string]string{
+				"encryptionIntegrity": "false",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"encryptionIntegrity without encrypted": {
+			volumeID: "test-vol-encryption-integrity-unencrypted",
+			volumeOptions: map[string]string{
+				"encryptionIntegrity": "true",
+			},
+			expectedError: true,
+		},
+		"encryptionIntegrity with encrypted false": {
+			volumeID: "test-vol-encryption-integrity-encrypted-false",
+			volumeOptions: map[string]string{
+				"encrypted":           "false",
+				"encryptionIntegrity": "true",
+			},
+			expectedError: true,
+		},
+		"encryptionIntegrity shared": {
+			volumeID: "test-vol-encryption-integrity-shared",
+			volumeOptions: map[string]string{
+				"share":               "true",
+				"encrypted":           "true",
+				"encryptionIntegrity": "true",
+			},
+			expectedError: true,
+		},
+		"encryptionIntegrity invalid": {
+			volumeID: "test-vol-encryption-integrity-invalid",
+			volumeOptions: map[string]string{
+				"encrypted":           "true",
+				"encryptionIntegrity": "yes",
+			},
+			expectedError: true,
+		},
+		"replicaAutoBalance disabled": {
+			volumeID: "test-vol-auto-balance-disabled",
+			volumeOptions: map[string]string{
+				"replicaAutoBalance": string(longhorn.ReplicaAutoBalanceDisabled),
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				ReplicaAutoBalance:      string(longhorn.ReplicaAutoBalanceDisabled),
+			},
+		},
+		"replicaAutoBalance least-effort": {
+			volumeID: "test-vol-auto-balance-least-effort",
+			volumeOptions: map[string]string{
+				"replicaAutoBalance": string(longhorn.ReplicaAutoBalanceLeastEffort),
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				ReplicaAutoBalance:      string(longhorn.ReplicaAutoBalanceLeastEffort),
+			},
+		},
+		"replicaAutoBalance best-effort": {
+			volumeID: "test-vol-auto-balance-best-effort",
+			volumeOptions: map[string]string{
+				"replicaAutoBalance": string(longhorn.ReplicaAutoBalanceBestEffort),
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+				ReplicaAutoBalance:      string(longhorn.ReplicaAutoBalanceBestEffort),
+			},
+		},
+		"replicaAutoBalance invalid": {
+			volumeID: "test-vol-auto-balance-invalid",
+			volumeOptions: map[string]string{
+				"replicaAutoBalance": "most-effort",
+			},
+			expectedError: true,
+		},
+		"replicaAutoBalance empty": {
+			volumeID: "test-vol-auto-balance-empty",
+			volumeOptions: map[string]string{
+				"replicaAutoBalance": "",
+			},
+			expectedError: true,
+		},
+		"replicaAutoBalanceDiskPressurePercentage": {
+			volumeID: "test-vol-disk-pressure",
+			volumeOptions: map[string]string{
+				"replicaAutoBalanceDiskPressurePercentage": "80",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:                      defaultStaleReplicaTimeout,
+				AccessMode:                               string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                               string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:                  true,
+				ReplicaAutoBalanceDiskPressurePercentage: 80,
+			},
+		},
+		"replicaAutoBalanceDiskPressurePercentage with best-effort": {
+			volumeID: "test-vol-disk-pressure-best-effort",
+			volumeOptions: map[string]string{
+				"replicaAutoBalance":                       string(longhorn.ReplicaAutoBalanceBestEffort),
+				"replicaAutoBalanceDiskPressurePercentage": "100",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:                      defaultStaleReplicaTimeout,
+				AccessMode:                               string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:                               string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled:                  true,
+				ReplicaAutoBalance:                       string(longhorn.ReplicaAutoBalanceBestEffort),
+				ReplicaAutoBalanceDiskPressurePercentage: 100,
+			},
+		},
+		"replicaAutoBalanceDiskPressurePercentage zero": {
+			volumeID: "test-vol-disk-pressure-zero",
+			volumeOptions: map[string]string{
+				"replicaAutoBalanceDiskPressurePercentage": "0",
+			},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"replicaAutoBalanceDiskPressurePercentage above 100": {
+			volumeID: "test-vol-disk-pressure-above",
+			volumeOptions: map[string]string{
+				"replicaAutoBalanceDiskPressurePercentage": "101",
+			},
+			expectedError: true,
+		},
+		"replicaAutoBalanceDiskPressurePercentage negative": {
+			volumeID: "test-vol-disk-pressure-negative",
+			volumeOptions: map[string]string{
+				"replicaAutoBalanceDiskPressurePercentage": "-1",
+			},
+			expectedError: true,
+		},
+		"replicaAutoBalanceDiskPressurePercentage invalid": {
+			volumeID: "test-vol-disk-pressure-invalid",
+			volumeOptions: map[string]string{
+				"replicaAutoBalanceDiskPressurePercentage": "80%",
+			},
+			expectedError: true,
+		},
+		"replicaAutoBalanceDiskPressurePercentage with least-effort": {
+			volumeID: "test-vol-disk-pressure-least-effort",
+			volumeOptions: map[string]string{
+				"replicaAutoBalance":                       string(longhorn.ReplicaAutoBalanceLeastEffort),
+				"replicaAutoBalanceDiskPressurePercentage": "80",
+			},
+			expectedError: true,
+		},
+		"staleReplicaTimeout from the setting": {
+			volumeID:                   "test-vol-stale-replica-timeout-setting",
+			volumeOptions:              map[string]string{},
+			settingStaleReplicaTimeout: 60,
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     60,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"staleReplicaTimeout without the setting": {
+			volumeID:      "test-vol-stale-replica-timeout-no-setting",
+			volumeOptions: map[string]string{},
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
 				DataEngine:              string(longhorn.DataEngineTypeV1),
 				RevisionCounterDisabled: true,
 			},
 		},
-		"shared access": {
-			volumeID: "test-vol-shared",
+		"staleReplicaTimeout overriding the setting": {
+			volumeID: "test-vol-stale-replica-timeout-override",
 			volumeOptions: map[string]string{
-				"share": "true",
+				"staleReplicaTimeout": "30",
+			},
+			settingStaleReplicaTimeout: 60,
+			expectedVolume: &longhornclient.Volume{
+				StaleReplicaTimeout:     30,
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
+				RevisionCounterDisabled: true,
+			},
+		},
+		"shareManagerNodeSelector": {
+			volumeID: "test-vol-share-manager-node-selector",
+			volumeOptions: map[string]string{
+				"share":                    "true",
+				"shareManagerNodeSelector": "node.longhorn.io/share-manager:true;topology.kubernetes.io/zone:zone-a",
 			},
 			expectedVolume: &longhornclient.Volume{
 				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
@@ -55,60 +1570,143 @@ func TestGetVolumeOptions(t *testing.T) {
 				RevisionCounterDisabled: true,
 			},
 		},
-		"exclusive and shared conflict": {
-			volumeID: "test-vol-conflict",
+		"shareManagerNodeSelector without share": {
+			volumeID: "test-vol-share-manager-node-selector-rwo",
 			volumeOptions: map[string]string{
-				"exclusive": "true",
-				"share":     "true",
+				"shareManagerNodeSelector": "node.longhorn.io/share-manager:true",
 			},
 			expectedError: true,
 		},
-		"migratable requires RWX": {
-			volumeID: "test-vol-migratable-no-rwx",
+		"shareManagerNodeSelector with share disabled": {
+			volumeID: "test-vol-share-manager-node-selector-not-shared",
 			volumeOptions: map[string]string{
-				"migratable": "true",
+				"share":                    "false",
+				"shareManagerNodeSelector": "node.longhorn.io/share-manager:true",
+			},
+			expectedError: true,
+		},
+		"shareManagerNodeSelector invalid": {
+			volumeID: "test-vol-share-manager-node-selector-invalid",
+			volumeOptions: map[string]string{
+				"share":                    "true",
+				"shareManagerNodeSelector": "node.longhorn.io/share-manager",
+			},
+			expectedError: true,
+		},
+		"fromBackup": {
+			volumeID: "test-vol-from-backup",
+			volumeOptions: map[string]string{
+				"fromBackup": "s3://backupbucket@us-east-1/backupstore?backup=backup-1&volume=source-vol",
 			},
 			expectedVolume: &longhornclient.Volume{
 				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
 				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
 				DataEngine:              string(longhorn.DataEngineTypeV1),
 				RevisionCounterDisabled: true,
-				Migratable:              false,
+				FromBackup:              "s3://backupbucket@us-east-1/backupstore?backup=backup-1&volume=source-vol",
 			},
 		},
-		"migratable with RWX": {
-			volumeID: "test-vol-migratable-rwx",
+		"fromBackup with numberOfReplicas": {
+			volumeID: "test-vol-from-backup-replicas",
 			volumeOptions: map[string]string{
-				"share":      "true",
-				"migratable": "true",
+				"fromBackup":       "nfs://longhorn-test-nfs-svc.default:/opt/backupstore?backup=backup-1&volume=source-vol",
+				"numberOfReplicas": "2",
 			},
 			expectedVolume: &longhornclient.Volume{
 				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
-				AccessMode:              string(longhorn.AccessModeReadWriteMany),
+				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
 				DataEngine:              string(longhorn.DataEngineTypeV1),
 				RevisionCounterDisabled: true,
-				Migratable:              true,
+				NumberOfReplicas:        2,
+				FromBackup:              "nfs://longhorn-test-nfs-svc.default:/opt/backupstore?backup=backup-1&volume=source-vol",
 			},
 		},
-		"dataEngine override to v2": {
-			volumeID: "test-vol-dataengine-v2",
+		"fromBackup without backup name": {
+			volumeID: "test-vol-from-backup-no-backup",
 			volumeOptions: map[string]string{
-				"dataEngine": "v2",
+				"fromBackup": "s3://backupbucket@us-east-1/backupstore?volume=source-vol",
+			},
+			expectedError: true,
+		},
+		"fromBackup without volume name": {
+			volumeID: "test-vol-from-backup-no-volume",
+			volumeOptions: map[string]string{
+				"fromBackup": "s3://backupbucket@us-east-1/backupstore?backup=backup-1",
+			},
+			expectedError: true,
+		},
+		"fromBackup without backup target scheme": {
+			volumeID: "test-vol-from-backup-no-scheme",
+			volumeOptions: map[string]string{
+				"fromBackup": "backupbucket/backupstore?backup=backup-1&volume=source-vol",
+			},
+			expectedError: true,
+		},
+		"fromBackup malformed": {
+			volumeID: "test-vol-from-backup-malformed",
+			volumeOptions: map[string]string{
+				"fromBackup": "s3://backupbucket@us-east-1/%zz?backup=backup-1&volume=source-vol",
+			},
+			expectedError: true,
+		},
+		"fromVolume": {
+			volumeID: "test-vol-from-volume",
+			volumeOptions: map[string]string{
+				"fromVolume": "source-vol",
+				"cloneMode":  string(longhorn.CloneModeFullCopy),
 			},
 			expectedVolume: &longhornclient.Volume{
 				StaleReplicaTimeout:     defaultStaleReplicaTimeout,
 				AccessMode:              string(longhorn.AccessModeReadWriteOnce),
-				DataEngine:              string(longhorn.DataEngineTypeV2),
+				DataEngine:              string(longhorn.DataEngineTypeV1),
 				RevisionCounterDisabled: true,
+				DataSource:              "vol://source-vol",
+				CloneMode:               string(longhorn.CloneModeFullCopy),
 			},
 		},
+		"fromVolume empty": {
+			volumeID: "test-vol-from-volume-empty",
+			volumeOptions: map[string]string{
+				"fromVolume": "",
+			},
+			expectedError: true,
+		},
+		"fromVolume with a snapshot content source": {
+			volumeID: "test-vol-from-volume-and-snapshot",
+			volumeOptions: map[string]string{
+				"fromVolume": "source-vol",
+				"dataSource": "snap://source-vol/source-snap",
+			},
+			expectedError: true,
+		},
+		"fromVolume with a volume content source": {
+			volumeID: "test-vol-from-volume-and-volume",
+			volumeOptions: map[string]string{
+				"fromVolume": "source-vol",
+				"dataSource": "vol://another-vol",
+			},
+			expectedError: true,
+		},
+		"fromVolume with fromBackup": {
+			volumeID: "test-vol-from-volume-and-backup",
+			volumeOptions: map[string]string{
+				"fromVolume": "source-vol",
+				"fromBackup": "s3://backupbucket@us-east-1/?backup=backup-1&volume=source-vol",
+			},
+			expectedError: true,
+		},
 	}
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			vol, err := getVolumeOptions(tc.volumeID, tc.volumeOptions)
+			vol, err := getVolumeOptions(tc.volumeID, tc.volumeOptions, tc.defaultRevisionCounterDisabled, tc.settingStaleReplicaTimeout)
 			if tc.expectedError {
 				require.Error(t, err)
+				assert.True(t, errors.Is(err, ErrInvalidVolumeOptions), err.Error())
+				assert.Equal(t, codes.InvalidArgument, getVolumeOptionsErrorCode(err))
+				if tc.expectedErrorIs != nil {
+					assert.True(t, errors.Is(err, tc.expectedErrorIs), err.Error())
+				}
 				return
 			}
 			require.NoError(t, err)
@@ -117,6 +1715,170 @@ func TestGetVolumeOptions(t *testing.T) {
 	}
 }
 
+func TestGetVolumeOptionsErrorCode(t *testing.T) {
+	_, err := getVolumeOptions("test-vol", map[string]string{"exclusive": "true", "share": "true"}, nil, 0)
+	assert.Equal(t, codes.InvalidArgument, getVolumeOptionsErrorCode(err))
+	assert.Equal(t, codes.InvalidArgument, getVolumeOptionsErrorCode(errors.Wrap(err, "failed to create volume")))
+	assert.Equal(t, codes.Internal, getVolumeOptionsErrorCode(errors.New("unexpected")))
+}
+
+func TestParseDefaultRevisionCounterDisabled(t *testing.T) {
+	tests := map[string]struct {
+		value         string
+		expected      map[longhorn.DataEngineType]bool
+		expectedError bool
+	}{
+		"per data engine": {
+			value: `{"v1":"true","v2":"false"}`,
+			expected: map[longhorn.DataEngineType]bool{
+				longhorn.DataEngineTypeV1: true,
+				longhorn.DataEngineTypeV2: false,
+			},
+		},
+		"single value": {
+			value: "false",
+			expected: map[longhorn.DataEngineType]bool{
+				longhorn.DataEngineTypeV1: false,
+				longhorn.DataEngineTypeV2: false,
+			},
+		},
+		"invalid": {
+			value:         `{"v1":"maybe"}`,
+			expectedError: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			defaults, err := parseDefaultRevisionCounterDisabled(tc.value)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, defaults)
+		})
+	}
+}
+
+func TestGetBackingImageReadyTimeout(t *testing.T) {
+	tests := map[string]struct {
+		volumeContext map[string]string
+		expected      time.Duration
+		expectedError bool
+	}{
+		"not set": {
+			volumeContext: map[string]string{"backingImage": "test-bi"},
+		},
+		"seconds": {
+			volumeContext: map[string]string{"backingImage": "test-bi", "backingImageReadyTimeout": "300"},
+			expected:      5 * time.Minute,
+		},
+		"negative": {
+			volumeContext: map[string]string{"backingImageReadyTimeout": "-1"},
+			expectedError: true,
+		},
+		"duration": {
+			volumeContext: map[string]string{"backingImageReadyTimeout": "5m"},
+			expectedError: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			timeout, err := getBackingImageReadyTimeout(tc.volumeContext)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, timeout)
+		})
+	}
+}
+
+func TestGetNodeStageMountOptions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mountFlags    []string
+		fsType        string
+		volumeContext map[string]string
+		expected      []string
+	}{
+		{
+			name:       "ext4",
+			mountFlags: []string{"noatime"},
+			fsType:     "ext4",
+			expected:   []string{"noatime"},
+		},
+		{
+			name:     "xfs",
+			fsType:   "xfs",
+			expected: []string{"nouuid"},
+		},
+		{
+			name:          "discard enabled",
+			mountFlags:    []string{"noatime"},
+			fsType:        "ext4",
+			volumeContext: map[string]string{"discardEnabled": "true"},
+			expected:      []string{"noatime", "discard"},
+		},
+		{
+			name:          "discard disabled",
+			fsType:        "ext4",
+			volumeContext: map[string]string{"discardEnabled": "false"},
+			expected:      []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getNodeStageMountOptions(tc.mountFlags, tc.fsType, tc.volumeContext))
+		})
+	}
+}
+
+func TestGetVolumeFsType(t *testing.T) {
+	mountCapability := func(fsType string) *csi.VolumeCapability {
+		return &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{
+				Mount: &csi.VolumeCapability_MountVolume{FsType: fsType},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		capability    *csi.VolumeCapability
+		volumeContext map[string]string
+		expected      string
+	}{
+		{
+			name:       "default",
+			capability: mountCapability(""),
+			expected:   "ext4",
+		},
+		{
+			name:          "from the volume context",
+			capability:    mountCapability(""),
+			volumeContext: map[string]string{"fsType": "xfs"},
+			expected:      "xfs",
+		},
+		{
+			name:          "from the volume capability",
+			capability:    mountCapability("xfs"),
+			volumeContext: map[string]string{"fsType": "ext4"},
+			expected:      "xfs",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, getVolumeFsType(tc.capability, tc.volumeContext))
+		})
+	}
+}
+
 func TestRequireExclusiveAccess(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -160,6 +1922,39 @@ func TestRequireExclusiveAccess(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name: "rwo volume with single node multi writer",
+			volume: &longhornclient.Volume{
+				AccessMode: string(longhorn.AccessModeReadWriteOnce),
+			},
+			capability: &csi.VolumeCapability{
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "rwop volume with single node multi writer",
+			volume: &longhornclient.Volume{
+				AccessMode: string(longhorn.AccessModeReadWriteOncePod),
+			},
+			capability: &csi.VolumeCapability{
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "single node multi writer without volume",
+			capability: &csi.VolumeCapability{
+				AccessMode: &csi.VolumeCapability_AccessMode{
+					Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER,
+				},
+			},
+			expected: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -247,3 +2042,153 @@ func TestRequiresSharedAccess(t *testing.T) {
 		})
 	}
 }
+
+func TestRequiresSharedMount(t *testing.T) {
+	multiNodeCapability := &csi.VolumeCapability{
+		AccessMode: &csi.VolumeCapability_AccessMode{
+			Mode: csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		volume   *longhornclient.Volume
+		expected bool
+	}{
+		{
+			name: "rwx volume",
+			volume: &longhornclient.Volume{
+				AccessMode: string(longhorn.AccessModeReadWriteMany),
+			},
+			expected: true,
+		},
+		{
+			name: "migratable rwx volume",
+			volume: &longhornclient.Volume{
+				AccessMode: string(longhorn.AccessModeReadWriteMany),
+				Migratable: true,
+			},
+			expected: false,
+		},
+		{
+			name: "rwx volume allowing auto downgrade",
+			volume: &longhornclient.Volume{
+				AccessMode:           string(longhorn.AccessModeReadWriteMany),
+				AutoDowngradeFromRWX: true,
+			},
+			expected: true,
+		},
+		{
+			name: "volume downgraded from rwx",
+			volume: &longhornclient.Volume{
+				AccessMode:           string(longhorn.AccessModeReadWriteOnce),
+				AutoDowngradeFromRWX: true,
+			},
+			expected: false,
+		},
+		{
+			name: "rwo volume",
+			volume: &longhornclient.Volume{
+				AccessMode: string(longhorn.AccessModeReadWriteOnce),
+			},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := requiresSharedMount(tc.volume, multiNodeCapability)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestValidateAccessModeCompatibility(t *testing.T) {
+	volumes := map[string]*longhornclient.Volume{
+		"detached rwo": {
+			AccessMode: string(longhorn.AccessModeReadWriteOnce),
+			State:      string(longhorn.VolumeStateDetached),
+		},
+		"attached rwo": {
+			AccessMode: string(longhorn.AccessModeReadWriteOnce),
+			State:      string(longhorn.VolumeStateAttached),
+		},
+		"attached rwo downgraded from rwx": {
+			AccessMode:           string(longhorn.AccessModeReadWriteOnce),
+			AutoDowngradeFromRWX: true,
+			State:                string(longhorn.VolumeStateAttached),
+		},
+		"detached rwx": {
+			AccessMode: string(longhorn.AccessModeReadWriteMany),
+			State:      string(longhorn.VolumeStateDetached),
+		},
+		"attached rwx": {
+			AccessMode: string(longhorn.AccessModeReadWriteMany),
+			State:      string(longhorn.VolumeStateAttached),
+		},
+		"detached migratable rwx": {
+			AccessMode: string(longhorn.AccessModeReadWriteMany),
+			Migratable: true,
+			State:      string(longhorn.VolumeStateDetached),
+		},
+		"detached rwop": {
+			AccessMode: string(longhorn.AccessModeReadWriteOncePod),
+			State:      string(longhorn.VolumeStateDetached),
+		},
+		"attached rwop": {
+			AccessMode: string(longhorn.AccessModeReadWriteOncePod),
+			State:      string(longhorn.VolumeStateAttached),
+		},
+	}
+
+	// incompatibleModes lists the capability modes each volume cannot serve, every other mode is compatible.
+	incompatibleModes := map[string][]csi.VolumeCapability_AccessMode_Mode{
+		"detached rwo": nil,
+		"attached rwo": {
+			csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+			csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER,
+			csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+		},
+		"attached rwo downgraded from rwx": {
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+		},
+		"detached rwx": nil,
+		"attached rwx": {
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+		},
+		"detached migratable rwx": {
+			csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER,
+		},
+		"detached rwop": {
+			csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+			csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER,
+			csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+		},
+		"attached rwop": {
+			csi.VolumeCapability_AccessMode_MULTI_NODE_READER_ONLY,
+			csi.VolumeCapability_AccessMode_MULTI_NODE_SINGLE_WRITER,
+			csi.VolumeCapability_AccessMode_MULTI_NODE_MULTI_WRITER,
+		},
+	}
+
+	for volumeName, volume := range volumes {
+		for value := range csi.VolumeCapability_AccessMode_Mode_name {
+			mode := csi.VolumeCapability_AccessMode_Mode(value)
+			t.Run(fmt.Sprintf("%v with %v", volumeName, mode), func(t *testing.T) {
+				capability := &csi.VolumeCapability{
+					AccessMode: &csi.VolumeCapability_AccessMode{
+						Mode: mode,
+					},
+				}
+
+				err := ValidateAccessModeCompatibility(volume, capability)
+				if slices.Contains(incompatibleModes[volumeName], mode) {
+					assert.ErrorIs(t, err, ErrIncompatibleAccessMode)
+				} else {
+					assert.NoError(t, err)
+				}
+			})
+		}
+	}
+}
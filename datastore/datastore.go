@@ -4,10 +4,14 @@ import (
 	"errors"
 	"time"
 
+	"github.com/sirupsen/logrus"
+
 	"k8s.io/client-go/tools/cache"
 
 	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery/cached/memory"
 	clientset "k8s.io/client-go/kubernetes"
 	appslisters "k8s.io/client-go/listers/apps/v1"
 	batchlisters_v1 "k8s.io/client-go/listers/batch/v1"
@@ -16,6 +20,8 @@ import (
 	policylisters "k8s.io/client-go/listers/policy/v1"
 	schedulinglisters "k8s.io/client-go/listers/scheduling/v1"
 	storagelisters_v1 "k8s.io/client-go/listers/storage/v1"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/restmapper"
 
 	"github.com/longhorn/longhorn-manager/types"
 	"github.com/longhorn/longhorn-manager/util"
@@ -94,8 +100,10 @@ type DataStore struct {
 	LHVolumeAttachmentInformer     cache.SharedInformer
 
 	kubeClient                    clientset.Interface
+	metadataClient                metadata.Interface
+	restMapper                    meta.RESTMapper
 	podLister                     corelisters.PodLister
-	PodInformer                   cache.SharedInformer
+	PodInformer                   cache.SharedIndexInformer
 	cronJobLister                 batchlisters_v1.CronJobLister
 	CronJobInformer               cache.SharedInformer
 	daemonSetLister               appslisters.DaemonSetLister
@@ -107,7 +115,7 @@ type DataStore struct {
 	persistentVolumeClaimLister   corelisters.PersistentVolumeClaimLister
 	PersistentVolumeClaimInformer cache.SharedInformer
 	volumeAttachmentLister        storagelisters_v1.VolumeAttachmentLister
-	VolumeAttachmentInformer      cache.SharedInformer
+	VolumeAttachmentInformer      cache.SharedIndexInformer
 	configMapLister               corelisters.ConfigMapLister
 	ConfigMapInformer             cache.SharedInformer
 	secretLister                  corelisters.SecretLister
@@ -185,6 +193,9 @@ func NewDataStore(namespace string, lhClient lhclientset.Interface, kubeClient c
 	// Kube Informers
 	podInformer := informerFactories.KubeInformerFactory.Core().V1().Pods()
 	cacheSyncs = append(cacheSyncs, podInformer.Informer().HasSynced)
+	if err := addPodPersistentVolumeClaimIndexer(podInformer.Informer()); err != nil {
+		logrus.WithError(err).Warn("Failed to add the persistent volume claim indexer to the Pod informer")
+	}
 	kubeNodeInformer := informerFactories.KubeInformerFactory.Core().V1().Nodes()
 	cacheSyncs = append(cacheSyncs, kubeNodeInformer.Informer().HasSynced)
 	persistentVolumeInformer := informerFactories.KubeInformerFactory.Core().V1().PersistentVolumes()
@@ -193,6 +204,9 @@ func NewDataStore(namespace string, lhClient lhclientset.Interface, kubeClient c
 	cacheSyncs = append(cacheSyncs, persistentVolumeClaimInformer.Informer().HasSynced)
 	volumeAttachmentInformer := informerFactories.KubeInformerFactory.Storage().V1().VolumeAttachments()
 	cacheSyncs = append(cacheSyncs, volumeAttachmentInformer.Informer().HasSynced)
+	if err := addVolumeAttachmentPersistentVolumeIndexer(volumeAttachmentInformer.Informer()); err != nil {
+		logrus.WithError(err).Warn("Failed to add the persistent volume indexer to the VolumeAttachment informer")
+	}
 	csiDriverInformer := informerFactories.KubeInformerFactory.Storage().V1().CSIDrivers()
 	cacheSyncs = append(cacheSyncs, csiDriverInformer.Informer().HasSynced)
 	storageclassInformer := informerFactories.KubeInformerFactory.Storage().V1().StorageClasses()
@@ -272,6 +286,7 @@ func NewDataStore(namespace string, lhClient lhclientset.Interface, kubeClient c
 		LHVolumeAttachmentInformer:     lhVolumeAttachmentInformer.Informer(),
 
 		kubeClient:                    kubeClient,
+		restMapper:                    restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(kubeClient.Discovery())),
 		podLister:                     podInformer.Lister(),
 		PodInformer:                   podInformer.Informer(),
 		persistentVolumeLister:        persistentVolumeInformer.Lister(),
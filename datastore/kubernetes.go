@@ -12,10 +12,14 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/sirupsen/logrus"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
@@ -29,6 +33,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
 
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 )
@@ -39,6 +44,14 @@ const (
 	// KubeStatusPollInterval is the waiting time between each KubeStatusPollCount
 	KubeStatusPollInterval = 1 * time.Second
 
+	volumeAttachmentPersistentVolumeIndex = "persistentVolume"
+	podPersistentVolumeClaimIndex         = "persistentVolumeClaim"
+
+	// storageClassParameterNodeStageSecretName and storageClassParameterNodeStageSecretNamespace are the CSI
+	// parameters of a storage class locating the secret passed to the CSI node server to stage the volume
+	storageClassParameterNodeStageSecretName      = "csi.storage.k8s.io/node-stage-secret-name"
+	storageClassParameterNodeStageSecretNamespace = "csi.storage.k8s.io/node-stage-secret-namespace"
+
 	PodProbeInitialDelay             = 3
 	PodProbeTimeoutSeconds           = PodProbePeriodSeconds - 1
 	PodProbePeriodSeconds            = 5
@@ -375,6 +388,32 @@ func (s *DataStore) GetStorageClass(name string) (*storagev1.StorageClass, error
 	return resultRO.DeepCopy(), nil
 }
 
+// GetEncryptionSecretRefForStorageClass returns the secret of the encryption key set by the CSI node stage secret
+// parameters of the storage class. Nil is returned if the storage class does not exist, sets no secret, or templates
+// the secret by the PVC, which only the CSI provisioner resolves.
+func (s *DataStore) GetEncryptionSecretRefForStorageClass(storageClassName string) (*corev1.SecretReference, error) {
+	if storageClassName == "" {
+		return nil, nil
+	}
+	storageClass, err := s.GetStorageClassRO(storageClassName)
+	if err != nil {
+		if ErrorIsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	name := storageClass.Parameters[storageClassParameterNodeStageSecretName]
+	namespace := storageClass.Parameters[storageClassParameterNodeStageSecretNamespace]
+	if name == "" || namespace == "" || strings.Contains(name, "${") || strings.Contains(namespace, "${") {
+		return nil, nil
+	}
+	return &corev1.SecretReference{
+		Name:      name,
+		Namespace: namespace,
+	}, nil
+}
+
 // ListStorageClassesInPersistentVolumesWithLonghornProvisioner returns a list
 // of StorageClasses used by PersistenVolumes with provisioner "driver.longhorn.io".
 func (s *DataStore) ListStorageClassesInPersistentVolumesWithLonghornProvisioner() ([]string, error) {
@@ -449,6 +488,106 @@ func (s *DataStore) ListPodsByPersistentVolumeClaimName(claimName string, namesp
 	return matchedPods, nil
 }
 
+// ListPodsUsingVolume returns a list of pods in all namespaces whose
+// PersistentVolumeClaims are bound to a Longhorn PersistentVolume of the volume.
+func (s *DataStore) ListPodsUsingVolume(volumeName string) ([]*corev1.Pod, error) {
+	pvs, err := s.ListPersistentVolumesRO()
+	if err != nil {
+		return nil, err
+	}
+
+	matchedPods := []*corev1.Pod{}
+	matchedPodKeys := map[string]struct{}{}
+	for _, pv := range pvs {
+		if !IsLonghornPersistentVolume(pv) || pv.Spec.CSI.VolumeHandle != volumeName || pv.Spec.ClaimRef == nil {
+			continue
+		}
+
+		pvc, err := s.GetPersistentVolumeClaimRO(pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name)
+		if err != nil {
+			if ErrorIsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if pvc.Spec.VolumeName != pv.Name {
+			continue
+		}
+
+		pods, err := s.listPodsByPersistentVolumeClaimRO(pvc.Namespace, pvc.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods {
+			key := pod.Namespace + "/" + pod.Name
+			if _, ok := matchedPodKeys[key]; ok {
+				continue
+			}
+			matchedPodKeys[key] = struct{}{}
+			matchedPods = append(matchedPods, pod)
+		}
+	}
+
+	return matchedPods, nil
+}
+
+// listPodsByPersistentVolumeClaimRO gets a list of pods using the given PersistentVolumeClaim
+// This function returns direct reference to the internal cache object and should not be mutated.
+func (s *DataStore) listPodsByPersistentVolumeClaimRO(namespace, claimName string) ([]*corev1.Pod, error) {
+	if _, ok := s.PodInformer.GetIndexer().GetIndexers()[podPersistentVolumeClaimIndex]; !ok {
+		pods, err := s.ListPodsRO(namespace)
+		if err != nil {
+			return nil, err
+		}
+		result := []*corev1.Pod{}
+		for _, pod := range pods {
+			for _, volume := range pod.Spec.Volumes {
+				if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == claimName {
+					result = append(result, pod)
+					break
+				}
+			}
+		}
+		return result, nil
+	}
+
+	objs, err := s.PodInformer.GetIndexer().ByIndex(podPersistentVolumeClaimIndex, namespace+"/"+claimName)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*corev1.Pod, 0, len(objs))
+	for _, obj := range objs {
+		pod, ok := obj.(*corev1.Pod)
+		if !ok {
+			return nil, fmt.Errorf("BUG: invalid object type %T in Pod indexer", obj)
+		}
+		result = append(result, pod)
+	}
+	return result, nil
+}
+
+// addPodPersistentVolumeClaimIndexer indexes the pods by the namespaced names of the PersistentVolumeClaims they use
+func addPodPersistentVolumeClaimIndexer(informer cache.SharedIndexInformer) error {
+	if _, ok := informer.GetIndexer().GetIndexers()[podPersistentVolumeClaimIndex]; ok {
+		return nil
+	}
+	return informer.AddIndexers(cache.Indexers{
+		podPersistentVolumeClaimIndex: func(obj interface{}) ([]string, error) {
+			pod, ok := obj.(*corev1.Pod)
+			if !ok {
+				return []string{}, nil
+			}
+			keys := []string{}
+			for _, volume := range pod.Spec.Volumes {
+				if volume.PersistentVolumeClaim != nil {
+					keys = append(keys, pod.Namespace+"/"+volume.PersistentVolumeClaim.ClaimName)
+				}
+			}
+			return keys, nil
+		},
+	})
+}
+
 // GetPod returns a mutable Pod object for the given name and namespace
 func (s *DataStore) GetPod(name string) (*corev1.Pod, error) {
 	var pod *corev1.Pod
@@ -807,6 +946,12 @@ func (s *DataStore) ListPersistentVolumesRO() ([]*corev1.PersistentVolume, error
 	return s.persistentVolumeLister.List(labels.Everything())
 }
 
+// IsLonghornPersistentVolume returns whether the PersistentVolume is
+// provisioned by the Longhorn CSI driver.
+func IsLonghornPersistentVolume(pv *corev1.PersistentVolume) bool {
+	return pv.Spec.CSI != nil && pv.Spec.CSI.Driver == types.LonghornDriverName
+}
+
 // CreatePersistentVolumeClaim creates a PersistentVolumeClaim resource
 // for the given PersistentVolumeclaim object and namespace
 func (s *DataStore) CreatePersistentVolumeClaim(ns string, pvc *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
@@ -850,6 +995,55 @@ func (s *DataStore) ListVolumeAttachmentsRO() ([]*storagev1.VolumeAttachment, er
 	return s.volumeAttachmentLister.List(labels.Everything())
 }
 
+// ListVolumeAttachmentsByPersistentVolumeRO gets a list of volumeattachments of the given PersistentVolume
+// This function returns direct reference to the internal cache object and should not be mutated.
+// Consider using this function when you can guarantee read only access and don't want the overhead of deep copies
+func (s *DataStore) ListVolumeAttachmentsByPersistentVolumeRO(pvName string) ([]*storagev1.VolumeAttachment, error) {
+	if _, ok := s.VolumeAttachmentInformer.GetIndexer().GetIndexers()[volumeAttachmentPersistentVolumeIndex]; !ok {
+		kubeVAs, err := s.ListVolumeAttachmentsRO()
+		if err != nil {
+			return nil, err
+		}
+		result := []*storagev1.VolumeAttachment{}
+		for _, kubeVA := range kubeVAs {
+			if kubeVA.Spec.Source.PersistentVolumeName != nil && *kubeVA.Spec.Source.PersistentVolumeName == pvName {
+				result = append(result, kubeVA)
+			}
+		}
+		return result, nil
+	}
+
+	objs, err := s.VolumeAttachmentInformer.GetIndexer().ByIndex(volumeAttachmentPersistentVolumeIndex, pvName)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*storagev1.VolumeAttachment, 0, len(objs))
+	for _, obj := range objs {
+		kubeVA, ok := obj.(*storagev1.VolumeAttachment)
+		if !ok {
+			return nil, fmt.Errorf("BUG: invalid object type %T in VolumeAttachment indexer", obj)
+		}
+		result = append(result, kubeVA)
+	}
+	return result, nil
+}
+
+// addVolumeAttachmentPersistentVolumeIndexer indexes the volumeattachments by the PersistentVolume they attach
+func addVolumeAttachmentPersistentVolumeIndexer(informer cache.SharedIndexInformer) error {
+	if _, ok := informer.GetIndexer().GetIndexers()[volumeAttachmentPersistentVolumeIndex]; ok {
+		return nil
+	}
+	return informer.AddIndexers(cache.Indexers{
+		volumeAttachmentPersistentVolumeIndex: func(obj interface{}) ([]string, error) {
+			kubeVA, ok := obj.(*storagev1.VolumeAttachment)
+			if !ok || kubeVA.Spec.Source.PersistentVolumeName == nil {
+				return []string{}, nil
+			}
+			return []string{*kubeVA.Spec.Source.PersistentVolumeName}, nil
+		},
+	})
+}
+
 // CreateConfigMap creates a ConfigMap resource
 func (s *DataStore) CreateConfigMap(configMap *corev1.ConfigMap) (*corev1.ConfigMap, error) {
 	return s.kubeClient.CoreV1().ConfigMaps(s.namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
@@ -1088,8 +1282,97 @@ func (s *DataStore) GetKubernetesEndpointRO(name string) (*corev1.Endpoints, err
 	return s.endpointLister.Endpoints(s.namespace).Get(name)
 }
 
-// NewPVManifestForVolume returns a new PersistentVolume object for a longhorn volume
-func NewPVManifestForVolume(v *longhorn.Volume, pvName, storageClassName, fsType string) *corev1.PersistentVolume {
+// IsNodeDownPodDeletionDisabledForPV returns true if the CSI volume attributes of the PV opt its pods out of the force
+// deletion on down nodes.
+func IsNodeDownPodDeletionDisabledForPV(pv *corev1.PersistentVolume) bool {
+	if pv.Spec.CSI == nil {
+		return false
+	}
+	value, ok := pv.Spec.CSI.VolumeAttributes[types.PVVolumeAttributeDisableNodeDownPodDeletion]
+	if !ok {
+		return false
+	}
+	disabled, err := strconv.ParseBool(value)
+	if err != nil {
+		logrus.WithError(err).Warnf("Invalid volume attribute %v value %v of PV %v, ignoring it", types.PVVolumeAttributeDisableNodeDownPodDeletion, value, pv.Name)
+		return false
+	}
+	return disabled
+}
+
+// NewPVManifestForVolume returns a new PersistentVolume object for a longhorn volume, with the given mount options of
+// its filesystem, the given reclaim policy, Retain if empty, and the given annotations except the Longhorn reserved ones.
+// The PV of an encrypted volume references the given secret of the encryption key, if any.
+func NewPVManifestForVolume(v *longhorn.Volume, pvName, storageClassName, fsType string, mountOptions []string, reclaimPolicy corev1.PersistentVolumeReclaimPolicy, annotations map[string]string, encryptionSecretRef *corev1.SecretReference) *corev1.PersistentVolume {
+	volAttributes, accessMode := getPVAttributesAndAccessModeForVolume(v)
+	pv := NewPVManifest(v.Spec.Size, pvName, v.Name, storageClassName, fsType, volAttributes, accessMode)
+	if v.Spec.Encrypted && encryptionSecretRef != nil {
+		SetPVEncryptionSecretRef(pv, encryptionSecretRef.Name, encryptionSecretRef.Namespace)
+	}
+	pv.Spec.MountOptions = mountOptions
+	if reclaimPolicy != "" {
+		pv.Spec.PersistentVolumeReclaimPolicy = reclaimPolicy
+	}
+	pv.Annotations = filterPVManifestAnnotations(pvName, annotations)
+	return pv
+}
+
+// SetPVEncryptionSecretRef sets the secret of the encryption key of the encrypted volume of the PV, which is passed
+// to the CSI node server to stage and publish the volume
+func SetPVEncryptionSecretRef(pv *corev1.PersistentVolume, secretName, secretNamespace string) {
+	secretRef := &corev1.SecretReference{
+		Name:      secretName,
+		Namespace: secretNamespace,
+	}
+	pv.Spec.CSI.NodeStageSecretRef = secretRef
+	pv.Spec.CSI.NodePublishSecretRef = secretRef
+}
+
+// filterPVManifestAnnotations drops the annotations under the Longhorn prefix, like longhorn.io/ or
+// driver.longhorn.io/, which are reserved for Longhorn itself.
+func filterPVManifestAnnotations(pvName string, annotations map[string]string) map[string]string {
+	if len(annotations) == 0 {
+		return nil
+	}
+
+	filtered := map[string]string{}
+	for key, value := range annotations {
+		if isLonghornReservedKey(key) {
+			logrus.Warnf("Ignoring the Longhorn reserved annotation %v of PV %v", key, pvName)
+			continue
+		}
+		filtered[key] = value
+	}
+	return filtered
+}
+
+func isLonghornReservedKey(key string) bool {
+	prefix, _, found := strings.Cut(key, "/")
+	if !found {
+		return false
+	}
+	return prefix == types.LonghornLabelKeyPrefix || strings.HasSuffix(prefix, "."+types.LonghornLabelKeyPrefix)
+}
+
+// NewBlockPVManifestForVolume returns a new PersistentVolume object in the block volume mode for a longhorn volume,
+// which is used as a raw block device, so it has no filesystem type and rejects any mount option. The PV of an encrypted
+// volume references the given secret of the encryption key, if any.
+func NewBlockPVManifestForVolume(v *longhorn.Volume, pvName, storageClassName string, mountOptions []string, encryptionSecretRef *corev1.SecretReference) (*corev1.PersistentVolume, error) {
+	if len(mountOptions) > 0 {
+		return nil, fmt.Errorf("cannot set mount options %v for PV %v in the block volume mode", mountOptions, pvName)
+	}
+
+	volAttributes, accessMode := getPVAttributesAndAccessModeForVolume(v)
+	pv := NewPVManifest(v.Spec.Size, pvName, v.Name, storageClassName, "", volAttributes, accessMode)
+	if v.Spec.Encrypted && encryptionSecretRef != nil {
+		SetPVEncryptionSecretRef(pv, encryptionSecretRef.Name, encryptionSecretRef.Namespace)
+	}
+	blockVolumeMode := corev1.PersistentVolumeBlock
+	pv.Spec.VolumeMode = &blockVolumeMode
+	return pv, nil
+}
+
+func getPVAttributesAndAccessModeForVolume(v *longhorn.Volume) (map[string]string, corev1.PersistentVolumeAccessMode) {
 	diskSelector := strings.Join(v.Spec.DiskSelector, ",")
 	nodeSelector := strings.Join(v.Spec.NodeSelector, ",")
 
@@ -1100,10 +1383,29 @@ func NewPVManifestForVolume(v *longhorn.Volume, pvName, storageClassName, fsType
 		"staleReplicaTimeout": strconv.Itoa(v.Spec.StaleReplicaTimeout),
 	}
 
+	// Keep the data engine, otherwise a v2 volume restored from the PV would be created as a v1 one.
+	dataEngine := v.Spec.DataEngine
+	if dataEngine == "" {
+		dataEngine = longhorn.DataEngineTypeV1
+	}
+	volAttributes["dataEngine"] = string(dataEngine)
+
 	if v.Spec.Encrypted {
 		volAttributes["encrypted"] = strconv.FormatBool(v.Spec.Encrypted)
 	}
 
+	// Keep the trim behavior of the volume, so that the volume restored from the PV reclaims the space the same way.
+	if v.Spec.UnmapMarkSnapChainRemoved != "" {
+		volAttributes["unmapMarkSnapChainRemoved"] = string(v.Spec.UnmapMarkSnapChainRemoved)
+	}
+
+	// Keep the recurring jobs and groups of the volume in the same format as the recurringJobSelector parameter,
+	// so that they can be restored from the PV.
+	if recurringJobs := getVolumeRecurringJobsFromLabels(v.Labels); len(recurringJobs) > 0 {
+		recurringJobSelector, _ := json.Marshal(recurringJobs)
+		volAttributes["recurringJobSelector"] = string(recurringJobSelector)
+	}
+
 	accessMode := corev1.ReadWriteOnce
 	switch v.Spec.AccessMode {
 	case longhorn.AccessModeReadWriteMany:
@@ -1113,7 +1415,53 @@ func NewPVManifestForVolume(v *longhorn.Volume, pvName, storageClassName, fsType
 		accessMode = corev1.ReadWriteOncePod
 	}
 
-	return NewPVManifest(v.Spec.Size, pvName, v.Name, storageClassName, fsType, volAttributes, accessMode)
+	return volAttributes, accessMode
+}
+
+// GetPVVolumeAttributesDrift returns the sorted keys of the volume attributes of the PV that differ from, or miss,
+// the ones NewPVManifestForVolume generates for the volume. The other attributes of the PV, like the ones set from
+// the StorageClass parameters on the provisioning, are not part of the drift.
+func GetPVVolumeAttributesDrift(pv *corev1.PersistentVolume, v *longhorn.Volume) []string {
+	actual := map[string]string{}
+	if pv.Spec.CSI != nil {
+		actual = pv.Spec.CSI.VolumeAttributes
+	}
+	desired, _ := getPVAttributesAndAccessModeForVolume(v)
+
+	drifted := []string{}
+	for key, value := range desired {
+		if actualValue, ok := actual[key]; !ok || actualValue != value {
+			drifted = append(drifted, key)
+		}
+	}
+	sort.Strings(drifted)
+	return drifted
+}
+
+// getVolumeRecurringJobsFromLabels returns the enabled recurring jobs and groups of the volume labels, the jobs
+// first, sorted by name.
+func getVolumeRecurringJobsFromLabels(volumeLabels map[string]string) []longhorn.VolumeRecurringJob {
+	groupPrefix := types.GetRecurringJobLabelKey(types.LonghornLabelRecurringJobGroup, "")
+	jobPrefix := types.GetRecurringJobLabelKey(types.LonghornLabelRecurringJob, "")
+
+	recurringJobs := []longhorn.VolumeRecurringJob{}
+	for key, value := range volumeLabels {
+		if !types.IsRecurringJobLabel(key) || value != types.LonghornLabelValueEnabled {
+			continue
+		}
+		if strings.HasPrefix(key, groupPrefix) {
+			recurringJobs = append(recurringJobs, longhorn.VolumeRecurringJob{Name: strings.TrimPrefix(key, groupPrefix), IsGroup: true})
+		} else if strings.HasPrefix(key, jobPrefix) {
+			recurringJobs = append(recurringJobs, longhorn.VolumeRecurringJob{Name: strings.TrimPrefix(key, jobPrefix)})
+		}
+	}
+	sort.Slice(recurringJobs, func(i, j int) bool {
+		if recurringJobs[i].IsGroup != recurringJobs[j].IsGroup {
+			return !recurringJobs[i].IsGroup
+		}
+		return recurringJobs[i].Name < recurringJobs[j].Name
+	})
+	return recurringJobs
 }
 
 // NewPVManifest returns a new PersistentVolume object
@@ -1159,7 +1507,51 @@ func NewPVCManifestForVolume(v *longhorn.Volume, pvName, ns, pvcName, storageCla
 		accessMode = corev1.ReadWriteOncePod
 	}
 
-	return NewPVCManifest(v.Spec.Size, pvName, ns, pvcName, storageClassName, accessMode)
+	pvc := NewPVCManifest(getPVCStorageRequestRoundedDownForVolumeSize(v.Spec.Size), pvName, ns, pvcName, storageClassName, accessMode)
+	pvc.Labels = getPVCLabelsForVolume(v)
+	return pvc
+}
+
+// getPVCLabelsForVolume returns the labels of the PVC created for the volume, which are the user-defined labels of the
+// volume, without the Longhorn internal ones, and the label marking the PVC as managed by Longhorn.
+func getPVCLabelsForVolume(v *longhorn.Volume) map[string]string {
+	labels := map[string]string{
+		types.GetLonghornLabelKey(types.LonghornLabelManaged): "true",
+	}
+	for key, value := range v.Labels {
+		if key == types.LonghornLabelVolume || isLonghornReservedKey(key) {
+			continue
+		}
+		labels[key] = value
+	}
+	return labels
+}
+
+// getPVCStorageRequestRoundedDownForVolumeSize returns the storage request of the PVC of a volume rounded down to
+// whole Gi, or to whole Mi for the volumes smaller than 1Gi. The PVC is bound to the PV of the volume by name, and the
+// PV cannot be bound to a PVC requesting more than its capacity, so the request is rounded down rather than up while
+// the PV capacity keeps the exact size, which the PVC reports as its capacity once bound.
+func getPVCStorageRequestRoundedDownForVolumeSize(size int64) int64 {
+	for _, unit := range []int64{util.GiB, util.MiB} {
+		if size >= unit {
+			return size - size%unit
+		}
+	}
+	return size
+}
+
+// NewPVCManifestForVolumeWithStorageClass returns a new PersistentVolumeClaim object for a longhorn volume, after
+// verifying that the StorageClass exists and is provisioned by Longhorn, since a PVC of another class never binds
+// to the PV of the volume.
+func (s *DataStore) NewPVCManifestForVolumeWithStorageClass(v *longhorn.Volume, pvName, ns, pvcName, storageClassName string) (*corev1.PersistentVolumeClaim, error) {
+	storageClass, err := s.GetStorageClassRO(storageClassName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get StorageClass %v", storageClassName)
+	}
+	if storageClass.Provisioner != types.LonghornDriverName {
+		return nil, fmt.Errorf("StorageClass %v is provisioned by %v instead of %v", storageClassName, storageClass.Provisioner, types.LonghornDriverName)
+	}
+	return NewPVCManifestForVolume(v, pvName, ns, pvcName, storageClassName), nil
 }
 
 // NewPVCManifest returns a new PersistentVolumeClaim object
@@ -1382,3 +1774,66 @@ func (s *DataStore) GetRoleBinding(name string) (*rbacv1.RoleBinding, error) {
 func (s *DataStore) UpdateRoleBinding(roleBinding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error) {
 	return s.kubeClient.RbacV1().RoleBindings(s.namespace).Update(context.TODO(), roleBinding, metav1.UpdateOptions{})
 }
+
+// GetOwnerReferencesOfOwner returns the owner references of the object referred by the given
+// owner reference in the given namespace. It allows following the ownership chain of an object,
+// e.g., a pod created by a custom resource which is in turn owned by a StatefulSet.
+func (s *DataStore) GetOwnerReferencesOfOwner(namespace string, ownerRef metav1.OwnerReference) ([]metav1.OwnerReference, error) {
+	gv, err := schema.ParseGroupVersion(ownerRef.APIVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse API version of owner %v %v", ownerRef.Kind, ownerRef.Name)
+	}
+
+	var obj metav1.Object
+	switch gv.WithKind(ownerRef.Kind) {
+	case appsv1.SchemeGroupVersion.WithKind(types.KubernetesKindReplicaSet):
+		obj, err = s.kubeClient.AppsV1().ReplicaSets(namespace).Get(context.TODO(), ownerRef.Name, metav1.GetOptions{})
+	case appsv1.SchemeGroupVersion.WithKind(types.KubernetesKindStatefulSet):
+		obj, err = s.kubeClient.AppsV1().StatefulSets(namespace).Get(context.TODO(), ownerRef.Name, metav1.GetOptions{})
+	case appsv1.SchemeGroupVersion.WithKind(types.KubernetesKindDeployment):
+		obj, err = s.kubeClient.AppsV1().Deployments(namespace).Get(context.TODO(), ownerRef.Name, metav1.GetOptions{})
+	case appsv1.SchemeGroupVersion.WithKind(types.KubernetesKindDaemonSet):
+		obj, err = s.kubeClient.AppsV1().DaemonSets(namespace).Get(context.TODO(), ownerRef.Name, metav1.GetOptions{})
+	case batchv1.SchemeGroupVersion.WithKind(types.KubernetesKindJob):
+		obj, err = s.kubeClient.BatchV1().Jobs(namespace).Get(context.TODO(), ownerRef.Name, metav1.GetOptions{})
+	case batchv1.SchemeGroupVersion.WithKind(types.KubernetesKindCronJob):
+		obj, err = s.kubeClient.BatchV1().CronJobs(namespace).Get(context.TODO(), ownerRef.Name, metav1.GetOptions{})
+	default:
+		obj, err = s.getObjectMetadataByOwnerReference(namespace, gv, ownerRef)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The owner was recreated with the same name, so it is not the owner anymore.
+	if ownerRef.UID != "" && obj.GetUID() != ownerRef.UID {
+		return nil, apierrors.NewNotFound(gv.WithResource(strings.ToLower(ownerRef.Kind)).GroupResource(), ownerRef.Name)
+	}
+
+	return obj.GetOwnerReferences(), nil
+}
+
+// SetMetadataClient sets the client used to retrieve the metadata of objects of any kind,
+// including custom resources, e.g., the owners of a pod.
+func (s *DataStore) SetMetadataClient(metadataClient metadata.Interface) {
+	s.metadataClient = metadataClient
+}
+
+// getObjectMetadataByOwnerReference retrieves the metadata of an object of any kind, including
+// custom resources, from the API server. The resource of the kind is resolved by the cached
+// discovery information, which is refreshed only when the kind is unknown.
+func (s *DataStore) getObjectMetadataByOwnerReference(namespace string, gv schema.GroupVersion, ownerRef metav1.OwnerReference) (metav1.Object, error) {
+	if s.metadataClient == nil {
+		return nil, fmt.Errorf("cannot get %v %v without metadata client", ownerRef.Kind, ownerRef.Name)
+	}
+
+	mapping, err := s.restMapper.RESTMapping(gv.WithKind(ownerRef.Kind).GroupKind(), gv.Version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find resource of kind %v in API version %v", ownerRef.Kind, ownerRef.APIVersion)
+	}
+
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return s.metadataClient.Resource(mapping.Resource).Namespace(namespace).Get(context.TODO(), ownerRef.Name, metav1.GetOptions{})
+	}
+	return s.metadataClient.Resource(mapping.Resource).Get(context.TODO(), ownerRef.Name, metav1.GetOptions{})
+}
@@ -6,6 +6,13 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/longhorn/longhorn-manager/types"
+	"github.com/longhorn/longhorn-manager/util"
 
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 )
@@ -53,6 +60,212 @@ func TestNewPVCManifestForVolume(t *testing.T) {
 	}
 }
 
+func TestNewPVCManifestForVolumeLabels(t *testing.T) {
+	managedLabelKey := types.GetLonghornLabelKey(types.LonghornLabelManaged)
+
+	t.Run("user labels", func(t *testing.T) {
+		v := &longhorn.Volume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "vol-labels",
+				Labels: map[string]string{
+					"app":                     "database",
+					"team.example.com/owner":  "storage",
+					types.LonghornLabelVolume: "vol-labels",
+					types.GetLonghornLabelKey(types.LonghornLabelBackupTarget):                     "default",
+					types.GetRecurringJobLabelKey(types.LonghornLabelRecurringJobGroup, "default"): types.LonghornLabelValueEnabled,
+				},
+			},
+			Spec: longhorn.VolumeSpec{
+				Size:       1024 * 1024 * 1024, // 1Gi
+				AccessMode: longhorn.AccessModeReadWriteOnce,
+			},
+		}
+		pvc := NewPVCManifestForVolume(v, "pv-name", "default", "pvc-name", "longhorn")
+		assert.Equal(t, map[string]string{
+			"app":                    "database",
+			"team.example.com/owner": "storage",
+			managedLabelKey:          "true",
+		}, pvc.Labels)
+	})
+
+	t.Run("no labels", func(t *testing.T) {
+		v := &longhorn.Volume{
+			Spec: longhorn.VolumeSpec{
+				Size:       1024 * 1024 * 1024, // 1Gi
+				AccessMode: longhorn.AccessModeReadWriteOnce,
+			},
+		}
+		pvc := NewPVCManifestForVolume(v, "pv-name", "default", "pvc-name", "longhorn")
+		assert.Equal(t, map[string]string{managedLabelKey: "true"}, pvc.Labels)
+	})
+}
+
+func TestNewPVCManifestForVolumeStorageRequestRoundedDown(t *testing.T) {
+	tests := map[string]struct {
+		size            int64
+		expectedRequest string
+	}{
+		"gi aligned": {
+			size:            2 * util.GiB,
+			expectedRequest: "2Gi",
+		},
+		"not gi aligned rounded down": {
+			size:            util.GiB + 2*util.MiB,
+			expectedRequest: "1Gi",
+		},
+		"one byte above gi rounded down": {
+			size:            util.GiB + 1,
+			expectedRequest: "1Gi",
+		},
+		"below gi rounded down to mi": {
+			size:            512*util.MiB + 1,
+			expectedRequest: "512Mi",
+		},
+		"below mi": {
+			size:            512 * util.KiB,
+			expectedRequest: "512Ki",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := &longhorn.Volume{
+				Spec: longhorn.VolumeSpec{
+					Size:       tc.size,
+					AccessMode: longhorn.AccessModeReadWriteOnce,
+				},
+			}
+			pvc := NewPVCManifestForVolume(v, "pv-name", "default", "pvc-name", "longhorn")
+			require.NotNil(t, pvc)
+			request := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			assert.Equal(t, tc.expectedRequest, request.String())
+			assert.LessOrEqual(t, request.Value(), tc.size)
+
+			// The PV keeps the exact size of the volume.
+			pv := NewPVManifestForVolume(v, "pv-name", "longhorn", "ext4", nil, "", nil, nil)
+			capacity := pv.Spec.Capacity[corev1.ResourceStorage]
+			assert.Equal(t, tc.size, capacity.Value())
+		})
+	}
+}
+
+func TestGetEncryptionSecretRefForStorageClass(t *testing.T) {
+	newStorageClass := func(name string, parameters map[string]string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: name},
+			Provisioner: types.LonghornDriverName,
+			Parameters:  parameters,
+		}
+	}
+
+	kubeClient := kubefake.NewSimpleClientset() // nolint: staticcheck
+	storageClassInformer := informers.NewSharedInformerFactory(kubeClient, 0).Storage().V1().StorageClasses()
+	for _, storageClass := range []*storagev1.StorageClass{
+		newStorageClass("longhorn-crypto", map[string]string{
+			storageClassParameterNodeStageSecretName:      "test-crypto",
+			storageClassParameterNodeStageSecretNamespace: "test-crypto-namespace",
+		}),
+		newStorageClass("longhorn-crypto-per-volume", map[string]string{
+			storageClassParameterNodeStageSecretName:      "${pvc.name}",
+			storageClassParameterNodeStageSecretNamespace: "${pvc.namespace}",
+		}),
+		newStorageClass("longhorn", nil),
+	} {
+		require.NoError(t, storageClassInformer.Informer().GetIndexer().Add(storageClass))
+	}
+	ds := &DataStore{
+		storageclassLister: storageClassInformer.Lister(),
+	}
+
+	tests := map[string]struct {
+		storageClassName string
+
+		expected *corev1.SecretReference
+	}{
+		"storage class with the secret": {
+			storageClassName: "longhorn-crypto",
+			expected:         &corev1.SecretReference{Name: "test-crypto", Namespace: "test-crypto-namespace"},
+		},
+		"storage class templating the secret": {
+			storageClassName: "longhorn-crypto-per-volume",
+		},
+		"storage class without the secret": {
+			storageClassName: "longhorn",
+		},
+		"storage class not found": {
+			storageClassName: "missing",
+		},
+		"no storage class": {},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			secretRef, err := ds.GetEncryptionSecretRefForStorageClass(tc.storageClassName)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, secretRef)
+		})
+	}
+}
+
+func TestNewPVCManifestForVolumeWithStorageClass(t *testing.T) {
+	newStorageClass := func(name, provisioner string) *storagev1.StorageClass {
+		return &storagev1.StorageClass{
+			ObjectMeta:  metav1.ObjectMeta{Name: name},
+			Provisioner: provisioner,
+		}
+	}
+
+	kubeClient := kubefake.NewSimpleClientset() // nolint: staticcheck
+	storageClassInformer := informers.NewSharedInformerFactory(kubeClient, 0).Storage().V1().StorageClasses()
+	for _, storageClass := range []*storagev1.StorageClass{
+		newStorageClass("longhorn", types.LonghornDriverName),
+		newStorageClass("local-path", "rancher.io/local-path"),
+	} {
+		require.NoError(t, storageClassInformer.Informer().GetIndexer().Add(storageClass))
+	}
+	ds := &DataStore{
+		storageclassLister: storageClassInformer.Lister(),
+	}
+
+	v := &longhorn.Volume{
+		Spec: longhorn.VolumeSpec{
+			Size:       1024 * 1024 * 1024, // 1Gi
+			AccessMode: longhorn.AccessModeReadWriteOnce,
+		},
+	}
+
+	tests := map[string]struct {
+		storageClassName string
+		expectError      bool
+	}{
+		"longhorn storage class": {
+			storageClassName: "longhorn",
+		},
+		"storage class of another provisioner": {
+			storageClassName: "local-path",
+			expectError:      true,
+		},
+		"storage class not found": {
+			storageClassName: "missing",
+			expectError:      true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			pvc, err := ds.NewPVCManifestForVolumeWithStorageClass(v, "pv-name", "default", "pvc-name", tc.storageClassName)
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, pvc.Spec.StorageClassName)
+			assert.Equal(t, tc.storageClassName, *pvc.Spec.StorageClassName)
+			assert.Equal(t, "pv-name", pvc.Spec.VolumeName)
+		})
+	}
+}
+
 func TestNewPVManifestForVolumeAttributesAndAccessModes(t *testing.T) {
 	newVolume := func(mode longhorn.AccessMode, migratable, encrypted bool, replicas, srt int, diskSel, nodeSel []string) *longhorn.Volume {
 		return &longhorn.Volume{
@@ -71,7 +284,7 @@ func TestNewPVManifestForVolumeAttributesAndAccessModes(t *testing.T) {
 
 	t.Run("rwop volume manifest attributes", func(t *testing.T) {
 		v := newVolume(longhorn.AccessModeReadWriteOncePod, false, true, 3, 2880, []string{"ssd"}, []string{"fast"})
-		pv := NewPVManifestForVolume(v, "pv-rwop", "longhorn", "ext4")
+		pv := NewPVManifestForVolume(v, "pv-rwop", "longhorn", "ext4", nil, "", nil, nil)
 		require.NotNil(t, pv)
 		assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOncePod}, pv.Spec.AccessModes)
 		attrs := pv.Spec.CSI.VolumeAttributes
@@ -87,7 +300,7 @@ func TestNewPVManifestForVolumeAttributesAndAccessModes(t *testing.T) {
 
 	t.Run("rwx volume manifest attributes", func(t *testing.T) {
 		v := newVolume(longhorn.AccessModeReadWriteMany, true, false, 2, 1440, []string{"nvme", "hot"}, []string{"zone-a"})
-		pv := NewPVManifestForVolume(v, "pv-rwx", "longhorn", "ext4")
+		pv := NewPVManifestForVolume(v, "pv-rwx", "longhorn", "ext4", nil, "", nil, nil)
 		require.NotNil(t, pv)
 		assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, pv.Spec.AccessModes)
 		attrs := pv.Spec.CSI.VolumeAttributes
@@ -99,5 +312,350 @@ func TestNewPVManifestForVolumeAttributesAndAccessModes(t *testing.T) {
 		assert.Equal(t, "true", attrs["migratable"])
 		_, hasEncrypted := attrs["encrypted"]
 		assert.False(t, hasEncrypted)
+		_, hasRecurringJobSelector := attrs["recurringJobSelector"]
+		assert.False(t, hasRecurringJobSelector)
+	})
+
+	t.Run("encryption secret references", func(t *testing.T) {
+		secretRef := &corev1.SecretReference{
+			Name:      "test-crypto",
+			Namespace: "test-crypto-namespace",
+		}
+		v := newVolume(longhorn.AccessModeReadWriteOnce, false, true, 3, 2880, nil, nil)
+		pv := NewPVManifestForVolume(v, "pv-encrypted", "longhorn", "ext4", nil, "", nil, secretRef)
+		assert.Equal(t, secretRef, pv.Spec.CSI.NodeStageSecretRef)
+		assert.Equal(t, secretRef, pv.Spec.CSI.NodePublishSecretRef)
+
+		// The secret of an encrypted volume is not guessed if unknown.
+		pv = NewPVManifestForVolume(v, "pv-encrypted-unknown-secret", "longhorn", "ext4", nil, "", nil, nil)
+		assert.Nil(t, pv.Spec.CSI.NodeStageSecretRef)
+		assert.Nil(t, pv.Spec.CSI.NodePublishSecretRef)
+
+		v = newVolume(longhorn.AccessModeReadWriteOnce, false, false, 3, 2880, nil, nil)
+		pv = NewPVManifestForVolume(v, "pv-not-encrypted", "longhorn", "ext4", nil, "", nil, secretRef)
+		assert.Nil(t, pv.Spec.CSI.NodeStageSecretRef)
+		assert.Nil(t, pv.Spec.CSI.NodePublishSecretRef)
+	})
+
+	t.Run("volume with recurring jobs and groups", func(t *testing.T) {
+		v := newVolume(longhorn.AccessModeReadWriteOnce, false, false, 3, 2880, nil, nil)
+		v.Labels = map[string]string{
+			types.GetRecurringJobLabelKey(types.LonghornLabelRecurringJobGroup, "default"): types.LonghornLabelValueEnabled,
+			types.GetRecurringJobLabelKey(types.LonghornLabelRecurringJobGroup, "backup"):  types.LonghornLabelValueEnabled,
+			types.GetRecurringJobLabelKey(types.LonghornLabelRecurringJob, "snapshot"):     types.LonghornLabelValueEnabled,
+			types.GetRecurringJobSourceLabelKey():                                          "volume",
+			types.LonghornLabelVolume:                                                      "test-volume",
+		}
+		pv := NewPVManifestForVolume(v, "pv-recurring-jobs", "longhorn", "ext4", nil, "", nil, nil)
+		require.NotNil(t, pv)
+		assert.Equal(t, `[{"name":"snapshot","isGroup":false},{"name":"backup","isGroup":true},{"name":"default","isGroup":true}]`,
+			pv.Spec.CSI.VolumeAttributes["recurringJobSelector"])
+	})
+
+	t.Run("volume data engine", func(t *testing.T) {
+		for dataEngine, expected := range map[longhorn.DataEngineType]string{
+			"":                        string(longhorn.DataEngineTypeV1),
+			longhorn.DataEngineTypeV1: string(longhorn.DataEngineTypeV1),
+			longhorn.DataEngineTypeV2: string(longhorn.DataEngineTypeV2),
+		} {
+			v := newVolume(longhorn.AccessModeReadWriteOnce, false, false, 3, 2880, nil, nil)
+			v.Spec.DataEngine = dataEngine
+			pv := NewPVManifestForVolume(v, "pv-data-engine", "longhorn", "ext4", nil, "", nil, nil)
+			require.NotNil(t, pv)
+			assert.Equal(t, expected, pv.Spec.CSI.VolumeAttributes["dataEngine"], "data engine %q", dataEngine)
+		}
+	})
+
+	t.Run("volume unmap mark snapshot chain removed", func(t *testing.T) {
+		for unmap, expected := range map[longhorn.UnmapMarkSnapChainRemoved]string{
+			longhorn.UnmapMarkSnapChainRemovedIgnored:  "ignored",
+			longhorn.UnmapMarkSnapChainRemovedEnabled:  "enabled",
+			longhorn.UnmapMarkSnapChainRemovedDisabled: "disabled",
+		} {
+			v := newVolume(longhorn.AccessModeReadWriteOnce, false, false, 3, 2880, nil, nil)
+			v.Spec.UnmapMarkSnapChainRemoved = unmap
+			pv := NewPVManifestForVolume(v, "pv-unmap", "longhorn", "ext4", nil, "", nil, nil)
+			require.NotNil(t, pv)
+			assert.Equal(t, expected, pv.Spec.CSI.VolumeAttributes["unmapMarkSnapChainRemoved"], "unmap %q", unmap)
+		}
+
+		v := newVolume(longhorn.AccessModeReadWriteOnce, false, false, 3, 2880, nil, nil)
+		pv := NewPVManifestForVolume(v, "pv-no-unmap", "longhorn", "ext4", nil, "", nil, nil)
+		require.NotNil(t, pv)
+		_, hasUnmap := pv.Spec.CSI.VolumeAttributes["unmapMarkSnapChainRemoved"]
+		assert.False(t, hasUnmap)
+	})
+
+	t.Run("volume without labels", func(t *testing.T) {
+		v := newVolume(longhorn.AccessModeReadWriteOnce, false, false, 3, 2880, nil, nil)
+		v.Labels = nil
+		pv := NewPVManifestForVolume(v, "pv-no-labels", "longhorn", "ext4", nil, "", nil, nil)
+		require.NotNil(t, pv)
+		_, hasRecurringJobSelector := pv.Spec.CSI.VolumeAttributes["recurringJobSelector"]
+		assert.False(t, hasRecurringJobSelector)
+		assert.Empty(t, pv.Annotations)
+	})
+
+	t.Run("volume mount options", func(t *testing.T) {
+		v := newVolume(longhorn.AccessModeReadWriteOnce, false, false, 3, 2880, nil, nil)
+		pv := NewPVManifestForVolume(v, "pv-mount-options", "longhorn", "ext4", []string{"noatime", "discard", "commit=30"}, "", nil, nil)
+		require.NotNil(t, pv)
+		assert.Equal(t, []string{"noatime", "discard", "commit=30"}, pv.Spec.MountOptions)
+		assert.Equal(t, "ext4", pv.Spec.CSI.FSType)
+
+		pv = NewPVManifestForVolume(v, "pv-no-mount-options", "longhorn", "ext4", nil, "", nil, nil)
+		require.NotNil(t, pv)
+		assert.Empty(t, pv.Spec.MountOptions)
+	})
+
+	t.Run("volume reclaim policy", func(t *testing.T) {
+		for reclaimPolicy, expected := range map[corev1.PersistentVolumeReclaimPolicy]corev1.PersistentVolumeReclaimPolicy{
+			"":                                   corev1.PersistentVolumeReclaimRetain,
+			corev1.PersistentVolumeReclaimRetain: corev1.PersistentVolumeReclaimRetain,
+			corev1.PersistentVolumeReclaimDelete: corev1.PersistentVolumeReclaimDelete,
+		} {
+			v := newVolume(longhorn.AccessModeReadWriteOnce, false, false, 3, 2880, nil, nil)
+			pv := NewPVManifestForVolume(v, "pv-reclaim-policy", "longhorn", "ext4", nil, reclaimPolicy, nil, nil)
+			require.NotNil(t, pv)
+			assert.Equal(t, expected, pv.Spec.PersistentVolumeReclaimPolicy, "reclaim policy %q", reclaimPolicy)
+		}
+	})
+
+	t.Run("volume annotations", func(t *testing.T) {
+		v := newVolume(longhorn.AccessModeReadWriteOnce, false, false, 3, 2880, nil, nil)
+		pv := NewPVManifestForVolume(v, "pv-annotations", "longhorn", "ext4", nil, "", map[string]string{
+			"backup.example.com/policy":           "daily",
+			"prometheus.io/scrape":                "true",
+			"plain":                               "value",
+			"longhorn.io/volume-scheduling-error": "overwritten",
+			"node.longhorn.io/node":               "overwritten",
+			"pv.kubernetes.io/provisioned-by":     types.LonghornDriverName,
+			"notlonghorn.io/annotation":           "kept",
+			"example.com/longhorn.io":             "kept",
+		}, nil)
+		require.NotNil(t, pv)
+		assert.Equal(t, map[string]string{
+			"backup.example.com/policy":       "daily",
+			"prometheus.io/scrape":            "true",
+			"plain":                           "value",
+			"pv.kubernetes.io/provisioned-by": types.LonghornDriverName,
+			"notlonghorn.io/annotation":       "kept",
+			"example.com/longhorn.io":         "kept",
+		}, pv.Annotations)
 	})
 }
+
+func TestNewBlockPVManifestForVolume(t *testing.T) {
+	v := &longhorn.Volume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "vol-block",
+		},
+		Spec: longhorn.VolumeSpec{
+			Size:                2 * 1024 * 1024 * 1024, // 2Gi
+			AccessMode:          longhorn.AccessModeReadWriteMany,
+			Migratable:          true,
+			Encrypted:           true,
+			NumberOfReplicas:    3,
+			StaleReplicaTimeout: 2880,
+		},
+	}
+
+	secretRef := &corev1.SecretReference{
+		Name:      "test-crypto",
+		Namespace: "test-crypto-namespace",
+	}
+	pv, err := NewBlockPVManifestForVolume(v, "pv-block", "longhorn", nil, secretRef)
+	require.NoError(t, err)
+	require.NotNil(t, pv)
+	assert.Empty(t, pv.Spec.MountOptions)
+	require.NotNil(t, pv.Spec.VolumeMode)
+	assert.Equal(t, corev1.PersistentVolumeBlock, *pv.Spec.VolumeMode)
+	assert.Equal(t, []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}, pv.Spec.AccessModes)
+	assert.Equal(t, "longhorn", pv.Spec.StorageClassName)
+	require.NotNil(t, pv.Spec.CSI)
+	assert.Equal(t, "vol-block", pv.Spec.CSI.VolumeHandle)
+	assert.Empty(t, pv.Spec.CSI.FSType)
+	attrs := pv.Spec.CSI.VolumeAttributes
+	require.NotNil(t, attrs)
+	assert.Equal(t, "3", attrs["numberOfReplicas"])
+	assert.Equal(t, "true", attrs["encrypted"])
+	assert.Equal(t, "true", attrs["migratable"])
+	_, hasFSType := attrs["fsType"]
+	assert.False(t, hasFSType)
+	assert.Equal(t, secretRef, pv.Spec.CSI.NodeStageSecretRef)
+	assert.Equal(t, secretRef, pv.Spec.CSI.NodePublishSecretRef)
+
+	// the filesystem manifest of the same volume is not affected
+	pv = NewPVManifestForVolume(v, "pv-filesystem", "longhorn", "ext4", nil, "", nil, nil)
+	require.NotNil(t, pv.Spec.VolumeMode)
+	assert.Equal(t, corev1.PersistentVolumeFilesystem, *pv.Spec.VolumeMode)
+	assert.Equal(t, "ext4", pv.Spec.CSI.FSType)
+
+	// the block volume mode has no filesystem to mount with options
+	_, err = NewBlockPVManifestForVolume(v, "pv-block", "longhorn", []string{"noatime"}, nil)
+	assert.Error(t, err)
+}
+
+func TestGetPVVolumeAttributesDrift(t *testing.T) {
+	newVolume := func() *longhorn.Volume {
+		return &longhorn.Volume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "vol-drift",
+			},
+			Spec: longhorn.VolumeSpec{
+				Size:                2 * 1024 * 1024 * 1024, // 2Gi
+				AccessMode:          longhorn.AccessModeReadWriteOnce,
+				NumberOfReplicas:    3,
+				StaleReplicaTimeout: 2880,
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		updateVolume func(v *longhorn.Volume)
+		updatePV     func(pv *corev1.PersistentVolume)
+		expected     []string
+	}{
+		"matching": {
+			expected: []string{},
+		},
+		"matching with provisioner attributes": {
+			updatePV: func(pv *corev1.PersistentVolume) {
+				pv.Spec.CSI.VolumeAttributes["fsType"] = "ext4"
+				pv.Spec.CSI.VolumeAttributes["storage.kubernetes.io/csiProvisionerIdentity"] = "1700000000000-8081-driver.longhorn.io"
+			},
+			expected: []string{},
+		},
+		"replica count changed on the volume": {
+			updateVolume: func(v *longhorn.Volume) {
+				v.Spec.NumberOfReplicas = 2
+			},
+			expected: []string{"numberOfReplicas"},
+		},
+		"attributes changed on the volume": {
+			updateVolume: func(v *longhorn.Volume) {
+				v.Spec.StaleReplicaTimeout = 30
+				v.Spec.DiskSelector = []string{"ssd"}
+			},
+			expected: []string{"diskSelector", "staleReplicaTimeout"},
+		},
+		"attribute missing from the pv": {
+			updatePV: func(pv *corev1.PersistentVolume) {
+				delete(pv.Spec.CSI.VolumeAttributes, "dataEngine")
+			},
+			expected: []string{"dataEngine"},
+		},
+		"pv without csi source": {
+			updatePV: func(pv *corev1.PersistentVolume) {
+				pv.Spec.CSI = nil
+			},
+			expected: []string{"dataEngine", "diskSelector", "nodeSelector", "numberOfReplicas", "staleReplicaTimeout"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			v := newVolume()
+			pv := NewPVManifestForVolume(v, "pv-drift", "longhorn", "ext4", nil, "", nil, nil)
+			if tc.updatePV != nil {
+				tc.updatePV(pv)
+			}
+			if tc.updateVolume != nil {
+				tc.updateVolume(v)
+			}
+
+			assert.Equal(t, tc.expected, GetPVVolumeAttributesDrift(pv, v))
+		})
+	}
+}
+
+func TestListPodsUsingVolume(t *testing.T) {
+	const testVolumeName = "test-volume"
+
+	newPV := func(name, driver, volumeHandle, claimName string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{
+						Driver:       driver,
+						VolumeHandle: volumeHandle,
+					},
+				},
+				ClaimRef: &corev1.ObjectReference{Namespace: "default", Name: claimName},
+			},
+		}
+	}
+	newPVC := func(namespace, name, pvName string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: pvName},
+		}
+	}
+	newPod := func(namespace, name string, claimNames ...string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+		for _, claimName := range claimNames {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+				Name: claimName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+				},
+			})
+		}
+		return pod
+	}
+
+	kubeClient := kubefake.NewSimpleClientset() // nolint: staticcheck
+	informerFactory := informers.NewSharedInformerFactory(kubeClient, 0)
+	pvInformer := informerFactory.Core().V1().PersistentVolumes()
+	pvcInformer := informerFactory.Core().V1().PersistentVolumeClaims()
+	podInformer := informerFactory.Core().V1().Pods()
+	require.NoError(t, addPodPersistentVolumeClaimIndexer(podInformer.Informer()))
+	for _, pv := range []*corev1.PersistentVolume{
+		newPV("longhorn-pv", types.LonghornDriverName, testVolumeName, "longhorn-claim"),
+		newPV("other-longhorn-pv", types.LonghornDriverName, "other-volume", "other-longhorn-claim"),
+		// A PV of another driver with the same volume handle is not of the volume.
+		newPV("other-driver-pv", "other.csi.example.com", testVolumeName, "other-driver-claim"),
+		// A released PV still referencing a claim that is now bound to another PV.
+		newPV("released-longhorn-pv", types.LonghornDriverName, testVolumeName, "other-longhorn-claim"),
+	} {
+		require.NoError(t, pvInformer.Informer().GetIndexer().Add(pv))
+	}
+	for _, pvc := range []*corev1.PersistentVolumeClaim{
+		newPVC("default", "longhorn-claim", "longhorn-pv"),
+		newPVC("default", "other-longhorn-claim", "other-longhorn-pv"),
+		newPVC("default", "other-driver-claim", "other-driver-pv"),
+	} {
+		require.NoError(t, pvcInformer.Informer().GetIndexer().Add(pvc))
+	}
+	for _, pod := range []*corev1.Pod{
+		newPod("default", "pod-1", "longhorn-claim"),
+		newPod("default", "pod-2", "other-driver-claim", "longhorn-claim"),
+		newPod("default", "pod-3", "other-longhorn-claim"),
+		newPod("default", "pod-4", "other-driver-claim"),
+		newPod("default", "pod-5", "missing-claim"),
+		newPod("default", "pod-6"),
+		// The claim of the same name in another namespace is not bound to the PV.
+		newPod("other", "pod-7", "longhorn-claim"),
+	} {
+		require.NoError(t, podInformer.Informer().GetIndexer().Add(pod))
+	}
+	ds := &DataStore{
+		persistentVolumeLister:      pvInformer.Lister(),
+		persistentVolumeClaimLister: pvcInformer.Lister(),
+		podLister:                   podInformer.Lister(),
+		PodInformer:                 podInformer.Informer(),
+	}
+
+	pods, err := ds.ListPodsUsingVolume(testVolumeName)
+	require.NoError(t, err)
+	podNames := []string{}
+	for _, pod := range pods {
+		podNames = append(podNames, pod.Namespace+"/"+pod.Name)
+	}
+	assert.ElementsMatch(t, []string{"default/pod-1", "default/pod-2"}, podNames)
+
+	pods, err = ds.ListPodsUsingVolume("missing-volume")
+	require.NoError(t, err)
+	assert.Empty(t, pods)
+}
@@ -1086,6 +1086,25 @@ func (s *DataStore) GetAutoBalancedReplicasSetting(volume *longhorn.Volume, logg
 	return setting
 }
 
+// GetReplicaAutoBalanceDiskPressurePercentage returns the disk usage percentage above which the replicas of the
+// volume are moved away from the disk, by the ReplicaAutoBalanceDiskPressurePercentage of the volume, or by the
+// replica-auto-balance-disk-pressure-percentage setting if the volume does not set it. 0 means disabled.
+func (s *DataStore) GetReplicaAutoBalanceDiskPressurePercentage(volume *longhorn.Volume) (int64, error) {
+	if volume.Spec.ReplicaAutoBalanceDiskPressurePercentage > 0 {
+		return volume.Spec.ReplicaAutoBalanceDiskPressurePercentage, nil
+	}
+	return s.GetSettingAsInt(types.SettingNameReplicaAutoBalanceDiskPressurePercentage)
+}
+
+// IsVolumeReplicaRebuildingEnabled returns whether Longhorn rebuilds the failed replicas of the volume, by the
+// ReplicaRebuilding of the volume, or by the replica rebuilding setting if the volume ignores it.
+func (s *DataStore) IsVolumeReplicaRebuildingEnabled(volume *longhorn.Volume) (bool, error) {
+	if volume.Spec.ReplicaRebuilding != "" && volume.Spec.ReplicaRebuilding != longhorn.VolumeReplicaRebuildingIgnored {
+		return volume.Spec.ReplicaRebuilding == longhorn.VolumeReplicaRebuildingEnabled, nil
+	}
+	return s.GetSettingAsBool(types.SettingNameReplicaRebuilding)
+}
+
 func (s *DataStore) GetVolumeSnapshotDataIntegrity(volumeName string) (longhorn.SnapshotDataIntegrity, error) {
 	volume, err := s.GetVolumeRO(volumeName)
 	if err != nil {
@@ -1338,6 +1357,22 @@ func (s *DataStore) GetVolume(name string) (*longhorn.Volume, error) {
 	return resultRO.DeepCopy(), nil
 }
 
+// GetVolumeLastHealthyTime returns the time the volume last became healthy, or the zero time if the volume has never been healthy
+func (s *DataStore) GetVolumeLastHealthyTime(name string) (time.Time, error) {
+	v, err := s.GetVolumeRO(name)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if v.Status.LastHealthyAt == "" {
+		return time.Time{}, nil
+	}
+	lastHealthyAt, err := util.ParseTime(v.Status.LastHealthyAt)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(err, "failed to parse last healthy timestamp %v of volume %v", v.Status.LastHealthyAt, name)
+	}
+	return lastHealthyAt, nil
+}
+
 // ListVolumesRO returns a list of all Volumes for the given namespace
 func (s *DataStore) ListVolumesRO() ([]*longhorn.Volume, error) {
 	return s.volumeLister.Volumes(s.namespace).List(labels.Everything())
@@ -3368,26 +3403,112 @@ func (s *DataStore) IsNodeDownOrDeletedOrMissingManager(name string) (bool, erro
 	return false, nil
 }
 
+// NodeReadinessState tells a node temporarily not ready apart from a node gone for good.
+type NodeReadinessState string
+
+const (
+	NodeReadinessStateReady = NodeReadinessState("Ready")
+	// NodeReadinessStateNotReady is a node whose Kubernetes node is not ready, which may come back.
+	NodeReadinessStateNotReady = NodeReadinessState("NotReady")
+	// NodeReadinessStateUnknown is a node whose Ready condition is unknown, or false for a reason other than its
+	// Kubernetes node, like a missing manager pod, so the node itself may still be up.
+	NodeReadinessStateUnknown = NodeReadinessState("Unknown")
+	// NodeReadinessStateDeleted is a node whose Longhorn node or Kubernetes node is deleted.
+	NodeReadinessStateDeleted = NodeReadinessState("Deleted")
+)
+
+// NodeReadiness is the readiness of a node with the Ready condition it is decided from. The reason and the
+// timestamps are empty if the Longhorn node is deleted.
+type NodeReadiness struct {
+	State              NodeReadinessState
+	Reason             string
+	LastProbeTime      string
+	LastTransitionTime string
+}
+
+// GetNodeReadiness gets Node for the given name and returns its readiness
+func (s *DataStore) GetNodeReadiness(name string) (*NodeReadiness, error) {
+	if name == "" {
+		return nil, errors.New("no node name provided to get node readiness")
+	}
+	node, err := s.GetNodeRO(name)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return &NodeReadiness{State: NodeReadinessStateDeleted}, nil
+		}
+		return nil, err
+	}
+	return GetNodeReadiness(node), nil
+}
+
+// GetNodeReadiness returns the readiness of the node by its Ready condition
+func GetNodeReadiness(node *longhorn.Node) *NodeReadiness {
+	cond := types.GetCondition(node.Status.Conditions, longhorn.NodeConditionTypeReady)
+	readiness := &NodeReadiness{
+		State:              NodeReadinessStateUnknown,
+		Reason:             cond.Reason,
+		LastProbeTime:      cond.LastProbeTime,
+		LastTransitionTime: cond.LastTransitionTime,
+	}
+	switch cond.Status {
+	case longhorn.ConditionStatusTrue:
+		readiness.State = NodeReadinessStateReady
+	case longhorn.ConditionStatusFalse:
+		switch cond.Reason {
+		case string(longhorn.NodeConditionReasonKubernetesNodeGone):
+			readiness.State = NodeReadinessStateDeleted
+		case string(longhorn.NodeConditionReasonKubernetesNodeNotReady):
+			readiness.State = NodeReadinessStateNotReady
+		}
+	}
+	return readiness
+}
+
 // IsNodeDownOrDeleted gets Node for the given name and namespace and checks
 // if the Node condition is gone or not ready
 func (s *DataStore) IsNodeDownOrDeleted(name string) (bool, error) {
 	if name == "" {
 		return false, errors.New("no node name provided to check node down or deleted")
 	}
-	node, err := s.GetNodeRO(name)
+	readiness, err := s.GetNodeReadiness(name)
 	if err != nil {
-		if apierrors.IsNotFound(err) {
-			return true, nil
-		}
 		return false, err
 	}
-	cond := types.GetCondition(node.Status.Conditions, longhorn.NodeConditionTypeReady)
-	if cond.Status == longhorn.ConditionStatusFalse &&
-		(cond.Reason == string(longhorn.NodeConditionReasonKubernetesNodeGone) ||
-			cond.Reason == string(longhorn.NodeConditionReasonKubernetesNodeNotReady)) {
-		return true, nil
+	if readiness.State != NodeReadinessStateNotReady {
+		return isNodeReadinessDown(readiness), nil
 	}
-	return false, nil
+	gracePeriod, err := s.GetSettingAsInt(types.SettingNameNodeNotReadyGracePeriod)
+	if err != nil {
+		return false, err
+	}
+	return isNodeReadinessDownAfterGracePeriod(readiness, time.Duration(gracePeriod)*time.Second, time.Now()), nil
+}
+
+// IsNodeDown checks if the Ready condition of the node reports the Kubernetes node gone or not ready
+func IsNodeDown(node *longhorn.Node) bool {
+	return isNodeReadinessDown(GetNodeReadiness(node))
+}
+
+func isNodeReadinessDown(readiness *NodeReadiness) bool {
+	return readiness.State == NodeReadinessStateNotReady || readiness.State == NodeReadinessStateDeleted
+}
+
+// isNodeReadinessDownAfterGracePeriod only reports a not ready node down once it has been continuously not ready
+// for the grace period, counted from the last transition of its Ready condition, so that a brief network blip does
+// not make the node down. A deleted node is down right away.
+func isNodeReadinessDownAfterGracePeriod(readiness *NodeReadiness, gracePeriod time.Duration, now time.Time) bool {
+	if !isNodeReadinessDown(readiness) {
+		return false
+	}
+	if readiness.State != NodeReadinessStateNotReady || gracePeriod <= 0 {
+		return true
+	}
+	notReadySince, err := util.ParseTime(readiness.LastTransitionTime)
+	if err != nil {
+		// Without a valid transition time, the grace period cannot be counted, so the node is down as before.
+		return true
+	}
+	return !now.Before(notReadySince.Add(gracePeriod))
 }
 
 // IsNodeDelinquent checks an early-warning condition of Lease expiration
@@ -6740,11 +6861,13 @@ func (s *DataStore) GetFreezeFilesystemForSnapshotSetting(e *longhorn.Engine) (b
 		return false, err
 	}
 
-	if volume.Spec.FreezeFilesystemForSnapshot != longhorn.FreezeFilesystemForSnapshotDefault {
-		return volume.Spec.FreezeFilesystemForSnapshot == longhorn.FreezeFilesystemForSnapshotEnabled, nil
+	settingEnabled := false
+	if volume.Spec.FreezeFilesystemForSnapshot == longhorn.FreezeFilesystemForSnapshotDefault {
+		if settingEnabled, err = s.GetSettingAsBoolByDataEngine(types.SettingNameFreezeFilesystemForSnapshot, e.Spec.DataEngine); err != nil {
+			return false, err
+		}
 	}
-
-	return s.GetSettingAsBoolByDataEngine(types.SettingNameFreezeFilesystemForSnapshot, e.Spec.DataEngine)
+	return types.IsFreezeFilesystemForSnapshotEnabled(volume.Spec.FreezeFilesystemForSnapshot, settingEnabled), nil
 }
 
 func (s *DataStore) CanPutBackingImageOnDisk(backingImage *longhorn.BackingImage, diskUUID string) (bool, error) {
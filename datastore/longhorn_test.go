@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,6 +14,8 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/longhorn/longhorn-manager/types"
+
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 	lhfake "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned/fake"
 	lhinformerfactory "github.com/longhorn/longhorn-manager/k8s/pkg/client/informers/externalversions"
@@ -204,3 +207,202 @@ func TestCreateReplica(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNodeReadiness(t *testing.T) {
+	const (
+		testNamespace = "longhorn-system"
+		testNodeID    = "test-node"
+	)
+
+	newNode := func(status longhorn.ConditionStatus, reason string) *longhorn.Node {
+		return &longhorn.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testNodeID,
+				Namespace: testNamespace,
+			},
+			Status: longhorn.NodeStatus{
+				Conditions: []longhorn.Condition{
+					{
+						Type:               longhorn.NodeConditionTypeReady,
+						Status:             status,
+						Reason:             reason,
+						LastProbeTime:      "2026-01-01T00:00:00Z",
+						LastTransitionTime: "2026-01-01T00:01:00Z",
+					},
+				},
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		node *longhorn.Node
+
+		expectedState NodeReadinessState
+		expectedDown  bool
+	}{
+		"ready": {
+			node:          newNode(longhorn.ConditionStatusTrue, ""),
+			expectedState: NodeReadinessStateReady,
+		},
+		"kubernetes node not ready": {
+			node:          newNode(longhorn.ConditionStatusFalse, string(longhorn.NodeConditionReasonKubernetesNodeNotReady)),
+			expectedState: NodeReadinessStateNotReady,
+			expectedDown:  true,
+		},
+		"kubernetes node gone": {
+			node:          newNode(longhorn.ConditionStatusFalse, string(longhorn.NodeConditionReasonKubernetesNodeGone)),
+			expectedState: NodeReadinessStateDeleted,
+			expectedDown:  true,
+		},
+		"manager pod missing": {
+			node:          newNode(longhorn.ConditionStatusFalse, string(longhorn.NodeConditionReasonManagerPodMissing)),
+			expectedState: NodeReadinessStateUnknown,
+		},
+		"condition unknown": {
+			node:          newNode(longhorn.ConditionStatusUnknown, ""),
+			expectedState: NodeReadinessStateUnknown,
+		},
+		"longhorn node deleted": {
+			expectedState: NodeReadinessStateDeleted,
+			expectedDown:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lhClient := lhfake.NewSimpleClientset() // nolint: staticcheck
+			informerFactory := lhinformerfactory.NewSharedInformerFactory(lhClient, 0)
+			nodeInformer := informerFactory.Longhorn().V1beta2().Nodes()
+			ds := &DataStore{
+				namespace:     testNamespace,
+				lhClient:      lhClient,
+				nodeLister:    nodeInformer.Lister(),
+				settingLister: informerFactory.Longhorn().V1beta2().Settings().Lister(),
+			}
+			if tc.node != nil {
+				require.NoError(t, nodeInformer.Informer().GetIndexer().Add(tc.node))
+			}
+
+			readiness, err := ds.GetNodeReadiness(testNodeID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedState, readiness.State)
+			if tc.node != nil {
+				assert.Equal(t, "2026-01-01T00:00:00Z", readiness.LastProbeTime)
+				assert.Equal(t, "2026-01-01T00:01:00Z", readiness.LastTransitionTime)
+			} else {
+				assert.Empty(t, readiness.LastTransitionTime)
+			}
+
+			down, err := ds.IsNodeDownOrDeleted(testNodeID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedDown, down)
+		})
+	}
+
+	ds := &DataStore{}
+	_, err := ds.GetNodeReadiness("")
+	assert.Error(t, err)
+}
+
+func TestIsNodeDownOrDeletedWithGracePeriod(t *testing.T) {
+	const (
+		testNamespace   = "longhorn-system"
+		testNodeID      = "test-node"
+		testGracePeriod = 60 * time.Second
+	)
+
+	now := time.Now()
+	newNotReadyNode := func(reason string, notReadySince time.Time) *longhorn.Node {
+		return &longhorn.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      testNodeID,
+				Namespace: testNamespace,
+			},
+			Status: longhorn.NodeStatus{
+				Conditions: []longhorn.Condition{
+					{
+						Type:               longhorn.NodeConditionTypeReady,
+						Status:             longhorn.ConditionStatusFalse,
+						Reason:             reason,
+						LastTransitionTime: notReadySince.UTC().Format(time.RFC3339),
+					},
+				},
+			},
+		}
+	}
+
+	testCases := map[string]struct {
+		node        *longhorn.Node
+		gracePeriod string
+
+		expectedDown bool
+	}{
+		"not ready without grace period": {
+			node:         newNotReadyNode(longhorn.NodeConditionReasonKubernetesNodeNotReady, now.Add(-time.Second)),
+			gracePeriod:  "0",
+			expectedDown: true,
+		},
+		"not ready just inside grace period": {
+			node:        newNotReadyNode(longhorn.NodeConditionReasonKubernetesNodeNotReady, now.Add(-testGracePeriod+5*time.Second)),
+			gracePeriod: "60",
+		},
+		"not ready just outside grace period": {
+			node:         newNotReadyNode(longhorn.NodeConditionReasonKubernetesNodeNotReady, now.Add(-testGracePeriod-5*time.Second)),
+			gracePeriod:  "60",
+			expectedDown: true,
+		},
+		"kubernetes node gone inside grace period": {
+			node:         newNotReadyNode(longhorn.NodeConditionReasonKubernetesNodeGone, now.Add(-time.Second)),
+			gracePeriod:  "60",
+			expectedDown: true,
+		},
+		"longhorn node deleted": {
+			gracePeriod:  "60",
+			expectedDown: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lhClient := lhfake.NewSimpleClientset() // nolint: staticcheck
+			informerFactory := lhinformerfactory.NewSharedInformerFactory(lhClient, 0)
+			nodeInformer := informerFactory.Longhorn().V1beta2().Nodes()
+			settingInformer := informerFactory.Longhorn().V1beta2().Settings()
+			ds := &DataStore{
+				namespace:     testNamespace,
+				lhClient:      lhClient,
+				nodeLister:    nodeInformer.Lister(),
+				settingLister: settingInformer.Lister(),
+			}
+			if tc.node != nil {
+				require.NoError(t, nodeInformer.Informer().GetIndexer().Add(tc.node))
+			}
+			require.NoError(t, settingInformer.Informer().GetIndexer().Add(&longhorn.Setting{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      string(types.SettingNameNodeNotReadyGracePeriod),
+					Namespace: testNamespace,
+				},
+				Value: tc.gracePeriod,
+			}))
+
+			down, err := ds.IsNodeDownOrDeleted(testNodeID)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedDown, down)
+		})
+	}
+}
+
+func TestIsNodeReadinessDownAfterGracePeriod(t *testing.T) {
+	notReadySince := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	readiness := &NodeReadiness{
+		State:              NodeReadinessStateNotReady,
+		LastTransitionTime: notReadySince.Format(time.RFC3339),
+	}
+
+	assert.False(t, isNodeReadinessDownAfterGracePeriod(readiness, time.Minute, notReadySince.Add(time.Minute-time.Second)))
+	assert.True(t, isNodeReadinessDownAfterGracePeriod(readiness, time.Minute, notReadySince.Add(time.Minute)))
+	assert.True(t, isNodeReadinessDownAfterGracePeriod(readiness, 0, notReadySince))
+	assert.True(t, isNodeReadinessDownAfterGracePeriod(&NodeReadiness{State: NodeReadinessStateNotReady}, time.Minute, notReadySince),
+		"a not ready node without a transition time is down as before")
+	assert.False(t, isNodeReadinessDownAfterGracePeriod(&NodeReadiness{State: NodeReadinessStateReady}, time.Minute, notReadySince))
+}
@@ -3,6 +3,7 @@ package datastore
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -420,3 +421,103 @@ func (s *DataStore) GetAllLonghornBackingImages() (runtime.Object, error) {
 func (s *DataStore) GetAllLonghornBackupTargets() (runtime.Object, error) {
 	return s.lhClient.LonghornV1beta2().BackupTargets(s.namespace).List(context.TODO(), metav1.ListOptions{})
 }
+
+// PodVolumeMapping is the mapping of a pod volume to the Longhorn volume backing it through the PVC and the PV.
+type PodVolumeMapping struct {
+	Namespace string
+	Pod       string
+	NodeID    string
+	PVC       string
+	PV        string
+	Volume    string
+	// VolumeFound is false when the Longhorn volume of the PV does not exist.
+	VolumeFound bool
+}
+
+// ListPodVolumeMappingsUncached rebuilds the pod->PVC->PV->Longhorn volume mappings of all the pods backed by
+// Longhorn volumes directly from the API server, so that they can be compared with the view of the informer caches.
+// Direct retrieval from the API server should only be used for one-shot tasks, like troubleshooting.
+func (s *DataStore) ListPodVolumeMappingsUncached() ([]PodVolumeMapping, error) {
+	podList, err := s.kubeClient.CoreV1().Pods("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pvcList, err := s.kubeClient.CoreV1().PersistentVolumeClaims("").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	pvList, err := s.kubeClient.CoreV1().PersistentVolumes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	volumeList, err := s.lhClient.LonghornV1beta2().Volumes(s.namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return BuildPodVolumeMappings(podList.Items, pvcList.Items, pvList.Items, volumeList.Items), nil
+}
+
+// BuildPodVolumeMappings maps the volumes of the pods to the Longhorn volumes through the bound PVCs and the PVs
+// provisioned by the Longhorn CSI driver. The pod volumes not backed by Longhorn are skipped. The mappings are
+// sorted by namespace, pod and PVC.
+func BuildPodVolumeMappings(pods []corev1.Pod, pvcs []corev1.PersistentVolumeClaim, pvs []corev1.PersistentVolume, volumes []longhorn.Volume) []PodVolumeMapping {
+	pvcMap := map[string]*corev1.PersistentVolumeClaim{}
+	for i := range pvcs {
+		pvcMap[pvcs[i].Namespace+"/"+pvcs[i].Name] = &pvcs[i]
+	}
+	pvMap := map[string]*corev1.PersistentVolume{}
+	for i := range pvs {
+		pvMap[pvs[i].Name] = &pvs[i]
+	}
+	volumeSet := map[string]struct{}{}
+	for _, volume := range volumes {
+		volumeSet[volume.Name] = struct{}{}
+	}
+
+	mappings := []PodVolumeMapping{}
+	for _, pod := range pods {
+		for _, podVolume := range pod.Spec.Volumes {
+			var claimName string
+			switch {
+			case podVolume.PersistentVolumeClaim != nil:
+				claimName = podVolume.PersistentVolumeClaim.ClaimName
+			case podVolume.Ephemeral != nil:
+				// The PVC of a generic ephemeral volume is named after the pod and the volume.
+				claimName = pod.Name + "-" + podVolume.Name
+			default:
+				continue
+			}
+
+			pvc, ok := pvcMap[pod.Namespace+"/"+claimName]
+			if !ok || pvc.Spec.VolumeName == "" {
+				continue
+			}
+			pv, ok := pvMap[pvc.Spec.VolumeName]
+			if !ok || pv.Spec.CSI == nil || pv.Spec.CSI.Driver != types.LonghornDriverName {
+				continue
+			}
+
+			_, volumeFound := volumeSet[pv.Spec.CSI.VolumeHandle]
+			mappings = append(mappings, PodVolumeMapping{
+				Namespace:   pod.Namespace,
+				Pod:         pod.Name,
+				NodeID:      pod.Spec.NodeName,
+				PVC:         pvc.Name,
+				PV:          pv.Name,
+				Volume:      pv.Spec.CSI.VolumeHandle,
+				VolumeFound: volumeFound,
+			})
+		}
+	}
+
+	sort.Slice(mappings, func(i, j int) bool {
+		if mappings[i].Namespace != mappings[j].Namespace {
+			return mappings[i].Namespace < mappings[j].Namespace
+		}
+		if mappings[i].Pod != mappings[j].Pod {
+			return mappings[i].Pod < mappings[j].Pod
+		}
+		return mappings[i].PVC < mappings[j].PVC
+	})
+	return mappings
+}
@@ -0,0 +1,98 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/longhorn/longhorn-manager/types"
+
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+	lhfake "github.com/longhorn/longhorn-manager/k8s/pkg/client/clientset/versioned/fake"
+)
+
+func TestListPodVolumeMappingsUncached(t *testing.T) {
+	const testNamespace = "longhorn-system"
+
+	newPod := func(namespace, name, nodeID string, volumes ...corev1.Volume) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       corev1.PodSpec{NodeName: nodeID, Volumes: volumes},
+		}
+	}
+	newClaimVolume := func(name, claimName string) corev1.Volume {
+		return corev1.Volume{
+			Name: name,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+			},
+		}
+	}
+	newPVC := func(namespace, name, pvName string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: pvName},
+		}
+	}
+	newPV := func(name, driver, volumeHandle string) *corev1.PersistentVolume {
+		return &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PersistentVolumeSpec{
+				PersistentVolumeSource: corev1.PersistentVolumeSource{
+					CSI: &corev1.CSIPersistentVolumeSource{Driver: driver, VolumeHandle: volumeHandle},
+				},
+			},
+		}
+	}
+
+	kubeClient := kubefake.NewSimpleClientset(
+		// a pod with a Longhorn volume, a volume of another driver and an unbound claim
+		newPod("app", "pod-b", "node-1",
+			newClaimVolume("data", "pvc-data"),
+			newClaimVolume("other", "pvc-other"),
+			newClaimVolume("pending", "pvc-pending"),
+			corev1.Volume{Name: "config", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		),
+		// a pod with a generic ephemeral volume
+		newPod("app", "pod-a", "node-2",
+			corev1.Volume{Name: "scratch", VolumeSource: corev1.VolumeSource{Ephemeral: &corev1.EphemeralVolumeSource{}}},
+		),
+		// a pod with a missing claim and a claim of a missing Longhorn volume
+		newPod("other", "pod-c", "node-1",
+			newClaimVolume("missing", "pvc-missing"),
+			newClaimVolume("orphan", "pvc-orphan"),
+		),
+		newPVC("app", "pvc-data", "pv-data"),
+		newPVC("app", "pvc-other", "pv-other"),
+		newPVC("app", "pvc-pending", ""),
+		newPVC("app", "pod-a-scratch", "pv-scratch"),
+		newPVC("other", "pvc-orphan", "pv-orphan"),
+		newPV("pv-data", types.LonghornDriverName, "vol-data"),
+		newPV("pv-other", "other.csi.example.com", "vol-other"),
+		newPV("pv-scratch", types.LonghornDriverName, "vol-scratch"),
+		newPV("pv-orphan", types.LonghornDriverName, "vol-orphan"),
+	)
+	lhClient := lhfake.NewSimpleClientset(
+		&longhorn.Volume{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "vol-data"}},
+		&longhorn.Volume{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "vol-scratch"}},
+	)
+
+	ds := &DataStore{
+		namespace:  testNamespace,
+		kubeClient: kubeClient,
+		lhClient:   lhClient,
+	}
+
+	mappings, err := ds.ListPodVolumeMappingsUncached()
+	require.NoError(t, err)
+	assert.Equal(t, []PodVolumeMapping{
+		{Namespace: "app", Pod: "pod-a", NodeID: "node-2", PVC: "pod-a-scratch", PV: "pv-scratch", Volume: "vol-scratch", VolumeFound: true},
+		{Namespace: "app", Pod: "pod-b", NodeID: "node-1", PVC: "pvc-data", PV: "pv-data", Volume: "vol-data", VolumeFound: true},
+		{Namespace: "other", Pod: "pod-c", NodeID: "node-1", PVC: "pvc-orphan", PV: "pv-orphan", Volume: "vol-orphan", VolumeFound: false},
+	}, mappings)
+}
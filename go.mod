@@ -58,6 +58,7 @@ replace (
 require (
 	github.com/cockroachdb/errors v1.12.0
 	github.com/container-storage-interface/spec v1.12.0
+	github.com/distribution/reference v0.6.0
 	github.com/docker/go-connections v0.6.0
 	github.com/go-co-op/gocron v1.37.0
 	github.com/google/uuid v1.6.0
@@ -77,6 +78,7 @@ require (
 	github.com/longhorn/longhorn-share-manager v1.9.2
 	github.com/longhorn/longhorn-spdk-engine v0.0.0-20251211073105-08609c16d3d1
 	github.com/prometheus/client_golang v1.23.2
+	github.com/prometheus/client_model v0.6.2
 	// dynamiclistener v0.7.1 has nil pointer dereference issues, so temporarily pin to v0.7.0
 	github.com/rancher/dynamiclistener v0.7.3
 	github.com/rancher/go-rancher v0.1.1-0.20220412083059-ff12399dd57b
@@ -116,7 +118,6 @@ require (
 	github.com/cockroachdb/redact v1.1.5 // indirect
 	github.com/coreos/go-systemd/v22 v22.6.0 // indirect
 	github.com/distatus/battery v0.11.0 // indirect
-	github.com/distribution/reference v0.6.0 // indirect
 	github.com/dolthub/maphash v0.1.0 // indirect
 	github.com/ebitengine/purego v0.9.1 // indirect
 	github.com/fxamacker/cbor/v2 v2.9.0 // indirect
@@ -186,7 +187,6 @@ require (
 	github.com/opencontainers/go-digest v1.0.0 // indirect
 	github.com/pierrec/lz4/v4 v4.1.22 // indirect
 	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
-	github.com/prometheus/client_model v0.6.2 // indirect
 	github.com/prometheus/common v0.66.1 // indirect
 	github.com/prometheus/procfs v0.16.1 // indirect
 	github.com/rancher/lasso v0.2.5 // indirect
@@ -88,6 +88,16 @@ type SnapshotCheckStatus struct {
 	LastPeriodicCheckedAt metav1.Time `json:"lastPeriodicCheckedAt"`
 }
 
+// NodeDownPodDeletionStatus reports the handling of the terminating pods on the node while it is down.
+type NodeDownPodDeletionStatus struct {
+	// Whether the node is considered down by the manager.
+	// +optional
+	Down bool `json:"down"`
+	// The number of the terminating pods on the node eligible for the force deletion by the node down pod deletion policy.
+	// +optional
+	PendingPodForceDeletions int `json:"pendingPodForceDeletions"`
+}
+
 type HealthDataSource string
 
 const (
@@ -235,6 +245,8 @@ type NodeStatus struct {
 	SnapshotCheckStatus SnapshotCheckStatus `json:"snapshotCheckStatus"`
 	// +optional
 	AutoEvicting bool `json:"autoEvicting"`
+	// +optional
+	NodeDownPodDeletion NodeDownPodDeletionStatus `json:"nodeDownPodDeletion"`
 }
 
 // +genclient
@@ -99,6 +99,14 @@ const (
 	VolumeOfflineRebuildingIgnored  = VolumeOfflineRebuilding("ignored")
 )
 
+type VolumeReplicaRebuilding string
+
+const (
+	VolumeReplicaRebuildingEnabled  = VolumeReplicaRebuilding("enabled")
+	VolumeReplicaRebuildingDisabled = VolumeReplicaRebuilding("disabled")
+	VolumeReplicaRebuildingIgnored  = VolumeReplicaRebuilding("ignored")
+)
+
 type VolumeCloneState string
 
 const (
@@ -128,6 +136,7 @@ const (
 	VolumeConditionTypeTooManySnapshots    = "TooManySnapshots"
 	VolumeConditionTypeWaitForBackingImage = "WaitForBackingImage"
 	VolumeConditionTypeOfflineRebuilding   = "OfflineRebuilding"
+	VolumeConditionTypeIdle                = "Idle"
 )
 
 const (
@@ -139,6 +148,7 @@ const (
 	VolumeConditionReasonWaitForBackingImageFailed     = "GetBackingImageFailed"
 	VolumeConditionReasonWaitForBackingImageWaiting    = "Waiting"
 	VolumeConditionReasonOfflineRebuildingInProgress   = "OfflineRebuildingInProgress"
+	VolumeConditionReasonNoConsumer                    = "NoConsumer"
 )
 
 type SnapshotDataIntegrity string
@@ -186,13 +196,40 @@ const (
 	ReplicaDiskSoftAntiAffinityDisabled = ReplicaDiskSoftAntiAffinity("disabled")
 )
 
-// +kubebuilder:validation:Enum=ignored;enabled;disabled
+// +kubebuilder:validation:Enum=ignored;enabled;disabled;app-managed
 type FreezeFilesystemForSnapshot string
 
 const (
 	FreezeFilesystemForSnapshotDefault  = FreezeFilesystemForSnapshot("ignored")
 	FreezeFilesystemForSnapshotEnabled  = FreezeFilesystemForSnapshot("enabled")
 	FreezeFilesystemForSnapshotDisabled = FreezeFilesystemForSnapshot("disabled")
+	// FreezeFilesystemForSnapshotAppManaged never freezes the filesystem, since the application quiesces itself
+	// before the snapshots by its own hook.
+	FreezeFilesystemForSnapshotAppManaged = FreezeFilesystemForSnapshot("app-managed")
+)
+
+// +kubebuilder:validation:Enum=retain;delete
+type BackingImageCleanupPolicy string
+
+const (
+	BackingImageCleanupPolicyRetain = BackingImageCleanupPolicy("retain")
+	BackingImageCleanupPolicyDelete = BackingImageCleanupPolicy("delete")
+)
+
+// +kubebuilder:validation:Enum=block;prune
+type SnapshotMaxSizeAction string
+
+const (
+	SnapshotMaxSizeActionBlock = SnapshotMaxSizeAction("block")
+	SnapshotMaxSizeActionPrune = SnapshotMaxSizeAction("prune")
+)
+
+// +kubebuilder:validation:Enum=skip;prune
+type RecurringSnapshotOverflowAction string
+
+const (
+	RecurringSnapshotOverflowActionSkip  = RecurringSnapshotOverflowAction("skip")
+	RecurringSnapshotOverflowActionPrune = RecurringSnapshotOverflowAction("prune")
 )
 
 type DataEngineType string
@@ -239,6 +276,10 @@ type VolumeSpec struct {
 	// +kubebuilder:validation:Type=string
 	// +optional
 	Size int64 `json:"size,string"`
+	// MaxSize is the maximum size in bytes the volume can be expanded to. 0 means no limit.
+	// +kubebuilder:validation:Type=string
+	// +optional
+	MaxSize int64 `json:"maxSize,string"`
 	// +optional
 	Frontend VolumeFrontend `json:"frontend"`
 	// ublkQueueDepth controls the depth of each queue for ublk frontend.
@@ -263,13 +304,27 @@ type VolumeSpec struct {
 	NodeID string `json:"nodeID"`
 	// +optional
 	MigrationNodeID string `json:"migrationNodeID"`
+	// MigrationTargetNodeID is the node preferred as the migration target of a migratable volume, when several
+	// nodes request the volume at the same time. Empty means no preference.
+	// +optional
+	MigrationTargetNodeID string `json:"migrationTargetNodeID"`
 	// +optional
 	Image string `json:"image"`
 	// +optional
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="BackingImage is immutable"
 	BackingImage string `json:"backingImage"`
+	// Specifies whether the backing image is deleted once the last volume referencing it is deleted.
+	// - retain: Keep the backing image after the last referencing volume is deleted.
+	// - delete: Delete the backing image when the last referencing volume is deleted.
+	// +optional
+	BackingImageCleanupPolicy BackingImageCleanupPolicy `json:"backingImageCleanupPolicy"`
 	// +optional
 	Standby bool `json:"Standby"`
+	// StandbyRestoreInterval is the interval in seconds at which a standby volume polls the backup target for new backups.
+	// 0 means following the poll interval of the backup target.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StandbyRestoreInterval int `json:"standbyRestoreInterval"`
 	// +optional
 	DiskSelector []string `json:"diskSelector"`
 	// +optional
@@ -293,6 +348,11 @@ type VolumeSpec struct {
 	LastAttachedBy string `json:"lastAttachedBy"`
 	// +optional
 	AccessMode AccessMode `json:"accessMode"`
+	// AutoDowngradeFromRWX allows Longhorn to downgrade the access mode of the volume from rwx to rwo,
+	// once the volume has had a single consumer for a sustained period and is detached.
+	// Only a volume claimed by a StatefulSet volume claim template is downgraded.
+	// +optional
+	AutoDowngradeFromRWX bool `json:"autoDowngradeFromRWX"`
 	// +optional
 	Migratable bool `json:"migratable"`
 	// +optional
@@ -302,9 +362,20 @@ type VolumeSpec struct {
 	NumberOfReplicas int `json:"numberOfReplicas"`
 	// +optional
 	ReplicaAutoBalance ReplicaAutoBalance `json:"replicaAutoBalance"`
+	// ReplicaAutoBalanceDiskPressurePercentage is the disk usage percentage above which the best-effort auto-balance
+	// moves the replicas of this volume away from the disk. 0 means the replica-auto-balance-disk-pressure-percentage
+	// setting is used.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	ReplicaAutoBalanceDiskPressurePercentage int64 `json:"replicaAutoBalanceDiskPressurePercentage"`
 	// +kubebuilder:validation:Enum=ignored;disabled;enabled;fast-check
 	// +optional
 	SnapshotDataIntegrity SnapshotDataIntegrity `json:"snapshotDataIntegrity"`
+	// SnapshotDataIntegrityCronJob is the unix-cron schedule of the data integrity check of the snapshots of this volume.
+	// Empty means the snapshot-data-integrity-cronjob setting is used.
+	// +optional
+	SnapshotDataIntegrityCronJob string `json:"snapshotDataIntegrityCronJob"`
 	// +kubebuilder:validation:Enum=none;lz4;gzip
 	// +optional
 	BackupCompressionMethod BackupCompressionMethod `json:"backupCompressionMethod"`
@@ -316,11 +387,39 @@ type VolumeSpec struct {
 	// +kubebuilder:validation:Enum=v1;v2
 	// +optional
 	DataEngine DataEngineType `json:"dataEngine"`
+	// DataEngineLogLevel raises the log level of the v2 data engine serving this volume, for debugging a specific volume.
+	// Supported values are: Error, Warning, Notice, Info, and Debug. Empty means the data-engine-log-level setting is used.
+	// +optional
+	DataEngineLogLevel string `json:"dataEngineLogLevel"`
+	// InstanceManagerImage pins the instance manager image of a v2 data engine volume.
+	// Empty means the default instance manager image is used.
+	// Only an image of a running instance manager can be pinned, and it cannot be changed after the volume is created.
+	// +optional
+	InstanceManagerImage string `json:"instanceManagerImage"`
+	// EngineImagePullSecret is the name of a secret in the Longhorn namespace to pull the images of the volume from a private registry.
+	// It is referenced by the instance manager pods created on the nodes of the engine and replicas of the volume.
+	// +optional
+	EngineImagePullSecret string `json:"engineImagePullSecret"`
 	// +optional
 	SnapshotMaxCount int `json:"snapshotMaxCount"`
 	// +kubebuilder:validation:Type=string
 	// +optional
 	SnapshotMaxSize int64 `json:"snapshotMaxSize,string"`
+	// Specifies what happens to a new snapshot once the snapshot space usage reaches SnapshotMaxSize.
+	// - block: Fail the new snapshot.
+	// - prune: Delete the oldest snapshots to make room for the new snapshot.
+	// +optional
+	SnapshotMaxSizeAction SnapshotMaxSizeAction `json:"snapshotMaxSizeAction"`
+	// Specifies what a recurring snapshot job does once the snapshot count of the volume reaches SnapshotMaxCount.
+	// Empty leaves the new snapshot to fail.
+	// - skip: Skip the new snapshot.
+	// - prune: Delete the oldest snapshots of the recurring job to make room for the new snapshot.
+	// +optional
+	RecurringSnapshotOverflowAction RecurringSnapshotOverflowAction `json:"recurringSnapshotOverflowAction"`
+	// SnapshotReclaimThreshold is the snapshot space usage at which Longhorn reclaims snapshot space of the volume.
+	// It is either a percentage of the volume size, like "80%", or a size in bytes. Empty means no threshold.
+	// +optional
+	SnapshotReclaimThreshold string `json:"snapshotReclaimThreshold"`
 	// Setting that freezes the filesystem on the root partition before a snapshot is created.
 	// +optional
 	FreezeFilesystemForSnapshot FreezeFilesystemForSnapshot `json:"freezeFilesystemForSnapshot"`
@@ -334,6 +433,14 @@ type VolumeSpec struct {
 	// - disabled: Disable offline rebuilding for this volume, regardless of the global setting
 	// +optional
 	OfflineRebuilding VolumeOfflineRebuilding `json:"offlineRebuilding"`
+	// +kubebuilder:validation:Enum=ignored;disabled;enabled
+	// Specifies whether Longhorn should rebuild the failed replicas of the volume.
+	// - ignored: Use the global setting for replica rebuilding.
+	// - enabled: Enable replica rebuilding for this volume, regardless of the global setting.
+	// - disabled: Disable replica rebuilding for this volume, regardless of the global setting.
+	// When disabled, Longhorn still rebuilds a replica if the volume is left with a single healthy replica, for example after its other replicas failed on down nodes.
+	// +optional
+	ReplicaRebuilding VolumeReplicaRebuilding `json:"replicaRebuilding"`
 	// ReplicaRebuildingBandwidthLimit controls the maximum write bandwidth (in megabytes per second) allowed on the destination replica during the rebuilding process. Set this value to 0 to disable bandwidth limiting.
 	// +kubebuilder:validation:Minimum=0
 	// +optional
@@ -382,10 +489,17 @@ type VolumeStatus struct {
 	ActualSize int64 `json:"actualSize"`
 	// +optional
 	LastDegradedAt string `json:"lastDegradedAt"`
+	// LastHealthyAt is the time the volume last became healthy. It is empty if the volume has never been healthy.
+	// +optional
+	LastHealthyAt string `json:"lastHealthyAt"`
 	// +optional
 	ShareEndpoint string `json:"shareEndpoint"`
 	// +optional
 	ShareState ShareManagerState `json:"shareState"`
+	// SingleConsumerSince is the time since when the rwx volume has been used by a single consumer.
+	// It is used by the rwx access mode auto downgrade.
+	// +optional
+	SingleConsumerSince string `json:"singleConsumerSince"`
 }
 
 // +genclient
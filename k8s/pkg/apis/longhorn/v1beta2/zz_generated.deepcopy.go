@@ -1734,6 +1734,22 @@ func (in *Node) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeDownPodDeletionStatus) DeepCopyInto(out *NodeDownPodDeletionStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeDownPodDeletionStatus.
+func (in *NodeDownPodDeletionStatus) DeepCopy() *NodeDownPodDeletionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeDownPodDeletionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeList) DeepCopyInto(out *NodeList) {
 	*out = *in
@@ -1819,6 +1835,7 @@ func (in *NodeStatus) DeepCopyInto(out *NodeStatus) {
 		}
 	}
 	in.SnapshotCheckStatus.DeepCopyInto(&out.SnapshotCheckStatus)
+	out.NodeDownPodDeletion = in.NodeDownPodDeletion
 	return
 }
 
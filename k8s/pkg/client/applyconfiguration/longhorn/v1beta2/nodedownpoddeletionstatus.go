@@ -0,0 +1,48 @@
+/*
+Copyright The Longhorn Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by applyconfiguration-gen. DO NOT EDIT.
+
+package v1beta2
+
+// NodeDownPodDeletionStatusApplyConfiguration represents a declarative configuration of the NodeDownPodDeletionStatus type for use
+// with apply.
+type NodeDownPodDeletionStatusApplyConfiguration struct {
+	Down                     *bool `json:"down,omitempty"`
+	PendingPodForceDeletions *int  `json:"pendingPodForceDeletions,omitempty"`
+}
+
+// NodeDownPodDeletionStatusApplyConfiguration constructs a declarative configuration of the NodeDownPodDeletionStatus type for use with
+// apply.
+func NodeDownPodDeletionStatus() *NodeDownPodDeletionStatusApplyConfiguration {
+	return &NodeDownPodDeletionStatusApplyConfiguration{}
+}
+
+// WithDown sets the Down field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the Down field is set to the value of the last call.
+func (b *NodeDownPodDeletionStatusApplyConfiguration) WithDown(value bool) *NodeDownPodDeletionStatusApplyConfiguration {
+	b.Down = &value
+	return b
+}
+
+// WithPendingPodForceDeletions sets the PendingPodForceDeletions field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the PendingPodForceDeletions field is set to the value of the last call.
+func (b *NodeDownPodDeletionStatusApplyConfiguration) WithPendingPodForceDeletions(value int) *NodeDownPodDeletionStatusApplyConfiguration {
+	b.PendingPodForceDeletions = &value
+	return b
+}
@@ -25,12 +25,13 @@ import (
 // NodeStatusApplyConfiguration represents a declarative configuration of the NodeStatus type for use
 // with apply.
 type NodeStatusApplyConfiguration struct {
-	Conditions          []ConditionApplyConfiguration          `json:"conditions,omitempty"`
-	DiskStatus          map[string]*longhornv1beta2.DiskStatus `json:"diskStatus,omitempty"`
-	Region              *string                                `json:"region,omitempty"`
-	Zone                *string                                `json:"zone,omitempty"`
-	SnapshotCheckStatus *SnapshotCheckStatusApplyConfiguration `json:"snapshotCheckStatus,omitempty"`
-	AutoEvicting        *bool                                  `json:"autoEvicting,omitempty"`
+	Conditions          []ConditionApplyConfiguration                `json:"conditions,omitempty"`
+	DiskStatus          map[string]*longhornv1beta2.DiskStatus       `json:"diskStatus,omitempty"`
+	Region              *string                                      `json:"region,omitempty"`
+	Zone                *string                                      `json:"zone,omitempty"`
+	SnapshotCheckStatus *SnapshotCheckStatusApplyConfiguration       `json:"snapshotCheckStatus,omitempty"`
+	AutoEvicting        *bool                                        `json:"autoEvicting,omitempty"`
+	NodeDownPodDeletion *NodeDownPodDeletionStatusApplyConfiguration `json:"nodeDownPodDeletion,omitempty"`
 }
 
 // NodeStatusApplyConfiguration constructs a declarative configuration of the NodeStatus type for use with
@@ -97,3 +98,11 @@ func (b *NodeStatusApplyConfiguration) WithAutoEvicting(value bool) *NodeStatusA
 	b.AutoEvicting = &value
 	return b
 }
+
+// WithNodeDownPodDeletion sets the NodeDownPodDeletion field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the NodeDownPodDeletion field is set to the value of the last call.
+func (b *NodeStatusApplyConfiguration) WithNodeDownPodDeletion(value *NodeDownPodDeletionStatusApplyConfiguration) *NodeStatusApplyConfiguration {
+	b.NodeDownPodDeletion = value
+	return b
+}
@@ -25,45 +25,59 @@ import (
 // VolumeSpecApplyConfiguration represents a declarative configuration of the VolumeSpec type for use
 // with apply.
 type VolumeSpecApplyConfiguration struct {
-	Size                            *int64                                         `json:"size,omitempty"`
-	Frontend                        *longhornv1beta2.VolumeFrontend                `json:"frontend,omitempty"`
-	UblkQueueDepth                  *int                                           `json:"ublkQueueDepth,omitempty"`
-	UblkNumberOfQueue               *int                                           `json:"ublkNumberOfQueue,omitempty"`
-	FromBackup                      *string                                        `json:"fromBackup,omitempty"`
-	RestoreVolumeRecurringJob       *longhornv1beta2.RestoreVolumeRecurringJobType `json:"restoreVolumeRecurringJob,omitempty"`
-	DataSource                      *longhornv1beta2.VolumeDataSource              `json:"dataSource,omitempty"`
-	CloneMode                       *longhornv1beta2.CloneMode                     `json:"cloneMode,omitempty"`
-	DataLocality                    *longhornv1beta2.DataLocality                  `json:"dataLocality,omitempty"`
-	StaleReplicaTimeout             *int                                           `json:"staleReplicaTimeout,omitempty"`
-	NodeID                          *string                                        `json:"nodeID,omitempty"`
-	MigrationNodeID                 *string                                        `json:"migrationNodeID,omitempty"`
-	Image                           *string                                        `json:"image,omitempty"`
-	BackingImage                    *string                                        `json:"backingImage,omitempty"`
-	Standby                         *bool                                          `json:"Standby,omitempty"`
-	DiskSelector                    []string                                       `json:"diskSelector,omitempty"`
-	NodeSelector                    []string                                       `json:"nodeSelector,omitempty"`
-	DisableFrontend                 *bool                                          `json:"disableFrontend,omitempty"`
-	RevisionCounterDisabled         *bool                                          `json:"revisionCounterDisabled,omitempty"`
-	UnmapMarkSnapChainRemoved       *longhornv1beta2.UnmapMarkSnapChainRemoved     `json:"unmapMarkSnapChainRemoved,omitempty"`
-	ReplicaSoftAntiAffinity         *longhornv1beta2.ReplicaSoftAntiAffinity       `json:"replicaSoftAntiAffinity,omitempty"`
-	ReplicaZoneSoftAntiAffinity     *longhornv1beta2.ReplicaZoneSoftAntiAffinity   `json:"replicaZoneSoftAntiAffinity,omitempty"`
-	ReplicaDiskSoftAntiAffinity     *longhornv1beta2.ReplicaDiskSoftAntiAffinity   `json:"replicaDiskSoftAntiAffinity,omitempty"`
-	LastAttachedBy                  *string                                        `json:"lastAttachedBy,omitempty"`
-	AccessMode                      *longhornv1beta2.AccessMode                    `json:"accessMode,omitempty"`
-	Migratable                      *bool                                          `json:"migratable,omitempty"`
-	Encrypted                       *bool                                          `json:"encrypted,omitempty"`
-	NumberOfReplicas                *int                                           `json:"numberOfReplicas,omitempty"`
-	ReplicaAutoBalance              *longhornv1beta2.ReplicaAutoBalance            `json:"replicaAutoBalance,omitempty"`
-	SnapshotDataIntegrity           *longhornv1beta2.SnapshotDataIntegrity         `json:"snapshotDataIntegrity,omitempty"`
-	BackupCompressionMethod         *longhornv1beta2.BackupCompressionMethod       `json:"backupCompressionMethod,omitempty"`
-	BackupBlockSize                 *int64                                         `json:"backupBlockSize,omitempty"`
-	DataEngine                      *longhornv1beta2.DataEngineType                `json:"dataEngine,omitempty"`
-	SnapshotMaxCount                *int                                           `json:"snapshotMaxCount,omitempty"`
-	SnapshotMaxSize                 *int64                                         `json:"snapshotMaxSize,omitempty"`
-	FreezeFilesystemForSnapshot     *longhornv1beta2.FreezeFilesystemForSnapshot   `json:"freezeFilesystemForSnapshot,omitempty"`
-	BackupTargetName                *string                                        `json:"backupTargetName,omitempty"`
-	OfflineRebuilding               *longhornv1beta2.VolumeOfflineRebuilding       `json:"offlineRebuilding,omitempty"`
-	ReplicaRebuildingBandwidthLimit *int64                                         `json:"replicaRebuildingBandwidthLimit,omitempty"`
+	Size                                     *int64                                           `json:"size,omitempty"`
+	MaxSize                                  *int64                                           `json:"maxSize,omitempty"`
+	Frontend                                 *longhornv1beta2.VolumeFrontend                  `json:"frontend,omitempty"`
+	UblkQueueDepth                           *int                                             `json:"ublkQueueDepth,omitempty"`
+	UblkNumberOfQueue                        *int                                             `json:"ublkNumberOfQueue,omitempty"`
+	FromBackup                               *string                                          `json:"fromBackup,omitempty"`
+	RestoreVolumeRecurringJob                *longhornv1beta2.RestoreVolumeRecurringJobType   `json:"restoreVolumeRecurringJob,omitempty"`
+	DataSource                               *longhornv1beta2.VolumeDataSource                `json:"dataSource,omitempty"`
+	CloneMode                                *longhornv1beta2.CloneMode                       `json:"cloneMode,omitempty"`
+	DataLocality                             *longhornv1beta2.DataLocality                    `json:"dataLocality,omitempty"`
+	StaleReplicaTimeout                      *int                                             `json:"staleReplicaTimeout,omitempty"`
+	NodeID                                   *string                                          `json:"nodeID,omitempty"`
+	MigrationNodeID                          *string                                          `json:"migrationNodeID,omitempty"`
+	MigrationTargetNodeID                    *string                                          `json:"migrationTargetNodeID,omitempty"`
+	Image                                    *string                                          `json:"image,omitempty"`
+	BackingImage                             *string                                          `json:"backingImage,omitempty"`
+	BackingImageCleanupPolicy                *longhornv1beta2.BackingImageCleanupPolicy       `json:"backingImageCleanupPolicy,omitempty"`
+	Standby                                  *bool                                            `json:"Standby,omitempty"`
+	StandbyRestoreInterval                   *int                                             `json:"standbyRestoreInterval,omitempty"`
+	DiskSelector                             []string                                         `json:"diskSelector,omitempty"`
+	NodeSelector                             []string                                         `json:"nodeSelector,omitempty"`
+	DisableFrontend                          *bool                                            `json:"disableFrontend,omitempty"`
+	RevisionCounterDisabled                  *bool                                            `json:"revisionCounterDisabled,omitempty"`
+	UnmapMarkSnapChainRemoved                *longhornv1beta2.UnmapMarkSnapChainRemoved       `json:"unmapMarkSnapChainRemoved,omitempty"`
+	ReplicaSoftAntiAffinity                  *longhornv1beta2.ReplicaSoftAntiAffinity         `json:"replicaSoftAntiAffinity,omitempty"`
+	ReplicaZoneSoftAntiAffinity              *longhornv1beta2.ReplicaZoneSoftAntiAffinity     `json:"replicaZoneSoftAntiAffinity,omitempty"`
+	ReplicaDiskSoftAntiAffinity              *longhornv1beta2.ReplicaDiskSoftAntiAffinity     `json:"replicaDiskSoftAntiAffinity,omitempty"`
+	LastAttachedBy                           *string                                          `json:"lastAttachedBy,omitempty"`
+	AccessMode                               *longhornv1beta2.AccessMode                      `json:"accessMode,omitempty"`
+	AutoDowngradeFromRWX                     *bool                                            `json:"autoDowngradeFromRWX,omitempty"`
+	Migratable                               *bool                                            `json:"migratable,omitempty"`
+	Encrypted                                *bool                                            `json:"encrypted,omitempty"`
+	NumberOfReplicas                         *int                                             `json:"numberOfReplicas,omitempty"`
+	ReplicaAutoBalance                       *longhornv1beta2.ReplicaAutoBalance              `json:"replicaAutoBalance,omitempty"`
+	ReplicaAutoBalanceDiskPressurePercentage *int64                                           `json:"replicaAutoBalanceDiskPressurePercentage,omitempty"`
+	SnapshotDataIntegrity                    *longhornv1beta2.SnapshotDataIntegrity           `json:"snapshotDataIntegrity,omitempty"`
+	SnapshotDataIntegrityCronJob             *string                                          `json:"snapshotDataIntegrityCronJob,omitempty"`
+	BackupCompressionMethod                  *longhornv1beta2.BackupCompressionMethod         `json:"backupCompressionMethod,omitempty"`
+	BackupBlockSize                          *int64                                           `json:"backupBlockSize,omitempty"`
+	DataEngine                               *longhornv1beta2.DataEngineType                  `json:"dataEngine,omitempty"`
+	DataEngineLogLevel                       *string                                          `json:"dataEngineLogLevel,omitempty"`
+	InstanceManagerImage                     *string                                          `json:"instanceManagerImage,omitempty"`
+	EngineImagePullSecret                    *string                                          `json:"engineImagePullSecret,omitempty"`
+	SnapshotMaxCount                         *int                                             `json:"snapshotMaxCount,omitempty"`
+	SnapshotMaxSize                          *int64                                           `json:"snapshotMaxSize,omitempty"`
+	SnapshotMaxSizeAction                    *longhornv1beta2.SnapshotMaxSizeAction           `json:"snapshotMaxSizeAction,omitempty"`
+	RecurringSnapshotOverflowAction          *longhornv1beta2.RecurringSnapshotOverflowAction `json:"recurringSnapshotOverflowAction,omitempty"`
+	SnapshotReclaimThreshold                 *string                                          `json:"snapshotReclaimThreshold,omitempty"`
+	FreezeFilesystemForSnapshot              *longhornv1beta2.FreezeFilesystemForSnapshot     `json:"freezeFilesystemForSnapshot,omitempty"`
+	BackupTargetName                         *string                                          `json:"backupTargetName,omitempty"`
+	OfflineRebuilding                        *longhornv1beta2.VolumeOfflineRebuilding         `json:"offlineRebuilding,omitempty"`
+	ReplicaRebuilding                        *longhornv1beta2.VolumeReplicaRebuilding         `json:"replicaRebuilding,omitempty"`
+	ReplicaRebuildingBandwidthLimit          *int64                                           `json:"replicaRebuildingBandwidthLimit,omitempty"`
 }
 
 // VolumeSpecApplyConfiguration constructs a declarative configuration of the VolumeSpec type for use with
@@ -168,6 +182,14 @@ func (b *VolumeSpecApplyConfiguration) WithMigrationNodeID(value string) *Volume
 	return b
 }
 
+// WithMigrationTargetNodeID sets the MigrationTargetNodeID field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MigrationTargetNodeID field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithMigrationTargetNodeID(value string) *VolumeSpecApplyConfiguration {
+	b.MigrationTargetNodeID = &value
+	return b
+}
+
 // WithImage sets the Image field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Image field is set to the value of the last call.
@@ -184,6 +206,14 @@ func (b *VolumeSpecApplyConfiguration) WithBackingImage(value string) *VolumeSpe
 	return b
 }
 
+// WithBackingImageCleanupPolicy sets the BackingImageCleanupPolicy field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the BackingImageCleanupPolicy field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithBackingImageCleanupPolicy(value longhornv1beta2.BackingImageCleanupPolicy) *VolumeSpecApplyConfiguration {
+	b.BackingImageCleanupPolicy = &value
+	return b
+}
+
 // WithStandby sets the Standby field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the Standby field is set to the value of the last call.
@@ -308,6 +338,14 @@ func (b *VolumeSpecApplyConfiguration) WithReplicaAutoBalance(value longhornv1be
 	return b
 }
 
+// WithReplicaAutoBalanceDiskPressurePercentage sets the ReplicaAutoBalanceDiskPressurePercentage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ReplicaAutoBalanceDiskPressurePercentage field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithReplicaAutoBalanceDiskPressurePercentage(value int64) *VolumeSpecApplyConfiguration {
+	b.ReplicaAutoBalanceDiskPressurePercentage = &value
+	return b
+}
+
 // WithSnapshotDataIntegrity sets the SnapshotDataIntegrity field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the SnapshotDataIntegrity field is set to the value of the last call.
@@ -316,6 +354,14 @@ func (b *VolumeSpecApplyConfiguration) WithSnapshotDataIntegrity(value longhornv
 	return b
 }
 
+// WithSnapshotDataIntegrityCronJob sets the SnapshotDataIntegrityCronJob field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SnapshotDataIntegrityCronJob field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithSnapshotDataIntegrityCronJob(value string) *VolumeSpecApplyConfiguration {
+	b.SnapshotDataIntegrityCronJob = &value
+	return b
+}
+
 // WithBackupCompressionMethod sets the BackupCompressionMethod field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the BackupCompressionMethod field is set to the value of the last call.
@@ -387,3 +433,83 @@ func (b *VolumeSpecApplyConfiguration) WithReplicaRebuildingBandwidthLimit(value
 	b.ReplicaRebuildingBandwidthLimit = &value
 	return b
 }
+
+// WithSnapshotReclaimThreshold sets the SnapshotReclaimThreshold field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SnapshotReclaimThreshold field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithSnapshotReclaimThreshold(value string) *VolumeSpecApplyConfiguration {
+	b.SnapshotReclaimThreshold = &value
+	return b
+}
+
+// WithInstanceManagerImage sets the InstanceManagerImage field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the InstanceManagerImage field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithInstanceManagerImage(value string) *VolumeSpecApplyConfiguration {
+	b.InstanceManagerImage = &value
+	return b
+}
+
+// WithAutoDowngradeFromRWX sets the AutoDowngradeFromRWX field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the AutoDowngradeFromRWX field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithAutoDowngradeFromRWX(value bool) *VolumeSpecApplyConfiguration {
+	b.AutoDowngradeFromRWX = &value
+	return b
+}
+
+// WithStandbyRestoreInterval sets the StandbyRestoreInterval field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the StandbyRestoreInterval field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithStandbyRestoreInterval(value int) *VolumeSpecApplyConfiguration {
+	b.StandbyRestoreInterval = &value
+	return b
+}
+
+// WithMaxSize sets the MaxSize field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the MaxSize field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithMaxSize(value int64) *VolumeSpecApplyConfiguration {
+	b.MaxSize = &value
+	return b
+}
+
+// WithReplicaRebuilding sets the ReplicaRebuilding field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the ReplicaRebuilding field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithReplicaRebuilding(value longhornv1beta2.VolumeReplicaRebuilding) *VolumeSpecApplyConfiguration {
+	b.ReplicaRebuilding = &value
+	return b
+}
+
+// WithSnapshotMaxSizeAction sets the SnapshotMaxSizeAction field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SnapshotMaxSizeAction field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithSnapshotMaxSizeAction(value longhornv1beta2.SnapshotMaxSizeAction) *VolumeSpecApplyConfiguration {
+	b.SnapshotMaxSizeAction = &value
+	return b
+}
+
+// WithRecurringSnapshotOverflowAction sets the RecurringSnapshotOverflowAction field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the RecurringSnapshotOverflowAction field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithRecurringSnapshotOverflowAction(value longhornv1beta2.RecurringSnapshotOverflowAction) *VolumeSpecApplyConfiguration {
+	b.RecurringSnapshotOverflowAction = &value
+	return b
+}
+
+// WithDataEngineLogLevel sets the DataEngineLogLevel field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the DataEngineLogLevel field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithDataEngineLogLevel(value string) *VolumeSpecApplyConfiguration {
+	b.DataEngineLogLevel = &value
+	return b
+}
+
+// WithEngineImagePullSecret sets the EngineImagePullSecret field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the EngineImagePullSecret field is set to the value of the last call.
+func (b *VolumeSpecApplyConfiguration) WithEngineImagePullSecret(value string) *VolumeSpecApplyConfiguration {
+	b.EngineImagePullSecret = &value
+	return b
+}
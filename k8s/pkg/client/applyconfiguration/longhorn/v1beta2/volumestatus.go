@@ -44,8 +44,10 @@ type VolumeStatusApplyConfiguration struct {
 	IsStandby              *bool                                `json:"isStandby,omitempty"`
 	ActualSize             *int64                               `json:"actualSize,omitempty"`
 	LastDegradedAt         *string                              `json:"lastDegradedAt,omitempty"`
+	LastHealthyAt          *string                              `json:"lastHealthyAt,omitempty"`
 	ShareEndpoint          *string                              `json:"shareEndpoint,omitempty"`
 	ShareState             *longhornv1beta2.ShareManagerState   `json:"shareState,omitempty"`
+	SingleConsumerSince    *string                              `json:"singleConsumerSince,omitempty"`
 }
 
 // VolumeStatusApplyConfiguration constructs a declarative configuration of the VolumeStatus type for use with
@@ -211,6 +213,14 @@ func (b *VolumeStatusApplyConfiguration) WithLastDegradedAt(value string) *Volum
 	return b
 }
 
+// WithLastHealthyAt sets the LastHealthyAt field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the LastHealthyAt field is set to the value of the last call.
+func (b *VolumeStatusApplyConfiguration) WithLastHealthyAt(value string) *VolumeStatusApplyConfiguration {
+	b.LastHealthyAt = &value
+	return b
+}
+
 // WithShareEndpoint sets the ShareEndpoint field in the declarative configuration to the given value
 // and returns the receiver, so that objects can be built by chaining "With" function invocations.
 // If called multiple times, the ShareEndpoint field is set to the value of the last call.
@@ -226,3 +236,11 @@ func (b *VolumeStatusApplyConfiguration) WithShareState(value longhornv1beta2.Sh
 	b.ShareState = &value
 	return b
 }
+
+// WithSingleConsumerSince sets the SingleConsumerSince field in the declarative configuration to the given value
+// and returns the receiver, so that objects can be built by chaining "With" function invocations.
+// If called multiple times, the SingleConsumerSince field is set to the value of the last call.
+func (b *VolumeStatusApplyConfiguration) WithSingleConsumerSince(value string) *VolumeStatusApplyConfiguration {
+	b.SingleConsumerSince = &value
+	return b
+}
@@ -136,6 +136,8 @@ func ForKind(kind schema.GroupVersionKind) interface{} {
 		return &longhornv1beta2.KubernetesStatusApplyConfiguration{}
 	case v1beta2.SchemeGroupVersion.WithKind("Node"):
 		return &longhornv1beta2.NodeApplyConfiguration{}
+	case v1beta2.SchemeGroupVersion.WithKind("NodeDownPodDeletionStatus"):
+		return &longhornv1beta2.NodeDownPodDeletionStatusApplyConfiguration{}
 	case v1beta2.SchemeGroupVersion.WithKind("NodeSpec"):
 		return &longhornv1beta2.NodeSpecApplyConfiguration{}
 	case v1beta2.SchemeGroupVersion.WithKind("NodeStatus"):
@@ -76,24 +76,31 @@ func (m *VolumeManager) PVCreate(name, pvName, fsType, secretNamespace, secretNa
 			util.MinimalVolumeSizeXFS)
 	}
 
-	pv := datastore.NewPVManifestForVolume(v, pvName, storageClassName, fsType)
+	// The secret of the encryption key is the given one, or the one of the storage class, or the default one.
+	var encryptionSecretRef *corev1.SecretReference
 	if v.Spec.Encrypted {
-		if secretName == "" {
-			secretName = "longhorn-crypto"
-		}
-
-		if secretNamespace == "" {
-			secretNamespace = "longhorn-system"
+		if secretName == "" && secretNamespace == "" {
+			encryptionSecretRef, err = m.ds.GetEncryptionSecretRefForStorageClass(storageClassName)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get the encryption secret of storage class %v for PV %v creation: %v", storageClassName, pvName, err)
+			}
 		}
-
-		secretRef := &corev1.SecretReference{
-			Name:      secretName,
-			Namespace: secretNamespace,
+		if encryptionSecretRef == nil {
+			if secretName == "" {
+				secretName = types.DefaultEncryptionSecretName
+			}
+			if secretNamespace == "" {
+				secretNamespace = types.DefaultEncryptionSecretNamespace
+			}
+			encryptionSecretRef = &corev1.SecretReference{
+				Name:      secretName,
+				Namespace: secretNamespace,
+			}
 		}
-		pv.Spec.CSI.NodeStageSecretRef = secretRef
-		pv.Spec.CSI.NodePublishSecretRef = secretRef
 	}
 
+	pv := datastore.NewPVManifestForVolume(v, pvName, storageClassName, fsType, nil, "", nil, encryptionSecretRef)
+
 	_, err = m.ds.CreatePersistentVolume(pv)
 	if err != nil {
 		return nil, err
@@ -2,8 +2,15 @@ package manager
 
 import (
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/longhorn/longhorn-manager/datastore"
 )
 
 func (m *VolumeManager) GetLonghornEventList() (*corev1.EventList, error) {
 	return m.ds.GetLonghornEventList()
 }
+
+// ListPodVolumeMappings rebuilds the pod volume mappings from the API server, bypassing the informer caches.
+func (m *VolumeManager) ListPodVolumeMappings() ([]datastore.PodVolumeMapping, error) {
+	return m.ds.ListPodVolumeMappingsUncached()
+}
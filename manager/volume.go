@@ -172,41 +172,56 @@ func (m *VolumeManager) Create(name string, spec *longhorn.VolumeSpec, recurring
 			Labels: labels,
 		},
 		Spec: longhorn.VolumeSpec{
-			Size:                            spec.Size,
-			AccessMode:                      spec.AccessMode,
-			Migratable:                      spec.Migratable,
-			Encrypted:                       spec.Encrypted,
-			Frontend:                        spec.Frontend,
-			Image:                           "",
-			FromBackup:                      spec.FromBackup,
-			RestoreVolumeRecurringJob:       spec.RestoreVolumeRecurringJob,
-			DataSource:                      spec.DataSource,
-			CloneMode:                       spec.CloneMode,
-			NumberOfReplicas:                spec.NumberOfReplicas,
-			ReplicaAutoBalance:              spec.ReplicaAutoBalance,
-			DataLocality:                    spec.DataLocality,
-			StaleReplicaTimeout:             spec.StaleReplicaTimeout,
-			BackingImage:                    spec.BackingImage,
-			Standby:                         spec.Standby,
-			DiskSelector:                    spec.DiskSelector,
-			NodeSelector:                    spec.NodeSelector,
-			RevisionCounterDisabled:         spec.RevisionCounterDisabled,
-			SnapshotDataIntegrity:           spec.SnapshotDataIntegrity,
-			SnapshotMaxCount:                spec.SnapshotMaxCount,
-			SnapshotMaxSize:                 spec.SnapshotMaxSize,
-			BackupCompressionMethod:         spec.BackupCompressionMethod,
-			BackupBlockSize:                 spec.BackupBlockSize,
-			UnmapMarkSnapChainRemoved:       spec.UnmapMarkSnapChainRemoved,
-			ReplicaSoftAntiAffinity:         spec.ReplicaSoftAntiAffinity,
-			ReplicaZoneSoftAntiAffinity:     spec.ReplicaZoneSoftAntiAffinity,
-			ReplicaDiskSoftAntiAffinity:     spec.ReplicaDiskSoftAntiAffinity,
-			DataEngine:                      spec.DataEngine,
-			FreezeFilesystemForSnapshot:     spec.FreezeFilesystemForSnapshot,
-			BackupTargetName:                backupTargetName,
-			OfflineRebuilding:               spec.OfflineRebuilding,
-			ReplicaRebuildingBandwidthLimit: spec.ReplicaRebuildingBandwidthLimit,
-			UblkQueueDepth:                  spec.UblkQueueDepth,
-			UblkNumberOfQueue:               spec.UblkNumberOfQueue,
+			Size:                                     spec.Size,
+			MaxSize:                                  spec.MaxSize,
+			AccessMode:                               spec.AccessMode,
+			AutoDowngradeFromRWX:                     spec.AutoDowngradeFromRWX,
+			Migratable:                               spec.Migratable,
+			Encrypted:                                spec.Encrypted,
+			Frontend:                                 spec.Frontend,
+			Image:                                    "",
+			FromBackup:                               spec.FromBackup,
+			RestoreVolumeRecurringJob:                spec.RestoreVolumeRecurringJob,
+			DataSource:                               spec.DataSource,
+			CloneMode:                                spec.CloneMode,
+			NumberOfReplicas:                         spec.NumberOfReplicas,
+			ReplicaAutoBalance:                       spec.ReplicaAutoBalance,
+			ReplicaAutoBalanceDiskPressurePercentage: spec.ReplicaAutoBalanceDiskPressurePercentage,
+			DataLocality:                             spec.DataLocality,
+			NodeID:                                   spec.NodeID,
+			MigrationTargetNodeID:                    spec.MigrationTargetNodeID,
+			StaleReplicaTimeout:                      spec.StaleReplicaTimeout,
+			BackingImage:                             spec.BackingImage,
+			BackingImageCleanupPolicy:                spec.BackingImageCleanupPolicy,
+			Standby:                                  spec.Standby,
+			StandbyRestoreInterval:                   spec.StandbyRestoreInterval,
+			DiskSelector:                             spec.DiskSelector,
+			NodeSelector:                             spec.NodeSelector,
+			RevisionCounterDisabled:                  spec.RevisionCounterDisabled,
+			SnapshotDataIntegrity:                    spec.SnapshotDataIntegrity,
+			SnapshotDataIntegrityCronJob:             spec.SnapshotDataIntegrityCronJob,
+			SnapshotMaxCount:                         spec.SnapshotMaxCount,
+			SnapshotMaxSize:                          spec.SnapshotMaxSize,
+			SnapshotMaxSizeAction:                    spec.SnapshotMaxSizeAction,
+			RecurringSnapshotOverflowAction:          spec.RecurringSnapshotOverflowAction,
+			SnapshotReclaimThreshold:                 spec.SnapshotReclaimThreshold,
+			BackupCompressionMethod:                  spec.BackupCompressionMethod,
+			BackupBlockSize:                          spec.BackupBlockSize,
+			UnmapMarkSnapChainRemoved:                spec.UnmapMarkSnapChainRemoved,
+			ReplicaSoftAntiAffinity:                  spec.ReplicaSoftAntiAffinity,
+			ReplicaZoneSoftAntiAffinity:              spec.ReplicaZoneSoftAntiAffinity,
+			ReplicaDiskSoftAntiAffinity:              spec.ReplicaDiskSoftAntiAffinity,
+			DataEngine:                               spec.DataEngine,
+			DataEngineLogLevel:                       spec.DataEngineLogLevel,
+			InstanceManagerImage:                     spec.InstanceManagerImage,
+			EngineImagePullSecret:                    spec.EngineImagePullSecret,
+			FreezeFilesystemForSnapshot:              spec.FreezeFilesystemForSnapshot,
+			BackupTargetName:                         backupTargetName,
+			OfflineRebuilding:                        spec.OfflineRebuilding,
+			ReplicaRebuilding:                        spec.ReplicaRebuilding,
+			ReplicaRebuildingBandwidthLimit:          spec.ReplicaRebuildingBandwidthLimit,
+			UblkQueueDepth:                           spec.UblkQueueDepth,
+			UblkNumberOfQueue:                        spec.UblkNumberOfQueue,
 		},
 	}
 
@@ -491,6 +506,10 @@ func (m *VolumeManager) Expand(volumeName string, size int64) (v *longhorn.Volum
 
 	size = util.RoundUpSize(size)
 
+	if v.Spec.MaxSize > 0 && size > v.Spec.MaxSize {
+		return nil, fmt.Errorf("cannot expand volume to size %v larger than the max size %v", size, v.Spec.MaxSize)
+	}
+
 	if v.Spec.Size >= size {
 		logrus.Infof("Volume %v expansion is not allowable since current size %v >= %v", v.Name, v.Spec.Size, size)
 		return v, nil
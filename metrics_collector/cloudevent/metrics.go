@@ -0,0 +1,43 @@
+package cloudevent
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/longhorn/longhorn-manager/metrics_collector/registry"
+)
+
+// Package cloudevent exposes the prometheus metrics of the CloudEvents
+// published by Longhorn.
+
+// Metrics subsystem and keys used by the CloudEvent emitters.
+const (
+	LonghornName        = "longhorn"
+	CloudEventSubsystem = "cloudevent"
+	DroppedKey          = "dropped_total"
+
+	// DroppedReasonQueueFull is the reason of an event dropped since the emitter queue is full.
+	DroppedReasonQueueFull = "queue_full"
+	// DroppedReasonDeliveryFailed is the reason of an event dropped since it cannot be delivered to the sink.
+	DroppedReasonDeliveryFailed = "delivery_failed"
+)
+
+var (
+	dropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: LonghornName,
+		Subsystem: CloudEventSubsystem,
+		Name:      DroppedKey,
+		Help:      "Total number of CloudEvents dropped before being delivered to the sink",
+	}, []string{"emitter", "reason"})
+)
+
+func init() {
+	if err := registry.Register(dropped); err != nil {
+		logrus.WithError(err).WithField("metric", dropped).Error("Failed to register CloudEvent metrics")
+	}
+}
+
+// IncDropped increases the number of the events dropped by the emitter for the reason.
+func IncDropped(emitter, reason string) {
+	dropped.WithLabelValues(emitter, reason).Inc()
+}
@@ -0,0 +1,128 @@
+// Package poddeletion exposes the prometheus metrics of the force deletion
+// of the pods on down nodes.
+package poddeletion
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/longhorn/longhorn-manager/metrics_collector/registry"
+)
+
+// Metrics subsystem and keys used by the pod force deletion.
+const (
+	LonghornName         = "longhorn"
+	PodDeletionSubsystem = "pod_force_deletion"
+	ObservedKey          = "observed_total"
+
+	NodeDownPodSubsystem = "node_down_pod"
+	ForceDeletionsKey    = "force_deletions_total"
+	TerminatingKey       = "terminating"
+
+	QuotaUsedKey          = "quota_used"
+	QuotaExceededTotalKey = "quota_exceeded_total"
+
+	VerificationsKey = "verifications_total"
+
+	// ObservedModeDryRun is the mode of a force deletion observed since the dry run is enabled.
+	ObservedModeDryRun = "dry_run"
+	// ObservedModeStartup is the mode of a force deletion observed during the observe period after the startup.
+	ObservedModeStartup = "startup"
+
+	// VerificationResultVerified is the result of a force deletion whose pod is gone at the verification.
+	VerificationResultVerified = "verified"
+	// VerificationResultUnverified is the result of a force deletion whose pod is still present at the verification.
+	VerificationResultUnverified = "unverified"
+)
+
+var (
+	observed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: LonghornName,
+		Subsystem: PodDeletionSubsystem,
+		Name:      ObservedKey,
+		Help:      "Total number of force deletions of pods on down nodes that would have been done but were only observed",
+	}, []string{"mode"})
+
+	forceDeletions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: LonghornName,
+		Subsystem: NodeDownPodSubsystem,
+		Name:      ForceDeletionsKey,
+		Help:      "Total number of pods force deleted on down nodes",
+	}, []string{"node", "namespace"})
+
+	terminating = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: LonghornName,
+		Subsystem: NodeDownPodSubsystem,
+		Name:      TerminatingKey,
+		Help:      "Number of pods currently observed terminating on down nodes",
+	}, []string{"node"})
+
+	quotaUsed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: LonghornName,
+		Subsystem: PodDeletionSubsystem,
+		Name:      QuotaUsedKey,
+		Help:      "Number of pods on down nodes force deleted in the namespace within the quota window",
+	}, []string{"namespace"})
+
+	quotaExceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: LonghornName,
+		Subsystem: PodDeletionSubsystem,
+		Name:      QuotaExceededTotalKey,
+		Help:      "Total number of force deletions of pods on down nodes deferred since the quota of the namespace is exceeded",
+	}, []string{"namespace"})
+
+	verifications = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: LonghornName,
+		Subsystem: PodDeletionSubsystem,
+		Name:      VerificationsKey,
+		Help:      "Total number of force deletions of pods on down nodes verified after the verification delay, by whether the pod was gone",
+	}, []string{"result"})
+)
+
+func init() {
+	for _, m := range []prometheus.Collector{observed, forceDeletions, terminating, quotaUsed, quotaExceeded, verifications} {
+		if err := registry.Register(m); err != nil {
+			logrus.WithError(err).WithField("metric", m).Error("Failed to register pod force deletion metrics")
+		}
+	}
+}
+
+// IncObserved increases the number of the force deletions observed in the mode.
+func IncObserved(mode string) {
+	observed.WithLabelValues(mode).Inc()
+}
+
+// IncForceDeletions increases the number of the pods of the namespace force deleted on the node.
+func IncForceDeletions(node, namespace string) {
+	forceDeletions.WithLabelValues(node, namespace).Inc()
+}
+
+// SetTerminating sets the number of the pods observed terminating on the down node,
+// and drops the series of the node once there is none.
+func SetTerminating(node string, count int) {
+	if count == 0 {
+		terminating.DeleteLabelValues(node)
+		return
+	}
+	terminating.WithLabelValues(node).Set(float64(count))
+}
+
+// SetQuotaUsed sets the number of the pods force deleted in the namespace within the quota window,
+// and drops the series of the namespace once there is none.
+func SetQuotaUsed(namespace string, count int) {
+	if count == 0 {
+		quotaUsed.DeleteLabelValues(namespace)
+		return
+	}
+	quotaUsed.WithLabelValues(namespace).Set(float64(count))
+}
+
+// IncQuotaExceeded increases the number of the force deletions deferred by the quota of the namespace.
+func IncQuotaExceeded(namespace string) {
+	quotaExceeded.WithLabelValues(namespace).Inc()
+}
+
+// IncVerifications increases the number of the force deletions verified with the result.
+func IncVerifications(result string) {
+	verifications.WithLabelValues(result).Inc()
+}
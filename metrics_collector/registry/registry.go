@@ -3,6 +3,7 @@ package registry
 import (
 	"net/http"
 
+	"github.com/cockroachdb/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
@@ -11,9 +12,15 @@ import (
 // We use longhornCustomRegistry to get rid of all default Prometheus go-client metrics
 var longhornCustomRegistry = prometheus.NewRegistry()
 
-// Register registers the provided Collector with the longhornCustomRegistry
+// Register registers the provided Collector with the longhornCustomRegistry.
+// Registering the same Collector again is a no-op.
 func Register(collector prometheus.Collector) error {
-	return longhornCustomRegistry.Register(collector)
+	err := longhornCustomRegistry.Register(collector)
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) && alreadyRegistered.ExistingCollector == collector {
+		return nil
+	}
+	return err
 }
 
 // Handler returns an http.Handler for longhornCustomRegistry, using default HandlerOpts
@@ -5,6 +5,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
+	storagev1 "k8s.io/api/storage/v1"
+
 	imtypes "github.com/longhorn/longhorn-instance-manager/pkg/types"
 
 	"github.com/longhorn/longhorn-manager/controller"
@@ -26,6 +28,8 @@ type VolumeCollector struct {
 	stateMetric              metricInfo
 	robustnessMetric         metricInfo
 	fileSystemReadOnlyMetric metricInfo
+	attachmentConflictMetric metricInfo
+	idleMetric               metricInfo
 
 	volumePerfMetrics
 }
@@ -61,6 +65,26 @@ func NewVolumeCollector(
 		Type: prometheus.GaugeValue,
 	}
 
+	vc.attachmentConflictMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemVolume, "attachment_conflict"),
+			"Whether this RWO volume is attached to more than one node. 1 means there is a conflict",
+			[]string{nodeLabel, volumeLabel, pvcLabel, pvcNamespaceLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
+	vc.idleMetric = metricInfo{
+		Desc: prometheus.NewDesc(
+			prometheus.BuildFQName(longhornName, subsystemVolume, "idle"),
+			"Volume without any consuming pod for longer than the volume idle threshold",
+			[]string{nodeLabel, volumeLabel, pvcLabel, pvcNamespaceLabel},
+			nil,
+		),
+		Type: prometheus.GaugeValue,
+	}
+
 	vc.capacityMetric = metricInfo{
 		Desc: prometheus.NewDesc(
 			prometheus.BuildFQName(longhornName, subsystemVolume, "capacity_bytes"),
@@ -170,6 +194,8 @@ func (vc *VolumeCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- vc.stateMetric.Desc
 	ch <- vc.robustnessMetric.Desc
 	ch <- vc.fileSystemReadOnlyMetric.Desc
+	ch <- vc.attachmentConflictMetric.Desc
+	ch <- vc.idleMetric.Desc
 }
 
 func (vc *VolumeCollector) Collect(ch chan<- prometheus.Metric) {
@@ -185,13 +211,27 @@ func (vc *VolumeCollector) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
+	kubeVAs, err := vc.ds.ListVolumeAttachmentsRO()
+	if err != nil {
+		vc.logger.WithError(err).Warn("Failed to list Kubernetes VolumeAttachments during scrape")
+	}
+
 	for _, v := range volumeLists {
 		if v.Status.OwnerID == vc.currentNodeID {
+			vc.collectAttachmentConflict(ch, v, kubeVAs)
 			vc.collectMetrics(ch, v)
 		}
 	}
 }
 
+func (vc *VolumeCollector) collectAttachmentConflict(ch chan<- prometheus.Metric, v *longhorn.Volume, kubeVAs []*storagev1.VolumeAttachment) {
+	conflict := 0
+	if len(controller.GetConflictingAttachmentNodes(v, kubeVAs)) > 0 {
+		conflict = 1
+	}
+	ch <- prometheus.MustNewConstMetric(vc.attachmentConflictMetric.Desc, vc.attachmentConflictMetric.Type, float64(conflict), vc.currentNodeID, v.Name, v.Status.KubernetesStatus.PVCName, v.Status.KubernetesStatus.Namespace)
+}
+
 func (vc *VolumeCollector) collectMetrics(ch chan<- prometheus.Metric, v *longhorn.Volume) {
 	defer func() {
 		if err := recover(); err != nil {
@@ -205,6 +245,10 @@ func (vc *VolumeCollector) collectMetrics(ch chan<- prometheus.Metric, v *longho
 	vc.collectVolumeState(ch, v)
 	vc.collectVolumeRobustness(ch, v)
 
+	if types.GetCondition(v.Status.Conditions, longhorn.VolumeConditionTypeIdle).Status == longhorn.ConditionStatusTrue {
+		ch <- prometheus.MustNewConstMetric(vc.idleMetric.Desc, vc.idleMetric.Type, float64(1), vc.currentNodeID, v.Name, v.Status.KubernetesStatus.PVCName, v.Status.KubernetesStatus.Namespace)
+	}
+
 	e, err := vc.ds.GetVolumeCurrentEngine(v.Name)
 	if err != nil {
 		vc.logger.WithError(err).Debugf("Failed to get engine for volume %v", v.Name)
@@ -98,6 +98,12 @@ func init() {
 	workqueue.SetProvider(prometheusMetricsProvider{})
 }
 
+// MetricsProvider returns the provider of the prometheus metrics of the workqueues, for a queue created with it
+// explicitly. The metrics of the queues of the same name are shared.
+func MetricsProvider() workqueue.MetricsProvider {
+	return prometheusMetricsProvider{}
+}
+
 func (prometheusMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
 	return depth.WithLabelValues(name)
 }
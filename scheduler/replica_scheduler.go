@@ -392,10 +392,10 @@ func (rcs *ReplicaScheduler) getDiskCandidates(nodeInfo map[string]*longhorn.Nod
 
 	replicaAutoBalance := rcs.ds.GetAutoBalancedReplicasSetting(volume, &logrus.Entry{})
 
-	diskPressurePercentage, err := rcs.ds.GetSettingAsInt(types.SettingNameReplicaAutoBalanceDiskPressurePercentage)
+	diskPressurePercentage, err := rcs.ds.GetReplicaAutoBalanceDiskPressurePercentage(volume)
 	if err != nil {
 		errs.Append(longhorn.ErrorReplicaScheduleLonghornClientOperationFailed,
-			errors.Wrapf(err, "failed to get %v setting", types.SettingNameReplicaAutoBalanceDiskPressurePercentage))
+			errors.Wrapf(err, "failed to get the replica auto-balance disk pressure percentage of volume %v", volume.Name))
 		return map[string]*Disk{}, errs
 	}
 
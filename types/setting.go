@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"slices"
 	"strconv"
 	"strings"
@@ -15,7 +16,9 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"github.com/longhorn/longhorn-manager/meta"
 	"github.com/longhorn/longhorn-manager/util"
@@ -82,8 +85,11 @@ const (
 	SettingNameLatestLonghornVersion                                    = SettingName("latest-longhorn-version")
 	SettingNameStableLonghornVersions                                   = SettingName("stable-longhorn-versions")
 	SettingNameDefaultReplicaCount                                      = SettingName("default-replica-count")
+	SettingNameDefaultStaleReplicaTimeout                               = SettingName("default-stale-replica-timeout")
 	SettingNameDefaultDataLocality                                      = SettingName("default-data-locality")
 	SettingNameDefaultLonghornStaticStorageClass                        = SettingName("default-longhorn-static-storage-class")
+	SettingNameRecreateMissingPVForBoundPVC                             = SettingName("recreate-missing-pv-for-bound-pvc")
+	SettingNameRecreateMissingPVForOrphanedVolume                       = SettingName("recreate-missing-pv-for-orphaned-volume")
 	SettingNameTaintToleration                                          = SettingName("taint-toleration")
 	SettingNameSystemManagedComponentsNodeSelector                      = SettingName("system-managed-components-node-selector")
 	SettingNameSystemManagedCSIComponentsResourceLimits                 = SettingName("system-managed-csi-components-resource-limits")
@@ -95,6 +101,40 @@ const (
 	SettingNameDisableSchedulingOnCordonedNode                          = SettingName("disable-scheduling-on-cordoned-node")
 	SettingNameReplicaZoneSoftAntiAffinity                              = SettingName("replica-zone-soft-anti-affinity")
 	SettingNameNodeDownPodDeletionPolicy                                = SettingName("node-down-pod-deletion-policy")
+	SettingNameNodeDownPodDeletionPaused                                = SettingName("node-down-pod-deletion-paused")
+	SettingNameNodeDownPodDeletionDryRun                                = SettingName("node-down-pod-deletion-dry-run")
+	SettingNameNodeDownPodDeletionRequireAllLonghornVolumes             = SettingName("node-down-pod-deletion-require-all-longhorn-volumes")
+	SettingNameNodeDownPodDeletionGracePeriod                           = SettingName("node-down-pod-deletion-grace-period")
+	SettingNameNodeDownPodDeletionSelector                              = SettingName("node-down-pod-deletion-selector")
+	SettingNameNodeDownPodDeletionNamespaces                            = SettingName("node-down-pod-deletion-namespaces")
+	SettingNameNodeDownPodDeletionBatchSize                             = SettingName("node-down-pod-deletion-batch-size")
+	SettingNameNodeDownPodDeletionBatchInterval                         = SettingName("node-down-pod-deletion-batch-interval")
+	SettingNameNodeDownPodDeletionStartupObservePeriod                  = SettingName("node-down-pod-deletion-startup-observe-period")
+	SettingNameNodeDownPodDeletionNamespaceRateLimit                    = SettingName("node-down-pod-deletion-namespace-rate-limit")
+	SettingNameNodeDownPodDeletionOwnerReferenceDepth                   = SettingName("node-down-pod-deletion-owner-reference-depth")
+	SettingNameNodeDownPodDeletionNamespaceQuotas                       = SettingName("node-down-pod-deletion-namespace-quotas")
+	SettingNameNodeDownPodDeletionNamespaceQuotaWindow                  = SettingName("node-down-pod-deletion-namespace-quota-window")
+	SettingNameNodeDownPodDeletionApprovalWebhookURL                    = SettingName("node-down-pod-deletion-approval-webhook-url")
+	SettingNameNodeDownPodDeletionApprovalWebhookTimeout                = SettingName("node-down-pod-deletion-approval-webhook-timeout")
+	SettingNameNodeDownPodDeletionApprovalWebhookFailOpen               = SettingName("node-down-pod-deletion-approval-webhook-fail-open")
+	SettingNameNodeDownPodDeletionWaitForRebuild                        = SettingName("node-down-pod-deletion-wait-for-rebuild")
+	SettingNameNodeDownPodDeletionRequireHealthyReplica                 = SettingName("node-down-pod-deletion-require-healthy-replica")
+	SettingNameNodeDownPodDeletionPDBMode                               = SettingName("node-down-pod-deletion-pdb-mode")
+	SettingNameNodeDownPodDeletionPriorityClassGracePeriods             = SettingName("node-down-pod-deletion-priority-class-grace-periods")
+	SettingNameNodeDownPodDeletionCloudEventSinkURL                     = SettingName("node-down-pod-deletion-cloud-event-sink-url")
+	SettingNameNodeDownPodDeletionCloudEventFormat                      = SettingName("node-down-pod-deletion-cloud-event-format")
+	SettingNameNodeDownPodDeletionEventMode                             = SettingName("node-down-pod-deletion-event-mode")
+	SettingNameNodeDownPodDeletionFencingEndpoint                       = SettingName("node-down-pod-deletion-fencing-endpoint")
+	SettingNameNodeDownPodDeletionFencingTimeout                        = SettingName("node-down-pod-deletion-fencing-timeout")
+	SettingNameNodeDownPodDeletionGracefulShutdownTimeout               = SettingName("node-down-pod-deletion-graceful-shutdown-timeout")
+	SettingNameNodeDownPodDeletionConfigAnnotation                      = SettingName("node-down-pod-deletion-config-annotation")
+	SettingNameNodeDownPodDeletionVerificationDelay                     = SettingName("node-down-pod-deletion-verification-delay")
+	SettingNameNodeDownSignals                                          = SettingName("node-down-signals")
+	SettingNameNodeDownSignalsOperator                                  = SettingName("node-down-signals-operator")
+	SettingNameNodeDownHeartbeatTimeout                                 = SettingName("node-down-heartbeat-timeout")
+	SettingNameNodeDownTaintKey                                         = SettingName("node-down-taint-key")
+	SettingNameNodeNotReadyGracePeriod                                  = SettingName("node-not-ready-grace-period")
+	SettingNameVolumeIdleThreshold                                      = SettingName("volume-idle-threshold")
 	SettingNameNodeDrainPolicy                                          = SettingName("node-drain-policy")
 	SettingNameDetachManuallyAttachedVolumesWhenCordoned                = SettingName("detach-manually-attached-volumes-when-cordoned")
 	SettingNamePriorityClass                                            = SettingName("priority-class")
@@ -156,7 +196,9 @@ const (
 	SettingNameDefaultMinNumberOfBackingImageCopies                     = SettingName("default-min-number-of-backing-image-copies")
 	SettingNameBackupExecutionTimeout                                   = SettingName("backup-execution-timeout")
 	SettingNameRWXVolumeFastFailover                                    = SettingName("rwx-volume-fast-failover")
+	SettingNameAutoDowngradeFromRWXPeriod                               = SettingName("auto-downgrade-from-rwx-period")
 	SettingNameOfflineReplicaRebuilding                                 = SettingName("offline-replica-rebuilding")
+	SettingNameReplicaRebuilding                                        = SettingName("replica-rebuilding")
 	SettingNameReplicaRebuildingBandwidthLimit                          = SettingName("replica-rebuilding-bandwidth-limit")
 	SettingNameDefaultUblkQueueDepth                                    = SettingName("default-ublk-queue-depth")
 	SettingNameDefaultUblkNumberOfQueue                                 = SettingName("default-ublk-number-of-queue")
@@ -200,8 +242,11 @@ var (
 		SettingNameLatestLonghornVersion,
 		SettingNameStableLonghornVersions,
 		SettingNameDefaultReplicaCount,
+		SettingNameDefaultStaleReplicaTimeout,
 		SettingNameDefaultDataLocality,
 		SettingNameDefaultLonghornStaticStorageClass,
+		SettingNameRecreateMissingPVForBoundPVC,
+		SettingNameRecreateMissingPVForOrphanedVolume,
 		SettingNameTaintToleration,
 		SettingNameSystemManagedComponentsNodeSelector,
 		SettingNameSystemManagedCSIComponentsResourceLimits,
@@ -213,6 +258,40 @@ var (
 		SettingNameDisableSchedulingOnCordonedNode,
 		SettingNameReplicaZoneSoftAntiAffinity,
 		SettingNameNodeDownPodDeletionPolicy,
+		SettingNameNodeDownPodDeletionPaused,
+		SettingNameNodeDownPodDeletionDryRun,
+		SettingNameNodeDownPodDeletionRequireAllLonghornVolumes,
+		SettingNameNodeDownPodDeletionGracePeriod,
+		SettingNameNodeDownPodDeletionSelector,
+		SettingNameNodeDownPodDeletionNamespaces,
+		SettingNameNodeDownPodDeletionBatchSize,
+		SettingNameNodeDownPodDeletionBatchInterval,
+		SettingNameNodeDownPodDeletionStartupObservePeriod,
+		SettingNameNodeDownPodDeletionNamespaceRateLimit,
+		SettingNameNodeDownPodDeletionOwnerReferenceDepth,
+		SettingNameNodeDownPodDeletionNamespaceQuotas,
+		SettingNameNodeDownPodDeletionNamespaceQuotaWindow,
+		SettingNameNodeDownPodDeletionApprovalWebhookURL,
+		SettingNameNodeDownPodDeletionApprovalWebhookTimeout,
+		SettingNameNodeDownPodDeletionApprovalWebhookFailOpen,
+		SettingNameNodeDownPodDeletionWaitForRebuild,
+		SettingNameNodeDownPodDeletionRequireHealthyReplica,
+		SettingNameNodeDownPodDeletionPDBMode,
+		SettingNameNodeDownPodDeletionPriorityClassGracePeriods,
+		SettingNameNodeDownPodDeletionCloudEventSinkURL,
+		SettingNameNodeDownPodDeletionCloudEventFormat,
+		SettingNameNodeDownPodDeletionEventMode,
+		SettingNameNodeDownPodDeletionFencingEndpoint,
+		SettingNameNodeDownPodDeletionFencingTimeout,
+		SettingNameNodeDownPodDeletionGracefulShutdownTimeout,
+		SettingNameNodeDownPodDeletionConfigAnnotation,
+		SettingNameNodeDownPodDeletionVerificationDelay,
+		SettingNameNodeDownSignals,
+		SettingNameNodeDownSignalsOperator,
+		SettingNameNodeDownHeartbeatTimeout,
+		SettingNameNodeDownTaintKey,
+		SettingNameNodeNotReadyGracePeriod,
+		SettingNameVolumeIdleThreshold,
 		SettingNameNodeDrainPolicy,
 		SettingNameDetachManuallyAttachedVolumesWhenCordoned,
 		SettingNamePriorityClass,
@@ -274,7 +353,9 @@ var (
 		SettingNameDefaultMinNumberOfBackingImageCopies,
 		SettingNameBackupExecutionTimeout,
 		SettingNameRWXVolumeFastFailover,
+		SettingNameAutoDowngradeFromRWXPeriod,
 		SettingNameOfflineReplicaRebuilding,
+		SettingNameReplicaRebuilding,
 		SettingNameReplicaRebuildingBandwidthLimit,
 		SettingNameDefaultUblkQueueDepth,
 		SettingNameDefaultUblkNumberOfQueue,
@@ -353,8 +434,11 @@ var (
 		SettingNameLatestLonghornVersion:                                    SettingDefinitionLatestLonghornVersion,
 		SettingNameStableLonghornVersions:                                   SettingDefinitionStableLonghornVersions,
 		SettingNameDefaultReplicaCount:                                      SettingDefinitionDefaultReplicaCount,
+		SettingNameDefaultStaleReplicaTimeout:                               SettingDefinitionDefaultStaleReplicaTimeout,
 		SettingNameDefaultDataLocality:                                      SettingDefinitionDefaultDataLocality,
 		SettingNameDefaultLonghornStaticStorageClass:                        SettingDefinitionDefaultLonghornStaticStorageClass,
+		SettingNameRecreateMissingPVForBoundPVC:                             SettingDefinitionRecreateMissingPVForBoundPVC,
+		SettingNameRecreateMissingPVForOrphanedVolume:                       SettingDefinitionRecreateMissingPVForOrphanedVolume,
 		SettingNameTaintToleration:                                          SettingDefinitionTaintToleration,
 		SettingNameSystemManagedComponentsNodeSelector:                      SettingDefinitionSystemManagedComponentsNodeSelector,
 		SettingNameSystemManagedCSIComponentsResourceLimits:                 SettingDefinitionSystemManagedCSIComponentsResourceLimits,
@@ -366,6 +450,40 @@ var (
 		SettingNameDisableSchedulingOnCordonedNode:                          SettingDefinitionDisableSchedulingOnCordonedNode,
 		SettingNameReplicaZoneSoftAntiAffinity:                              SettingDefinitionReplicaZoneSoftAntiAffinity,
 		SettingNameNodeDownPodDeletionPolicy:                                SettingDefinitionNodeDownPodDeletionPolicy,
+		SettingNameNodeDownPodDeletionPaused:                                SettingDefinitionNodeDownPodDeletionPaused,
+		SettingNameNodeDownPodDeletionDryRun:                                SettingDefinitionNodeDownPodDeletionDryRun,
+		SettingNameNodeDownPodDeletionRequireAllLonghornVolumes:             SettingDefinitionNodeDownPodDeletionRequireAllLonghornVolumes,
+		SettingNameNodeDownPodDeletionGracePeriod:                           SettingDefinitionNodeDownPodDeletionGracePeriod,
+		SettingNameNodeDownPodDeletionSelector:                              SettingDefinitionNodeDownPodDeletionSelector,
+		SettingNameNodeDownPodDeletionNamespaces:                            SettingDefinitionNodeDownPodDeletionNamespaces,
+		SettingNameNodeDownPodDeletionBatchSize:                             SettingDefinitionNodeDownPodDeletionBatchSize,
+		SettingNameNodeDownPodDeletionBatchInterval:                         SettingDefinitionNodeDownPodDeletionBatchInterval,
+		SettingNameNodeDownPodDeletionStartupObservePeriod:                  SettingDefinitionNodeDownPodDeletionStartupObservePeriod,
+		SettingNameNodeDownPodDeletionNamespaceRateLimit:                    SettingDefinitionNodeDownPodDeletionNamespaceRateLimit,
+		SettingNameNodeDownPodDeletionOwnerReferenceDepth:                   SettingDefinitionNodeDownPodDeletionOwnerReferenceDepth,
+		SettingNameNodeDownPodDeletionNamespaceQuotas:                       SettingDefinitionNodeDownPodDeletionNamespaceQuotas,
+		SettingNameNodeDownPodDeletionNamespaceQuotaWindow:                  SettingDefinitionNodeDownPodDeletionNamespaceQuotaWindow,
+		SettingNameNodeDownPodDeletionApprovalWebhookURL:                    SettingDefinitionNodeDownPodDeletionApprovalWebhookURL,
+		SettingNameNodeDownPodDeletionApprovalWebhookTimeout:                SettingDefinitionNodeDownPodDeletionApprovalWebhookTimeout,
+		SettingNameNodeDownPodDeletionApprovalWebhookFailOpen:               SettingDefinitionNodeDownPodDeletionApprovalWebhookFailOpen,
+		SettingNameNodeDownPodDeletionWaitForRebuild:                        SettingDefinitionNodeDownPodDeletionWaitForRebuild,
+		SettingNameNodeDownPodDeletionRequireHealthyReplica:                 SettingDefinitionNodeDownPodDeletionRequireHealthyReplica,
+		SettingNameNodeDownPodDeletionPDBMode:                               SettingDefinitionNodeDownPodDeletionPDBMode,
+		SettingNameNodeDownPodDeletionPriorityClassGracePeriods:             SettingDefinitionNodeDownPodDeletionPriorityClassGracePeriods,
+		SettingNameNodeDownPodDeletionCloudEventSinkURL:                     SettingDefinitionNodeDownPodDeletionCloudEventSinkURL,
+		SettingNameNodeDownPodDeletionCloudEventFormat:                      SettingDefinitionNodeDownPodDeletionCloudEventFormat,
+		SettingNameNodeDownPodDeletionEventMode:                             SettingDefinitionNodeDownPodDeletionEventMode,
+		SettingNameNodeDownPodDeletionFencingEndpoint:                       SettingDefinitionNodeDownPodDeletionFencingEndpoint,
+		SettingNameNodeDownPodDeletionFencingTimeout:                        SettingDefinitionNodeDownPodDeletionFencingTimeout,
+		SettingNameNodeDownPodDeletionGracefulShutdownTimeout:               SettingDefinitionNodeDownPodDeletionGracefulShutdownTimeout,
+		SettingNameNodeDownPodDeletionConfigAnnotation:                      SettingDefinitionNodeDownPodDeletionConfigAnnotation,
+		SettingNameNodeDownPodDeletionVerificationDelay:                     SettingDefinitionNodeDownPodDeletionVerificationDelay,
+		SettingNameNodeDownSignals:                                          SettingDefinitionNodeDownSignals,
+		SettingNameNodeDownSignalsOperator:                                  SettingDefinitionNodeDownSignalsOperator,
+		SettingNameNodeDownHeartbeatTimeout:                                 SettingDefinitionNodeDownHeartbeatTimeout,
+		SettingNameNodeDownTaintKey:                                         SettingDefinitionNodeDownTaintKey,
+		SettingNameNodeNotReadyGracePeriod:                                  SettingDefinitionNodeNotReadyGracePeriod,
+		SettingNameVolumeIdleThreshold:                                      SettingDefinitionVolumeIdleThreshold,
 		SettingNameNodeDrainPolicy:                                          SettingDefinitionNodeDrainPolicy,
 		SettingNameDetachManuallyAttachedVolumesWhenCordoned:                SettingDefinitionDetachManuallyAttachedVolumesWhenCordoned,
 		SettingNamePriorityClass:                                            SettingDefinitionPriorityClass,
@@ -426,7 +544,9 @@ var (
 		SettingNameDefaultMinNumberOfBackingImageCopies:                     SettingDefinitionDefaultMinNumberOfBackingImageCopies,
 		SettingNameBackupExecutionTimeout:                                   SettingDefinitionBackupExecutionTimeout,
 		SettingNameRWXVolumeFastFailover:                                    SettingDefinitionRWXVolumeFastFailover,
+		SettingNameAutoDowngradeFromRWXPeriod:                               SettingDefinitionAutoDowngradeFromRWXPeriod,
 		SettingNameOfflineReplicaRebuilding:                                 SettingDefinitionOfflineReplicaRebuilding,
+		SettingNameReplicaRebuilding:                                        SettingDefinitionReplicaRebuilding,
 		SettingNameReplicaRebuildingBandwidthLimit:                          SettingDefinitionReplicaRebuildingBandwidthLimit,
 		SettingNameDefaultUblkQueueDepth:                                    SettingDefinitionDefaultUblkQueueDepth,
 		SettingNameDefaultUblkNumberOfQueue:                                 SettingDefinitionDefaultUblkNumberOfQueue,
@@ -754,6 +874,22 @@ var (
 		},
 	}
 
+	SettingDefinitionDefaultStaleReplicaTimeout = SettingDefinition{
+		DisplayName: "Default Stale Replica Timeout",
+		Description: "The default time in minutes before a failed replica of a volume is cleaned up, when the volume is created by the CSI driver. " +
+			"For a StorageClass, set `staleReplicaTimeout` in its parameters to override it.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            DefaultStaleReplicaTimeout,
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 1,
+			ValueIntRangeMaximum: 43200,
+		},
+	}
+
 	SettingDefinitionDefaultDataLocality = SettingDefinition{
 		DisplayName: "Default Data Locality",
 		Description: "We say a Longhorn volume has data locality if there is a local replica of the volume on the same node as the pod which is using the volume.\n\n" +
@@ -786,6 +922,37 @@ var (
 		Default:            "longhorn-static",
 	}
 
+	SettingDefinitionRecreateMissingPVForBoundPVC = SettingDefinition{
+		DisplayName: "Recreate Missing PV For Bound PVC",
+		Description: "If enabled, Longhorn recreates the PersistentVolume of a bound PersistentVolumeClaim when the PersistentVolume is missing but the Longhorn volume still exists, so that the PersistentVolumeClaim is bound to the Longhorn volume again. " +
+			"This is useful in disaster recovery when PersistentVolumeClaims are restored without their PersistentVolumes. \n\n" +
+			"WARNING: \n\n" +
+			"  - Only enable this setting when the PersistentVolumes are known to be lost. The recreated PersistentVolume uses the static StorageClass settings of the Longhorn volume rather than the original PersistentVolume. \n\n" +
+			"  - Encrypted volumes are skipped since the secret of the original PersistentVolume cannot be recovered.",
+		Category:           SettingCategoryDangerZone,
+		Type:               SettingTypeBool,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "false",
+	}
+
+	SettingDefinitionRecreateMissingPVForOrphanedVolume = SettingDefinition{
+		DisplayName: "Recreate Missing PV For Orphaned Volume",
+		Description: "If enabled, Longhorn recreates the PersistentVolume of a Longhorn volume still used by a PersistentVolumeClaim when the PersistentVolume is missing, and the PersistentVolumeClaim as well if it is missing too, so that the volume can be used from Kubernetes again. " +
+			"This is useful when the PersistentVolume of a volume in use is deleted by accident. \n\n" +
+			"WARNING: \n\n" +
+			"  - The PersistentVolume and PersistentVolumeClaim of a volume are recreated even if they were deleted on purpose, unless the volume is deleted as well. \n\n" +
+			"  - The recreated PersistentVolume uses the StorageClass of the PersistentVolumeClaim, or the static StorageClass setting if the PersistentVolumeClaim is missing, rather than the original PersistentVolume. \n\n" +
+			"  - Encrypted volumes are skipped since the secret of the original PersistentVolume cannot be recovered.",
+		Category:           SettingCategoryDangerZone,
+		Type:               SettingTypeBool,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "false",
+	}
+
 	SettingDefinitionTaintToleration = SettingDefinition{
 		DisplayName: "Kubernetes Taint Toleration",
 		Description: "If you want to dedicate nodes to just store Longhorn replicas and reject other general workloads, you can set tolerations for **all** Longhorn components and add taints to the nodes dedicated for storage. " +
@@ -952,7 +1119,9 @@ var (
 			"- **do-nothing** is the default Kubernetes behavior of never force deleting StatefulSet/Deployment terminating pods. Since the pod on the node that is down isn't removed, Longhorn volumes are stuck on nodes that are down.\n" +
 			"- **delete-statefulset-pod** Longhorn will force delete StatefulSet terminating pods on nodes that are down to release Longhorn volumes so that Kubernetes can spin up replacement pods.\n" +
 			"- **delete-deployment-pod** Longhorn will force delete Deployment terminating pods on nodes that are down to release Longhorn volumes so that Kubernetes can spin up replacement pods.\n" +
-			"- **delete-both-statefulset-and-deployment-pod** Longhorn will force delete StatefulSet/Deployment terminating pods on nodes that are down to release Longhorn volumes so that Kubernetes can spin up replacement pods.\n",
+			"- **delete-both-statefulset-and-deployment-pod** Longhorn will force delete StatefulSet/Deployment terminating pods on nodes that are down to release Longhorn volumes so that Kubernetes can spin up replacement pods.\n" +
+			"- **delete-job-pod** Longhorn will force delete Job terminating pods, including the ones of CronJobs, on nodes that are down to release Longhorn volumes so that Kubernetes can spin up replacement pods. " +
+			"Since the pod may still be running on the node, the Job may run the work again in the replacement pod, so only use it for Jobs that can be safely re-executed.\n",
 		Category:           SettingCategoryGeneral,
 		Type:               SettingTypeString,
 		Required:           true,
@@ -964,6 +1133,494 @@ var (
 			string(NodeDownPodDeletionPolicyDeleteStatefulSetPod),
 			string(NodeDownPodDeletionPolicyDeleteDeploymentPod),
 			string(NodeDownPodDeletionPolicyDeleteBothStatefulsetAndDeploymentPod),
+			string(NodeDownPodDeletionPolicyDeleteJobPod),
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionPaused = SettingDefinition{
+		DisplayName: "Pause Pod Deletion When Node is Down",
+		Description: "Whether Longhorn pauses the force deletion of pods on down nodes, regardless of the Pod Deletion Policy When Node is Down setting. " +
+			"It allows pausing the force deletion during an incident and resuming it later without changing the policy.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeBool,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "false",
+	}
+
+	SettingDefinitionNodeDownPodDeletionDryRun = SettingDefinition{
+		DisplayName: "Pod Deletion Dry Run When Node is Down",
+		Description: "Whether Longhorn only logs and records an event for the pods on down nodes it would force delete according to the Pod Deletion Policy When Node is Down setting, without deleting them or their volume attachments. " +
+			"The pods still go through all the checks of the force deletion, including the approval webhook, but the down nodes are never fenced. " +
+			"It allows validating the policy against the workloads of the cluster before enabling the force deletion.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeBool,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "false",
+	}
+
+	SettingDefinitionNodeDownPodDeletionRequireAllLonghornVolumes = SettingDefinition{
+		DisplayName: "Pod Deletion Requires All Volumes Be Longhorn Volumes When Node is Down",
+		Description: "Whether Longhorn force deletes a pod on a down node only when all its persistent volumes are Longhorn volumes. " +
+			"By default, a pod using at least one Longhorn volume is force deleted according to the Pod Deletion Policy When Node is Down setting, even if it also uses volumes of other storage providers.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeBool,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "false",
+	}
+
+	SettingDefinitionNodeDownPodDeletionGracePeriod = SettingDefinition{
+		DisplayName: "Pod Deletion Grace Period When Node is Down",
+		Description: "The grace period in seconds Longhorn waits before force deleting a pod on a down node, which gives the pod time to terminate normally and run its pre-stop hooks if the kubelet is reachable again. " +
+			"The force deletion itself has no grace period, since the kubelet of a down node cannot complete it. By default, the pod is force deleted without waiting.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "0",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 0,
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionSelector = SettingDefinition{
+		DisplayName: "Pod Deletion Label Selector When Node is Down",
+		Description: "The label selector of the pods on down nodes Longhorn force deletes according to the Pod Deletion Policy When Node is Down setting, like `tier=best-effort,app!=database`. " +
+			"Pods that do not match the selector are not force deleted. By default, the selector is empty and all pods qualifying for the policy are force deleted.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           false,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "",
+	}
+
+	SettingDefinitionNodeDownPodDeletionNamespaces = SettingDefinition{
+		DisplayName: "Pod Deletion Namespaces When Node is Down",
+		Description: "The namespaces of the pods on down nodes Longhorn force deletes according to the Pod Deletion Policy When Node is Down setting, separated by commas, like `team-a,team-b`. " +
+			"Pods in the other namespaces are not force deleted. By default, the list is empty and the pods of all namespaces are force deleted.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           false,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "",
+	}
+
+	SettingDefinitionNodeDownPodDeletionBatchSize = SettingDefinition{
+		DisplayName: "Pod Deletion Batch Size When Node is Down",
+		Description: "The number of pods of a down node Longhorn force deletes in a batch, which is also the maximum number of their deletions in flight at once, " +
+			"so that a large node failure does not flood the Kubernetes API server with deletions. 0 means the pods are not deleted in batches.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "0",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 0,
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionBatchInterval = SettingDefinition{
+		DisplayName:        "Pod Deletion Batch Interval When Node is Down",
+		Description:        "In milliseconds. The delay between two batches of the force deletions of the pods of a down node, when the Pod Deletion Batch Size When Node is Down is set.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "1000",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 0,
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionStartupObservePeriod = SettingDefinition{
+		DisplayName: "Pod Deletion Observe Period After Startup When Node is Down",
+		Description: "The period in seconds after the Longhorn manager starts, during which the pods on down nodes are not force deleted. " +
+			"Longhorn only records the force deletions it would do as in the dry run, since the node states may be stale right after the startup. " +
+			"The force deletions are done once the period is over. By default, there is no observe period.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "0",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 0,
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionNamespaceRateLimit = SettingDefinition{
+		DisplayName: "Pod Deletion Rate Limit Per Namespace When Node is Down",
+		Description: "The maximum number of terminating pods per minute that Longhorn force deletes in a single namespace when their node is down. " +
+			"Each namespace is limited independently so that a namespace with many pods cannot starve the force deletion of pods in other namespaces. " +
+			"Set to 0 to disable the limit.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "0",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 0,
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionNamespaceQuotas = SettingDefinition{
+		DisplayName: "Pod Deletion Quotas Per Namespace When Node is Down",
+		Description: "The maximum number of pods on down nodes that Longhorn force deletes in a namespace within the Pod Deletion Quota Window Per Namespace When Node is Down. " +
+			"Further force deletions in the namespace are deferred until the quota is available again, so that the failover of one tenant cannot consume all the force deletions. " +
+			"The value is a list of `<namespace>:<count>` separated by semicolons, like `tenant-a:10;tenant-b:5`, where the namespace `*` applies to the namespaces not listed. " +
+			"A count of 0, or a namespace neither listed nor covered by `*`, has no quota. By default, the list is empty.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           false,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "",
+	}
+
+	SettingDefinitionNodeDownPodDeletionNamespaceQuotaWindow = SettingDefinition{
+		DisplayName:        "Pod Deletion Quota Window Per Namespace When Node is Down",
+		Description:        "In seconds. The sliding window in which the force deletions of a namespace are counted against its Pod Deletion Quota Per Namespace When Node is Down.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "600",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 1,
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionOwnerReferenceDepth = SettingDefinition{
+		DisplayName: "Owner Reference Depth for Pod Deletion When Node is Down",
+		Description: "The number of owner reference levels Longhorn follows to determine whether a pod belongs to a StatefulSet or a Deployment when applying the Pod Deletion Policy When Node is Down. " +
+			"Set to 1 to check only the direct controller of the pod. " +
+			"Increase it if the pods are created by an intermediate custom resource which is itself owned by a StatefulSet or a Deployment.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "1",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 1,
+			ValueIntRangeMaximum: 10,
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionApprovalWebhookURL = SettingDefinition{
+		DisplayName: "Pod Deletion Approval Webhook URL When Node is Down",
+		Description: "The URL of an HTTP webhook which Longhorn asks for approval before force deleting a pod on a down node according to the Pod Deletion Policy When Node is Down. " +
+			"Longhorn sends a POST request with the pod, node and policy as a JSON object, and proceeds only if the webhook responds with status 200 and {\"approved\": true}. " +
+			"The request has \"dryRun\": true if the pod is only evaluated by the Pod Deletion Dry Run When Node is Down setting. " +
+			"Leave it empty to force delete the pods without approval.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           false,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "",
+	}
+
+	SettingDefinitionNodeDownPodDeletionApprovalWebhookTimeout = SettingDefinition{
+		DisplayName:        "Pod Deletion Approval Webhook Timeout When Node is Down",
+		Description:        "In seconds. The timeout of a request to the Pod Deletion Approval Webhook.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "10",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 1,
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionApprovalWebhookFailOpen = SettingDefinition{
+		DisplayName: "Pod Deletion Approval Webhook Fail Open When Node is Down",
+		Description: "Whether Longhorn force deletes the pod when the Pod Deletion Approval Webhook cannot be reached, times out or responds with an unexpected status. " +
+			"By default, the deletion is denied in these cases.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeBool,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "false",
+	}
+
+	SettingDefinitionNodeDownPodDeletionWaitForRebuild = SettingDefinition{
+		DisplayName: "Pod Deletion Waits for Replica Rebuild When Node is Down",
+		Description: "Whether Longhorn defers the force deletion of a pod on a down node until its volumes have started rebuilding the replicas lost with the node on a surviving node. " +
+			"The deletion proceeds anyway if the rebuild has not started within 10 minutes after the pod deletion, so that a volume whose engine was on the down node can be attached again.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeBool,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "false",
+	}
+
+	SettingDefinitionNodeDownPodDeletionRequireHealthyReplica = SettingDefinition{
+		DisplayName: "Pod Deletion Requires Healthy Replica When Node is Down",
+		Description: "Whether Longhorn skips the force deletion of a pod on a down node while a volume of the pod has no healthy replica on another node, " +
+			"so that the replacement pod is not created for a volume that cannot start. A warning event is recorded on the pod, and the replicas are checked again every 30 seconds.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeBool,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "false",
+	}
+
+	SettingDefinitionNodeDownPodDeletionPDBMode = SettingDefinition{
+		DisplayName: "Pod Deletion PodDisruptionBudget Mode When Node is Down",
+		Description: "How Longhorn handles the force deletion of a pod on a down node while a PodDisruptionBudget selecting the pod allows no more disruption. " +
+			"Kubernetes does not honor PodDisruptionBudgets for force deletions, so Longhorn checks them itself.\n" +
+			"- **ignore** force deletes the pod without checking the PodDisruptionBudgets.\n" +
+			"- **warn** records a warning event on the pod and force deletes it anyway.\n" +
+			"- **skip** records a warning event on the pod and skips its force deletion, checking the PodDisruptionBudgets again every 30 seconds.\n",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            string(NodeDownPodDeletionPDBModeWarn),
+		Choices: []any{
+			string(NodeDownPodDeletionPDBModeIgnore),
+			string(NodeDownPodDeletionPDBModeWarn),
+			string(NodeDownPodDeletionPDBModeSkip),
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionPriorityClassGracePeriods = SettingDefinition{
+		DisplayName: "Pod Deletion Grace Periods by Priority Class When Node is Down",
+		Description: "The grace periods in seconds Longhorn waits after a pod on a down node is deleted before force deleting it, by the priority class of the pod, so that critical pods fail over faster than best-effort pods. " +
+			"The value is a list of `<priority class name>:<seconds>` separated by semicolons, like `system-cluster-critical:0;best-effort:600`. " +
+			"Pods whose priority class is not listed are force deleted once their own termination grace period is over. By default, the list is empty.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           false,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "",
+	}
+
+	SettingDefinitionNodeDownPodDeletionCloudEventSinkURL = SettingDefinition{
+		DisplayName: "Pod Deletion CloudEvent Sink URL When Node is Down",
+		Description: "The URL of an HTTP sink to which Longhorn publishes CloudEvents when it force deletes a pod on a down node, or skips the force deletion because of the quarantine, the approval webhook or the namespace rate limit. " +
+			"Events failing to be delivered after the retries are dropped. Leave it empty to disable publishing the events.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           false,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "",
+	}
+
+	SettingDefinitionNodeDownPodDeletionCloudEventFormat = SettingDefinition{
+		DisplayName: "Pod Deletion CloudEvent Format When Node is Down",
+		Description: "The HTTP content mode of the CloudEvents published to the Pod Deletion CloudEvent Sink.\n" +
+			"- **structured** sends the whole event as a JSON object with content type application/cloudevents+json.\n" +
+			"- **binary** sends the event attributes as ce- prefixed headers and the event data as the JSON body.\n",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            string(CloudEventFormatStructured),
+		Choices: []any{
+			string(CloudEventFormatStructured),
+			string(CloudEventFormatBinary),
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionEventMode = SettingDefinition{
+		DisplayName: "Pod Deletion Event Mode When Node is Down",
+		Description: "How Longhorn reports the pods it force deletes on a down node with Kubernetes events.\n" +
+			"- **per-pod** records an event on each force deleted pod.\n" +
+			"- **summary** records a single event on the Longhorn node per failover batch, counting the pods force deleted on the node within 30 seconds after the first one.\n",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            string(NodeDownPodDeletionEventModePerPod),
+		Choices: []any{
+			string(NodeDownPodDeletionEventModePerPod),
+			string(NodeDownPodDeletionEventModeSummary),
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionFencingEndpoint = SettingDefinition{
+		DisplayName: "Pod Deletion Fencing Endpoint When Node is Down",
+		Description: "The URL of an HTTP endpoint of a node fencing system, which Longhorn asks to power fence a down node before force deleting its pods according to the Pod Deletion Policy When Node is Down. " +
+			"Longhorn sends a POST request with the node as a JSON object, and proceeds only if the endpoint responds with status 200 and {\"fenced\": true}. " +
+			"The force deletion is denied if the endpoint cannot be reached, times out or does not confirm the fencing. " +
+			"Leave it empty to force delete the pods without fencing the node.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           false,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "",
+	}
+
+	SettingDefinitionNodeDownPodDeletionFencingTimeout = SettingDefinition{
+		DisplayName:        "Pod Deletion Fencing Timeout When Node is Down",
+		Description:        "In seconds. The timeout of a request to the Pod Deletion Fencing Endpoint, including the time to power fence the node.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "60",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 1,
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionGracefulShutdownTimeout = SettingDefinition{
+		DisplayName: "Pod Deletion Graceful Shutdown Timeout When Node is Down",
+		Description: "In seconds. How long Longhorn leaves the termination of the pods to the kubelet of a node shutting down gracefully, " +
+			"before force deleting them as on any down node. 0 means the graceful node shutdown is not waited for.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "300",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 0,
+		},
+	}
+
+	SettingDefinitionNodeDownPodDeletionConfigAnnotation = SettingDefinition{
+		DisplayName: "Pod Deletion Config Annotation When Node is Down",
+		Description: "Annotate each pod using Longhorn volumes with the force deletion configuration resolved for it, " +
+			"merging the Pod Deletion Policy When Node is Down, the opt-out of its persistent volumes and the grace periods, " +
+			"so that operators can see how Longhorn would handle the pod once its node is down.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeBool,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "false",
+	}
+
+	SettingDefinitionNodeDownPodDeletionVerificationDelay = SettingDefinition{
+		DisplayName: "Pod Deletion Verification Delay When Node is Down",
+		Description: "In seconds. How long after force deleting a pod on a down node Longhorn verifies the outcome: " +
+			"that the pod is gone from the API, and that no replacement pod using its volumes is stuck pending on a volume that is not attached or faulted. " +
+			"A warning event is recorded on the pod otherwise. 0 means the force deletions are not verified.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "0",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 0,
+		},
+	}
+
+	SettingDefinitionNodeDownSignals = SettingDefinition{
+		DisplayName: "Node Down Signals",
+		Description: "The signals Longhorn combines to decide whether a node is down before force deleting its pods, separated by commas.\n" +
+			"- **ready-condition** The Ready condition of the Longhorn node reports the Kubernetes node gone or not ready.\n" +
+			"- **heartbeat** The lease of the Kubernetes node has not been renewed for the Node Down Heartbeat Timeout.\n" +
+			"- **taint** The Kubernetes node has the taint of the Node Down Taint Key.\n" +
+			"- **instance-manager** None of the instance manager pods of the node is running and ready.\n" +
+			"Signals of a manager compiled with custom ones can be used by their names as well. A node deleted from Longhorn is always down.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            string(NodeDownSignalReadyCondition),
+	}
+
+	SettingDefinitionNodeDownSignalsOperator = SettingDefinition{
+		DisplayName: "Node Down Signals Operator",
+		Description: "How Longhorn combines the Node Down Signals.\n" +
+			"- **any** A node is down once any of the signals reports it down.\n" +
+			"- **all** A node is down only once all the signals report it down.\n",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            string(NodeDownSignalsOperatorAny),
+		Choices: []any{
+			string(NodeDownSignalsOperatorAny),
+			string(NodeDownSignalsOperatorAll),
+		},
+	}
+
+	SettingDefinitionNodeDownHeartbeatTimeout = SettingDefinition{
+		DisplayName:        "Node Down Heartbeat Timeout",
+		Description:        "In seconds. How long the lease of a Kubernetes node may go without being renewed before the heartbeat signal reports the node down.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "40",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 1,
+		},
+	}
+
+	SettingDefinitionNodeDownTaintKey = SettingDefinition{
+		DisplayName:        "Node Down Taint Key",
+		Description:        "The key of the taint with which the taint signal reports a Kubernetes node down. Leave it empty so that the taint signal never reports a node down.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeString,
+		Required:           false,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            corev1.TaintNodeOutOfService,
+	}
+
+	SettingDefinitionNodeNotReadyGracePeriod = SettingDefinition{
+		DisplayName: "Node Not Ready Grace Period",
+		Description: "In seconds. How long the Kubernetes node of a Longhorn node must be continuously not ready before Longhorn treats the node as down, " +
+			"for example to force delete its pods, so that a brief network blip does not make the node down. " +
+			"A deleted node is down right away. 0 means a not ready node is down right away.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "0",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 0,
+		},
+	}
+
+	SettingDefinitionVolumeIdleThreshold = SettingDefinition{
+		DisplayName: "Volume Idle Threshold",
+		Description: "In days. How long a volume may go without any pod consuming it before Longhorn reports it idle, " +
+			"by the Idle condition of the volume and the longhorn_volume_idle metric, so that it can be considered for cleanup. " +
+			"Set it to 0 to disable the idle volume detection.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "0",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 0,
 		},
 	}
 
@@ -1015,13 +1672,13 @@ var (
 
 	SettingDefinitionDisableRevisionCounter = SettingDefinition{
 		DisplayName:        "Disable Revision Counter",
-		Description:        "This setting is only for volumes created by UI. By default, this is true meaning Longhorn will not have revision counter file to track every write to the volume. During the salvage recovering, Longhorn will use the 'volume-head-xxx.img' file last modification time and file size to pick the replica candidate to recover the whole volume. If this setting is false, there will be a revision counter file to track every write to the volume. During salvage recovering Longhorn will pick the replica with largest revision counter as candidate to recover the whole volume.",
+		Description:        "This setting is for volumes created by UI, and for volumes created by a StorageClass without the 'disableRevisionCounter' parameter. By default, this is true meaning Longhorn will not have revision counter file to track every write to the volume. During the salvage recovering, Longhorn will use the 'volume-head-xxx.img' file last modification time and file size to pick the replica candidate to recover the whole volume. If this setting is false, there will be a revision counter file to track every write to the volume. During salvage recovering Longhorn will pick the replica with largest revision counter as candidate to recover the whole volume.",
 		Category:           SettingCategoryGeneral,
 		Type:               SettingTypeBool,
 		Required:           true,
 		ReadOnly:           false,
 		DataEngineSpecific: true,
-		Default:            fmt.Sprintf("{%q:\"true\"}", longhorn.DataEngineTypeV1),
+		Default:            fmt.Sprintf("{%q:\"true\",%q:\"true\"}", longhorn.DataEngineTypeV1, longhorn.DataEngineTypeV2),
 	}
 
 	SettingDefinitionReplicaReplenishmentWaitInterval = SettingDefinition{
@@ -1432,7 +2089,8 @@ var (
 
 	SettingDefinitionSnapshotDataIntegrityCronJob = SettingDefinition{
 		DisplayName: "Snapshot Data Integrity Check CronJob",
-		Description: "Unix-cron string format. The setting specifies when Longhorn checks the data integrity of snapshot disk files. \n\n" +
+		Description: "Unix-cron string format. The setting specifies when Longhorn checks the data integrity of snapshot disk files. " +
+			"A volume can use its own schedule instead, set by the StorageClass parameter snapshotDataIntegrityCronJob. \n\n" +
 			"Warning: Hashing snapshot disk files impacts the performance of the system. It is recommended to run data integrity checks during off-peak times and to reduce the frequency of checks.",
 		Category:           SettingCategorySnapshot,
 		Type:               SettingTypeString,
@@ -1827,6 +2485,21 @@ var (
 		Default:            "false",
 	}
 
+	SettingDefinitionAutoDowngradeFromRWXPeriod = SettingDefinition{
+		DisplayName: "Auto Downgrade From RWX Period",
+		Description: "The time in minutes an RWX volume with `autoDowngradeFromRWX` enabled must be used by a single consumer before its access mode is downgraded to RWO. " +
+			"The downgrade happens only while the volume is detached, and only for a volume claimed by a StatefulSet volume claim template, since no other pod of the workload can consume it.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeInt,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "10",
+		ValueIntRange: map[string]int{
+			ValueIntRangeMinimum: 1,
+		},
+	}
+
 	SettingDefinitionOfflineReplicaRebuilding = SettingDefinition{
 		DisplayName: "Offline Replica Rebuilding",
 		Description: "Enables automatic rebuilding of degraded replicas while the volume is detached. This setting only takes effect if the individual volume setting is set to `ignored` or `enabled`. \n\n" +
@@ -1842,6 +2515,21 @@ var (
 		Default:            fmt.Sprintf("{%q:\"false\",%q:\"false\"}", longhorn.DataEngineTypeV1, longhorn.DataEngineTypeV2),
 	}
 
+	SettingDefinitionReplicaRebuilding = SettingDefinition{
+		DisplayName: "Replica Rebuilding",
+		Description: "Enables the rebuilding of failed replicas. This setting only takes effect if the individual volume setting is set to `ignored`. \n\n" +
+			"Available options: \n\n" +
+			"- **true**: Rebuilds the failed replicas of all volumes, unless overridden by individual volume settings. \n\n" +
+			"- **false**: Does not rebuild the failed replicas, unless overridden by individual volume settings. \n\n" +
+			"**Note:** Longhorn still rebuilds a replica if a volume is left with a single healthy replica.",
+		Category:           SettingCategoryGeneral,
+		Type:               SettingTypeBool,
+		Required:           true,
+		ReadOnly:           false,
+		DataEngineSpecific: false,
+		Default:            "true",
+	}
+
 	SettingDefinitionLogPath = SettingDefinition{
 		DisplayName:        "Log Path",
 		Description:        "Specifies the directory on the host where Longhorn stores log files for the instance manager pod. Currently, it is only used for instance manager pods in the v2 data engine.",
@@ -1888,6 +2576,60 @@ const (
 	NodeDownPodDeletionPolicyDeleteStatefulSetPod                  = NodeDownPodDeletionPolicy("delete-statefulset-pod")
 	NodeDownPodDeletionPolicyDeleteDeploymentPod                   = NodeDownPodDeletionPolicy("delete-deployment-pod")
 	NodeDownPodDeletionPolicyDeleteBothStatefulsetAndDeploymentPod = NodeDownPodDeletionPolicy("delete-both-statefulset-and-deployment-pod")
+	NodeDownPodDeletionPolicyDeleteJobPod                          = NodeDownPodDeletionPolicy("delete-job-pod")
+)
+
+// ParseNodeDownPodDeletionPolicy returns the node down pod deletion policy of the value, or an error if it is not a
+// known policy.
+func ParseNodeDownPodDeletionPolicy(value string) (NodeDownPodDeletionPolicy, error) {
+	policy := NodeDownPodDeletionPolicy(value)
+	switch policy {
+	case NodeDownPodDeletionPolicyDoNothing,
+		NodeDownPodDeletionPolicyDeleteStatefulSetPod,
+		NodeDownPodDeletionPolicyDeleteDeploymentPod,
+		NodeDownPodDeletionPolicyDeleteBothStatefulsetAndDeploymentPod,
+		NodeDownPodDeletionPolicyDeleteJobPod:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown node down pod deletion policy %q", value)
+}
+
+type NodeDownSignal string
+
+const (
+	NodeDownSignalReadyCondition  = NodeDownSignal("ready-condition")
+	NodeDownSignalHeartbeat       = NodeDownSignal("heartbeat")
+	NodeDownSignalTaint           = NodeDownSignal("taint")
+	NodeDownSignalInstanceManager = NodeDownSignal("instance-manager")
+)
+
+type NodeDownSignalsOperator string
+
+const (
+	NodeDownSignalsOperatorAny = NodeDownSignalsOperator("any")
+	NodeDownSignalsOperatorAll = NodeDownSignalsOperator("all")
+)
+
+type CloudEventFormat string
+
+const (
+	CloudEventFormatStructured = CloudEventFormat("structured")
+	CloudEventFormatBinary     = CloudEventFormat("binary")
+)
+
+type NodeDownPodDeletionEventMode string
+
+const (
+	NodeDownPodDeletionEventModePerPod  = NodeDownPodDeletionEventMode("per-pod")
+	NodeDownPodDeletionEventModeSummary = NodeDownPodDeletionEventMode("summary")
+)
+
+type NodeDownPodDeletionPDBMode string
+
+const (
+	NodeDownPodDeletionPDBModeIgnore = NodeDownPodDeletionPDBMode("ignore")
+	NodeDownPodDeletionPDBModeWarn   = NodeDownPodDeletionPDBMode("warn")
+	NodeDownPodDeletionPDBModeSkip   = NodeDownPodDeletionPDBMode("skip")
 )
 
 type NodeDrainPolicy string
@@ -2150,6 +2892,102 @@ func UnmarshalOrphanResourceTypes(resourceTypesSetting string) (map[OrphanResour
 	return resourceTypes, nil
 }
 
+// UnmarshalPriorityClassGracePeriods parses a list of `<priority class name>:<seconds>` separated by semicolons.
+func UnmarshalPriorityClassGracePeriods(gracePeriodsSetting string) (map[string]time.Duration, error) {
+	gracePeriods := map[string]time.Duration{}
+
+	gracePeriodsSetting = strings.Trim(gracePeriodsSetting, " ")
+	if gracePeriodsSetting == "" {
+		return gracePeriods, nil
+	}
+
+	for _, item := range strings.Split(gracePeriodsSetting, ";") {
+		item = strings.Trim(item, " ")
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) != 2 || strings.Trim(parts[0], " ") == "" {
+			return nil, fmt.Errorf("invalid priority class grace period %q, it should be <priority class name>:<seconds>", item)
+		}
+		seconds, err := strconv.ParseInt(strings.Trim(parts[1], " "), 10, 64)
+		if err != nil || seconds < 0 {
+			return nil, fmt.Errorf("invalid grace period %q of priority class %v, it should be a non-negative number of seconds", parts[1], parts[0])
+		}
+		gracePeriods[strings.Trim(parts[0], " ")] = time.Duration(seconds) * time.Second
+	}
+	return gracePeriods, nil
+}
+
+// UnmarshalNodeDownSignals parses a list of node down signals separated by commas.
+func UnmarshalNodeDownSignals(signalsSetting string) ([]NodeDownSignal, error) {
+	signals := []NodeDownSignal{}
+	for _, item := range strings.Split(signalsSetting, ",") {
+		item = strings.Trim(item, " ")
+		if item == "" {
+			continue
+		}
+		if slices.Contains(signals, NodeDownSignal(item)) {
+			return nil, fmt.Errorf("duplicate node down signal %v", item)
+		}
+		signals = append(signals, NodeDownSignal(item))
+	}
+	if len(signals) == 0 {
+		return nil, fmt.Errorf("no node down signal")
+	}
+	return signals, nil
+}
+
+// UnmarshalNodeDownPodDeletionNamespaces parses a list of namespace names separated by commas.
+// An empty list means all namespaces.
+func UnmarshalNodeDownPodDeletionNamespaces(namespacesSetting string) ([]string, error) {
+	namespaces := []string{}
+	for _, item := range strings.Split(namespacesSetting, ",") {
+		item = strings.Trim(item, " ")
+		if item == "" {
+			continue
+		}
+		if errs := validation.IsDNS1123Label(item); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid namespace %q: %v", item, strings.Join(errs, ", "))
+		}
+		if slices.Contains(namespaces, item) {
+			return nil, fmt.Errorf("duplicate namespace %v", item)
+		}
+		namespaces = append(namespaces, item)
+	}
+	return namespaces, nil
+}
+
+// NamespaceForceDeletionQuotaDefault is the namespace of the quota applying to the namespaces not listed.
+const NamespaceForceDeletionQuotaDefault = "*"
+
+// UnmarshalNamespaceForceDeletionQuotas parses a list of `<namespace>:<count>` separated by semicolons.
+func UnmarshalNamespaceForceDeletionQuotas(quotasSetting string) (map[string]int, error) {
+	quotas := map[string]int{}
+
+	quotasSetting = strings.Trim(quotasSetting, " ")
+	if quotasSetting == "" {
+		return quotas, nil
+	}
+
+	for _, item := range strings.Split(quotasSetting, ";") {
+		item = strings.Trim(item, " ")
+		if item == "" {
+			continue
+		}
+		parts := strings.Split(item, ":")
+		if len(parts) != 2 || strings.Trim(parts[0], " ") == "" {
+			return nil, fmt.Errorf("invalid namespace quota %q, it should be <namespace>:<count>", item)
+		}
+		count, err := strconv.Atoi(strings.Trim(parts[1], " "))
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid quota %q of namespace %v, it should be a non-negative number", parts[1], parts[0])
+		}
+		quotas[strings.Trim(parts[0], " ")] = count
+	}
+	return quotas, nil
+}
+
 func IsSettingReplaced(name SettingName) bool {
 	return replacedSettingNames[name]
 }
@@ -2634,6 +3472,51 @@ func validateSettingString(name SettingName, definition SettingDefinition, value
 			if _, err := UnmarshalOrphanResourceTypes(strValue); err != nil {
 				return errors.Wrapf(err, "the value of %v is invalid", name)
 			}
+
+		case SettingNameNodeDownPodDeletionPriorityClassGracePeriods:
+			if _, err := UnmarshalPriorityClassGracePeriods(strValue); err != nil {
+				return errors.Wrapf(err, "the value of %v is invalid", name)
+			}
+
+		case SettingNameNodeDownPodDeletionNamespaceQuotas:
+			if _, err := UnmarshalNamespaceForceDeletionQuotas(strValue); err != nil {
+				return errors.Wrapf(err, "the value of %v is invalid", name)
+			}
+
+		case SettingNameNodeDownSignals:
+			if _, err := UnmarshalNodeDownSignals(strValue); err != nil {
+				return errors.Wrapf(err, "the value of %v is invalid", name)
+			}
+
+		case SettingNameNodeDownTaintKey:
+			if strValue == "" {
+				break
+			}
+			if errs := validation.IsQualifiedName(strValue); len(errs) > 0 {
+				return fmt.Errorf("the value of %v is invalid: %v", name, strings.Join(errs, ", "))
+			}
+
+		case SettingNameNodeDownPodDeletionSelector:
+			if _, err := labels.Parse(strValue); err != nil {
+				return errors.Wrapf(err, "the value of %v is invalid", name)
+			}
+
+		case SettingNameNodeDownPodDeletionNamespaces:
+			if _, err := UnmarshalNodeDownPodDeletionNamespaces(strValue); err != nil {
+				return errors.Wrapf(err, "the value of %v is invalid", name)
+			}
+
+		case SettingNameNodeDownPodDeletionApprovalWebhookURL, SettingNameNodeDownPodDeletionCloudEventSinkURL, SettingNameNodeDownPodDeletionFencingEndpoint:
+			if strValue == "" {
+				break
+			}
+			u, err := url.Parse(strValue)
+			if err != nil {
+				return errors.Wrapf(err, "the value of %v is invalid", name)
+			}
+			if u.Scheme != "http" && u.Scheme != "https" {
+				return fmt.Errorf("the value of %v is invalid: unsupported scheme %v", name, u.Scheme)
+			}
 		}
 	}
 
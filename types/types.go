@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -14,9 +15,13 @@ import (
 	"unsafe"
 
 	"github.com/cockroachdb/errors"
+	"github.com/distribution/reference"
+	"github.com/robfig/cron"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
 
+	"k8s.io/apimachinery/pkg/util/validation"
+
 	lhns "github.com/longhorn/go-common-libs/ns"
 
 	"github.com/longhorn/longhorn-manager/util"
@@ -176,6 +181,7 @@ const (
 	LonghornLabelBackingImage               = "backing-image"
 	LonghornLabelBackingImageManager        = "backing-image-manager"
 	LonghornLabelManagedBy                  = "managed-by"
+	LonghornLabelManaged                    = "managed"
 	LonghornLabelSnapshotForCloningVolume   = "for-cloning-volume"
 	LonghornLabelBackingImageDataSource     = "backing-image-data-source"
 	LonghornLabelBackupTarget               = "backup-target"
@@ -217,6 +223,11 @@ const (
 
 	LonghornDriverName = "driver.longhorn.io"
 
+	// DefaultEncryptionSecretName and DefaultEncryptionSecretNamespace locate the secret of the encryption key
+	// referenced by the PV of an encrypted volume, unless another secret is given.
+	DefaultEncryptionSecretName      = "longhorn-crypto"
+	DefaultEncryptionSecretNamespace = "longhorn-system"
+
 	DefaultDiskPrefix = "default-disk-"
 
 	DeprecatedProvisionerName           = "rancher.io/longhorn"
@@ -230,6 +241,19 @@ const (
 	DefaultRecurringJobConcurrency = 10
 
 	PVAnnotationLonghornVolumeSchedulingError = "longhorn.io/volume-scheduling-error"
+	PVCAnnotationLonghornVolumeName           = "longhorn.io/volume-name"
+
+	// PVVolumeAttributeDisableNodeDownPodDeletion is the CSI volume attribute of a PV that opts the pods using it out of
+	// the force deletion on down nodes, so that they are left for an operator to delete.
+	PVVolumeAttributeDisableNodeDownPodDeletion = "disableNodeDownPodDeletion"
+
+	// PodAnnotationNodeDownPodDeletionConfig is the annotation of a pod using Longhorn volumes with the force deletion
+	// configuration resolved for it.
+	PodAnnotationNodeDownPodDeletionConfig = "longhorn.io/node-down-pod-deletion-config"
+
+	// PVCAnnotationNodeDownPodDeletionPolicy is the annotation of a PVC overriding the node down pod deletion policy
+	// setting for the pods using the PVC.
+	PVCAnnotationNodeDownPodDeletionPolicy = "longhorn.io/node-down-pod-deletion"
 
 	CniNetworkNone           = ""
 	StorageNetworkInterface  = "lhnet1" // Data plane network
@@ -935,6 +959,15 @@ func ValidateReplicaAutoBalance(option longhorn.ReplicaAutoBalance) error {
 	}
 }
 
+// ValidateReplicaAutoBalanceDiskPressurePercentage validates the disk pressure percentage of a volume, where 0 means
+// the replica-auto-balance-disk-pressure-percentage setting is used.
+func ValidateReplicaAutoBalanceDiskPressurePercentage(percentage int64) error {
+	if percentage < 0 || percentage > 100 {
+		return fmt.Errorf("invalid replica auto-balance disk pressure percentage %v, must be between 0 and 100", percentage)
+	}
+	return nil
+}
+
 func ValidateDataLocality(mode longhorn.DataLocality) error {
 	if mode != longhorn.DataLocalityDisabled && mode != longhorn.DataLocalityBestEffort && mode != longhorn.DataLocalityStrictLocal {
 		return fmt.Errorf("invalid data locality mode: %v", mode)
@@ -1024,12 +1057,26 @@ func ValidateReplicaDiskSoftAntiAffinity(value longhorn.ReplicaDiskSoftAntiAffin
 func ValidateFreezeFilesystemForSnapshot(value longhorn.FreezeFilesystemForSnapshot) error {
 	if value != longhorn.FreezeFilesystemForSnapshotDefault &&
 		value != longhorn.FreezeFilesystemForSnapshotEnabled &&
-		value != longhorn.FreezeFilesystemForSnapshotDisabled {
+		value != longhorn.FreezeFilesystemForSnapshotDisabled &&
+		value != longhorn.FreezeFilesystemForSnapshotAppManaged {
 		return fmt.Errorf("invalid FreezeFilesystemForSnapshot setting: %v", value)
 	}
 	return nil
 }
 
+// IsFreezeFilesystemForSnapshotEnabled returns whether the filesystem of the volume is frozen before a snapshot,
+// from the volume value, or else from the freeze filesystem for snapshot setting.
+func IsFreezeFilesystemForSnapshotEnabled(value longhorn.FreezeFilesystemForSnapshot, settingEnabled bool) bool {
+	switch value {
+	case longhorn.FreezeFilesystemForSnapshotEnabled:
+		return true
+	case longhorn.FreezeFilesystemForSnapshotDisabled, longhorn.FreezeFilesystemForSnapshotAppManaged:
+		return false
+	default:
+		return settingEnabled
+	}
+}
+
 func ValidateOfflineRebuild(value longhorn.VolumeOfflineRebuilding) error {
 	if value != longhorn.VolumeOfflineRebuildingDisabled &&
 		value != longhorn.VolumeOfflineRebuildingEnabled &&
@@ -1039,6 +1086,133 @@ func ValidateOfflineRebuild(value longhorn.VolumeOfflineRebuilding) error {
 	return nil
 }
 
+func ValidateReplicaRebuilding(value longhorn.VolumeReplicaRebuilding) error {
+	if value != longhorn.VolumeReplicaRebuildingDisabled &&
+		value != longhorn.VolumeReplicaRebuildingEnabled &&
+		value != longhorn.VolumeReplicaRebuildingIgnored {
+		return fmt.Errorf("invalid ReplicaRebuilding setting: %v", value)
+	}
+	return nil
+}
+
+func ValidateSnapshotMaxSizeAction(value longhorn.SnapshotMaxSizeAction) error {
+	if value != longhorn.SnapshotMaxSizeActionBlock &&
+		value != longhorn.SnapshotMaxSizeActionPrune {
+		return fmt.Errorf("invalid SnapshotMaxSizeAction setting: %v", value)
+	}
+	return nil
+}
+
+// ValidateRecurringSnapshotOverflowAction validates the recurring snapshot overflow action of a volume,
+// which is empty if the volume leaves the new snapshot to fail once it reaches SnapshotMaxCount.
+func ValidateRecurringSnapshotOverflowAction(value longhorn.RecurringSnapshotOverflowAction) error {
+	if value != "" &&
+		value != longhorn.RecurringSnapshotOverflowActionSkip &&
+		value != longhorn.RecurringSnapshotOverflowActionPrune {
+		return fmt.Errorf("invalid RecurringSnapshotOverflowAction setting: %v", value)
+	}
+	return nil
+}
+
+// ValidateSnapshotDataIntegrityCronJob validates the data integrity check schedule of a volume,
+// which is empty if the volume uses the snapshot-data-integrity-cronjob setting.
+func ValidateSnapshotDataIntegrityCronJob(cronJob string) error {
+	if cronJob == "" {
+		return nil
+	}
+	if _, err := cron.ParseStandard(cronJob); err != nil {
+		return errors.Wrapf(err, "invalid snapshot data integrity cron job %v", cronJob)
+	}
+	return nil
+}
+
+// DataEngineLogLevels are the supported v2 data engine log levels, from the least to the most verbose.
+var DataEngineLogLevels = []string{"Error", "Warning", "Notice", "Info", "Debug"}
+
+func ValidateDataEngineLogLevel(level string) error {
+	if level == "" || slices.Contains(DataEngineLogLevels, level) {
+		return nil
+	}
+	return fmt.Errorf("invalid data engine log level %v, supported levels are %v", level, DataEngineLogLevels)
+}
+
+func ValidateBackingImageCleanupPolicy(value longhorn.BackingImageCleanupPolicy) error {
+	if value != longhorn.BackingImageCleanupPolicyRetain &&
+		value != longhorn.BackingImageCleanupPolicyDelete {
+		return fmt.Errorf("invalid BackingImageCleanupPolicy setting: %v", value)
+	}
+	return nil
+}
+
+// ValidateSnapshotReclaimThreshold skips the volume size check if volSize set to 0.
+func ValidateSnapshotReclaimThreshold(volSize int64, threshold string) error {
+	size, err := GetSnapshotReclaimThresholdSize(threshold, volSize)
+	if err != nil {
+		return errors.Wrap(err, "invalid SnapshotReclaimThreshold")
+	}
+	if volSize > 0 && size > volSize {
+		return fmt.Errorf("SnapshotReclaimThreshold %v should not be larger than the volume size %v", threshold, volSize)
+	}
+	return nil
+}
+
+// ValidateMaxSize checks that the volume size does not exceed the maximum size the volume can be expanded to.
+func ValidateMaxSize(size, maxSize int64) error {
+	if maxSize < 0 {
+		return fmt.Errorf("MaxSize %v should not be negative", maxSize)
+	}
+	if maxSize > 0 && size > maxSize {
+		return fmt.Errorf("volume size %v should not be larger than MaxSize %v", size, maxSize)
+	}
+	return nil
+}
+
+func ValidateStandbyRestoreInterval(interval int) error {
+	if interval < 0 {
+		return fmt.Errorf("StandbyRestoreInterval %v should not be negative", interval)
+	}
+	return nil
+}
+
+// ValidateAutoDowngradeFromRWX checks that the rwx access mode auto downgrade is applicable to the volume.
+func ValidateAutoDowngradeFromRWX(autoDowngradeFromRWX, migratable bool, accessMode longhorn.AccessMode) error {
+	if !autoDowngradeFromRWX {
+		return nil
+	}
+	if migratable {
+		return fmt.Errorf("AutoDowngradeFromRWX is not supported by migratable volumes")
+	}
+	if accessMode == longhorn.AccessModeReadWriteOncePod {
+		return fmt.Errorf("AutoDowngradeFromRWX is not supported by access mode %v", accessMode)
+	}
+	return nil
+}
+
+// ValidateInstanceManagerImage allows an empty image, which means the default instance manager image.
+func ValidateInstanceManagerImage(dataEngine longhorn.DataEngineType, image string) error {
+	if image == "" {
+		return nil
+	}
+	if !IsDataEngineV2(dataEngine) {
+		return fmt.Errorf("InstanceManagerImage is only supported by data engine %v", longhorn.DataEngineTypeV2)
+	}
+	if _, err := reference.ParseNormalizedNamed(image); err != nil {
+		return errors.Wrapf(err, "invalid InstanceManagerImage %v", image)
+	}
+	return nil
+}
+
+// ValidateEngineImagePullSecret allows an empty secret name, which means no secret is referenced.
+func ValidateEngineImagePullSecret(secret string) error {
+	if secret == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(secret); len(errs) > 0 {
+		return fmt.Errorf("invalid EngineImagePullSecret %v: %v", secret, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
 // ValidateBackupBlockSize skips the volume size check if volSize set to negative.
 func ValidateBackupBlockSize(volSize int64, backupBlockSize int64) error {
 	if backupBlockSize != BackupBlockSize2Mi && backupBlockSize != BackupBlockSize16Mi {
@@ -1050,6 +1224,18 @@ func ValidateBackupBlockSize(volSize int64, backupBlockSize int64) error {
 	return nil
 }
 
+// ValidateBackupBlockSizeForDataEngine checks the backup block size is supported by the data engine.
+// The v2 data engine backs up in 2 MiB blocks only.
+func ValidateBackupBlockSizeForDataEngine(dataEngine longhorn.DataEngineType, backupBlockSize int64) error {
+	if err := ValidateBackupBlockSize(-1, backupBlockSize); err != nil {
+		return err
+	}
+	if IsDataEngineV2(dataEngine) && backupBlockSize != BackupBlockSize2Mi {
+		return fmt.Errorf("BackupBlockSize %v is not supported by data engine %v, only %v is supported", backupBlockSize, dataEngine, BackupBlockSize2Mi)
+	}
+	return nil
+}
+
 func ValidateReplicaRebuildingBandwidthLimit(dataEengine longhorn.DataEngineType, replicaRebuildingBandwidthLimit int64) error {
 	if replicaRebuildingBandwidthLimit == 0 {
 		return nil
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
 	corev1 "k8s.io/api/core/v1"
 
+	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
+
 	. "gopkg.in/check.v1"
 )
 
@@ -233,3 +236,177 @@ func (s *TestSuite) TestGenerateEngineNameForVolume(c *C) {
 		c.Assert(actual, Equals, testCase.expectedEngineName, Commentf(TestErrResultFmt, testName))
 	}
 }
+
+func (s *TestSuite) TestNormalizeSnapshotReclaimThreshold(c *C) {
+	testCases := map[string]struct {
+		threshold string
+
+		expectedThreshold string
+		expectedErr       bool
+	}{
+		"empty": {
+			threshold:         "",
+			expectedThreshold: "",
+		},
+		"percentage": {
+			threshold:         "80%",
+			expectedThreshold: "80%",
+		},
+		"size": {
+			threshold:         "10Gi",
+			expectedThreshold: "10737418240",
+		},
+		"bytes": {
+			threshold:         "10737418240",
+			expectedThreshold: "10737418240",
+		},
+		"invalid size": {
+			threshold:   "10Gx",
+			expectedErr: true,
+		},
+	}
+
+	for testName, testCase := range testCases {
+		fmt.Printf("testing %v\n", testName)
+
+		actual, err := NormalizeSnapshotReclaimThreshold(testCase.threshold)
+		if testCase.expectedErr {
+			c.Assert(err, NotNil, Commentf(TestErrErrorFmt, testName, err))
+			continue
+		}
+		c.Assert(err, IsNil, Commentf(TestErrErrorFmt, testName, err))
+		c.Assert(actual, Equals, testCase.expectedThreshold, Commentf(TestErrResultFmt, testName))
+	}
+}
+
+func (s *TestSuite) TestValidateSnapshotDataIntegrityCronJob(c *C) {
+	for _, cronJob := range []string{"", "0 0 */7 * *", "*/30 * * * *", "@daily"} {
+		c.Assert(ValidateSnapshotDataIntegrityCronJob(cronJob), IsNil, Commentf("cron job %q", cronJob))
+	}
+	for _, cronJob := range []string{"invalid", "0 0 * *", "61 * * * *", "0 0 */7 * * * *"} {
+		c.Assert(ValidateSnapshotDataIntegrityCronJob(cronJob), NotNil, Commentf("cron job %q", cronJob))
+	}
+}
+
+func (s *TestSuite) TestIsFreezeFilesystemForSnapshotEnabled(c *C) {
+	for _, settingEnabled := range []bool{true, false} {
+		c.Assert(IsFreezeFilesystemForSnapshotEnabled(longhorn.FreezeFilesystemForSnapshotDefault, settingEnabled), Equals, settingEnabled)
+		c.Assert(IsFreezeFilesystemForSnapshotEnabled(longhorn.FreezeFilesystemForSnapshotEnabled, settingEnabled), Equals, true)
+		c.Assert(IsFreezeFilesystemForSnapshotEnabled(longhorn.FreezeFilesystemForSnapshotDisabled, settingEnabled), Equals, false)
+		// The application quiesces itself, whatever the setting is.
+		c.Assert(IsFreezeFilesystemForSnapshotEnabled(longhorn.FreezeFilesystemForSnapshotAppManaged, settingEnabled), Equals, false)
+	}
+	c.Assert(ValidateFreezeFilesystemForSnapshot(longhorn.FreezeFilesystemForSnapshotAppManaged), IsNil)
+	c.Assert(ValidateFreezeFilesystemForSnapshot("app"), NotNil)
+}
+
+func (s *TestSuite) TestValidateDataEngineLogLevel(c *C) {
+	for _, level := range []string{"", "Error", "Warning", "Notice", "Info", "Debug"} {
+		c.Assert(ValidateDataEngineLogLevel(level), IsNil, Commentf("level %q", level))
+	}
+	for _, level := range []string{"debug", "Verbose", " "} {
+		c.Assert(ValidateDataEngineLogLevel(level), NotNil, Commentf("level %q", level))
+	}
+}
+
+func (s *TestSuite) TestUnmarshalPriorityClassGracePeriods(c *C) {
+	gracePeriods, err := UnmarshalPriorityClassGracePeriods("")
+	c.Assert(err, IsNil)
+	c.Assert(gracePeriods, HasLen, 0)
+
+	gracePeriods, err = UnmarshalPriorityClassGracePeriods(" system-cluster-critical:0; best-effort : 600 ;")
+	c.Assert(err, IsNil)
+	c.Assert(gracePeriods, DeepEquals, map[string]time.Duration{
+		"system-cluster-critical": 0,
+		"best-effort":             10 * time.Minute,
+	})
+
+	for _, value := range []string{"critical", "critical:-1", "critical:1m", ":10", "a:1:2"} {
+		_, err = UnmarshalPriorityClassGracePeriods(value)
+		c.Assert(err, NotNil, Commentf("value %q", value))
+	}
+}
+
+func (s *TestSuite) TestUnmarshalNodeDownSignals(c *C) {
+	signals, err := UnmarshalNodeDownSignals(" ready-condition, heartbeat ,custom,")
+	c.Assert(err, IsNil)
+	c.Assert(signals, DeepEquals, []NodeDownSignal{NodeDownSignalReadyCondition, NodeDownSignalHeartbeat, "custom"})
+
+	for _, value := range []string{"", " , ", "taint,taint"} {
+		_, err = UnmarshalNodeDownSignals(value)
+		c.Assert(err, NotNil, Commentf("value %q", value))
+	}
+}
+
+func (s *TestSuite) TestUnmarshalNamespaceForceDeletionQuotas(c *C) {
+	quotas, err := UnmarshalNamespaceForceDeletionQuotas("")
+	c.Assert(err, IsNil)
+	c.Assert(quotas, HasLen, 0)
+
+	quotas, err = UnmarshalNamespaceForceDeletionQuotas(" tenant-a:10; * : 2 ;tenant-b:0")
+	c.Assert(err, IsNil)
+	c.Assert(quotas, DeepEquals, map[string]int{
+		"tenant-a":                         10,
+		NamespaceForceDeletionQuotaDefault: 2,
+		"tenant-b":                         0,
+	})
+
+	for _, value := range []string{"tenant-a", "tenant-a:-1", "tenant-a:ten", ":10", "a:1:2"} {
+		_, err = UnmarshalNamespaceForceDeletionQuotas(value)
+		c.Assert(err, NotNil, Commentf("value %q", value))
+	}
+}
+
+func (s *TestSuite) TestUnmarshalNodeDownPodDeletionNamespaces(c *C) {
+	namespaces, err := UnmarshalNodeDownPodDeletionNamespaces("")
+	c.Assert(err, IsNil)
+	c.Assert(namespaces, HasLen, 0)
+
+	namespaces, err = UnmarshalNodeDownPodDeletionNamespaces(" tenant-a, tenant-b ,")
+	c.Assert(err, IsNil)
+	c.Assert(namespaces, DeepEquals, []string{"tenant-a", "tenant-b"})
+
+	for _, value := range []string{"Tenant-A", "tenant_a", "tenant-a,tenant-a", "-tenant"} {
+		_, err = UnmarshalNodeDownPodDeletionNamespaces(value)
+		c.Assert(err, NotNil, Commentf("value %q", value))
+	}
+}
+
+func (s *TestSuite) TestParseNodeDownPodDeletionPolicy(c *C) {
+	for _, value := range []string{"do-nothing", "delete-statefulset-pod", "delete-job-pod"} {
+		policy, err := ParseNodeDownPodDeletionPolicy(value)
+		c.Assert(err, IsNil, Commentf("value %q", value))
+		c.Assert(string(policy), Equals, value)
+	}
+	for _, value := range []string{"", "deployment", "Delete-StatefulSet-Pod"} {
+		_, err := ParseNodeDownPodDeletionPolicy(value)
+		c.Assert(err, NotNil, Commentf("value %q", value))
+	}
+}
+
+func (s *TestSuite) TestValidateNodeDownPodDeletionGracePeriod(c *C) {
+	for _, value := range []string{"0", "30"} {
+		c.Assert(ValidateSetting(string(SettingNameNodeDownPodDeletionGracePeriod), value), IsNil, Commentf("value %q", value))
+	}
+	for _, value := range []string{"-1", "30s"} {
+		c.Assert(ValidateSetting(string(SettingNameNodeDownPodDeletionGracePeriod), value), NotNil, Commentf("value %q", value))
+	}
+}
+
+func (s *TestSuite) TestValidateEngineImagePullSecret(c *C) {
+	for _, secret := range []string{"", "registry-secret", "registry.secret"} {
+		c.Assert(ValidateEngineImagePullSecret(secret), IsNil, Commentf("secret %q", secret))
+	}
+	for _, secret := range []string{"Registry-Secret", "registry_secret", "-registry-secret", "namespace/registry-secret"} {
+		c.Assert(ValidateEngineImagePullSecret(secret), NotNil, Commentf("secret %q", secret))
+	}
+}
+
+func (s *TestSuite) TestValidateNodeDownPodDeletionSelector(c *C) {
+	for _, value := range []string{"", "tier=best-effort", "tier in (best-effort,batch),app!=database"} {
+		c.Assert(ValidateSetting(string(SettingNameNodeDownPodDeletionSelector), value), IsNil, Commentf("value %q", value))
+	}
+	for _, value := range []string{"tier in (best-effort", "tier==="} {
+		c.Assert(ValidateSetting(string(SettingNameNodeDownPodDeletionSelector), value), NotNil, Commentf("value %q", value))
+	}
+}
@@ -2,6 +2,12 @@ package types
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/longhorn/longhorn-manager/util"
 
 	longhorn "github.com/longhorn/longhorn-manager/k8s/pkg/apis/longhorn/v1beta2"
 )
@@ -54,3 +60,46 @@ func IsVolumeReady(v *longhorn.Volume, vrs []*longhorn.Replica, volOp string) (r
 	}
 	return true, ""
 }
+
+// GetSnapshotReclaimThresholdSize converts a snapshot reclaim threshold, either a
+// percentage of the volume size like "80%" or a size like "10Gi", into bytes.
+// An empty threshold returns 0, which means there is no threshold.
+func GetSnapshotReclaimThresholdSize(threshold string, volumeSize int64) (int64, error) {
+	if threshold == "" {
+		return 0, nil
+	}
+
+	if percentage, ok := strings.CutSuffix(threshold, "%"); ok {
+		value, err := strconv.Atoi(percentage)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid percentage %v", threshold)
+		}
+		if value <= 0 || value > 100 {
+			return 0, fmt.Errorf("percentage %v should be in range (0, 100]", threshold)
+		}
+		return volumeSize * int64(value) / 100, nil
+	}
+
+	size, err := util.ConvertSize(threshold)
+	if err != nil {
+		return 0, err
+	}
+	if size <= 0 {
+		return 0, fmt.Errorf("size %v should be greater than 0", threshold)
+	}
+	return size, nil
+}
+
+// NormalizeSnapshotReclaimThreshold returns the canonical form of a snapshot reclaim threshold
+// stored in the volume spec: a percentage is kept as is and a size is converted into bytes.
+func NormalizeSnapshotReclaimThreshold(threshold string) (string, error) {
+	if threshold == "" || strings.HasSuffix(threshold, "%") {
+		return threshold, nil
+	}
+
+	size, err := util.ConvertSize(threshold)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(size, 10), nil
+}
@@ -123,6 +123,15 @@ func upgradeVolumes(namespace string, lhClient *lhclientset.Clientset, resourceM
 		if v.Spec.BackupBlockSize == 0 {
 			v.Spec.BackupBlockSize = types.BackupBlockSize2Mi
 		}
+		if v.Spec.BackingImageCleanupPolicy == "" {
+			v.Spec.BackingImageCleanupPolicy = longhorn.BackingImageCleanupPolicyRetain
+		}
+		if v.Spec.SnapshotMaxSizeAction == "" {
+			v.Spec.SnapshotMaxSizeAction = longhorn.SnapshotMaxSizeActionBlock
+		}
+		if v.Spec.ReplicaRebuilding == "" {
+			v.Spec.ReplicaRebuilding = longhorn.VolumeReplicaRebuildingIgnored
+		}
 	}
 
 	return nil
@@ -12,6 +12,7 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/tools/clientcmd"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -86,6 +87,12 @@ func NewClients(kubeconfigPath string, needDataStore bool, stopCh <-chan struct{
 
 	var ds *datastore.DataStore
 	if needDataStore {
+		// Create metadata client to retrieve the metadata of objects of any kind
+		metadataClient, err := metadata.NewForConfig(config)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get metadata client")
+		}
+
 		// TODO: there shouldn't be a need for a 30s resync period unless our code is buggy and our controllers aren't really
 		//  level based. What we are effectively doing with this is hiding faulty logic in production.
 		//  Another reason for increasing this substantially, is that it introduces a lot of unnecessary work and will
@@ -94,6 +101,7 @@ func NewClients(kubeconfigPath string, needDataStore bool, stopCh <-chan struct{
 		//  some refs to look at: https://github.com/kubernetes-sigs/controller-runtime/issues/521
 		informerFactories := util.NewInformerFactories(namespace, clients.K8s, lhClient, 30*time.Second)
 		ds = datastore.NewDataStore(namespace, lhClient, clients.K8s, extensionsClient, informerFactories)
+		ds.SetMetadataClient(metadataClient)
 
 		informerFactories.Start(stopCh)
 		if !ds.Sync(stopCh) {
@@ -216,11 +216,16 @@ func (v *volumeMutator) Create(request *admission.Request, newObj runtime.Object
 		return nil, werror.NewInvalidError(fmt.Sprintf("invalid empty setting %s", defaultImageSetting), "")
 	}
 	if types.IsDataEngineV2(volume.Spec.DataEngine) {
-		activeInstanceManagerImage, err := v.getActiveInstanceManagerImage(defaultImage)
-		if err != nil {
-			return nil, werror.NewInvalidError(fmt.Sprintf("failed to get active instance manager image for volume %v: %v", name, err), "")
+		if volume.Spec.InstanceManagerImage != "" {
+			// The volume is pinned to the instance manager image, e.g., for a staged rollout.
+			defaultImage = volume.Spec.InstanceManagerImage
+		} else {
+			activeInstanceManagerImage, err := v.getActiveInstanceManagerImage(defaultImage)
+			if err != nil {
+				return nil, werror.NewInvalidError(fmt.Sprintf("failed to get active instance manager image for volume %v: %v", name, err), "")
+			}
+			defaultImage = activeInstanceManagerImage
 		}
-		defaultImage = activeInstanceManagerImage
 	}
 
 	patchOps = append(patchOps, fmt.Sprintf(`{"op": "replace", "path": "/spec/image", "value": "%s"}`, defaultImage))
@@ -375,6 +380,20 @@ func (v *volumeMutator) mutate(newObj runtime.Object, moreLabels map[string]stri
 	if string(volume.Spec.OfflineRebuilding) == "" {
 		patchOps = append(patchOps, fmt.Sprintf(`{"op": "replace", "path": "/spec/offlineRebuilding", "value": "%s"}`, longhorn.VolumeOfflineRebuildingIgnored))
 	}
+	if string(volume.Spec.ReplicaRebuilding) == "" {
+		patchOps = append(patchOps, fmt.Sprintf(`{"op": "replace", "path": "/spec/replicaRebuilding", "value": "%s"}`, longhorn.VolumeReplicaRebuildingIgnored))
+	}
+	if string(volume.Spec.BackingImageCleanupPolicy) == "" {
+		patchOps = append(patchOps, fmt.Sprintf(`{"op": "replace", "path": "/spec/backingImageCleanupPolicy", "value": "%s"}`, longhorn.BackingImageCleanupPolicyRetain))
+	}
+	if string(volume.Spec.SnapshotMaxSizeAction) == "" {
+		patchOps = append(patchOps, fmt.Sprintf(`{"op": "replace", "path": "/spec/snapshotMaxSizeAction", "value": "%s"}`, longhorn.SnapshotMaxSizeActionBlock))
+	}
+	// Store a size threshold in bytes regardless of whether the volume comes from CSI, the REST API or the CR,
+	// so that the threshold is compared the same way everywhere. An invalid threshold is rejected by the validator.
+	if threshold, err := types.NormalizeSnapshotReclaimThreshold(volume.Spec.SnapshotReclaimThreshold); err == nil && threshold != volume.Spec.SnapshotReclaimThreshold {
+		patchOps = append(patchOps, fmt.Sprintf(`{"op": "replace", "path": "/spec/snapshotReclaimThreshold", "value": "%s"}`, threshold))
+	}
 
 	var backupBlockSize = volume.Spec.BackupBlockSize
 	if volume.Spec.Standby {
@@ -399,6 +418,10 @@ func (v *volumeMutator) mutate(newObj runtime.Object, moreLabels map[string]stri
 			return nil, werror.NewInvalidError(fmt.Sprintf("invalid default backup block size setting: %s", settingErr.Error()), "")
 		}
 		backupBlockSize = defaultBackupBlockSize
+		if types.ValidateBackupBlockSizeForDataEngine(volume.Spec.DataEngine, backupBlockSize) != nil {
+			// The default may not be supported by the data engine of the volume
+			backupBlockSize = types.BackupBlockSize2Mi
+		}
 	}
 	if backupBlockSize != volume.Spec.BackupBlockSize {
 		patchOps = append(patchOps, fmt.Sprintf(`{"op": "replace", "path": "/spec/backupBlockSize", "value": "%d"}`, backupBlockSize))
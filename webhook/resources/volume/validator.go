@@ -89,10 +89,35 @@ func (v *volumeValidator) Create(request *admission.Request, newObj runtime.Obje
 		return werror.NewInvalidError(err.Error(), "spec.dataLocality and spec.migratable and spec.accessMode")
 	}
 
+	if volume.Spec.NodeID != "" {
+		if _, err := v.ds.GetNodeRO(volume.Spec.NodeID); err != nil {
+			if datastore.ErrorIsNotFound(err) {
+				return werror.NewInvalidError(fmt.Sprintf("node %v to attach the volume to is not found", volume.Spec.NodeID), "spec.nodeID")
+			}
+			return werror.NewInternalError(err.Error())
+		}
+	}
+
+	if volume.Spec.MigrationTargetNodeID != "" {
+		if !volume.Spec.Migratable {
+			return werror.NewInvalidError("migration target node is only supported by migratable volumes", "spec.migrationTargetNodeID")
+		}
+		if _, err := v.ds.GetNodeRO(volume.Spec.MigrationTargetNodeID); err != nil {
+			if datastore.ErrorIsNotFound(err) {
+				return werror.NewInvalidError(fmt.Sprintf("migration target node %v is not found", volume.Spec.MigrationTargetNodeID), "spec.migrationTargetNodeID")
+			}
+			return werror.NewInternalError(err.Error())
+		}
+	}
+
 	if err := types.ValidateReplicaAutoBalance(volume.Spec.ReplicaAutoBalance); err != nil {
 		return werror.NewInvalidError(err.Error(), "spec.replicaAutoBalance")
 	}
 
+	if err := types.ValidateReplicaAutoBalanceDiskPressurePercentage(volume.Spec.ReplicaAutoBalanceDiskPressurePercentage); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.replicaAutoBalanceDiskPressurePercentage")
+	}
+
 	if err := types.ValidateUnmapMarkSnapChainRemoved(volume.Spec.DataEngine, volume.Spec.UnmapMarkSnapChainRemoved); err != nil {
 		return werror.NewInvalidError(err.Error(), "spec.unmapMarkSnapChainRemoved")
 	}
@@ -113,9 +138,67 @@ func (v *volumeValidator) Create(request *admission.Request, newObj runtime.Obje
 		return werror.NewInvalidError(err.Error(), "spec.offlineRebuilding")
 	}
 
+	if err := types.ValidateReplicaRebuilding(volume.Spec.ReplicaRebuilding); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.replicaRebuilding")
+	}
+
+	if err := types.ValidateBackingImageCleanupPolicy(volume.Spec.BackingImageCleanupPolicy); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.backingImageCleanupPolicy")
+	}
+
+	if err := types.ValidateSnapshotMaxSizeAction(volume.Spec.SnapshotMaxSizeAction); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.snapshotMaxSizeAction")
+	}
+
+	if err := types.ValidateRecurringSnapshotOverflowAction(volume.Spec.RecurringSnapshotOverflowAction); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.recurringSnapshotOverflowAction")
+	}
+
+	if err := types.ValidateSnapshotDataIntegrityCronJob(volume.Spec.SnapshotDataIntegrityCronJob); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.snapshotDataIntegrityCronJob")
+	}
+
+	if err := types.ValidateDataEngineLogLevel(volume.Spec.DataEngineLogLevel); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.dataEngineLogLevel")
+	}
+	if volume.Spec.DataEngineLogLevel != "" && !types.IsDataEngineV2(volume.Spec.DataEngine) {
+		return werror.NewInvalidError("data engine log level is only supported by the v2 data engine", "spec.dataEngineLogLevel")
+	}
+
+	if err := types.ValidateSnapshotReclaimThreshold(volume.Spec.Size, volume.Spec.SnapshotReclaimThreshold); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.snapshotReclaimThreshold")
+	}
+
+	if err := types.ValidateEngineImagePullSecret(volume.Spec.EngineImagePullSecret); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.engineImagePullSecret")
+	}
+
+	if err := types.ValidateInstanceManagerImage(volume.Spec.DataEngine, volume.Spec.InstanceManagerImage); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.instanceManagerImage")
+	}
+
+	if err := v.validateInstanceManagerImageRunning("", volume.Spec.InstanceManagerImage, volume.Spec.DataEngine); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.instanceManagerImage")
+	}
+
+	if err := types.ValidateAutoDowngradeFromRWX(volume.Spec.AutoDowngradeFromRWX, volume.Spec.Migratable, volume.Spec.AccessMode); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.autoDowngradeFromRWX")
+	}
+
+	if err := types.ValidateMaxSize(volume.Spec.Size, volume.Spec.MaxSize); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.maxSize")
+	}
+
+	if err := types.ValidateStandbyRestoreInterval(volume.Spec.StandbyRestoreInterval); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.standbyRestoreInterval")
+	}
+
 	if err := types.ValidateBackupBlockSize(volume.Spec.Size, volume.Spec.BackupBlockSize); err != nil {
 		return werror.NewInvalidError(err.Error(), "spec.backupBlockSize")
 	}
+	if err := types.ValidateBackupBlockSizeForDataEngine(volume.Spec.DataEngine, volume.Spec.BackupBlockSize); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.backupBlockSize")
+	}
 
 	if err := types.ValidateReplicaRebuildingBandwidthLimit(volume.Spec.DataEngine, volume.Spec.ReplicaRebuildingBandwidthLimit); err != nil {
 		return werror.NewInvalidError(err.Error(), "spec.replicaRebuildingBandwidthLimit")
@@ -249,6 +332,10 @@ func (v *volumeValidator) Update(request *admission.Request, oldObj runtime.Obje
 		return werror.NewInvalidError(err.Error(), "spec.replicaAutoBalance")
 	}
 
+	if err := types.ValidateReplicaAutoBalanceDiskPressurePercentage(newVolume.Spec.ReplicaAutoBalanceDiskPressurePercentage); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.replicaAutoBalanceDiskPressurePercentage")
+	}
+
 	if err := types.ValidateUnmapMarkSnapChainRemoved(newVolume.Spec.DataEngine, newVolume.Spec.UnmapMarkSnapChainRemoved); err != nil {
 		return werror.NewInvalidError(err.Error(), "spec.unmapMarkSnapChainRemoved")
 	}
@@ -269,6 +356,61 @@ func (v *volumeValidator) Update(request *admission.Request, oldObj runtime.Obje
 		return werror.NewInvalidError(err.Error(), "spec.offlineRebuilding")
 	}
 
+	if err := types.ValidateReplicaRebuilding(newVolume.Spec.ReplicaRebuilding); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.replicaRebuilding")
+	}
+
+	if err := types.ValidateBackingImageCleanupPolicy(newVolume.Spec.BackingImageCleanupPolicy); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.backingImageCleanupPolicy")
+	}
+
+	if err := types.ValidateSnapshotMaxSizeAction(newVolume.Spec.SnapshotMaxSizeAction); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.snapshotMaxSizeAction")
+	}
+
+	if err := types.ValidateRecurringSnapshotOverflowAction(newVolume.Spec.RecurringSnapshotOverflowAction); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.recurringSnapshotOverflowAction")
+	}
+
+	if err := types.ValidateSnapshotDataIntegrityCronJob(newVolume.Spec.SnapshotDataIntegrityCronJob); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.snapshotDataIntegrityCronJob")
+	}
+
+	if err := types.ValidateDataEngineLogLevel(newVolume.Spec.DataEngineLogLevel); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.dataEngineLogLevel")
+	}
+	if newVolume.Spec.DataEngineLogLevel != "" && !types.IsDataEngineV2(newVolume.Spec.DataEngine) {
+		return werror.NewInvalidError("data engine log level is only supported by the v2 data engine", "spec.dataEngineLogLevel")
+	}
+
+	if err := types.ValidateSnapshotReclaimThreshold(newVolume.Spec.Size, newVolume.Spec.SnapshotReclaimThreshold); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.snapshotReclaimThreshold")
+	}
+
+	if err := types.ValidateEngineImagePullSecret(newVolume.Spec.EngineImagePullSecret); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.engineImagePullSecret")
+	}
+
+	if err := types.ValidateInstanceManagerImage(newVolume.Spec.DataEngine, newVolume.Spec.InstanceManagerImage); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.instanceManagerImage")
+	}
+
+	if err := v.validateInstanceManagerImageRunning(oldVolume.Spec.InstanceManagerImage, newVolume.Spec.InstanceManagerImage, newVolume.Spec.DataEngine); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.instanceManagerImage")
+	}
+
+	if err := types.ValidateAutoDowngradeFromRWX(newVolume.Spec.AutoDowngradeFromRWX, newVolume.Spec.Migratable, newVolume.Spec.AccessMode); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.autoDowngradeFromRWX")
+	}
+
+	if err := types.ValidateMaxSize(newVolume.Spec.Size, newVolume.Spec.MaxSize); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.maxSize")
+	}
+
+	if err := types.ValidateStandbyRestoreInterval(newVolume.Spec.StandbyRestoreInterval); err != nil {
+		return werror.NewInvalidError(err.Error(), "spec.standbyRestoreInterval")
+	}
+
 	if err := validateImmutable(".spec.dataSource", oldVolume.Spec.DataSource, newVolume.Spec.DataSource); err != nil {
 		return werror.NewInvalidError(err.Error(), ".spec.dataSource")
 	}
@@ -277,6 +419,11 @@ func (v *volumeValidator) Update(request *admission.Request, oldObj runtime.Obje
 		return werror.NewInvalidError(err.Error(), ".spec.cloneMode")
 	}
 
+	// The pinned instance manager image is only honored at creation, and the volume is upgraded through spec.image.
+	if err := validateImmutable(".spec.instanceManagerImage", oldVolume.Spec.InstanceManagerImage, newVolume.Spec.InstanceManagerImage); err != nil {
+		return werror.NewInvalidError(err.Error(), ".spec.instanceManagerImage")
+	}
+
 	if oldVolume.Spec.Image != newVolume.Spec.Image {
 		if err := v.ds.CheckDataEngineImageCompatiblityByImage(newVolume.Spec.Image, newVolume.Spec.DataEngine); err != nil {
 			return werror.NewInvalidError(err.Error(), "volume.spec.image")
@@ -315,6 +462,12 @@ func (v *volumeValidator) Update(request *admission.Request, oldObj runtime.Obje
 	if err := types.ValidateBackupBlockSize(newVolume.Spec.Size, newVolume.Spec.BackupBlockSize); err != nil {
 		return werror.NewInvalidError(err.Error(), "spec.backupBlockSize")
 	}
+	// Existing volumes may have been created with a block size not supported by their data engine, so only check the changed one
+	if oldVolume.Spec.BackupBlockSize != newVolume.Spec.BackupBlockSize {
+		if err := types.ValidateBackupBlockSizeForDataEngine(newVolume.Spec.DataEngine, newVolume.Spec.BackupBlockSize); err != nil {
+			return werror.NewInvalidError(err.Error(), "spec.backupBlockSize")
+		}
+	}
 
 	if err := types.ValidateReplicaRebuildingBandwidthLimit(newVolume.Spec.DataEngine, newVolume.Spec.ReplicaRebuildingBandwidthLimit); err != nil {
 		return werror.NewInvalidError(err.Error(), "spec.replicaRebuildingBandwidthLimit")
@@ -548,6 +701,25 @@ func validateSnapshotMaxSize(size, snapshotMaxSize int64) error {
 	return nil
 }
 
+// validateInstanceManagerImageRunning only allows pinning a volume to an instance manager image
+// that is still running on at least one node, since the volume cannot be started otherwise.
+func (v *volumeValidator) validateInstanceManagerImageRunning(oldImage, newImage string, dataEngine longhorn.DataEngineType) error {
+	if newImage == "" || oldImage == newImage {
+		return nil
+	}
+
+	ims, err := v.ds.ListInstanceManagersBySelectorRO("", newImage, longhorn.InstanceManagerTypeAllInOne, dataEngine)
+	if err != nil {
+		return errors.Wrapf(err, "failed to list instance managers with image %v", newImage)
+	}
+	for _, im := range ims {
+		if im.Status.CurrentState == longhorn.InstanceManagerStateRunning {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot pin to instance manager image %v since no instance manager with the image is running", newImage)
+}
+
 func (v *volumeValidator) validateBackupTarget(oldBackupTarget, newBackupTarget string) error {
 	if newBackupTarget == "" {
 		return fmt.Errorf("backup target name cannot be empty when creating a volume or updating from an existing backup target")